@@ -12,8 +12,9 @@ import (
 )
 
 var (
-	hash         = sha256.New
-	aukAlgorithm = "PBES2g-HS256"
+	hash            = sha256.New
+	aukAlgorithm    = "PBES2g-HS256"
+	exportAlgorithm = "PBES2-HS256-export"
 )
 
 type AUKParams struct {
@@ -87,3 +88,30 @@ func validateParams(params *AUKParams) error {
 	}
 	return nil
 }
+
+// DeriveExportKey derives the key protecting an export archive from its export password.
+//
+// Unlike the AUK, the export key only depends on the password, so the archive can be opened without the account's
+// email address or Secret Key.
+func DeriveExportKey(password string, salt *Salt, rounds int) (*JWK, error) {
+	if rounds <= 0 {
+		return nil, fmt.Errorf("rounds must be > 0")
+	}
+	if salt == nil {
+		return nil, fmt.Errorf("salt is required")
+	}
+	strippedPass := strings.TrimSpace(password)
+	if len(strippedPass) == 0 {
+		return nil, fmt.Errorf("password cannot be empty")
+	}
+	normalizedPass := norm.NFKD.Bytes([]byte(strippedPass))
+	expandedSalt, err := hkdf.Key(hash, salt[:], nil, exportAlgorithm, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expand salt with HKDF: %w", err)
+	}
+	key, err := pbkdf2.Key(hash, string(normalizedPass), expandedSalt, rounds, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key with PBKDF2: %w", err)
+	}
+	return NewKey(ExportKeyID, key, KeyUseEncryption)
+}
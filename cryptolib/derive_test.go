@@ -224,3 +224,36 @@ func TestDeriveValidation(t *testing.T) {
     })
   }
 }
+
+// TestDeriveExportKey tests that export keys only depend on the password, salt and rounds.
+func TestDeriveExportKey(t *testing.T) {
+  salt := randomSalt16()
+  key1, err := DeriveExportKey("  export PASSWORD", &salt, 1000)
+  if err != nil {
+    t.Fatalf("derive: %v", err)
+  }
+  if key1.KeyID != ExportKeyID || len(key1.Key.([]byte)) != 32 {
+    t.Fatalf("unexpected export key %s", key1.KeyID)
+  }
+  key2, err := DeriveExportKey("export PASSWORD", &salt, 1000)
+  if err != nil {
+    t.Fatalf("derive: %v", err)
+  }
+  if !bytes.Equal(key1.Key.([]byte), key2.Key.([]byte)) {
+    t.Fatalf("expected surrounding whitespace to be ignored")
+  }
+  otherSalt := randomSalt16()
+  key3, err := DeriveExportKey("export PASSWORD", &otherSalt, 1000)
+  if err != nil {
+    t.Fatalf("derive: %v", err)
+  }
+  if bytes.Equal(key1.Key.([]byte), key3.Key.([]byte)) {
+    t.Fatalf("expected a different salt to derive a different key")
+  }
+  if _, err := DeriveExportKey("   ", &salt, 1000); err == nil {
+    t.Fatalf("expected an empty password to fail")
+  }
+  if _, err := DeriveExportKey("export PASSWORD", &salt, 0); err == nil {
+    t.Fatalf("expected zero rounds to fail")
+  }
+}
@@ -15,7 +15,8 @@ import (
 )
 
 var (
-	AccountUnlockKeyID = "auk" // Special key ID for AUK (Account Unlock Key)
+	AccountUnlockKeyID = "auk"    // Special key ID for AUK (Account Unlock Key)
+	ExportKeyID        = "export" // Special key ID for keys derived from an export password
 )
 
 type JWK struct {
@@ -0,0 +1,149 @@
+package cryptolib
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ParseOTPMigration parses an otpauth-migration://offline?data=... URI, as exported by Google
+// Authenticator's "transfer accounts" QR codes, into the TOTP parameters of each exported account.
+func ParseOTPMigration(uri string) ([]*TOTPParams, error) {
+	u, err := url.Parse(strings.TrimSpace(uri))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidOTP, err)
+	}
+	if !strings.EqualFold(u.Scheme, "otpauth-migration") {
+		return nil, fmt.Errorf("%w: unexpected scheme %q", ErrInvalidOTP, u.Scheme)
+	}
+	data := strings.ReplaceAll(u.Query().Get("data"), " ", "+")
+	payload, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		payload, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(data, "="))
+		if err != nil {
+			return nil, fmt.Errorf("%w: migration data is not valid base64", ErrInvalidOTP)
+		}
+	}
+	// MigrationPayload { repeated OtpParameters otp_parameters = 1; ... }
+	var params []*TOTPParams
+	err = readProtoFields(payload, func(num int, wireType int, value []byte, _ uint64) error {
+		if num != 1 || wireType != protoWireBytes {
+			return nil
+		}
+		p, err := parseOTPParameters(value)
+		if err != nil {
+			return err
+		}
+		params = append(params, p)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// parseOTPParameters decodes a single OtpParameters message:
+//
+//	bytes secret = 1; string name = 2; string issuer = 3; Algorithm algorithm = 4;
+//	DigitCount digits = 5; OtpType type = 6; int64 counter = 7;
+func parseOTPParameters(msg []byte) (*TOTPParams, error) {
+	p := &TOTPParams{
+		Algorithm: OTPAlgorithmSHA1,
+		Digits:    defaultOTPDigits,
+		Period:    defaultOTPPeriod,
+	}
+	err := readProtoFields(msg, func(num int, wireType int, value []byte, varint uint64) error {
+		switch num {
+		case 1:
+			p.Secret = append([]byte(nil), value...)
+		case 2:
+			p.AccountName = string(value)
+			// Names are often exported as "issuer:account"
+			if _, account, ok := strings.Cut(p.AccountName, ":"); ok {
+				p.AccountName = strings.TrimSpace(account)
+			}
+		case 3:
+			p.Issuer = string(value)
+		case 4:
+			switch varint {
+			case 0, 1:
+				p.Algorithm = OTPAlgorithmSHA1
+			case 2:
+				p.Algorithm = OTPAlgorithmSHA256
+			case 3:
+				p.Algorithm = OTPAlgorithmSHA512
+			default:
+				return fmt.Errorf("%w: unsupported migration algorithm %d", ErrInvalidOTP, varint)
+			}
+		case 5:
+			if varint == 2 {
+				p.Digits = 8
+			}
+		case 6:
+			if varint == 1 {
+				return fmt.Errorf("%w: HOTP accounts are not supported", ErrInvalidOTP)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return p, p.validate()
+}
+
+const (
+	protoWireVarint = 0
+	protoWireI64    = 1
+	protoWireBytes  = 2
+	protoWireI32    = 5
+)
+
+// readProtoFields walks the top-level fields of a protobuf message, calling fn with the field number, wire type
+// and either the length-delimited value or the varint value.
+func readProtoFields(msg []byte, fn func(num int, wireType int, value []byte, varint uint64) error) error {
+	for len(msg) > 0 {
+		tag, n := binary.Uvarint(msg)
+		if n <= 0 {
+			return fmt.Errorf("%w: malformed migration data", ErrInvalidOTP)
+		}
+		msg = msg[n:]
+		num, wireType := int(tag>>3), int(tag&0x7)
+		var value []byte
+		var varint uint64
+		switch wireType {
+		case protoWireVarint:
+			varint, n = binary.Uvarint(msg)
+			if n <= 0 {
+				return fmt.Errorf("%w: malformed migration data", ErrInvalidOTP)
+			}
+			msg = msg[n:]
+		case protoWireBytes:
+			length, n := binary.Uvarint(msg)
+			if n <= 0 || uint64(len(msg)-n) < length {
+				return fmt.Errorf("%w: malformed migration data", ErrInvalidOTP)
+			}
+			value = msg[n : n+int(length)]
+			msg = msg[n+int(length):]
+		case protoWireI64:
+			if len(msg) < 8 {
+				return fmt.Errorf("%w: malformed migration data", ErrInvalidOTP)
+			}
+			msg = msg[8:]
+		case protoWireI32:
+			if len(msg) < 4 {
+				return fmt.Errorf("%w: malformed migration data", ErrInvalidOTP)
+			}
+			msg = msg[4:]
+		default:
+			return fmt.Errorf("%w: unsupported protobuf wire type %d", ErrInvalidOTP, wireType)
+		}
+		if err := fn(num, wireType, value, varint); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package cryptolib
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrInvalidSignature = errors.New("invalid signature")
+)
+
+// signatureHash returns the hash function matching the strength of the given ECDSA curve size.
+func signatureHash(bitSize int) crypto.Hash {
+	switch {
+	case bitSize > 384:
+		return crypto.SHA512
+	case bitSize > 256:
+		return crypto.SHA384
+	default:
+		return crypto.SHA256
+	}
+}
+
+// Sign signs the given data using this Key and returns an ASN.1 encoded signature.
+//
+// Only ECDSA private keys (such as the key set signing key) are supported. The data is hashed
+// with a SHA-2 function matching the size of the key's curve before signing.
+func (k *JWK) Sign(data []byte) ([]byte, error) {
+	if k.cleared {
+		return nil, ErrKeyCleared
+	}
+	key, ok := k.Key.(*ecdsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: cannot use algorithm \"%s\" for signing", ErrUnsupportedAlg, k.Algorithm)
+	}
+	h := signatureHash(key.Curve.Params().BitSize).New()
+	h.Write(data)
+	return ecdsa.SignASN1(rand.Reader, key, h.Sum(nil))
+}
+
+// Verify checks that sig is a valid signature of data made by the private half of this Key.
+//
+// Both ECDSA public and private keys may be used for verification.
+func (k *JWK) Verify(data []byte, sig []byte) error {
+	var key *ecdsa.PublicKey
+	switch pk := k.Key.(type) {
+	case *ecdsa.PublicKey:
+		key = pk
+	case *ecdsa.PrivateKey:
+		if k.cleared {
+			return ErrKeyCleared
+		}
+		key = &pk.PublicKey
+	default:
+		return fmt.Errorf("%w: cannot use algorithm \"%s\" for verifying", ErrUnsupportedAlg, k.Algorithm)
+	}
+	h := signatureHash(key.Curve.Params().BitSize).New()
+	h.Write(data)
+	if !ecdsa.VerifyASN1(key, h.Sum(nil), sig) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// Fingerprint returns the RFC 7638 SHA-256 thumbprint of the public portion of this Key.
+func (k *JWK) Fingerprint() ([]byte, error) {
+	pub := k.Public()
+	return pub.Thumbprint(crypto.SHA256)
+}
@@ -0,0 +1,64 @@
+package cryptolib
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestSignVerify(t *testing.T) {
+	privKey, pubKey, err := generateSigningKey(ECDSA_CURVE)
+	if err != nil {
+		t.Fatalf("failed to generate signing key: %v", err)
+	}
+	sig, err := privKey.Sign([]byte("test message"))
+	if err != nil {
+		t.Fatalf("failed to sign data: %v", err)
+	}
+	if err := pubKey.Verify([]byte("test message"), sig); err != nil {
+		t.Fatalf("failed to verify signature: %v", err)
+	}
+	if err := pubKey.Verify([]byte("tampered message"), sig); err == nil {
+		t.Fatal("expected error when verifying tampered data, but got none")
+	}
+}
+
+func TestSignWithPublicKey(t *testing.T) {
+	_, pubKey, err := generateSigningKey(ECDSA_CURVE)
+	if err != nil {
+		t.Fatalf("failed to generate signing key: %v", err)
+	}
+	_, err = pubKey.Sign([]byte("test message"))
+	if err == nil {
+		t.Fatal("expected error when signing with a public key, but got none")
+	}
+}
+
+func TestSignWithClearedKey(t *testing.T) {
+	privKey, _, err := generateSigningKey(ECDSA_CURVE)
+	if err != nil {
+		t.Fatalf("failed to generate signing key: %v", err)
+	}
+	privKey.Close()
+	_, err = privKey.Sign([]byte("test message"))
+	if err == nil {
+		t.Fatal("expected error when signing with a cleared key, but got none")
+	}
+}
+
+func TestFingerprintMatchesPublicKey(t *testing.T) {
+	privKey, pubKey, err := generateSigningKey(ECDSA_CURVE)
+	if err != nil {
+		t.Fatalf("failed to generate signing key: %v", err)
+	}
+	privPrint, err := privKey.Fingerprint()
+	if err != nil {
+		t.Fatalf("failed to fingerprint private key: %v", err)
+	}
+	pubPrint, err := pubKey.Fingerprint()
+	if err != nil {
+		t.Fatalf("failed to fingerprint public key: %v", err)
+	}
+	if !bytes.Equal(privPrint, pubPrint) {
+		t.Fatalf("private key fingerprint (%x) does not match public key fingerprint (%x)", privPrint, pubPrint)
+	}
+}
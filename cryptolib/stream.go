@@ -0,0 +1,198 @@
+package cryptolib
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Streams are encrypted in fixed size chunks using AES-GCM so large files never need to be held in memory.
+//
+// The stream starts with a header of streamMagic followed by a random nonce prefix. Each chunk is sealed with
+// the nonce prefix || big-endian chunk counter || last chunk flag, which prevents chunks from being reordered,
+// dropped or the stream from being truncated without detection (the STREAM construction).
+const (
+	StreamChunkSize   = 64 * 1024
+	streamPrefixSize  = 7
+	streamTagSize     = 16
+	streamLastChunk   = 1
+	streamHeaderMagic = "OVS1"
+)
+
+var (
+	ErrStreamCorrupt = errors.New("encrypted stream is corrupt or truncated")
+)
+
+func newStreamAEAD(k *JWK) (cipher.AEAD, error) {
+	if k.cleared {
+		return nil, ErrKeyCleared
+	}
+	key, ok := k.Key.([]byte)
+	if !ok {
+		return nil, fmt.Errorf("%w: cannot use algorithm \"%s\" for stream encryption", ErrUnsupportedAlg, k.Algorithm)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func streamNonce(prefix []byte, counter uint32, last bool) []byte {
+	nonce := make([]byte, aesNonceSize)
+	copy(nonce, prefix)
+	binary.BigEndian.PutUint32(nonce[streamPrefixSize:], counter)
+	if last {
+		nonce[aesNonceSize-1] = streamLastChunk
+	}
+	return nonce
+}
+
+type encryptWriter struct {
+	w       io.Writer
+	aead    cipher.AEAD
+	prefix  []byte
+	counter uint32
+	buf     []byte
+	closed  bool
+}
+
+// NewEncryptWriter returns a writer which encrypts everything written to it with this symmetric Key and writes
+// the ciphertext to w. Close must be called to write the final chunk; it does not close w.
+func (k *JWK) NewEncryptWriter(w io.Writer) (io.WriteCloser, error) {
+	aead, err := newStreamAEAD(k)
+	if err != nil {
+		return nil, err
+	}
+	prefix := make([]byte, streamPrefixSize)
+	if _, err := rand.Read(prefix); err != nil {
+		return nil, fmt.Errorf("failed to read random nonce prefix: %w", err)
+	}
+	if _, err := w.Write(append([]byte(streamHeaderMagic), prefix...)); err != nil {
+		return nil, err
+	}
+	return &encryptWriter{w: w, aead: aead, prefix: prefix, buf: make([]byte, 0, StreamChunkSize)}, nil
+}
+
+func (ew *encryptWriter) Write(p []byte) (int, error) {
+	if ew.closed {
+		return 0, errors.New("write to closed encrypt writer")
+	}
+	written := 0
+	for len(p) > 0 {
+		// Only flush a full chunk once more data arrives, so the final chunk is always written by Close
+		if len(ew.buf) == StreamChunkSize {
+			if err := ew.flush(false); err != nil {
+				return written, err
+			}
+		}
+		n := copy(ew.buf[len(ew.buf):StreamChunkSize], p)
+		ew.buf = ew.buf[:len(ew.buf)+n]
+		p = p[n:]
+		written += n
+	}
+	return written, nil
+}
+
+func (ew *encryptWriter) flush(last bool) error {
+	if ew.counter == ^uint32(0) {
+		return errors.New("encrypted stream is too large")
+	}
+	ct := ew.aead.Seal(nil, streamNonce(ew.prefix, ew.counter, last), ew.buf, nil)
+	clear(ew.buf)
+	ew.buf = ew.buf[:0]
+	ew.counter++
+	_, err := ew.w.Write(ct)
+	return err
+}
+
+func (ew *encryptWriter) Close() error {
+	if ew.closed {
+		return nil
+	}
+	ew.closed = true
+	return ew.flush(true)
+}
+
+type decryptReader struct {
+	r       *bufio.Reader
+	aead    cipher.AEAD
+	prefix  []byte
+	counter uint32
+	buf     []byte
+	chunk   []byte
+	done    bool
+}
+
+// NewDecryptReader returns a reader which decrypts a stream written by NewEncryptWriter using this symmetric Key.
+//
+// Reads return ErrStreamCorrupt if any chunk fails authentication or the stream was truncated. Data is only
+// returned after the chunk containing it has been authenticated.
+func (k *JWK) NewDecryptReader(r io.Reader) (io.Reader, error) {
+	aead, err := newStreamAEAD(k)
+	if err != nil {
+		return nil, err
+	}
+	header := make([]byte, len(streamHeaderMagic)+streamPrefixSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, ErrStreamCorrupt
+	}
+	if !bytes.Equal(header[:len(streamHeaderMagic)], []byte(streamHeaderMagic)) {
+		return nil, ErrStreamCorrupt
+	}
+	return &decryptReader{
+		r:      bufio.NewReaderSize(r, StreamChunkSize+streamTagSize+1),
+		aead:   aead,
+		prefix: header[len(streamHeaderMagic):],
+		chunk:  make([]byte, StreamChunkSize+streamTagSize),
+	}, nil
+}
+
+func (dr *decryptReader) Read(p []byte) (int, error) {
+	for len(dr.buf) == 0 {
+		if dr.done {
+			return 0, io.EOF
+		}
+		if err := dr.next(); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, dr.buf)
+	dr.buf = dr.buf[n:]
+	return n, nil
+}
+
+// next reads and authenticates the next chunk
+func (dr *decryptReader) next() error {
+	n, err := io.ReadFull(dr.r, dr.chunk)
+	last := false
+	switch err {
+	case nil:
+		// A full chunk is the last one only if nothing follows it
+		if _, err := dr.r.Peek(1); err == io.EOF {
+			last = true
+		} else if err != nil {
+			return err
+		}
+	case io.ErrUnexpectedEOF:
+		last = true
+	case io.EOF:
+		return ErrStreamCorrupt
+	default:
+		return err
+	}
+	plaintext, err := dr.aead.Open(dr.chunk[:0], streamNonce(dr.prefix, dr.counter, last), dr.chunk[:n], nil)
+	if err != nil {
+		return ErrStreamCorrupt
+	}
+	dr.counter++
+	dr.buf = plaintext
+	dr.done = last
+	return nil
+}
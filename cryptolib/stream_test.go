@@ -0,0 +1,87 @@
+package cryptolib
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"io"
+	"testing"
+)
+
+func encryptStream(t *testing.T, key *JWK, plaintext []byte) []byte {
+	t.Helper()
+	var ct bytes.Buffer
+	w, err := key.NewEncryptWriter(&ct)
+	if err != nil {
+		t.Fatalf("failed to create encrypt writer: %v", err)
+	}
+	// Write in uneven pieces to exercise chunk buffering
+	for len(plaintext) > 0 {
+		n := min(len(plaintext), 10000)
+		if _, err := w.Write(plaintext[:n]); err != nil {
+			t.Fatalf("failed to write plaintext: %v", err)
+		}
+		plaintext = plaintext[n:]
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("failed to close encrypt writer: %v", err)
+	}
+	return ct.Bytes()
+}
+
+func decryptStream(key *JWK, ciphertext []byte) ([]byte, error) {
+	r, err := key.NewDecryptReader(bytes.NewReader(ciphertext))
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+func TestStreamRoundTrip(t *testing.T) {
+	key := randomSymmetricKey()
+	sizes := []int{0, 1, StreamChunkSize - 1, StreamChunkSize, StreamChunkSize + 1, 3*StreamChunkSize + 123}
+	for _, size := range sizes {
+		plaintext := make([]byte, size)
+		rand.Read(plaintext)
+		ct := encryptStream(t, key, plaintext)
+		decrypted, err := decryptStream(key, ct)
+		if err != nil {
+			t.Fatalf("failed to decrypt %d byte stream: %v", size, err)
+		}
+		if !bytes.Equal(decrypted, plaintext) {
+			t.Fatalf("decrypted %d byte stream does not match plaintext", size)
+		}
+	}
+}
+
+func TestStreamTruncated(t *testing.T) {
+	key := randomSymmetricKey()
+	plaintext := make([]byte, 2*StreamChunkSize)
+	rand.Read(plaintext)
+	ct := encryptStream(t, key, plaintext)
+	// Dropping the final chunk leaves a stream of valid chunks which must still be rejected
+	truncated := ct[:len(ct)-streamTagSize]
+	if _, err := decryptStream(key, truncated); !errors.Is(err, ErrStreamCorrupt) {
+		t.Fatalf("expected ErrStreamCorrupt for truncated stream, got %v", err)
+	}
+	truncated = ct[:len(streamHeaderMagic)+streamPrefixSize+StreamChunkSize+streamTagSize]
+	if _, err := decryptStream(key, truncated); !errors.Is(err, ErrStreamCorrupt) {
+		t.Fatalf("expected ErrStreamCorrupt for stream truncated at a chunk boundary, got %v", err)
+	}
+}
+
+func TestStreamTampered(t *testing.T) {
+	key := randomSymmetricKey()
+	ct := encryptStream(t, key, []byte("attachment contents"))
+	ct[len(ct)-1] ^= 0xff
+	if _, err := decryptStream(key, ct); !errors.Is(err, ErrStreamCorrupt) {
+		t.Fatalf("expected ErrStreamCorrupt for tampered stream, got %v", err)
+	}
+}
+
+func TestStreamWrongKey(t *testing.T) {
+	ct := encryptStream(t, randomSymmetricKey(), []byte("attachment contents"))
+	if _, err := decryptStream(randomSymmetricKey(), ct); !errors.Is(err, ErrStreamCorrupt) {
+		t.Fatalf("expected ErrStreamCorrupt when decrypting with the wrong key, got %v", err)
+	}
+}
@@ -0,0 +1,201 @@
+package cryptolib
+
+import (
+	"crypto"
+	"crypto/hmac"
+	_ "crypto/sha1"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type OTPAlgorithm string
+
+var (
+	OTPAlgorithmSHA1   OTPAlgorithm = "SHA1"
+	OTPAlgorithmSHA256 OTPAlgorithm = "SHA256"
+	OTPAlgorithmSHA512 OTPAlgorithm = "SHA512"
+)
+
+var (
+	ErrInvalidOTP = errors.New("invalid one-time password parameters")
+)
+
+const (
+	defaultOTPDigits = 6
+	defaultOTPPeriod = 30
+)
+
+// TOTPParams are the parameters of an RFC 6238 time-based one-time password
+type TOTPParams struct {
+	Secret      []byte
+	Algorithm   OTPAlgorithm
+	Digits      int
+	Period      int
+	Issuer      string
+	AccountName string
+}
+
+// ParseTOTP parses either an otpauth://totp/ URI or a raw base32 secret. Raw secrets use the default
+// parameters (SHA1, 6 digits, 30 second period).
+func ParseTOTP(s string) (*TOTPParams, error) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(strings.ToLower(s), "otpauth://") {
+		return ParseOTPAuthURI(s)
+	}
+	secret, err := decodeOTPSecret(s)
+	if err != nil {
+		return nil, err
+	}
+	p := &TOTPParams{
+		Secret:    secret,
+		Algorithm: OTPAlgorithmSHA1,
+		Digits:    defaultOTPDigits,
+		Period:    defaultOTPPeriod,
+	}
+	return p, p.validate()
+}
+
+// ParseOTPAuthURI parses an otpauth://totp/ URI as used in authenticator QR codes
+func ParseOTPAuthURI(uri string) (*TOTPParams, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidOTP, err)
+	}
+	if !strings.EqualFold(u.Scheme, "otpauth") {
+		return nil, fmt.Errorf("%w: unexpected scheme %q", ErrInvalidOTP, u.Scheme)
+	}
+	if !strings.EqualFold(u.Host, "totp") {
+		return nil, fmt.Errorf("%w: unsupported OTP type %q", ErrInvalidOTP, u.Host)
+	}
+	q := u.Query()
+	secret, err := decodeOTPSecret(q.Get("secret"))
+	if err != nil {
+		return nil, err
+	}
+	p := &TOTPParams{
+		Secret:    secret,
+		Algorithm: OTPAlgorithmSHA1,
+		Digits:    defaultOTPDigits,
+		Period:    defaultOTPPeriod,
+		Issuer:    q.Get("issuer"),
+	}
+	// The label is "issuer:account" or just "account"
+	label := strings.TrimPrefix(u.Path, "/")
+	if issuer, account, ok := strings.Cut(label, ":"); ok {
+		p.AccountName = strings.TrimSpace(account)
+		if p.Issuer == "" {
+			p.Issuer = issuer
+		}
+	} else {
+		p.AccountName = label
+	}
+	if alg := q.Get("algorithm"); alg != "" {
+		p.Algorithm = OTPAlgorithm(strings.ToUpper(alg))
+	}
+	if digits := q.Get("digits"); digits != "" {
+		if p.Digits, err = strconv.Atoi(digits); err != nil {
+			return nil, fmt.Errorf("%w: invalid digits %q", ErrInvalidOTP, digits)
+		}
+	}
+	if period := q.Get("period"); period != "" {
+		if p.Period, err = strconv.Atoi(period); err != nil {
+			return nil, fmt.Errorf("%w: invalid period %q", ErrInvalidOTP, period)
+		}
+	}
+	return p, p.validate()
+}
+
+// decodeOTPSecret decodes a base32 secret, tolerating lowercase letters, spaces and missing padding
+func decodeOTPSecret(s string) ([]byte, error) {
+	s = strings.ToUpper(strings.NewReplacer(" ", "", "-", "").Replace(s))
+	s = strings.TrimRight(s, "=")
+	if s == "" {
+		return nil, fmt.Errorf("%w: missing secret", ErrInvalidOTP)
+	}
+	secret, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: secret is not valid base32", ErrInvalidOTP)
+	}
+	return secret, nil
+}
+
+func (p *TOTPParams) validate() error {
+	if len(p.Secret) == 0 {
+		return fmt.Errorf("%w: missing secret", ErrInvalidOTP)
+	}
+	if _, err := p.hash(); err != nil {
+		return err
+	}
+	if p.Digits != 6 && p.Digits != 8 {
+		return fmt.Errorf("%w: digits must be 6 or 8", ErrInvalidOTP)
+	}
+	if p.Period <= 0 {
+		return fmt.Errorf("%w: period must be positive", ErrInvalidOTP)
+	}
+	return nil
+}
+
+func (p *TOTPParams) hash() (crypto.Hash, error) {
+	switch p.Algorithm {
+	case OTPAlgorithmSHA1:
+		return crypto.SHA1, nil
+	case OTPAlgorithmSHA256:
+		return crypto.SHA256, nil
+	case OTPAlgorithmSHA512:
+		return crypto.SHA512, nil
+	default:
+		return 0, fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidOTP, p.Algorithm)
+	}
+}
+
+// Code returns the one-time password for the time step containing t and the number of seconds until
+// the next time step.
+func (p *TOTPParams) Code(t time.Time) (code string, remaining int, err error) {
+	if err := p.validate(); err != nil {
+		return "", 0, err
+	}
+	h, _ := p.hash()
+	unix := t.Unix()
+	counter := uint64(unix / int64(p.Period))
+	remaining = p.Period - int(unix%int64(p.Period))
+
+	// HOTP (RFC 4226 section 5.3) over the time step counter
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+	mac := hmac.New(h.New, p.Secret)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0x0f
+	truncated := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	mod := uint32(1)
+	for range p.Digits {
+		mod *= 10
+	}
+	return fmt.Sprintf("%0*d", p.Digits, truncated%mod), remaining, nil
+}
+
+// URI returns the otpauth://totp/ URI for these parameters
+func (p *TOTPParams) URI() string {
+	label := p.AccountName
+	if p.Issuer != "" {
+		label = p.Issuer + ":" + p.AccountName
+	}
+	q := url.Values{}
+	q.Set("secret", base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(p.Secret))
+	if p.Issuer != "" {
+		q.Set("issuer", p.Issuer)
+	}
+	q.Set("algorithm", string(p.Algorithm))
+	q.Set("digits", strconv.Itoa(p.Digits))
+	q.Set("period", strconv.Itoa(p.Period))
+	u := url.URL{Scheme: "otpauth", Host: "totp", Path: "/" + label, RawQuery: q.Encode()}
+	return u.String()
+}
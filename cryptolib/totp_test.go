@@ -0,0 +1,160 @@
+package cryptolib
+
+import (
+	"bytes"
+	"encoding/base32"
+	"encoding/base64"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// TestTOTPVectors checks code generation against the test vectors in RFC 6238 Appendix B.
+func TestTOTPVectors(t *testing.T) {
+	seeds := map[OTPAlgorithm][]byte{
+		OTPAlgorithmSHA1:   []byte("12345678901234567890"),
+		OTPAlgorithmSHA256: []byte("12345678901234567890123456789012"),
+		OTPAlgorithmSHA512: []byte("1234567890123456789012345678901234567890123456789012345678901234"),
+	}
+	tests := []struct {
+		unix int64
+		alg  OTPAlgorithm
+		code string
+	}{
+		{59, OTPAlgorithmSHA1, "94287082"},
+		{59, OTPAlgorithmSHA256, "46119246"},
+		{59, OTPAlgorithmSHA512, "90693936"},
+		{1111111109, OTPAlgorithmSHA1, "07081804"},
+		{1111111109, OTPAlgorithmSHA256, "68084774"},
+		{1111111109, OTPAlgorithmSHA512, "25091201"},
+		{1234567890, OTPAlgorithmSHA1, "89005924"},
+		{1234567890, OTPAlgorithmSHA256, "91819424"},
+		{1234567890, OTPAlgorithmSHA512, "93441116"},
+		{20000000000, OTPAlgorithmSHA1, "65353130"},
+		{20000000000, OTPAlgorithmSHA256, "77737706"},
+		{20000000000, OTPAlgorithmSHA512, "47863826"},
+	}
+
+	for _, tt := range tests {
+		p := &TOTPParams{Secret: seeds[tt.alg], Algorithm: tt.alg, Digits: 8, Period: 30}
+		code, _, err := p.Code(time.Unix(tt.unix, 0))
+		if err != nil {
+			t.Fatalf("failed to generate code: %v", err)
+		}
+		if code != tt.code {
+			t.Fatalf("unexpected %s code at %d: got %s, want %s", tt.alg, tt.unix, code, tt.code)
+		}
+	}
+}
+
+func TestTOTPRemaining(t *testing.T) {
+	p := &TOTPParams{Secret: []byte("12345678901234567890"), Algorithm: OTPAlgorithmSHA1, Digits: 6, Period: 60}
+	code, remaining, err := p.Code(time.Unix(130, 0))
+	if err != nil {
+		t.Fatalf("failed to generate code: %v", err)
+	}
+	if len(code) != 6 {
+		t.Fatalf("expected a 6 digit code, got %q", code)
+	}
+	if remaining != 50 {
+		t.Fatalf("expected 50 seconds remaining, got %d", remaining)
+	}
+}
+
+func TestParseOTPAuthURI(t *testing.T) {
+	p, err := ParseTOTP("otpauth://totp/Example:alice@example.com?secret=JBSWY3DPEHPK3PXP&algorithm=SHA256&digits=8&period=60")
+	if err != nil {
+		t.Fatalf("failed to parse URI: %v", err)
+	}
+	if p.Issuer != "Example" || p.AccountName != "alice@example.com" {
+		t.Fatalf("unexpected label: issuer %q account %q", p.Issuer, p.AccountName)
+	}
+	if p.Algorithm != OTPAlgorithmSHA256 || p.Digits != 8 || p.Period != 60 {
+		t.Fatalf("unexpected parameters: %+v", p)
+	}
+	if !bytes.Equal(p.Secret, []byte("Hello!\xde\xad\xbe\xef")) {
+		t.Fatalf("unexpected secret %x", p.Secret)
+	}
+	// Round trip through URI
+	p2, err := ParseOTPAuthURI(p.URI())
+	if err != nil {
+		t.Fatalf("failed to parse generated URI: %v", err)
+	}
+	if !bytes.Equal(p.Secret, p2.Secret) || p.Issuer != p2.Issuer || p.AccountName != p2.AccountName || p.Period != p2.Period {
+		t.Fatalf("round trip mismatch: %+v != %+v", p, p2)
+	}
+}
+
+func TestParseRawTOTPSecret(t *testing.T) {
+	p, err := ParseTOTP("jbsw y3dp ehpk 3pxp")
+	if err != nil {
+		t.Fatalf("failed to parse raw secret: %v", err)
+	}
+	if p.Algorithm != OTPAlgorithmSHA1 || p.Digits != 6 || p.Period != 30 {
+		t.Fatalf("expected default parameters, got %+v", p)
+	}
+}
+
+func TestParseTOTPInvalid(t *testing.T) {
+	tests := []string{
+		"",
+		"not base32!",
+		"otpauth://hotp/Example?secret=JBSWY3DPEHPK3PXP&counter=1",
+		"otpauth://totp/Example?secret=JBSWY3DPEHPK3PXP&digits=7",
+		"otpauth://totp/Example?secret=JBSWY3DPEHPK3PXP&algorithm=MD5",
+	}
+	for _, s := range tests {
+		if _, err := ParseTOTP(s); err == nil {
+			t.Fatalf("expected error parsing %q, but got none", s)
+		}
+	}
+}
+
+// protoBytesField encodes a length-delimited protobuf field
+func protoBytesField(num int, value []byte) []byte {
+	return append([]byte{byte(num<<3 | protoWireBytes), byte(len(value))}, value...)
+}
+
+// protoVarintField encodes a small varint protobuf field
+func protoVarintField(num int, value byte) []byte {
+	return []byte{byte(num<<3 | protoWireVarint), value}
+}
+
+func TestParseOTPMigration(t *testing.T) {
+	secret, _ := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString("JBSWY3DPEHPK3PXP")
+	var otp []byte
+	otp = append(otp, protoBytesField(1, secret)...)
+	otp = append(otp, protoBytesField(2, []byte("Example:alice@example.com"))...)
+	otp = append(otp, protoBytesField(3, []byte("Example"))...)
+	otp = append(otp, protoVarintField(4, 2)...) // SHA256
+	otp = append(otp, protoVarintField(5, 2)...) // 8 digits
+	otp = append(otp, protoVarintField(6, 2)...) // TOTP
+	payload := protoBytesField(1, otp)
+	payload = append(payload, protoVarintField(2, 1)...) // version
+	uri := "otpauth-migration://offline?data=" + url.QueryEscape(base64.StdEncoding.EncodeToString(payload))
+
+	params, err := ParseOTPMigration(uri)
+	if err != nil {
+		t.Fatalf("failed to parse migration URI: %v", err)
+	}
+	if len(params) != 1 {
+		t.Fatalf("expected 1 account, got %d", len(params))
+	}
+	p := params[0]
+	if !bytes.Equal(p.Secret, secret) || p.Issuer != "Example" || p.AccountName != "alice@example.com" {
+		t.Fatalf("unexpected account: %+v", p)
+	}
+	if p.Algorithm != OTPAlgorithmSHA256 || p.Digits != 8 || p.Period != 30 {
+		t.Fatalf("unexpected parameters: %+v", p)
+	}
+}
+
+func TestParseOTPMigrationRejectsHOTP(t *testing.T) {
+	otp := protoBytesField(1, []byte("12345678901234567890"))
+	otp = append(otp, protoVarintField(6, 1)...) // HOTP
+	payload := protoBytesField(1, otp)
+	uri := "otpauth-migration://offline?data=" + url.QueryEscape(base64.StdEncoding.EncodeToString(payload))
+	if _, err := ParseOTPMigration(uri); err == nil {
+		t.Fatal("expected error for HOTP account, but got none")
+	}
+}
@@ -6,3 +6,11 @@ package cryptolib
 func GenerateVaultKey() (*JWK, error) {
   return generateSymmetricKey(AES_BYTES)
 }
+
+// GenerateContentKey generates a new random symmetric key for encrypting a single payload.
+//
+// Content keys are used for data which leaves a vault (such as share packages), and should be wrapped with the recipient's public key,
+// and for attachments, where each file has its own key so it can be re-wrapped with another vault key without re-encrypting the file.
+func GenerateContentKey() (*JWK, error) {
+  return generateSymmetricKey(AES_BYTES)
+}
@@ -0,0 +1,291 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/BradHacker/openvault/openvault/internal/apiserver"
+	"github.com/BradHacker/openvault/openvault/internal/constants"
+	"github.com/BradHacker/openvault/openvault/internal/fs"
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+
+	"github.com/sirupsen/logrus"
+)
+
+// apiServerBackend exposes the items of the unlocked vaults to the local API
+type apiServerBackend struct {
+	core *CoreService
+}
+
+func (b *apiServerBackend) IsLocked() bool {
+	return b.core.isLocked()
+}
+
+func (b *apiServerBackend) Token(tokenId string) (*structs.APIToken, bool) {
+	token, ok := b.core.state.APITokens[tokenId]
+	return token, ok
+}
+
+func (b *apiServerBackend) Vault(vaultId string) (*apiserver.Vault, error) {
+	vault, ok := b.core.state.Vaults[vaultId]
+	if !ok || vault.IsTrashed() {
+		return nil, fmt.Errorf("vault %s: %w", vaultId, apiserver.ErrNotFound)
+	}
+	if _, ok := b.core.state.AUK[vault.AccountID]; !ok {
+		return nil, fmt.Errorf("account %q: %w", vault.AccountID, apiserver.ErrLocked)
+	}
+	meta, err := b.core.getVaultMetadata(vaultId)
+	if err != nil {
+		return nil, err
+	}
+	return &apiserver.Vault{ID: vaultId, Name: meta.Name, Description: meta.Description}, nil
+}
+
+func (b *apiServerBackend) Items(vaultId string, filter apiserver.ItemFilter) ([]*apiserver.Item, error) {
+	tag, err := structs.NormalizeTag(filter.Tag)
+	if err != nil {
+		return nil, err
+	}
+	itemFilter := ItemFilter{Tag: tag, Favorites: filter.Favorites}
+	overviews, err := b.core.listVaultItemOverviews(vaultId, filter.IncludeTrashed)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]*apiserver.Item, 0, len(overviews))
+	for _, overview := range overviews {
+		if filter.Category != "" && overview.Category != filter.Category || !itemFilter.Matches(overview.VaultItemOverview) {
+			continue
+		}
+		items = append(items, apiItem(overview))
+	}
+	slices.SortFunc(items, func(x, y *apiserver.Item) int {
+		return strings.Compare(strings.ToLower(x.Overview.Title), strings.ToLower(y.Overview.Title))
+	})
+	return items, nil
+}
+
+func (b *apiServerBackend) ItemVault(itemId string) (string, error) {
+	encOverview, ok := b.core.state.ItemOverviews[itemId]
+	if !ok {
+		return "", fmt.Errorf("item %s: %w", itemId, apiserver.ErrNotFound)
+	}
+	// Items of a trashed vault are hidden from the API along with the vault
+	vault, ok := b.core.state.Vaults[encOverview.VaultID]
+	if !ok || vault.IsTrashed() {
+		return "", fmt.Errorf("item %s: %w", itemId, apiserver.ErrNotFound)
+	}
+	if _, ok := b.core.state.AUK[vault.AccountID]; !ok {
+		return "", fmt.Errorf("account %q: %w", vault.AccountID, apiserver.ErrLocked)
+	}
+	return encOverview.VaultID, nil
+}
+
+func (b *apiServerBackend) Item(itemId string, reveal bool) (*apiserver.Item, error) {
+	overview, err := b.core.getItemOverview(itemId)
+	if err != nil {
+		return nil, err
+	}
+	details, err := b.core.getVaultItemDetails(itemId, ItemDetailsOptions{RevealConcealed: reveal})
+	if err != nil {
+		return nil, err
+	}
+	item := apiItem(overview)
+	item.Details = details.VaultItemDetails
+	return item, nil
+}
+
+func (b *apiServerBackend) CreateItem(vaultId string, overview *structs.VaultItemOverview, details *structs.VaultItemDetails) (*apiserver.Item, error) {
+	created, err := b.core.createItem(vaultId, *overview, *details)
+	if err != nil {
+		return nil, err
+	}
+	return apiItem(created), nil
+}
+
+func (b *apiServerBackend) UpdateItem(itemId string, overview *structs.VaultItemOverview, details *structs.VaultItemDetails) (*apiserver.Item, error) {
+	updated, err := b.core.updateItem(itemId, *overview, *details)
+	if err != nil {
+		return nil, err
+	}
+	return apiItem(updated), nil
+}
+
+func (b *apiServerBackend) TrashItem(itemId string) error {
+	return b.core.deleteItem(itemId)
+}
+
+func apiItem(overview *DecryptedVaultItemOverview) *apiserver.Item {
+	return &apiserver.Item{
+		ID:        overview.ItemID,
+		VaultID:   overview.VaultID,
+		CreatedAt: overview.CreatedAt,
+		UpdatedAt: overview.UpdatedAt,
+		TrashedAt: overview.TrashedAt,
+		Overview:  overview.VaultItemOverview,
+	}
+}
+
+// startAPIServer starts serving the local API if it is enabled and not already running. It is never started by
+// command line modes, which would otherwise take over the desktop app's socket.
+func (a *CoreService) startAPIServer() error {
+	if a.headless || a.apiServer != nil || !a.state.Settings.APIServerEnabled {
+		return nil
+	}
+	address := a.state.Settings.APIServerAddress
+	var listener net.Listener
+	var err error
+	if address == "" {
+		address = constants.API_SOCKET
+		listener, err = listenLocalSocket(address)
+	} else if err = apiserver.CheckLoopbackAddress(address); err == nil {
+		listener, err = net.Listen("tcp", address)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to start api server: %w", err)
+	}
+	server := apiserver.NewServer(&apiServerBackend{core: a}, &a.state.mu)
+	go func() {
+		if err := server.Serve(listener); err != nil {
+			logrus.Errorf("api server stopped accepting connections: %v", err)
+		}
+	}()
+	a.apiServer = server
+	a.apiServerAddress = address
+	logrus.Printf("api server listening on %s", address)
+	return nil
+}
+
+// stopAPIServer stops the local API if it is running
+func (a *CoreService) stopAPIServer() {
+	if a.apiServer == nil {
+		return
+	}
+	if err := a.apiServer.Close(); err != nil {
+		logrus.Errorf("failed to stop api server: %v", err)
+	}
+	if a.apiServerAddress == constants.API_SOCKET {
+		removeLocalSocket(constants.API_SOCKET)
+	}
+	a.apiServer = nil
+	a.apiServerAddress = ""
+	logrus.Printf("api server stopped")
+}
+
+type APIServerStatus struct {
+	Running bool `json:"running"`
+	// Socket path or loopback address the API listens on
+	Address string `json:"address"`
+	// Path prefix of the current API version
+	BasePath string `json:"base_path"`
+}
+
+// GetAPIServerStatus returns whether the local API is running and where it listens
+func (a *CoreService) GetAPIServerStatus() *APIServerStatus {
+	a.state.mu.RLock()
+	defer a.state.mu.RUnlock()
+	return &APIServerStatus{
+		Running:  a.apiServer != nil,
+		Address:  a.apiServerAddress,
+		BasePath: "/" + apiserver.Version,
+	}
+}
+
+type APITokenOptions struct {
+	Name     string   `json:"name"`
+	VaultIDs []string `json:"vault_ids"`
+	// "read" or "write"
+	Access structs.APIAccess `json:"access"`
+	// RFC 3339 time after which the token stops working (empty for no expiry)
+	ExpiresAt string `json:"expires_at"`
+}
+
+type CreatedAPIToken struct {
+	*structs.APIToken
+	// The bearer token. It is only available now, as only a hash of it is stored.
+	Token string `json:"token"`
+}
+
+// CreateAPIToken creates a token for the local API limited to the given vaults and access
+func (a *CoreService) CreateAPIToken(opts APITokenOptions) (*CreatedAPIToken, error) {
+	a.state.mu.Lock()
+	defer a.state.mu.Unlock()
+	if a.isLocked() {
+		return nil, fmt.Errorf("application not unlocked")
+	}
+	var expiresAt time.Time
+	if opts.ExpiresAt != "" {
+		var err error
+		if expiresAt, err = time.Parse(time.RFC3339, opts.ExpiresAt); err != nil {
+			return nil, fmt.Errorf("invalid expiry %q: %w", opts.ExpiresAt, err)
+		}
+		if !expiresAt.After(time.Now()) {
+			return nil, fmt.Errorf("expiry must be in the future")
+		}
+	}
+	vaultIds := slices.Compact(slices.Sorted(slices.Values(opts.VaultIDs)))
+	for _, vaultId := range vaultIds {
+		vault, ok := a.state.Vaults[vaultId]
+		if !ok || vault.IsTrashed() {
+			return nil, fmt.Errorf("vault %s not found", vaultId)
+		}
+		// Tokens may only grant access to vaults of accounts the user has unlocked
+		if _, ok := a.state.AUK[vault.AccountID]; !ok {
+			return nil, fmt.Errorf("account %q is locked", vault.AccountID)
+		}
+	}
+	token, secret, err := structs.NewAPIToken(strings.TrimSpace(opts.Name), vaultIds, opts.Access, expiresAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate api token: %w", err)
+	}
+	if err := token.Validate(); err != nil {
+		return nil, err
+	}
+	a.state.APITokens[token.TokenID] = token
+	if err := fs.SaveAPITokens(a.state.APITokens); err != nil {
+		delete(a.state.APITokens, token.TokenID)
+		return nil, fmt.Errorf("failed to save api tokens: %w", err)
+	}
+	logrus.Printf("created api token %s with %s access to %d vaults", token.TokenID, token.Access, len(vaultIds))
+	return &CreatedAPIToken{APIToken: token, Token: secret}, nil
+}
+
+// ListAPITokens returns every API token, including expired and revoked tokens, newest first
+func (a *CoreService) ListAPITokens() []*structs.APIToken {
+	a.state.mu.RLock()
+	defer a.state.mu.RUnlock()
+	tokens := make([]*structs.APIToken, 0, len(a.state.APITokens))
+	for _, token := range a.state.APITokens {
+		tokens = append(tokens, token)
+	}
+	slices.SortFunc(tokens, func(x, y *structs.APIToken) int {
+		if c := strings.Compare(y.CreatedAt, x.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(x.TokenID, y.TokenID)
+	})
+	return tokens
+}
+
+// RevokeAPIToken stops a token from working. Revoking does not need the app to be unlocked, so a leaked token
+// can be shut off straight away.
+func (a *CoreService) RevokeAPIToken(tokenId string) error {
+	a.state.mu.Lock()
+	defer a.state.mu.Unlock()
+	token, ok := a.state.APITokens[tokenId]
+	if !ok {
+		return fmt.Errorf("api token %s not found", tokenId)
+	}
+	if token.IsRevoked() {
+		return nil
+	}
+	token.RevokedAt = time.Now().Format(time.RFC3339)
+	// The token stays revoked in memory even if saving fails
+	if err := fs.SaveAPITokens(a.state.APITokens); err != nil {
+		return fmt.Errorf("failed to save api tokens: %w", err)
+	}
+	logrus.Printf("revoked api token %s", tokenId)
+	return nil
+}
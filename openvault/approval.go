@@ -0,0 +1,33 @@
+package main
+
+import (
+	"time"
+
+	"github.com/wailsapp/wails/v3/pkg/application"
+)
+
+// How long an approval prompt waits before the request is denied
+const approvalTimeout = 60 * time.Second
+
+// askApproval asks the user to allow a request made by another application. The request is denied if the prompt
+// is dismissed, not answered in time or cancel is closed first. It must not be called while holding the state
+// lock, as the app keeps running while the prompt is open.
+func askApproval(title string, message string, cancel <-chan struct{}) bool {
+	result := make(chan bool, 1)
+	dialog := application.QuestionDialog().
+		SetTitle(title).
+		SetMessage(message)
+	dialog.AddButton("Allow").OnClick(func() { result <- true })
+	deny := dialog.AddButton("Deny").OnClick(func() { result <- false })
+	dialog.SetDefaultButton(deny)
+	dialog.SetCancelButton(deny)
+	dialog.Show()
+	select {
+	case approved := <-result:
+		return approved
+	case <-time.After(approvalTimeout):
+		return false
+	case <-cancel:
+		return false
+	}
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"os"
+	"path/filepath"
+
+	"github.com/BradHacker/openvault/openvault/internal/fs"
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+
+	"github.com/BradHacker/openvault/cryptolib"
+)
+
+type DecryptedAttachment struct {
+	*structs.EncryptedAttachment
+	*structs.AttachmentMetadata
+}
+
+// lookupAttachment returns an attachment record and the overview of the item it belongs to
+func (a *CoreService) lookupAttachment(attachmentId string) (*structs.EncryptedAttachment, *structs.EncryptedVaultItemOverview, error) {
+	attachment, ok := a.state.Attachments[attachmentId]
+	if !ok {
+		return nil, nil, fmt.Errorf("attachment %s not found", attachmentId)
+	}
+	encOverview, ok := a.state.ItemOverviews[attachment.ItemID]
+	if !ok {
+		return nil, nil, fmt.Errorf("no item overview found for item %s", attachment.ItemID)
+	}
+	return attachment, encOverview, nil
+}
+
+// AddAttachment encrypts the file at sourcePath and attaches it to an item. The file is streamed
+// through the cipher, so large files are never held in memory, and the state is only locked to look up
+// the vault key and to register the encrypted file.
+func (a *CoreService) AddAttachment(itemId string, sourcePath string) (*DecryptedAttachment, error) {
+	vaultId, vaultKey, err := a.attachmentVaultKey(itemId)
+	if err != nil {
+		return nil, err
+	}
+	defer vaultKey.Close()
+	src, err := os.Open(sourcePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open attachment source: %w", err)
+	}
+	defer src.Close()
+	decAttachment, err := newAttachment(vaultKey, itemId, vaultId, filepath.Base(sourcePath), src)
+	if err != nil {
+		return nil, err
+	}
+
+	a.state.mu.Lock()
+	defer a.state.mu.Unlock()
+	// The app may have been locked, or the item deleted or moved to another vault, while the file was encrypted
+	if a.isLocked() {
+		fs.RemoveAttachmentBlob(decAttachment.AttachmentID)
+		return nil, fmt.Errorf("application not unlocked")
+	}
+	if encOverview, ok := a.state.ItemOverviews[itemId]; !ok || encOverview.VaultID != vaultId {
+		fs.RemoveAttachmentBlob(decAttachment.AttachmentID)
+		return nil, fmt.Errorf("no item overview found for item %s in vault %s", itemId, vaultId)
+	}
+	a.state.Attachments[decAttachment.AttachmentID] = decAttachment.EncryptedAttachment
+	if err := a.state.SaveAttachments(); err != nil {
+		// Drop the attachment again so neither the store nor the blob directory keeps a file which was never saved
+		delete(a.state.Attachments, decAttachment.AttachmentID)
+		fs.RemoveAttachmentBlob(decAttachment.AttachmentID)
+		return nil, err
+	}
+	return decAttachment, nil
+}
+
+// attachmentVaultKey returns the vault of an item and its key, which the caller must close
+func (a *CoreService) attachmentVaultKey(itemId string) (string, *cryptolib.JWK, error) {
+	a.state.mu.RLock()
+	defer a.state.mu.RUnlock()
+	if a.isLocked() {
+		return "", nil, fmt.Errorf("application not unlocked")
+	}
+	encOverview, ok := a.state.ItemOverviews[itemId]
+	if !ok {
+		return "", nil, fmt.Errorf("no item overview found for item %s", itemId)
+	}
+	vaultKey, err := a.state.VaultKey(encOverview.VaultID)
+	if err != nil {
+		return "", nil, err
+	}
+	return encOverview.VaultID, vaultKey, nil
+}
+
+// addAttachment encrypts src into a new attachment of an item and adds it to the in-memory store.
+// SaveAttachments must be called to persist the new attachment.
+func (a *CoreService) addAttachment(vaultKey *cryptolib.JWK, encOverview *structs.EncryptedVaultItemOverview, name string, src io.Reader) (*DecryptedAttachment, error) {
+	decAttachment, err := newAttachment(vaultKey, encOverview.ItemID, encOverview.VaultID, name, src)
+	if err != nil {
+		return nil, err
+	}
+	a.state.Attachments[decAttachment.AttachmentID] = decAttachment.EncryptedAttachment
+	return decAttachment, nil
+}
+
+// newAttachment encrypts src into the blob of a new attachment of an item. The attachment is not added to the
+// store, so no state is needed.
+func newAttachment(vaultKey *cryptolib.JWK, itemId string, vaultId string, name string, src io.Reader) (*DecryptedAttachment, error) {
+	attachment, fileKey, err := structs.NewAttachment(vaultKey, itemId, vaultId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate file key: %w", err)
+	}
+	defer fileKey.Close()
+	blob, err := fs.CreateAttachmentBlob(attachment.AttachmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create attachment blob: %w", err)
+	}
+	size, err := encryptAttachment(fileKey, blob, src)
+	if err != nil {
+		fs.RemoveAttachmentBlob(attachment.AttachmentID)
+		return nil, fmt.Errorf("failed to encrypt attachment: %w", err)
+	}
+
+	metadata := &structs.AttachmentMetadata{
+		Name:        name,
+		ContentType: mime.TypeByExtension(filepath.Ext(name)),
+		Size:        size,
+	}
+	if metadata.ContentType == "" {
+		metadata.ContentType = "application/octet-stream"
+	}
+	if err := attachment.Update(vaultKey, metadata); err != nil {
+		fs.RemoveAttachmentBlob(attachment.AttachmentID)
+		return nil, fmt.Errorf("failed to encrypt attachment metadata: %w", err)
+	}
+	return &DecryptedAttachment{
+		EncryptedAttachment: attachment,
+		AttachmentMetadata:  metadata,
+	}, nil
+}
+
+// encryptAttachment encrypts src with the file key into blob and closes blob. It returns the number of
+// plaintext bytes written.
+func encryptAttachment(fileKey *cryptolib.JWK, blob *os.File, src io.Reader) (int64, error) {
+	w, err := fileKey.NewEncryptWriter(blob)
+	if err != nil {
+		blob.Close()
+		return 0, err
+	}
+	size, err := io.Copy(w, src)
+	if err == nil {
+		err = w.Close()
+	}
+	if closeErr := blob.Close(); err == nil {
+		err = closeErr
+	}
+	return size, err
+}
+
+// ListAttachments returns the decrypted metadata of the attachments of an item
+func (a *CoreService) ListAttachments(itemId string) ([]*DecryptedAttachment, error) {
+	a.state.mu.RLock()
+	defer a.state.mu.RUnlock()
+	if a.isLocked() {
+		return nil, fmt.Errorf("application not unlocked")
+	}
+	encOverview, ok := a.state.ItemOverviews[itemId]
+	if !ok {
+		return nil, fmt.Errorf("no item overview found for item %s", itemId)
+	}
+	vaultKey, err := a.state.VaultKey(encOverview.VaultID)
+	if err != nil {
+		return nil, err
+	}
+	defer vaultKey.Close()
+	attachments := make([]*DecryptedAttachment, 0)
+	for _, attachment := range a.state.ItemAttachments(itemId) {
+		metadata, err := attachment.Read(vaultKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt metadata for attachment %s: %w", attachment.AttachmentID, err)
+		}
+		attachments = append(attachments, &DecryptedAttachment{
+			EncryptedAttachment: attachment,
+			AttachmentMetadata:  metadata,
+		})
+	}
+	return attachments, nil
+}
+
+// DownloadAttachment decrypts an attachment to destPath. Nothing is left at destPath if the blob
+// fails authentication.
+func (a *CoreService) DownloadAttachment(attachmentId string, destPath string) error {
+	a.state.mu.RLock()
+	defer a.state.mu.RUnlock()
+	if a.isLocked() {
+		return fmt.Errorf("application not unlocked")
+	}
+	attachment, encOverview, err := a.lookupAttachment(attachmentId)
+	if err != nil {
+		return err
+	}
+	vaultKey, err := a.state.VaultKey(encOverview.VaultID)
+	if err != nil {
+		return err
+	}
+	defer vaultKey.Close()
+	fileKey, err := attachment.FileKey(vaultKey)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt file key for attachment %s: %w", attachmentId, err)
+	}
+	defer fileKey.Close()
+
+	blob, err := fs.OpenAttachmentBlob(attachmentId)
+	if err != nil {
+		return fmt.Errorf("failed to open attachment blob: %w", err)
+	}
+	defer blob.Close()
+	r, err := fileKey.NewDecryptReader(blob)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt attachment %s: %w", attachmentId, err)
+	}
+	// Write to a temporary file first so a corrupt blob never leaves partial plaintext at destPath
+	tmp, err := os.CreateTemp(filepath.Dir(destPath), ".openvault-download-*")
+	if err != nil {
+		return fmt.Errorf("failed to create download file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to decrypt attachment %s: %w", attachmentId, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write download file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), destPath); err != nil {
+		return fmt.Errorf("failed to write download file: %w", err)
+	}
+	return nil
+}
+
+// RemoveAttachment permanently deletes an attachment and its encrypted blob
+func (a *CoreService) RemoveAttachment(attachmentId string) error {
+	a.state.mu.Lock()
+	defer a.state.mu.Unlock()
+	if a.isLocked() {
+		return fmt.Errorf("application not unlocked")
+	}
+	if _, ok := a.state.Attachments[attachmentId]; !ok {
+		return fmt.Errorf("attachment %s not found", attachmentId)
+	}
+	delete(a.state.Attachments, attachmentId)
+	if err := a.state.SaveAttachments(); err != nil {
+		return err
+	}
+	if err := fs.RemoveAttachmentBlob(attachmentId); err != nil {
+		return fmt.Errorf("failed to remove attachment blob: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/BradHacker/openvault/openvault/internal/breach"
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+
+	"github.com/sirupsen/logrus"
+)
+
+// breachResult is the outcome of checking an item's password against the Pwned Passwords list
+type breachResult struct {
+	// UpdatedAt of the item details which were checked. The result no longer applies once the item changes.
+	updatedAt string
+	count     int
+}
+
+type BreachedPassword struct {
+	HealthItem
+	// Number of times the password was seen in breaches
+	Count int `json:"count"`
+}
+
+type BreachReport struct {
+	// Number of logins with a password which were checked
+	Checked  int                 `json:"checked"`
+	Breached []*BreachedPassword `json:"breached"`
+}
+
+// breachSource opens the configured local copy of the Pwned Passwords list, or the range API if online checks
+// are enabled
+func (a *CoreService) breachSource() (breach.Source, error) {
+	settings := a.state.Settings
+	if settings.PwnedPasswordsPath != "" {
+		return breach.Open(settings.PwnedPasswordsPath)
+	}
+	if settings.PwnedPasswordsOnline {
+		return breach.NewRangeClient(breach.DefaultRangeAPI), nil
+	}
+	return nil, fmt.Errorf("no pwned passwords data configured")
+}
+
+// breachCheck is a login password waiting to be looked up in the Pwned Passwords list
+type breachCheck struct {
+	item      HealthItem
+	updatedAt string
+	hash      breach.Hash
+}
+
+// CheckBreachedPasswords looks up the password of every login in the unlocked vaults in the Pwned Passwords list.
+// Breached items are flagged with structs.ItemFlagBreached until they are changed or the app is locked. The
+// lookups, which may go over the network, run without the state lock held.
+func (a *CoreService) CheckBreachedPasswords() (*BreachReport, error) {
+	checks, source, err := a.breachChecks()
+	if err != nil {
+		return nil, err
+	}
+	defer source.Close()
+
+	report := &BreachReport{Breached: make([]*BreachedPassword, 0)}
+	results := make(map[string]*breachResult)
+	for _, check := range checks {
+		count, err := source.Count(check.hash)
+		if err != nil {
+			return nil, err
+		}
+		report.Checked++
+		results[check.item.ItemID] = &breachResult{updatedAt: check.updatedAt, count: count}
+		if count > 0 {
+			report.Breached = append(report.Breached, &BreachedPassword{HealthItem: check.item, Count: count})
+		}
+	}
+	a.state.mu.Lock()
+	// The app may have been locked during the lookups, which clears the results
+	if a.isLocked() {
+		a.state.mu.Unlock()
+		return nil, fmt.Errorf("application not unlocked")
+	}
+	// Only replace the previous results once every item has been checked. Items changed in the meantime are not
+	// flagged, as their UpdatedAt no longer matches.
+	a.state.BreachedItems = results
+	a.state.mu.Unlock()
+	slices.SortFunc(report.Breached, func(x, y *BreachedPassword) int {
+		if x.Count != y.Count {
+			return y.Count - x.Count
+		}
+		return compareHealthItems(&x.HealthItem, &y.HealthItem)
+	})
+	logrus.Printf("checked %d passwords for breaches, %d breached", report.Checked, len(report.Breached))
+	return report, nil
+}
+
+// breachChecks hashes the password of every login in the unlocked vaults and opens the breach source to look them
+// up in
+func (a *CoreService) breachChecks() ([]*breachCheck, breach.Source, error) {
+	a.state.mu.RLock()
+	defer a.state.mu.RUnlock()
+	if a.isLocked() {
+		return nil, nil, fmt.Errorf("application not unlocked")
+	}
+	var checks []*breachCheck
+	for _, overview := range a.unlockedItemOverviews() {
+		if overview.Category != structs.CategoryLogin {
+			continue
+		}
+		details, err := a.getVaultItemDetails(overview.ItemID, ItemDetailsOptions{})
+		if err != nil {
+			return nil, nil, err
+		}
+		if details.Password == "" {
+			continue
+		}
+		checks = append(checks, &breachCheck{
+			item:      HealthItem{ItemID: overview.ItemID, VaultID: overview.VaultID, Title: overview.Title},
+			updatedAt: details.UpdatedAt,
+			hash:      breach.HashPassword(details.Password),
+		})
+	}
+	source, err := a.breachSource()
+	if err != nil {
+		return nil, nil, err
+	}
+	return checks, source, nil
+}
+
+// itemFlags returns the flags of an item from the checks run since the app was unlocked
+func (a *CoreService) itemFlags(itemId string) []structs.ItemFlag {
+	var flags []structs.ItemFlag
+	result, ok := a.state.BreachedItems[itemId]
+	if !ok || result.count == 0 {
+		return flags
+	}
+	if encDetails, ok := a.state.ItemDetails[itemId]; ok && encDetails.UpdatedAt == result.updatedAt {
+		flags = append(flags, structs.ItemFlagBreached)
+	}
+	return flags
+}
@@ -0,0 +1,380 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/BradHacker/openvault/openvault/internal/constants"
+	"github.com/BradHacker/openvault/openvault/internal/fs"
+	"github.com/BradHacker/openvault/openvault/internal/nativemsg"
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	errBrowserNotPaired = errors.New("the extension is not paired with openvault")
+	errBrowserLocked    = errors.New("openvault is locked")
+	errBrowserDenied    = errors.New("the request was denied")
+)
+
+// Pairing codes are shown in the approval prompt, so they are kept short
+const (
+	browserPairingCodeMin = 6
+	browserPairingCodeMax = 32
+)
+
+// browserHello is the first message the native messaging host sends to the desktop app
+type browserHello struct {
+	// The extension which started the host, as reported by the browser
+	Extension string `json:"extension"`
+}
+
+// browserRequest is a message from the browser extension
+type browserRequest struct {
+	// Echoed in the response so the extension can match it to the request
+	ID   json.RawMessage `json:"id,omitempty"`
+	Type string          `json:"type"`
+	// Origin of the page being filled (logins and credentials)
+	Origin string `json:"origin,omitempty"`
+	// Login to fill (credentials)
+	ItemID string `json:"item_id,omitempty"`
+	// Code the extension displays while pairing (pair)
+	Code string `json:"code,omitempty"`
+	// Secret the extension was given when it was paired (every request except pair)
+	Secret string `json:"secret,omitempty"`
+}
+
+// browserLogin is a login offered for a page. Credentials are only sent after the user approves filling it.
+type browserLogin struct {
+	ItemID string              `json:"item_id"`
+	Title  string              `json:"title"`
+	URL    string              `json:"url"`
+	Match  structs.OriginMatch `json:"match"`
+}
+
+type browserResponse struct {
+	ID       json.RawMessage `json:"id,omitempty"`
+	Error    string          `json:"error,omitempty"`
+	Paired   bool            `json:"paired"`
+	Locked   bool            `json:"locked"`
+	Logins   []*browserLogin `json:"logins,omitempty"`
+	Username string          `json:"username,omitempty"`
+	Password string          `json:"password,omitempty"`
+	// Secret to send with later requests, given once when the extension is paired (pair)
+	Secret string `json:"secret,omitempty"`
+}
+
+// browserHost answers the requests browser extensions send through the native messaging host. Connections come
+// from runNativeMessagingHost, which relays the extension's messages over a local socket.
+type browserHost struct {
+	core     *CoreService
+	listener net.Listener
+
+	mu    sync.Mutex
+	conns map[net.Conn]struct{}
+	// Closed when the browser host stops, which denies requests waiting for approval
+	done chan struct{}
+}
+
+func (bh *browserHost) serve() {
+	for {
+		conn, err := bh.listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				logrus.Errorf("browser host failed to accept connection: %v", err)
+			}
+			return
+		}
+		bh.mu.Lock()
+		bh.conns[conn] = struct{}{}
+		bh.mu.Unlock()
+		go func() {
+			defer func() {
+				bh.mu.Lock()
+				delete(bh.conns, conn)
+				bh.mu.Unlock()
+				conn.Close()
+			}()
+			if err := bh.serveConn(conn); err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				logrus.Debugf("browser host connection closed: %v", err)
+			}
+		}()
+	}
+}
+
+func (bh *browserHost) serveConn(conn net.Conn) error {
+	var hello browserHello
+	if err := nativemsg.ReadMessage(conn, &hello); err != nil {
+		return err
+	}
+	if hello.Extension == "" {
+		return fmt.Errorf("native messaging host did not identify the extension")
+	}
+	for {
+		var req browserRequest
+		if err := nativemsg.ReadMessage(conn, &req); err != nil {
+			return err
+		}
+		resp := bh.handle(hello.Extension, &req)
+		resp.ID = req.ID
+		if err := nativemsg.WriteMessage(conn, resp); err != nil {
+			return err
+		}
+	}
+}
+
+// handle answers a request. Each request type takes the state lock itself, as requests needing approval release
+// it while the prompt is open.
+func (bh *browserHost) handle(extension string, req *browserRequest) *browserResponse {
+	resp := &browserResponse{}
+	var err error
+	switch req.Type {
+	case "status":
+	case "pair":
+		resp.Secret, err = bh.pair(extension, req.Secret, req.Code)
+	case "logins":
+		resp.Logins, err = bh.logins(extension, req.Secret, req.Origin)
+	case "credentials":
+		resp.Username, resp.Password, err = bh.credentials(extension, req.Secret, req.Origin, req.ItemID)
+	default:
+		err = fmt.Errorf("unknown request type %q", req.Type)
+	}
+	if err != nil {
+		resp.Error = err.Error()
+	}
+	bh.core.state.mu.RLock()
+	defer bh.core.state.mu.RUnlock()
+	resp.Paired = resp.Secret != "" || bh.core.isPairedExtension(extension, req.Secret)
+	resp.Locked = bh.core.isLocked()
+	return resp
+}
+
+// pair pairs the extension once the user approves it and returns the secret it must send with later requests.
+// The prompt shows the code the extension displays, so the user can tell the request comes from their browser.
+// Pairing again, e.g. after the extension lost its secret, replaces the previous secret.
+func (bh *browserHost) pair(extension string, secret string, code string) (string, error) {
+	bh.core.state.mu.RLock()
+	paired := bh.core.isPairedExtension(extension, secret)
+	bh.core.state.mu.RUnlock()
+	if paired {
+		return "", nil
+	}
+	if len(code) < browserPairingCodeMin || len(code) > browserPairingCodeMax {
+		return "", fmt.Errorf("pairing code must be %d to %d characters", browserPairingCodeMin, browserPairingCodeMax)
+	}
+	if !askApproval("Browser Extension Pairing",
+		fmt.Sprintf("Allow the browser extension %s to request logins? Only allow it if the extension shows the code %s.", extension, code),
+		bh.done) {
+		return "", errBrowserDenied
+	}
+	bh.core.state.mu.Lock()
+	defer bh.core.state.mu.Unlock()
+	if bh.core.browserHost != bh {
+		return "", errBrowserDenied
+	}
+	return bh.core.pairExtension(extension)
+}
+
+// checkRequest returns the origin of a request from a paired extension while the vaults are unlocked
+func (bh *browserHost) checkRequest(extension string, secret string, origin string) (*url.URL, error) {
+	if !bh.core.isPairedExtension(extension, secret) {
+		return nil, errBrowserNotPaired
+	}
+	if bh.core.isLocked() {
+		return nil, errBrowserLocked
+	}
+	u, err := url.Parse(origin)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Hostname() == "" {
+		return nil, fmt.Errorf("invalid origin %q", origin)
+	}
+	return u, nil
+}
+
+// logins returns the logins matching an origin, best match first
+func (bh *browserHost) logins(extension string, secret string, origin string) ([]*browserLogin, error) {
+	bh.core.state.mu.RLock()
+	defer bh.core.state.mu.RUnlock()
+	u, err := bh.checkRequest(extension, secret, origin)
+	if err != nil {
+		return nil, err
+	}
+	logins := make([]*browserLogin, 0)
+	for _, overview := range bh.core.unlockedItemOverviews() {
+		if overview.Category != structs.CategoryLogin {
+			continue
+		}
+		if match := structs.MatchOrigin(overview.URL, u); match != structs.OriginMatchNone {
+			logins = append(logins, &browserLogin{
+				ItemID: overview.ItemID,
+				Title:  overview.Title,
+				URL:    overview.URL,
+				Match:  match,
+			})
+		}
+	}
+	slices.SortFunc(logins, func(x, y *browserLogin) int {
+		if x.Match != y.Match {
+			return int(y.Match - x.Match)
+		}
+		return cmp.Compare(x.Title, y.Title)
+	})
+	return logins, nil
+}
+
+// credentials returns the username and password of a login matching the origin once the user approves filling it
+func (bh *browserHost) credentials(extension string, secret string, origin string, itemId string) (string, string, error) {
+	bh.core.state.mu.RLock()
+	login, u, err := bh.login(extension, secret, origin, itemId)
+	bh.core.state.mu.RUnlock()
+	if err != nil {
+		return "", "", err
+	}
+	if !askApproval("Browser Autofill", fmt.Sprintf("Fill the login %q into %s?", login.Title, u.Host), bh.done) {
+		return "", "", errBrowserDenied
+	}
+	bh.core.state.mu.RLock()
+	defer bh.core.state.mu.RUnlock()
+	// The host may have been stopped, the app locked or the login changed while the prompt was open
+	if bh.core.browserHost != bh {
+		return "", "", errBrowserDenied
+	}
+	if login, u, err = bh.login(extension, secret, origin, itemId); err != nil {
+		return "", "", err
+	}
+	details, err := bh.core.getVaultItemDetails(itemId, ItemDetailsOptions{RevealConcealed: true})
+	if err != nil {
+		return "", "", err
+	}
+	logrus.Printf("browser extension filled %s into %s", login.Title, u.Host)
+	return details.Username, details.Password, nil
+}
+
+// login returns a login matching the origin of a request from a paired extension. The caller holds the state lock.
+func (bh *browserHost) login(extension string, secret string, origin string, itemId string) (*DecryptedVaultItemOverview, *url.URL, error) {
+	u, err := bh.checkRequest(extension, secret, origin)
+	if err != nil {
+		return nil, nil, err
+	}
+	var login *DecryptedVaultItemOverview
+	for _, overview := range bh.core.unlockedItemOverviews() {
+		if overview.ItemID == itemId {
+			login = overview
+			break
+		}
+	}
+	if login == nil || login.Category != structs.CategoryLogin || structs.MatchOrigin(login.URL, u) == structs.OriginMatchNone {
+		return nil, nil, fmt.Errorf("no login %s found for %s", itemId, u.Host)
+	}
+	return login, u, nil
+}
+
+// stop closes the listener and every open connection, so no further requests are served
+func (bh *browserHost) stop() {
+	close(bh.done)
+	bh.listener.Close()
+	bh.mu.Lock()
+	defer bh.mu.Unlock()
+	for conn := range bh.conns {
+		conn.Close()
+	}
+	removeLocalSocket(constants.BROWSER_SOCKET)
+}
+
+// isPairedExtension returns whether the extension is paired and the secret is the one it was given
+func (a *CoreService) isPairedExtension(extension string, secret string) bool {
+	pairing, ok := a.state.BrowserPairings[extension]
+	return ok && pairing.Verify(secret)
+}
+
+// pairExtension pairs an extension with a new secret, replacing any previous pairing, and returns the secret. The
+// caller holds the state lock for writing.
+func (a *CoreService) pairExtension(extension string) (string, error) {
+	pairing, secret, err := structs.NewBrowserPairing(extension)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate pairing secret: %w", err)
+	}
+	previous, paired := a.state.BrowserPairings[extension]
+	a.state.BrowserPairings[extension] = pairing
+	if err := fs.SaveBrowserPairings(a.state.BrowserPairings); err != nil {
+		if paired {
+			a.state.BrowserPairings[extension] = previous
+		} else {
+			delete(a.state.BrowserPairings, extension)
+		}
+		return "", fmt.Errorf("failed to save browser pairings: %w", err)
+	}
+	logrus.Printf("paired browser extension %s", extension)
+	return secret, nil
+}
+
+// ListBrowserPairings returns the paired browser extensions, most recently paired first
+func (a *CoreService) ListBrowserPairings() []*structs.BrowserPairing {
+	a.state.mu.RLock()
+	defer a.state.mu.RUnlock()
+	pairings := slices.Collect(maps.Values(a.state.BrowserPairings))
+	slices.SortFunc(pairings, func(x, y *structs.BrowserPairing) int {
+		if c := strings.Compare(y.PairedAt, x.PairedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(x.Extension, y.Extension)
+	})
+	return pairings
+}
+
+// UnpairBrowserExtension stops answering a browser extension until it is paired again
+func (a *CoreService) UnpairBrowserExtension(extension string) error {
+	a.state.mu.Lock()
+	defer a.state.mu.Unlock()
+	pairing, ok := a.state.BrowserPairings[extension]
+	if !ok {
+		return fmt.Errorf("browser extension %s is not paired", extension)
+	}
+	delete(a.state.BrowserPairings, extension)
+	if err := fs.SaveBrowserPairings(a.state.BrowserPairings); err != nil {
+		a.state.BrowserPairings[extension] = pairing
+		return fmt.Errorf("failed to save browser pairings: %w", err)
+	}
+	logrus.Printf("unpaired browser extension %s", extension)
+	return nil
+}
+
+// startBrowserHost starts answering browser extensions if browser integration is enabled and it is not already
+// running. Like the SSH agent it is never started by command line modes.
+func (a *CoreService) startBrowserHost() error {
+	if a.headless || a.browserHost != nil || !a.state.Settings.BrowserIntegrationEnabled {
+		return nil
+	}
+	listener, err := listenLocalSocket(constants.BROWSER_SOCKET)
+	if err != nil {
+		return fmt.Errorf("failed to start browser integration: %w", err)
+	}
+	a.browserHost = &browserHost{
+		core:     a,
+		listener: listener,
+		conns:    make(map[net.Conn]struct{}),
+		done:     make(chan struct{}),
+	}
+	go a.browserHost.serve()
+	logrus.Printf("browser integration listening on %s", constants.BROWSER_SOCKET)
+	return nil
+}
+
+// stopBrowserHost stops answering browser extensions if the browser host is running
+func (a *CoreService) stopBrowserHost() {
+	if a.browserHost == nil {
+		return
+	}
+	a.browserHost.stop()
+	a.browserHost = nil
+	logrus.Printf("browser integration stopped")
+}
@@ -0,0 +1,32 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+)
+
+// ListItemCategories returns every supported item category
+func (a *CoreService) ListItemCategories() []structs.ItemCategory {
+	return structs.ItemCategories
+}
+
+// ListItemOverviewsByCategory returns the decrypted overviews of all non-trashed items in the given category
+func (a *CoreService) ListItemOverviewsByCategory(category structs.ItemCategory) ([]*DecryptedVaultItemOverview, error) {
+	a.state.mu.RLock()
+	defer a.state.mu.RUnlock()
+	if !category.IsValid() {
+		return nil, fmt.Errorf("%w: %q", structs.ErrInvalidCategory, category)
+	}
+	overviews, err := a.listAllItemOverviews(false, ItemFilter{})
+	if err != nil {
+		return nil, err
+	}
+	var filtered []*DecryptedVaultItemOverview
+	for _, ov := range overviews {
+		if ov.Category == category {
+			filtered = append(filtered, ov)
+		}
+	}
+	return filtered, nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BradHacker/openvault/openvault/internal/credhelper"
+)
+
+// cliCommand is a command line mode which runs instead of the desktop app
+type cliCommand struct {
+	// Whether the command uses the vaults. These commands are relayed to the running desktop app, which holds the
+	// unlocked vaults and asks the user to approve them (see credentialHelperHost).
+	relay bool
+	// Whether a relayed command reads its input from stdin, which is sent along with it
+	stdin bool
+	// Environment variables a relayed command reads, which are sent along with it
+	env []string
+	// Returns the approval prompt for a relayed command
+	describe func(args []string, stdin []byte) string
+	run      func(core *CoreService, args []string, getenv func(string) string, stdin io.Reader, stdout io.Writer) error
+}
+
+var cliCommands = map[string]*cliCommand{
+	"git-credential":    {relay: true, stdin: true, describe: credhelper.DescribeGit, run: runGitCredential},
+	"docker-credential": {relay: true, stdin: true, describe: credhelper.DescribeDocker, run: runDockerCredential},
+	// credential_process = openvault aws-credential-process <item>
+	"aws-credential-process": {relay: true, describe: credhelper.DescribeAWSCredentialProcess, run: runAWSCredentialProcess},
+	// users[].user.exec.command = openvault, args = [kube-exec-credential, <item>]
+	"kube-exec-credential": {relay: true, env: []string{"KUBERNETES_EXEC_INFO"}, describe: credhelper.DescribeKubeExecCredential, run: runKubeExecCredential},
+	// Started by the browser; the desktop app unlocks the vaults and approves requests
+	"native-messaging-host": {run: runNativeMessagingHost},
+	"generate":              {run: runGenerate},
+}
+
+// cliAliases maps executable names to commands, so the binary can be linked as a helper which tools find by name
+// (e.g. git runs git-credential-openvault for credential.helper=openvault and docker runs
+// docker-credential-openvault for "credsStore": "openvault"). Browsers start native messaging hosts with their own
+// arguments, so the host is only selected by name.
+var cliAliases = map[string]string{
+	"git-credential-openvault":        "git-credential",
+	"docker-credential-openvault":     "docker-credential",
+	"openvault-native-messaging-host": "native-messaging-host",
+}
+
+// runCLI runs the command line mode selected by the executable name or first argument. It returns false if the
+// arguments do not select a command and the desktop app should start.
+func runCLI(argv []string) (exitCode int, handled bool) {
+	name := strings.TrimSuffix(filepath.Base(argv[0]), filepath.Ext(argv[0]))
+	args := argv[1:]
+	commandName := cliAliases[name]
+	command, ok := cliCommands[commandName]
+	if !ok {
+		if len(args) == 0 {
+			return 0, false
+		}
+		if command, ok = cliCommands[args[0]]; !ok {
+			return 0, false
+		}
+		name = args[0]
+		commandName = args[0]
+		args = args[1:]
+	}
+	// The protocols spoken over stdout must not be interleaved with log output, so anything printed while
+	// running the command is sent to stderr instead
+	stdout := os.Stdout
+	os.Stdout = os.Stderr
+	defer func() { os.Stdout = stdout }()
+
+	var err error
+	if command.relay {
+		err = relayCLI(commandName, command, args, os.Stdin, stdout)
+	} else {
+		core := NewCoreService()
+		core.headless = true
+		err = command.run(core, args, os.Getenv, os.Stdin, stdout)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "openvault %s: %v\n", name, err)
+		return 1, true
+	}
+	return 0, true
+}
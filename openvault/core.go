@@ -1,43 +1,109 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"slices"
+	"time"
 
+	"github.com/BradHacker/openvault/openvault/internal/apiserver"
 	"github.com/BradHacker/openvault/openvault/internal/fs"
+	"github.com/BradHacker/openvault/openvault/internal/secretservice"
 	"github.com/BradHacker/openvault/openvault/internal/structs"
 
 	"github.com/BradHacker/openvault/cryptolib"
+	"github.com/godbus/dbus/v5"
 	"github.com/sirupsen/logrus"
+	"github.com/wailsapp/wails/v3/pkg/application"
 )
 
 // CoreService struct
 type CoreService struct {
 	state *State
+	// The running SSH agent (nil when stopped)
+	sshAgent *sshAgent
+	// The running Secret Service and its session bus connection (nil when stopped)
+	secretService     *secretservice.Server
+	secretServiceConn *dbus.Conn
+	// Closed when the Secret Service stops, which denies requests waiting for approval
+	secretServiceDone chan struct{}
+	// The running browser integration (nil when stopped)
+	browserHost *browserHost
+	// The running credential helper relay (nil when stopped)
+	credentialHelperHost *credentialHelperHost
+	// The running local API and the socket path or address it listens on (nil when stopped)
+	apiServer        *apiserver.Server
+	apiServerAddress string
+	// Whether the service is running a command line mode rather than the desktop app
+	headless bool
 }
 
 // NewCoreService creates a new CoreService struct
 func NewCoreService() *CoreService {
 	core := &CoreService{
 		state: &State{
-			IsInitialized: false,
-			Accounts:      make(fs.AccountStore),
-			KeySets:       make(fs.KeySetStore),
-			Vaults:        make(fs.VaultStore),
-			ItemOverviews: make(fs.ItemOverviewsStore),
-			ItemDetails:   make(fs.ItemDetailsStore),
-			AUK:           make(map[string]*cryptolib.JWK),
+			IsInitialized:   false,
+			Accounts:        make(fs.AccountStore),
+			KeySets:         make(fs.KeySetStore),
+			Vaults:          make(fs.VaultStore),
+			ItemOverviews:   make(fs.ItemOverviewsStore),
+			ItemDetails:     make(fs.ItemDetailsStore),
+			AUK:             make(map[string]*cryptolib.JWK),
+			ImportedShares:  make(fs.ImportedSharesStore),
+			ItemHistory:     make(fs.ItemHistoryStore),
+			Settings:        structs.DefaultSettings(),
+			Attachments:     make(fs.AttachmentStore),
+			BreachedItems:   make(map[string]*breachResult),
+			APITokens:       make(fs.APITokenStore),
+			BrowserPairings: make(fs.BrowserPairingStore),
 		},
 	}
 	core.startup()
 	return core
 }
 
+// ServiceStartup starts the background services which run while the desktop app is open
+func (a *CoreService) ServiceStartup(ctx context.Context, options application.ServiceOptions) error {
+	a.state.mu.Lock()
+	defer a.state.mu.Unlock()
+	if err := a.startSecretService(); err != nil {
+		logrus.Errorf("%v", err)
+	}
+	if err := a.startBrowserHost(); err != nil {
+		logrus.Errorf("%v", err)
+	}
+	if err := a.startCredentialHelperHost(); err != nil {
+		logrus.Errorf("%v", err)
+	}
+	if err := a.startAPIServer(); err != nil {
+		logrus.Errorf("%v", err)
+	}
+	return nil
+}
+
+// ServiceShutdown stops the background services when the desktop app exits
+func (a *CoreService) ServiceShutdown() error {
+	a.state.mu.Lock()
+	defer a.state.mu.Unlock()
+	a.stopSecretService()
+	a.stopBrowserHost()
+	a.stopCredentialHelperHost()
+	a.stopAPIServer()
+	a.stopSSHAgent()
+	return nil
+}
+
 func (a *CoreService) startup() {
+	// Load settings
+	settings, err := fs.LoadSettings()
+	if err != nil {
+		fmt.Println("Error loading settings:", err)
+	} else {
+		a.state.Settings = settings
+	}
 	// Check if the application is initialized
 	a.state.IsInitialized = fs.IsInitialized()
 	if a.state.IsInitialized {
-		var err error
 		// Load accounts
 		a.state.Accounts, err = fs.LoadAccounts()
 		if err != nil {
@@ -68,11 +134,50 @@ func (a *CoreService) startup() {
 			fmt.Println("Error loading item details:", err)
 			return
 		}
+		// Load imported share records
+		a.state.ImportedShares, err = fs.LoadImportedShares()
+		if err != nil {
+			fmt.Println("Error loading imported shares:", err)
+			return
+		}
+		// Load item history
+		a.state.ItemHistory, err = fs.LoadItemHistory()
+		if err != nil {
+			fmt.Println("Error loading item history:", err)
+			return
+		}
+		// Load attachment records
+		a.state.Attachments, err = fs.LoadAttachments()
+		if err != nil {
+			fmt.Println("Error loading attachments:", err)
+			return
+		}
+		// Load local API tokens
+		a.state.APITokens, err = fs.LoadAPITokens()
+		if err != nil {
+			fmt.Println("Error loading api tokens:", err)
+			return
+		}
+		// Load paired browser extensions
+		a.state.BrowserPairings, err = fs.LoadBrowserPairings()
+		if err != nil {
+			fmt.Println("Error loading browser pairings:", err)
+			return
+		}
+		// Purge anything which has been in the trash longer than the retention period
+		if a.state.PurgeExpiredTrash(time.Now()) {
+			if err := a.state.SaveAll(); err != nil {
+				fmt.Println("Error purging trash:", err)
+				return
+			}
+		}
 	}
 }
 
 // IsInitialized returns whether the application has been initialized
 func (a *CoreService) IsInitialized() bool {
+	a.state.mu.Lock()
+	defer a.state.mu.Unlock()
 	a.state.IsInitialized = fs.IsInitialized()
 	return a.state.IsInitialized
 }
@@ -80,6 +185,8 @@ func (a *CoreService) IsInitialized() bool {
 // Initialize initializes the application with the given options. If the application
 // is already initialized, it does nothing.
 func (a *CoreService) Initialize(opts fs.InitOptions) error {
+	a.state.mu.Lock()
+	defer a.state.mu.Unlock()
 	if a.state.IsInitialized {
 		return nil
 	}
@@ -93,31 +200,58 @@ func (a *CoreService) Initialize(opts fs.InitOptions) error {
 }
 
 func (a *CoreService) IsLocked() bool {
+	a.state.mu.RLock()
+	defer a.state.mu.RUnlock()
+	return a.isLocked()
+}
+
+func (a *CoreService) isLocked() bool {
 	return len(a.state.AUK) == 0
 }
 
 func (a *CoreService) Lock() error {
+	a.state.mu.Lock()
+	defer a.state.mu.Unlock()
+	return a.lock()
+}
+
+func (a *CoreService) lock() error {
+	// Stop serving keys before the account keys are cleared
+	a.stopSSHAgent()
 	for _, auk := range a.state.AUK {
 		auk.Close()
 	}
 	a.state.AUK = make(map[string]*cryptolib.JWK)
+	a.state.BreachedItems = make(map[string]*breachResult)
+	a.state.Search = nil
+	a.state.SecretServiceItems.clear()
+	a.notifySecretServiceLock()
 	return nil
 }
 
 func (a *CoreService) TryUnlock(password string) error {
+	a.state.mu.Lock()
+	defer a.state.mu.Unlock()
 	for _, account := range a.state.Accounts {
 		// Get the keyset for the account
 		keySet, ok := a.state.KeySets[account.ID]
 		if !ok {
 			return fmt.Errorf("no keyset found for account %s", account.ID)
 		}
-		fmt.Printf("%+v\n", keySet.EncSymKey)
 		// Try to unlock the account
 		auk, err := account.TryUnlock(password, keySet.EncSymKey)
 		if err == nil {
 			// If it unlocks, the AUK matches
 			a.state.AUK[account.ID] = auk
 			logrus.Printf("Successfully unlocked account %s", account.ID)
+			// Command line modes look items up directly and would not use the index
+			if !a.headless {
+				a.buildSearchIndex()
+			}
+			if err := a.startSSHAgent(); err != nil {
+				logrus.Errorf("%v", err)
+			}
+			a.notifySecretServiceLock()
 			return nil
 		}
 		logrus.Printf("Account %s did not unlock: %v", account.ID, err)
@@ -132,6 +266,8 @@ type AccountWithUnlockStatus struct {
 
 // GetAccounts returns the accounts for the application.
 func (a *CoreService) GetAccounts() ([]*AccountWithUnlockStatus, error) {
+	a.state.mu.RLock()
+	defer a.state.mu.RUnlock()
 	if !a.state.IsInitialized {
 		return nil, fmt.Errorf("application not initialized")
 	}
@@ -146,6 +282,8 @@ func (a *CoreService) GetAccounts() ([]*AccountWithUnlockStatus, error) {
 }
 
 func (a *CoreService) GetAccount(accountId string) (*AccountWithUnlockStatus, error) {
+	a.state.mu.RLock()
+	defer a.state.mu.RUnlock()
 	if !a.state.IsInitialized {
 		return nil, fmt.Errorf("application not initialized")
 	}
@@ -161,11 +299,16 @@ func (a *CoreService) GetAccount(accountId string) (*AccountWithUnlockStatus, er
 
 // GetVaultMetadatas returns the vault metadata for the given account IDs.
 func (a *CoreService) ListVaultMetadatas(accountIds []string) ([]*structs.VaultMetadata, error) {
-	if a.IsLocked() {
+	a.state.mu.RLock()
+	defer a.state.mu.RUnlock()
+	if a.isLocked() {
 		return nil, fmt.Errorf("application not unlocked")
 	}
 	vaultByAccount := make(map[string][]*structs.Vault)
 	for _, vault := range a.state.Vaults {
+		if vault.IsTrashed() {
+			continue
+		}
 		if vaultByAccount[vault.AccountID] == nil {
 			vaultByAccount[vault.AccountID] = []*structs.Vault{}
 		}
@@ -201,7 +344,13 @@ func (a *CoreService) ListVaultMetadatas(accountIds []string) ([]*structs.VaultM
 
 // GetVaultMetadata returns the vault metadata for the given vault ID.
 func (a *CoreService) GetVaultMetadata(vaultId string) (*structs.VaultMetadata, error) {
-	if a.IsLocked() {
+	a.state.mu.RLock()
+	defer a.state.mu.RUnlock()
+	return a.getVaultMetadata(vaultId)
+}
+
+func (a *CoreService) getVaultMetadata(vaultId string) (*structs.VaultMetadata, error) {
+	if a.isLocked() {
 		return nil, fmt.Errorf("application not unlocked")
 	}
 	keySet, auk, vault, err := a.state.LookupVaultCrypto(vaultId)
@@ -223,15 +372,24 @@ func (a *CoreService) GetVaultMetadata(vaultId string) (*structs.VaultMetadata,
 type DecryptedVaultItemOverview struct {
 	*structs.EncryptedVaultItemOverview
 	*structs.VaultItemOverview
+	Flags []structs.ItemFlag `json:"flags,omitempty"`
 }
 
-func (a *CoreService) ListVaultItemOverviews(vaultId string) ([]*DecryptedVaultItemOverview, error) {
-	if a.IsLocked() {
+// ListVaultItemOverviews returns the decrypted item overviews in the given vault. Trashed items are only
+// included if includeTrashed is set.
+func (a *CoreService) ListVaultItemOverviews(vaultId string, includeTrashed bool) ([]*DecryptedVaultItemOverview, error) {
+	a.state.mu.RLock()
+	defer a.state.mu.RUnlock()
+	return a.listVaultItemOverviews(vaultId, includeTrashed)
+}
+
+func (a *CoreService) listVaultItemOverviews(vaultId string, includeTrashed bool) ([]*DecryptedVaultItemOverview, error) {
+	if a.isLocked() {
 		return nil, fmt.Errorf("application not unlocked")
 	}
 	encItemOverviews := slices.Collect(func(yield func(*structs.EncryptedVaultItemOverview) bool) {
 		for _, encOverview := range a.state.ItemOverviews {
-			if encOverview.VaultID == vaultId {
+			if encOverview.VaultID == vaultId && (includeTrashed || !a.state.IsItemTrashed(encOverview)) {
 				if !yield(encOverview) {
 					break
 				}
@@ -257,13 +415,20 @@ func (a *CoreService) ListVaultItemOverviews(vaultId string) ([]*DecryptedVaultI
 		decryptedOverviews = append(decryptedOverviews, &DecryptedVaultItemOverview{
 			EncryptedVaultItemOverview: encItemOverviews[i],
 			VaultItemOverview:          ov,
+			Flags:                      a.itemFlags(encItemOverviews[i].ItemID),
 		})
 	}
 	return decryptedOverviews, nil
 }
 
 func (a *CoreService) GetItemOverview(itemId string) (*DecryptedVaultItemOverview, error) {
-	if a.IsLocked() {
+	a.state.mu.RLock()
+	defer a.state.mu.RUnlock()
+	return a.getItemOverview(itemId)
+}
+
+func (a *CoreService) getItemOverview(itemId string) (*DecryptedVaultItemOverview, error) {
+	if a.isLocked() {
 		return nil, fmt.Errorf("application not unlocked")
 	}
 	var encItemOverview *structs.EncryptedVaultItemOverview
@@ -294,16 +459,33 @@ func (a *CoreService) GetItemOverview(itemId string) (*DecryptedVaultItemOvervie
 	return &DecryptedVaultItemOverview{
 		EncryptedVaultItemOverview: encItemOverview,
 		VaultItemOverview:          overviews[0],
+		Flags:                      a.itemFlags(itemId),
 	}, nil
 }
 
-func (a *CoreService) ListAllItemOverviews() ([]*DecryptedVaultItemOverview, error) {
-	if a.IsLocked() {
+// ListAllItemOverviews returns the decrypted item overviews across all vaults which match the filter. Trashed
+// items are only included if includeTrashed is set.
+func (a *CoreService) ListAllItemOverviews(includeTrashed bool, filter ItemFilter) ([]*DecryptedVaultItemOverview, error) {
+	a.state.mu.RLock()
+	defer a.state.mu.RUnlock()
+	return a.listAllItemOverviews(includeTrashed, filter)
+}
+
+func (a *CoreService) listAllItemOverviews(includeTrashed bool, filter ItemFilter) ([]*DecryptedVaultItemOverview, error) {
+	if a.isLocked() {
 		return nil, fmt.Errorf("application not unlocked")
 	}
+	tag, err := structs.NormalizeTag(filter.Tag)
+	if err != nil {
+		return nil, err
+	}
+	filter.Tag = tag
 
 	encItemsByVault := make(map[string][]*structs.EncryptedVaultItemOverview)
 	for _, encOverview := range a.state.ItemOverviews {
+		if !includeTrashed && a.state.IsItemTrashed(encOverview) {
+			continue
+		}
 		if encItemsByVault[encOverview.VaultID] == nil {
 			encItemsByVault[encOverview.VaultID] = []*structs.EncryptedVaultItemOverview{}
 		}
@@ -326,9 +508,13 @@ func (a *CoreService) ListAllItemOverviews() ([]*DecryptedVaultItemOverview, err
 			return nil, fmt.Errorf("failed to decrypt item overviews for vault %s: %w", vaultId, err)
 		}
 		for i, ov := range overviews {
+			if !filter.Matches(ov) {
+				continue
+			}
 			decryptedOverviews = append(decryptedOverviews, &DecryptedVaultItemOverview{
 				EncryptedVaultItemOverview: encItemOverviews[i],
 				VaultItemOverview:          ov,
+				Flags:                      a.itemFlags(encItemOverviews[i].ItemID),
 			})
 		}
 	}
@@ -340,7 +526,33 @@ type DecryptedVaultItemDetails struct {
 	*structs.VaultItemDetails
 }
 
-func (a *CoreService) GetVaultItemDetails(itemId string) (*DecryptedVaultItemDetails, error) {
+type ItemDetailsOptions struct {
+	// Return the values of concealed custom fields and SSH private keys
+	RevealConcealed bool `json:"reveal_concealed"`
+	// Only return the custom field with this ID (including its value, even if concealed)
+	FieldID string `json:"field_id"`
+}
+
+// apply redacts or narrows decrypted details as requested. It returns nil if opts.FieldID is not a field of the item.
+func (opts ItemDetailsOptions) apply(details *structs.VaultItemDetails) *structs.VaultItemDetails {
+	switch {
+	case opts.FieldID != "":
+		return details.OnlyField(opts.FieldID)
+	case !opts.RevealConcealed:
+		return details.Redacted()
+	}
+	return details
+}
+
+// GetVaultItemDetails returns the decrypted details of an item. Concealed custom fields and SSH private keys are
+// redacted unless opts.RevealConcealed is set or the field is requested by opts.FieldID.
+func (a *CoreService) GetVaultItemDetails(itemId string, opts ItemDetailsOptions) (*DecryptedVaultItemDetails, error) {
+	a.state.mu.RLock()
+	defer a.state.mu.RUnlock()
+	return a.getVaultItemDetails(itemId, opts)
+}
+
+func (a *CoreService) getVaultItemDetails(itemId string, opts ItemDetailsOptions) (*DecryptedVaultItemDetails, error) {
 	// Get the encrypted details for the item
 	encItemDetails, ok := a.state.ItemDetails[itemId]
 	if !ok {
@@ -360,6 +572,10 @@ func (a *CoreService) GetVaultItemDetails(itemId string) (*DecryptedVaultItemDet
 	if err != nil {
 		return nil, fmt.Errorf("failed to decrypt item details for item %s: %w", itemId, err)
 	}
+	details = opts.apply(details)
+	if details == nil {
+		return nil, fmt.Errorf("no field %s found for item %s", opts.FieldID, itemId)
+	}
 	return &DecryptedVaultItemDetails{
 		EncryptedVaultItemDetails: encItemDetails,
 		VaultItemDetails:          details,
@@ -0,0 +1,208 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"slices"
+
+	"github.com/BradHacker/openvault/openvault/internal/credhelper"
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+
+	"github.com/BradHacker/openvault/cryptolib"
+	"github.com/sirupsen/logrus"
+)
+
+// unlockedItemOverviews returns the decrypted overviews of every non-trashed item in the vaults of unlocked
+// accounts. Unlike ListAllItemOverviews, vaults of locked accounts are skipped rather than being an error.
+func (a *CoreService) unlockedItemOverviews() []*DecryptedVaultItemOverview {
+	vaultKeys := make(map[string]*cryptolib.JWK)
+	defer func() {
+		for _, vaultKey := range vaultKeys {
+			vaultKey.Close()
+		}
+	}()
+	overviews := make([]*DecryptedVaultItemOverview, 0)
+	for itemId, encOverview := range a.state.ItemOverviews {
+		if a.state.IsItemTrashed(encOverview) {
+			continue
+		}
+		vaultKey, ok := vaultKeys[encOverview.VaultID]
+		if !ok {
+			var err error
+			if vaultKey, err = a.state.VaultKey(encOverview.VaultID); err != nil {
+				continue
+			}
+			vaultKeys[encOverview.VaultID] = vaultKey
+		}
+		overview, err := encOverview.Read(vaultKey)
+		if err != nil {
+			logrus.Errorf("failed to decrypt item overview for item %s: %v", itemId, err)
+			continue
+		}
+		overviews = append(overviews, &DecryptedVaultItemOverview{
+			EncryptedVaultItemOverview: encOverview,
+			VaultItemOverview:          overview,
+		})
+	}
+	return overviews
+}
+
+// credentialHelperBackend gives the credential helpers the logins of the unlocked vaults. The caller holds the
+// state lock for writing.
+type credentialHelperBackend struct {
+	core *CoreService
+}
+
+func (b *credentialHelperBackend) FindLogins(target *url.URL, username string) ([]*credhelper.Login, error) {
+	return b.core.findLogins(target, username)
+}
+
+func (b *credentialHelperBackend) VaultLogins(vaultId string) ([]*credhelper.Login, error) {
+	var logins []*credhelper.Login
+	for _, overview := range b.core.unlockedItemOverviews() {
+		if overview.VaultID != vaultId || overview.Category != structs.CategoryLogin {
+			continue
+		}
+		details, err := b.core.getVaultItemDetails(overview.ItemID, ItemDetailsOptions{})
+		if err != nil {
+			return nil, err
+		}
+		logins = append(logins, &credhelper.Login{
+			ItemID:   overview.ItemID,
+			VaultID:  overview.VaultID,
+			Overview: overview.VaultItemOverview,
+			Details:  details.VaultItemDetails,
+		})
+	}
+	return logins, nil
+}
+
+func (b *credentialHelperBackend) ItemDetails(ref string) (*structs.VaultItemDetails, error) {
+	overview, err := b.core.resolveItem(ref)
+	if err != nil {
+		return nil, err
+	}
+	details, err := b.core.getVaultItemDetails(overview.ItemID, ItemDetailsOptions{RevealConcealed: true})
+	if err != nil {
+		return nil, err
+	}
+	return details.VaultItemDetails, nil
+}
+
+func (b *credentialHelperBackend) DefaultVault() (string, error) {
+	return b.core.credentialHelperVault()
+}
+
+func (b *credentialHelperBackend) CreateItem(vaultId string, overview *structs.VaultItemOverview, details *structs.VaultItemDetails) error {
+	_, err := b.core.createItem(vaultId, *overview, *details)
+	return err
+}
+
+func (b *credentialHelperBackend) UpdateItem(itemId string, overview *structs.VaultItemOverview, details *structs.VaultItemDetails) error {
+	_, err := b.core.updateItem(itemId, *overview, *details)
+	return err
+}
+
+func (b *credentialHelperBackend) TrashItem(itemId string) error {
+	return b.core.deleteItem(itemId)
+}
+
+// runGitCredential implements the get, store and erase actions of a git credential helper
+func runGitCredential(core *CoreService, args []string, getenv func(string) string, stdin io.Reader, stdout io.Writer) error {
+	return credhelper.Git(&credentialHelperBackend{core: core}, args, stdin, stdout)
+}
+
+// runDockerCredential implements the get, store, erase and list actions of a docker credential helper
+func runDockerCredential(core *CoreService, args []string, getenv func(string) string, stdin io.Reader, stdout io.Writer) error {
+	return credhelper.Docker(&credentialHelperBackend{core: core}, args, stdin, stdout)
+}
+
+// runAWSCredentialProcess writes the credentials of an item in the AWS credential_process format
+func runAWSCredentialProcess(core *CoreService, args []string, getenv func(string) string, stdin io.Reader, stdout io.Writer) error {
+	return credhelper.AWSCredentialProcess(&credentialHelperBackend{core: core}, args, stdout)
+}
+
+// runKubeExecCredential writes the credentials of an item as a kubectl ExecCredential
+func runKubeExecCredential(core *CoreService, args []string, getenv func(string) string, stdin io.Reader, stdout io.Writer) error {
+	return credhelper.KubeExecCredential(&credentialHelperBackend{core: core}, args, getenv, stdout)
+}
+
+// findLogins returns the login items whose URL matches target, most specific first. If username is set only
+// logins with that username are returned.
+func (a *CoreService) findLogins(target *url.URL, username string) ([]*credhelper.Login, error) {
+	var matches []*credhelper.Login
+	scores := make(map[*credhelper.Login]int)
+	for _, overview := range a.unlockedItemOverviews() {
+		if overview.Category != structs.CategoryLogin {
+			continue
+		}
+		score := structs.MatchURL(overview.URL, target)
+		if score == 0 {
+			continue
+		}
+		details, err := a.getVaultItemDetails(overview.ItemID, ItemDetailsOptions{RevealConcealed: true})
+		if err != nil {
+			return nil, err
+		}
+		if username != "" && details.Username != username {
+			continue
+		}
+		match := &credhelper.Login{
+			ItemID:   overview.ItemID,
+			VaultID:  overview.VaultID,
+			Overview: overview.VaultItemOverview,
+			Details:  details.VaultItemDetails,
+		}
+		matches = append(matches, match)
+		scores[match] = score
+	}
+	slices.SortStableFunc(matches, func(x, y *credhelper.Login) int {
+		return scores[y] - scores[x]
+	})
+	return matches, nil
+}
+
+// credentialHelperVault returns the vault credential helpers store new logins in. This is the vault configured
+// in the settings or, if none is configured, the only vault of the unlocked accounts.
+func (a *CoreService) credentialHelperVault() (string, error) {
+	if vaultId := a.state.Settings.CredentialHelperVaultID; vaultId != "" {
+		vault, ok := a.state.Vaults[vaultId]
+		if !ok || vault.IsTrashed() {
+			return "", fmt.Errorf("credential helper vault %s not found", vaultId)
+		}
+		return vaultId, nil
+	}
+	var candidates []string
+	for vaultId, vault := range a.state.Vaults {
+		if _, unlocked := a.state.AUK[vault.AccountID]; unlocked && !vault.IsTrashed() {
+			candidates = append(candidates, vaultId)
+		}
+	}
+	if len(candidates) != 1 {
+		return "", fmt.Errorf("no credential helper vault configured")
+	}
+	return candidates[0], nil
+}
+
+// resolveItem finds a non-trashed item in an unlocked vault by its ID or, failing that, its title. Titles must be
+// unique to be used.
+func (a *CoreService) resolveItem(ref string) (*DecryptedVaultItemOverview, error) {
+	var byTitle []*DecryptedVaultItemOverview
+	for _, overview := range a.unlockedItemOverviews() {
+		if overview.ItemID == ref {
+			return overview, nil
+		}
+		if overview.Title == ref {
+			byTitle = append(byTitle, overview)
+		}
+	}
+	switch len(byTitle) {
+	case 0:
+		return nil, fmt.Errorf("no item found for %q", ref)
+	case 1:
+		return byTitle[0], nil
+	default:
+		return nil, fmt.Errorf("%d items are titled %q; use the item ID instead", len(byTitle), ref)
+	}
+}
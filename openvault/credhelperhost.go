@@ -0,0 +1,212 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/BradHacker/openvault/openvault/internal/constants"
+	"github.com/BradHacker/openvault/openvault/internal/nativemsg"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	errCredentialHelperLocked = errors.New("openvault is locked")
+	errCredentialHelperDenied = errors.New("the request was denied")
+)
+
+// Largest input a credential helper relays. Credential descriptions are only a few lines.
+const credentialHelperMaxInput = 64 << 10
+
+// credentialHelperRequest is the command a credential helper relays to the desktop app
+type credentialHelperRequest struct {
+	Command string   `json:"command"`
+	Args    []string `json:"args"`
+	Stdin   []byte   `json:"stdin,omitempty"`
+	// The environment variables the command reads, as set for the helper
+	Env map[string]string `json:"env,omitempty"`
+}
+
+type credentialHelperResponse struct {
+	Stdout []byte `json:"stdout,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// relayCLI runs a command in the desktop app, which holds the unlocked vaults and asks the user to approve it, and
+// writes its output
+func relayCLI(commandName string, command *cliCommand, args []string, stdin io.Reader, stdout io.Writer) error {
+	req := &credentialHelperRequest{Command: commandName, Args: args}
+	if command.stdin {
+		input, err := io.ReadAll(io.LimitReader(stdin, credentialHelperMaxInput+1))
+		if err != nil {
+			return fmt.Errorf("failed to read input: %w", err)
+		}
+		if len(input) > credentialHelperMaxInput {
+			return fmt.Errorf("input exceeds %d bytes", credentialHelperMaxInput)
+		}
+		req.Stdin = input
+	}
+	for _, key := range command.env {
+		if value, ok := os.LookupEnv(key); ok {
+			if req.Env == nil {
+				req.Env = make(map[string]string)
+			}
+			req.Env[key] = value
+		}
+	}
+	conn, err := net.Dial("unix", constants.CREDENTIAL_HELPER_SOCKET)
+	if err != nil {
+		return fmt.Errorf("openvault is not running or credential helpers are disabled: %w", err)
+	}
+	defer conn.Close()
+	if err := nativemsg.WriteMessage(conn, req); err != nil {
+		return fmt.Errorf("failed to send request to openvault: %w", err)
+	}
+	var resp credentialHelperResponse
+	if err := nativemsg.ReadMessage(conn, &resp); err != nil {
+		return fmt.Errorf("failed to read response from openvault: %w", err)
+	}
+	if _, err := stdout.Write(resp.Stdout); err != nil {
+		return err
+	}
+	if resp.Error != "" {
+		return errors.New(resp.Error)
+	}
+	return nil
+}
+
+// credentialHelperHost runs the commands credential helpers relay with relayCLI once the user approves them. Each
+// connection carries one request and its response.
+type credentialHelperHost struct {
+	core     *CoreService
+	listener net.Listener
+
+	mu    sync.Mutex
+	conns map[net.Conn]struct{}
+	// Closed when the host stops, which denies requests waiting for approval
+	done chan struct{}
+}
+
+func (h *credentialHelperHost) serve() {
+	for {
+		conn, err := h.listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				logrus.Errorf("credential helper host failed to accept connection: %v", err)
+			}
+			return
+		}
+		h.mu.Lock()
+		h.conns[conn] = struct{}{}
+		h.mu.Unlock()
+		go func() {
+			defer func() {
+				h.mu.Lock()
+				delete(h.conns, conn)
+				h.mu.Unlock()
+				conn.Close()
+			}()
+			if err := h.serveConn(conn); err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, net.ErrClosed) {
+				logrus.Debugf("credential helper connection closed: %v", err)
+			}
+		}()
+	}
+}
+
+func (h *credentialHelperHost) serveConn(conn net.Conn) error {
+	var req credentialHelperRequest
+	if err := nativemsg.ReadMessage(conn, &req); err != nil {
+		return err
+	}
+	var stdout bytes.Buffer
+	resp := &credentialHelperResponse{}
+	if err := h.run(&req, &stdout); err != nil {
+		resp.Error = err.Error()
+	}
+	resp.Stdout = stdout.Bytes()
+	return nativemsg.WriteMessage(conn, resp)
+}
+
+// run runs a relayed command once the user approves it. The state lock is released while the prompt is open.
+func (h *credentialHelperHost) run(req *credentialHelperRequest, stdout io.Writer) error {
+	command, ok := cliCommands[req.Command]
+	if !ok || !command.relay {
+		return fmt.Errorf("unknown command %q", req.Command)
+	}
+	h.core.state.mu.RLock()
+	locked := h.core.isLocked()
+	h.core.state.mu.RUnlock()
+	if locked {
+		return errCredentialHelperLocked
+	}
+	if !askApproval("Credential Helper", command.describe(req.Args, req.Stdin), h.done) {
+		return errCredentialHelperDenied
+	}
+	h.core.state.mu.Lock()
+	defer h.core.state.mu.Unlock()
+	// The host may have been stopped or the app locked while the prompt was open
+	if h.core.credentialHelperHost != h {
+		return errCredentialHelperDenied
+	}
+	if h.core.isLocked() {
+		return errCredentialHelperLocked
+	}
+	getenv := func(key string) string {
+		if !slices.Contains(command.env, key) {
+			return ""
+		}
+		return req.Env[key]
+	}
+	logrus.Printf("running approved credential helper request: %s %s", req.Command, strings.Join(req.Args, " "))
+	return command.run(h.core, req.Args, getenv, bytes.NewReader(req.Stdin), stdout)
+}
+
+// stop closes the listener and every open connection, so no further requests are served
+func (h *credentialHelperHost) stop() {
+	close(h.done)
+	h.listener.Close()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for conn := range h.conns {
+		conn.Close()
+	}
+	removeLocalSocket(constants.CREDENTIAL_HELPER_SOCKET)
+}
+
+// startCredentialHelperHost starts running credential helper requests if credential helpers are enabled and it is
+// not already running. It is never started by command line modes, which relay their requests to it instead.
+func (a *CoreService) startCredentialHelperHost() error {
+	if a.headless || a.credentialHelperHost != nil || !a.state.Settings.CredentialHelpersEnabled {
+		return nil
+	}
+	listener, err := listenLocalSocket(constants.CREDENTIAL_HELPER_SOCKET)
+	if err != nil {
+		return fmt.Errorf("failed to start credential helpers: %w", err)
+	}
+	a.credentialHelperHost = &credentialHelperHost{
+		core:     a,
+		listener: listener,
+		conns:    make(map[net.Conn]struct{}),
+		done:     make(chan struct{}),
+	}
+	go a.credentialHelperHost.serve()
+	logrus.Printf("credential helpers listening on %s", constants.CREDENTIAL_HELPER_SOCKET)
+	return nil
+}
+
+// stopCredentialHelperHost stops running credential helper requests if the host is running
+func (a *CoreService) stopCredentialHelperHost() {
+	if a.credentialHelperHost == nil {
+		return
+	}
+	a.credentialHelperHost.stop()
+	a.credentialHelperHost = nil
+	logrus.Printf("credential helpers stopped")
+}
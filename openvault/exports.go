@@ -0,0 +1,235 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/BradHacker/openvault/openvault/internal/constants"
+	"github.com/BradHacker/openvault/openvault/internal/exporter"
+	"github.com/BradHacker/openvault/openvault/internal/fs"
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+
+	"github.com/BradHacker/openvault/cryptolib"
+	"github.com/sirupsen/logrus"
+)
+
+// Export passwords are the only protection of an archive once it leaves the device
+const minExportPasswordLength = 10
+
+type ExportOptions struct {
+	Format exporter.Format `json:"format"`
+	// Vaults to export
+	VaultIDs []string `json:"vault_ids"`
+	// The file to write the export to
+	Path string `json:"path"`
+	// Password the archive is encrypted with (openvault format only)
+	ExportPassword string `json:"export_password"`
+	// The account password, re-entered to confirm plaintext exports
+	MasterPassword string `json:"master_password"`
+}
+
+type ExportReport struct {
+	Items       int `json:"items"`
+	Attachments int `json:"attachments"`
+	// Data which cannot be represented in the format and was not exported
+	Skipped []string `json:"skipped"`
+}
+
+// GetExportFormats returns the formats vaults can be exported to
+func (a *CoreService) GetExportFormats() []exporter.Format {
+	return exporter.Formats
+}
+
+// ExportVaults writes the items of the given vaults which are not in the trash to opts.Path. The openvault
+// format is encrypted with opts.ExportPassword. Plaintext formats write every secret unencrypted, so the account
+// password must be entered again in opts.MasterPassword before they run.
+func (a *CoreService) ExportVaults(opts ExportOptions) (*ExportReport, error) {
+	a.state.mu.RLock()
+	defer a.state.mu.RUnlock()
+	if a.isLocked() {
+		return nil, fmt.Errorf("application not unlocked")
+	}
+	if !opts.Format.IsValid() {
+		return nil, fmt.Errorf("unsupported export format %q", opts.Format)
+	}
+	if len(opts.VaultIDs) == 0 {
+		return nil, fmt.Errorf("no vaults selected for export")
+	}
+	for _, vaultId := range opts.VaultIDs {
+		if vault, ok := a.state.Vaults[vaultId]; !ok || vault.IsTrashed() {
+			return nil, fmt.Errorf("vault %s not found", vaultId)
+		}
+	}
+	if opts.Format.IsPlaintext() {
+		if err := a.confirmMasterPassword(opts.VaultIDs, opts.MasterPassword); err != nil {
+			return nil, err
+		}
+	} else if len(strings.TrimSpace(opts.ExportPassword)) < minExportPasswordLength {
+		return nil, fmt.Errorf("export password must be at least %d characters", minExportPasswordLength)
+	}
+
+	data := &structs.ExportData{Vaults: make([]*structs.ExportVault, 0, len(opts.VaultIDs))}
+	defer clearExportData(data)
+	for _, vaultId := range opts.VaultIDs {
+		vault, err := a.exportVault(vaultId)
+		if err != nil {
+			return nil, err
+		}
+		data.Vaults = append(data.Vaults, vault)
+	}
+
+	// Write to a temporary file first so a failed export never leaves a partial file at opts.Path
+	tmp, err := os.CreateTemp(filepath.Dir(opts.Path), ".openvault-export-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create export file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	result, err := exporter.Export(opts.Format, tmp, data, exporter.Options{
+		Password: opts.ExportPassword,
+		Rounds:   constants.PBKDF2_ROUNDS,
+	})
+	if closeErr := tmp.Close(); err == nil && closeErr != nil {
+		err = fmt.Errorf("failed to write export file: %w", closeErr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmp.Name(), opts.Path); err != nil {
+		return nil, fmt.Errorf("failed to write export file: %w", err)
+	}
+	logrus.Printf("exported %d items from %d vaults as %s", result.Items, len(opts.VaultIDs), opts.Format)
+	return &ExportReport{
+		Items:       result.Items,
+		Attachments: result.Attachments,
+		Skipped:     append([]string{}, result.Skipped...),
+	}, nil
+}
+
+// confirmMasterPassword checks the password unlocks every account owning one of the vaults
+func (a *CoreService) confirmMasterPassword(vaultIds []string, password string) error {
+	if password == "" {
+		return fmt.Errorf("the account password is required for plaintext exports")
+	}
+	checked := make([]string, 0)
+	for _, vaultId := range vaultIds {
+		accountId := a.state.Vaults[vaultId].AccountID
+		if slices.Contains(checked, accountId) {
+			continue
+		}
+		account, ok := a.state.Accounts[accountId]
+		if !ok {
+			return fmt.Errorf("account %s not found", accountId)
+		}
+		keySet, ok := a.state.KeySets[accountId]
+		if !ok {
+			return fmt.Errorf("no keyset found for account %s", accountId)
+		}
+		auk, err := account.TryUnlock(password, keySet.EncSymKey)
+		if err != nil {
+			return fmt.Errorf("the account password is incorrect")
+		}
+		auk.Close()
+		checked = append(checked, accountId)
+	}
+	return nil
+}
+
+// exportVault decrypts the items of a vault which are not in the trash, along with their attachments
+func (a *CoreService) exportVault(vaultId string) (*structs.ExportVault, error) {
+	meta, err := a.getVaultMetadata(vaultId)
+	if err != nil {
+		return nil, err
+	}
+	vaultKey, err := a.state.VaultKey(vaultId)
+	if err != nil {
+		return nil, err
+	}
+	defer vaultKey.Close()
+	vault := &structs.ExportVault{
+		Name:        meta.Name,
+		Description: meta.Description,
+		Folders:     meta.Folders,
+		Items:       make([]*structs.ExportItem, 0),
+	}
+	for itemId, encOverview := range a.state.ItemOverviews {
+		if encOverview.VaultID != vaultId || encOverview.IsTrashed() {
+			continue
+		}
+		encDetails, ok := a.state.ItemDetails[itemId]
+		if !ok {
+			return nil, fmt.Errorf("no item details found for item %s", itemId)
+		}
+		overview, err := encOverview.Read(vaultKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt item overview for item %s: %w", itemId, err)
+		}
+		details, err := encDetails.Read(vaultKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt item details for item %s: %w", itemId, err)
+		}
+		item := &structs.ExportItem{
+			Overview:  overview,
+			Details:   details,
+			CreatedAt: encOverview.CreatedAt,
+			UpdatedAt: encOverview.UpdatedAt,
+		}
+		for _, attachment := range a.state.ItemAttachments(itemId) {
+			exported, err := readAttachment(vaultKey, attachment)
+			if err != nil {
+				return nil, err
+			}
+			item.Attachments = append(item.Attachments, exported)
+		}
+		vault.Items = append(vault.Items, item)
+	}
+	slices.SortFunc(vault.Items, func(x, y *structs.ExportItem) int {
+		return strings.Compare(x.Overview.Title, y.Overview.Title)
+	})
+	return vault, nil
+}
+
+// readAttachment decrypts an attachment's metadata and contents
+func readAttachment(vaultKey *cryptolib.JWK, attachment *structs.EncryptedAttachment) (*structs.ExportAttachment, error) {
+	metadata, err := attachment.Read(vaultKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt metadata for attachment %s: %w", attachment.AttachmentID, err)
+	}
+	fileKey, err := attachment.FileKey(vaultKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt file key for attachment %s: %w", attachment.AttachmentID, err)
+	}
+	defer fileKey.Close()
+	blob, err := fs.OpenAttachmentBlob(attachment.AttachmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open attachment blob: %w", err)
+	}
+	defer blob.Close()
+	r, err := fileKey.NewDecryptReader(blob)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt attachment %s: %w", attachment.AttachmentID, err)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt attachment %s: %w", attachment.AttachmentID, err)
+	}
+	return &structs.ExportAttachment{
+		Name:        metadata.Name,
+		ContentType: metadata.ContentType,
+		Data:        data,
+	}, nil
+}
+
+// clearExportData zeroes the decrypted attachment contents once they have been written
+func clearExportData(data *structs.ExportData) {
+	for _, vault := range data.Vaults {
+		for _, item := range vault.Items {
+			for _, attachment := range item.Attachments {
+				clear(attachment.Data)
+			}
+		}
+	}
+}
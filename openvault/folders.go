@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+
+	"github.com/google/uuid"
+)
+
+// editVaultMetadata decrypts the metadata of a vault, applies edit and saves the encrypted result
+func (a *CoreService) editVaultMetadata(vaultId string, edit func(meta *structs.VaultMetadata) error) (*structs.VaultMetadata, error) {
+	if a.isLocked() {
+		return nil, fmt.Errorf("application not unlocked")
+	}
+	vault, ok := a.state.Vaults[vaultId]
+	if !ok {
+		return nil, fmt.Errorf("vault %s not found", vaultId)
+	}
+	if vault.IsTrashed() {
+		return nil, fmt.Errorf("vault %s is in the trash", vaultId)
+	}
+	vaultKey, err := a.state.VaultKey(vaultId)
+	if err != nil {
+		return nil, err
+	}
+	defer vaultKey.Close()
+	var meta structs.VaultMetadata
+	if err := vaultKey.DecryptJSON(vault.EncryptedMetadata, &meta); err != nil {
+		return nil, fmt.Errorf("failed to decrypt vault metadata for vault %s: %w", vaultId, err)
+	}
+	if err := edit(&meta); err != nil {
+		return nil, err
+	}
+	meta.UpdatedAt = time.Now().Format(time.RFC3339)
+	encMeta, err := meta.Encrypt(vaultKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt vault metadata: %w", err)
+	}
+	vault.EncryptedMetadata = encMeta
+	if err := a.state.SaveVaults(); err != nil {
+		return nil, err
+	}
+	return &meta, nil
+}
+
+// findFolder returns the index of a folder in the vault metadata, or an error if it does not exist
+func findFolder(meta *structs.VaultMetadata, folderId string) (int, error) {
+	i := slices.IndexFunc(meta.Folders, func(folder *structs.Folder) bool {
+		return folder.ID == folderId
+	})
+	if i < 0 {
+		return -1, fmt.Errorf("folder %s not found in vault %s", folderId, meta.VaultID)
+	}
+	return i, nil
+}
+
+// checkFolder returns an error if folderId is set but is not a folder of the vault
+func (a *CoreService) checkFolder(vaultId string, folderId string) error {
+	if folderId == "" {
+		return nil
+	}
+	meta, err := a.getVaultMetadata(vaultId)
+	if err != nil {
+		return err
+	}
+	_, err = findFolder(meta, folderId)
+	return err
+}
+
+// CreateFolder adds a folder to a vault. Folder names are stored in the encrypted vault metadata.
+func (a *CoreService) CreateFolder(vaultId string, name string) (*structs.Folder, error) {
+	a.state.mu.Lock()
+	defer a.state.mu.Unlock()
+	name, err := structs.NormalizeFolderName(name)
+	if err != nil {
+		return nil, err
+	}
+	folder := &structs.Folder{ID: uuid.New().String(), Name: name}
+	if _, err := a.editVaultMetadata(vaultId, func(meta *structs.VaultMetadata) error {
+		meta.Folders = append(meta.Folders, folder)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return folder, nil
+}
+
+// RenameFolder changes the name of a folder in a vault
+func (a *CoreService) RenameFolder(vaultId string, folderId string, name string) (*structs.Folder, error) {
+	a.state.mu.Lock()
+	defer a.state.mu.Unlock()
+	name, err := structs.NormalizeFolderName(name)
+	if err != nil {
+		return nil, err
+	}
+	var folder *structs.Folder
+	if _, err := a.editVaultMetadata(vaultId, func(meta *structs.VaultMetadata) error {
+		i, err := findFolder(meta, folderId)
+		if err != nil {
+			return err
+		}
+		folder = meta.Folders[i]
+		folder.Name = name
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return folder, nil
+}
+
+// DeleteFolder removes a folder from a vault. The items filed into it, including trashed items, become unfiled.
+func (a *CoreService) DeleteFolder(vaultId string, folderId string) error {
+	a.state.mu.Lock()
+	defer a.state.mu.Unlock()
+	if _, err := a.editVaultMetadata(vaultId, func(meta *structs.VaultMetadata) error {
+		i, err := findFolder(meta, folderId)
+		if err != nil {
+			return err
+		}
+		meta.Folders = slices.Delete(meta.Folders, i, i+1)
+		return nil
+	}); err != nil {
+		return err
+	}
+	_, err := a.editAllItemOverviews(vaultId, func(overview *structs.VaultItemOverview) (bool, error) {
+		if overview.FolderID != folderId {
+			return false, nil
+		}
+		overview.FolderID = ""
+		return true, nil
+	})
+	return err
+}
+
+// SetItemFolder files an item into a folder of its vault, or unfiles it if folderId is empty
+func (a *CoreService) SetItemFolder(itemId string, folderId string) (*DecryptedVaultItemOverview, error) {
+	a.state.mu.Lock()
+	defer a.state.mu.Unlock()
+	if a.isLocked() {
+		return nil, fmt.Errorf("application not unlocked")
+	}
+	encOverview, ok := a.state.ItemOverviews[itemId]
+	if !ok {
+		return nil, fmt.Errorf("no item overview found for item %s", itemId)
+	}
+	if err := a.checkFolder(encOverview.VaultID, folderId); err != nil {
+		return nil, err
+	}
+	return a.editItemOverview(itemId, func(overview *structs.VaultItemOverview) error {
+		overview.FolderID = folderId
+		return nil
+	})
+}
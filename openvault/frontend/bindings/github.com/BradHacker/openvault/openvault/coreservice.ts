@@ -10,20 +10,198 @@
 // @ts-ignore: Unused imports
 import { Call as $Call, CancellablePromise as $CancellablePromise, Create as $Create } from "@wailsio/runtime";
 
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as exporter$0 from "./internal/exporter/models.js";
 // eslint-disable-next-line @typescript-eslint/ban-ts-comment
 // @ts-ignore: Unused imports
 import * as fs$0 from "./internal/fs/models.js";
 // eslint-disable-next-line @typescript-eslint/ban-ts-comment
 // @ts-ignore: Unused imports
+import * as generator$0 from "./internal/generator/models.js";
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as importer$0 from "./internal/importer/models.js";
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
 import * as structs$0 from "./internal/structs/models.js";
 
 // eslint-disable-next-line @typescript-eslint/ban-ts-comment
 // @ts-ignore: Unused imports
 import * as $models from "./models.js";
 
+/**
+ * AddAttachment encrypts the file at sourcePath and attaches it to an item. The file is streamed
+ * through the cipher, so large files are never held in memory, and the state is only locked to look up
+ * the vault key and to register the encrypted file.
+ */
+export function AddAttachment(itemId: string, sourcePath: string): $CancellablePromise<$models.DecryptedAttachment | null> {
+    return $Call.ByID(261743952, itemId, sourcePath).then(($result: any) => {
+        return $$createType1($result);
+    });
+}
+
+/**
+ * CheckBreachedPasswords looks up the password of every login in the unlocked vaults in the Pwned Passwords list.
+ * Breached items are flagged with structs.ItemFlagBreached until they are changed or the app is locked. The
+ * lookups, which may go over the network, run without the state lock held.
+ */
+export function CheckBreachedPasswords(): $CancellablePromise<$models.BreachReport | null> {
+    return $Call.ByID(2519031872).then(($result: any) => {
+        return $$createType3($result);
+    });
+}
+
+/**
+ * CopyItem copies an item, including its history, to another vault under a new item ID. The destination
+ * vault may belong to any unlocked account. The copy keeps the original timestamps.
+ */
+export function CopyItem(itemId: string, destVaultId: string): $CancellablePromise<$models.DecryptedVaultItemOverview | null> {
+    return $Call.ByID(3971054356, itemId, destVaultId).then(($result: any) => {
+        return $$createType5($result);
+    });
+}
+
+/**
+ * CreateAPIToken creates a token for the local API limited to the given vaults and access
+ */
+export function CreateAPIToken(opts: $models.APITokenOptions): $CancellablePromise<$models.CreatedAPIToken | null> {
+    return $Call.ByID(3638602731, opts).then(($result: any) => {
+        return $$createType7($result);
+    });
+}
+
+/**
+ * CreateFolder adds a folder to a vault. Folder names are stored in the encrypted vault metadata.
+ */
+export function CreateFolder(vaultId: string, name: string): $CancellablePromise<structs$0.Folder | null> {
+    return $Call.ByID(2848631298, vaultId, name).then(($result: any) => {
+        return $$createType9($result);
+    });
+}
+
+/**
+ * CreateItem encrypts a new item with the vault key and saves it to the given vault.
+ */
+export function CreateItem(vaultId: string, overview: structs$0.VaultItemOverview, details: structs$0.VaultItemDetails): $CancellablePromise<$models.DecryptedVaultItemOverview | null> {
+    return $Call.ByID(550089457, vaultId, overview, details).then(($result: any) => {
+        return $$createType5($result);
+    });
+}
+
+/**
+ * DeleteFolder removes a folder from a vault. The items filed into it, including trashed items, become unfiled.
+ */
+export function DeleteFolder(vaultId: string, folderId: string): $CancellablePromise<void> {
+    return $Call.ByID(4032360649, vaultId, folderId);
+}
+
+/**
+ * DeleteItem moves an item to the trash. It can be restored with RestoreItem until it is purged.
+ */
+export function DeleteItem(itemId: string): $CancellablePromise<void> {
+    return $Call.ByID(225079330, itemId);
+}
+
+/**
+ * DeleteTag removes a tag, and the tags nested under it, from every item in the vaults of unlocked accounts. It
+ * returns the number of changed items.
+ */
+export function DeleteTag(tag: string): $CancellablePromise<number> {
+    return $Call.ByID(188890191, tag);
+}
+
+/**
+ * DeleteVault moves a vault, and with it all of its items, to the trash
+ */
+export function DeleteVault(vaultId: string): $CancellablePromise<void> {
+    return $Call.ByID(4085781515, vaultId);
+}
+
+/**
+ * DownloadAttachment decrypts an attachment to destPath. Nothing is left at destPath if the blob
+ * fails authentication.
+ */
+export function DownloadAttachment(attachmentId: string, destPath: string): $CancellablePromise<void> {
+    return $Call.ByID(2682521507, attachmentId, destPath);
+}
+
+/**
+ * EmptyTrash permanently deletes every trashed vault and item
+ */
+export function EmptyTrash(): $CancellablePromise<void> {
+    return $Call.ByID(213249263);
+}
+
+/**
+ * ExportItemShare encrypts a single item to the recipient's public key, signs it with the owning
+ * account's signing key and writes the resulting share package to opts.Path.
+ */
+export function ExportItemShare(itemId: string, opts: $models.ShareExportOptions): $CancellablePromise<void> {
+    return $Call.ByID(776288178, itemId, opts);
+}
+
+/**
+ * ExportVaults writes the items of the given vaults which are not in the trash to opts.Path. The openvault
+ * format is encrypted with opts.ExportPassword. Plaintext formats write every secret unencrypted, so the account
+ * password must be entered again in opts.MasterPassword before they run.
+ */
+export function ExportVaults(opts: $models.ExportOptions): $CancellablePromise<$models.ExportReport | null> {
+    return $Call.ByID(653627577, opts).then(($result: any) => {
+        return $$createType11($result);
+    });
+}
+
+/**
+ * GeneratePIN returns a random numeric PIN code along with its estimated entropy
+ */
+export function GeneratePIN(length: number): $CancellablePromise<generator$0.Result | null> {
+    return $Call.ByID(2446337712, length).then(($result: any) => {
+        return $$createType13($result);
+    });
+}
+
+/**
+ * GeneratePassphrase returns a random diceware passphrase along with its estimated entropy. Words are drawn from
+ * the EFF large word list.
+ */
+export function GeneratePassphrase(opts: generator$0.PassphraseOptions): $CancellablePromise<generator$0.Result | null> {
+    return $Call.ByID(4198536105, opts).then(($result: any) => {
+        return $$createType13($result);
+    });
+}
+
+/**
+ * GeneratePassword returns a random password along with its estimated entropy
+ */
+export function GeneratePassword(opts: generator$0.PasswordOptions): $CancellablePromise<generator$0.Result | null> {
+    return $Call.ByID(4090179836, opts).then(($result: any) => {
+        return $$createType13($result);
+    });
+}
+
+/**
+ * GenerateSSHKey generates a new SSH key pair and stores it as a new item in the given vault. The private
+ * key never leaves the vault.
+ */
+export function GenerateSSHKey(vaultId: string, opts: $models.SSHKeyGenerateOptions): $CancellablePromise<$models.DecryptedVaultItemOverview | null> {
+    return $Call.ByID(918590300, vaultId, opts).then(($result: any) => {
+        return $$createType5($result);
+    });
+}
+
+/**
+ * GetAPIServerStatus returns whether the local API is running and where it listens
+ */
+export function GetAPIServerStatus(): $CancellablePromise<$models.APIServerStatus | null> {
+    return $Call.ByID(3592128427).then(($result: any) => {
+        return $$createType15($result);
+    });
+}
+
 export function GetAccount(accountId: string): $CancellablePromise<$models.AccountWithUnlockStatus | null> {
     return $Call.ByID(2445503429, accountId).then(($result: any) => {
-        return $$createType1($result);
+        return $$createType17($result);
     });
 }
 
@@ -32,19 +210,114 @@ export function GetAccount(accountId: string): $CancellablePromise<$models.Accou
  */
 export function GetAccounts(): $CancellablePromise<($models.AccountWithUnlockStatus | null)[]> {
     return $Call.ByID(748851074).then(($result: any) => {
-        return $$createType2($result);
+        return $$createType18($result);
+    });
+}
+
+/**
+ * GetDefaultPassphraseOptions returns the options the passphrase generator starts with
+ */
+export function GetDefaultPassphraseOptions(): $CancellablePromise<generator$0.PassphraseOptions> {
+    return $Call.ByID(356113035).then(($result: any) => {
+        return $$createType19($result);
+    });
+}
+
+/**
+ * GetDefaultPasswordOptions returns the options the password generator starts with
+ */
+export function GetDefaultPasswordOptions(): $CancellablePromise<generator$0.PasswordOptions> {
+    return $Call.ByID(1868073168).then(($result: any) => {
+        return $$createType20($result);
+    });
+}
+
+/**
+ * GetExportFormats returns the formats vaults can be exported to
+ */
+export function GetExportFormats(): $CancellablePromise<exporter$0.Format[]> {
+    return $Call.ByID(753544392).then(($result: any) => {
+        return $$createType21($result);
+    });
+}
+
+/**
+ * GetImportFormats returns the export formats which can be imported
+ */
+export function GetImportFormats(): $CancellablePromise<importer$0.Format[]> {
+    return $Call.ByID(3069516009).then(($result: any) => {
+        return $$createType22($result);
     });
 }
 
 export function GetItemOverview(itemId: string): $CancellablePromise<$models.DecryptedVaultItemOverview | null> {
     return $Call.ByID(1670617126, itemId).then(($result: any) => {
-        return $$createType4($result);
+        return $$createType5($result);
     });
 }
 
-export function GetVaultItemDetails(itemId: string): $CancellablePromise<$models.DecryptedVaultItemDetails | null> {
-    return $Call.ByID(1775651925, itemId).then(($result: any) => {
-        return $$createType6($result);
+/**
+ * GetPasswordHealthReport audits the logins in every unlocked vault for weak, reused and old passwords and for
+ * URLs without HTTPS. Reuse is found by comparing HMACs of the passwords under a random key which only exists for
+ * the duration of the call, so no comparable form of a password outlives the report or is written to disk.
+ */
+export function GetPasswordHealthReport(): $CancellablePromise<$models.PasswordHealthReport | null> {
+    return $Call.ByID(3813076383).then(($result: any) => {
+        return $$createType24($result);
+    });
+}
+
+/**
+ * GetPublicKey returns the public encryption key of the given account as a JWK. This is the key
+ * other users need in order to send share packages to this account.
+ */
+export function GetPublicKey(accountId: string): $CancellablePromise<string> {
+    return $Call.ByID(3151001114, accountId);
+}
+
+/**
+ * GetSSHAgentStatus returns whether the SSH agent is running and the socket it listens on
+ */
+export function GetSSHAgentStatus(): $CancellablePromise<$models.SSHAgentStatus | null> {
+    return $Call.ByID(3736908883).then(($result: any) => {
+        return $$createType26($result);
+    });
+}
+
+/**
+ * GetSecretServiceStatus returns whether the Secret Service is running
+ */
+export function GetSecretServiceStatus(): $CancellablePromise<$models.SecretServiceStatus | null> {
+    return $Call.ByID(1249602147).then(($result: any) => {
+        return $$createType28($result);
+    });
+}
+
+/**
+ * GetSettings returns the current application settings
+ */
+export function GetSettings(): $CancellablePromise<structs$0.Settings | null> {
+    return $Call.ByID(382042735).then(($result: any) => {
+        return $$createType30($result);
+    });
+}
+
+/**
+ * GetTOTPCode returns the current one-time password for a one-time password field of an item
+ */
+export function GetTOTPCode(itemId: string, fieldId: string): $CancellablePromise<$models.TOTPCode | null> {
+    return $Call.ByID(3223863866, itemId, fieldId).then(($result: any) => {
+        return $$createType32($result);
+    });
+}
+
+/**
+ * GetVaultItemDetails returns the decrypted details of an item. Concealed custom fields and SSH private keys are
+ * redacted unless opts.RevealConcealed is set or the field is requested by opts.FieldID.
+ */
+export function GetVaultItemDetails(itemId: string, opts: $models.ItemDetailsOptions): $CancellablePromise<$models.DecryptedVaultItemDetails | null> {
+    return $Call.ByID(1775651925, itemId, opts).then(($result: any) => {
+        return $$createType34($result);
     });
 }
 
@@ -53,7 +326,40 @@ export function GetVaultItemDetails(itemId: string): $CancellablePromise<$models
  */
 export function GetVaultMetadata(vaultId: string): $CancellablePromise<structs$0.VaultMetadata | null> {
     return $Call.ByID(2085091987, vaultId).then(($result: any) => {
-        return $$createType8($result);
+        return $$createType36($result);
+    });
+}
+
+/**
+ * ImportItemShare verifies and decrypts the share package at path and adds its item to the given vault.
+ * The package must be signed by the key with expectedSenderFingerprint, as returned by PreviewItemShare and
+ * confirmed with the sender, since anyone can claim any email address. Expired packages and one-time packages
+ * which have already been imported are rejected.
+ */
+export function ImportItemShare(vaultId: string, path: string, expectedSenderFingerprint: string): $CancellablePromise<$models.ShareImportResult | null> {
+    return $Call.ByID(1888520527, vaultId, path, expectedSenderFingerprint).then(($result: any) => {
+        return $$createType38($result);
+    });
+}
+
+/**
+ * ImportItems reads an export from another password manager and adds its items to the chosen vaults. Each item
+ * is encrypted with the key of the vault it is imported into. With opts.DryRun set, the report describes what
+ * would be imported and nothing is saved. Folders kept within a vault by the export, as in OpenVault archives, are
+ * created in the destination vault if it has none with the same name.
+ */
+export function ImportItems(opts: $models.ImportOptions): $CancellablePromise<$models.ImportReport | null> {
+    return $Call.ByID(1302608965, opts).then(($result: any) => {
+        return $$createType40($result);
+    });
+}
+
+/**
+ * ImportSSHKey imports an existing SSH private key as a new item in the given vault
+ */
+export function ImportSSHKey(vaultId: string, opts: $models.SSHKeyImportOptions): $CancellablePromise<$models.DecryptedVaultItemOverview | null> {
+    return $Call.ByID(2781305290, vaultId, opts).then(($result: any) => {
+        return $$createType5($result);
     });
 }
 
@@ -76,15 +382,98 @@ export function IsLocked(): $CancellablePromise<boolean> {
     return $Call.ByID(1874315844);
 }
 
-export function ListAllItemOverviews(): $CancellablePromise<($models.DecryptedVaultItemOverview | null)[]> {
-    return $Call.ByID(3858392174).then(($result: any) => {
-        return $$createType9($result);
+/**
+ * ListAPITokens returns every API token, including expired and revoked tokens, newest first
+ */
+export function ListAPITokens(): $CancellablePromise<(structs$0.APIToken | null)[]> {
+    return $Call.ByID(1657745912).then(($result: any) => {
+        return $$createType43($result);
     });
 }
 
-export function ListVaultItemOverviews(vaultId: string): $CancellablePromise<($models.DecryptedVaultItemOverview | null)[]> {
-    return $Call.ByID(2287033531, vaultId).then(($result: any) => {
-        return $$createType9($result);
+/**
+ * ListAllItemOverviews returns the decrypted item overviews across all vaults which match the filter. Trashed
+ * items are only included if includeTrashed is set.
+ */
+export function ListAllItemOverviews(includeTrashed: boolean, filter: $models.ItemFilter): $CancellablePromise<($models.DecryptedVaultItemOverview | null)[]> {
+    return $Call.ByID(3858392174, includeTrashed, filter).then(($result: any) => {
+        return $$createType44($result);
+    });
+}
+
+/**
+ * ListAttachments returns the decrypted metadata of the attachments of an item
+ */
+export function ListAttachments(itemId: string): $CancellablePromise<($models.DecryptedAttachment | null)[]> {
+    return $Call.ByID(1145548, itemId).then(($result: any) => {
+        return $$createType45($result);
+    });
+}
+
+/**
+ * ListBrowserPairings returns the paired browser extensions, most recently paired first
+ */
+export function ListBrowserPairings(): $CancellablePromise<(structs$0.BrowserPairing | null)[]> {
+    return $Call.ByID(1140089011).then(($result: any) => {
+        return $$createType48($result);
+    });
+}
+
+/**
+ * ListItemCategories returns every supported item category
+ */
+export function ListItemCategories(): $CancellablePromise<structs$0.ItemCategory[]> {
+    return $Call.ByID(3163386161).then(($result: any) => {
+        return $$createType49($result);
+    });
+}
+
+/**
+ * ListItemHistory returns the previous versions of an item, newest first. Concealed custom fields and SSH private
+ * keys are redacted as in GetVaultItemDetails. With opts.FieldID set, only versions containing that field are returned.
+ */
+export function ListItemHistory(itemId: string, opts: $models.ItemDetailsOptions): $CancellablePromise<($models.DecryptedItemRevision | null)[]> {
+    return $Call.ByID(3541579395, itemId, opts).then(($result: any) => {
+        return $$createType52($result);
+    });
+}
+
+/**
+ * ListItemOverviewsByCategory returns the decrypted overviews of all non-trashed items in the given category
+ */
+export function ListItemOverviewsByCategory(category: structs$0.ItemCategory): $CancellablePromise<($models.DecryptedVaultItemOverview | null)[]> {
+    return $Call.ByID(1450722818, category).then(($result: any) => {
+        return $$createType44($result);
+    });
+}
+
+/**
+ * ListTags returns every tag used by the non-trashed items in the vaults of unlocked accounts, along with the
+ * tags they are nested under, sorted by name
+ */
+export function ListTags(): $CancellablePromise<($models.TagCount | null)[]> {
+    return $Call.ByID(2580335379).then(($result: any) => {
+        return $$createType55($result);
+    });
+}
+
+/**
+ * ListTrash returns the trashed vaults and items. Items inside a trashed vault are included in Items. Vaults and
+ * items past the retention period are purged first, so it takes the write lock.
+ */
+export function ListTrash(): $CancellablePromise<$models.TrashContents | null> {
+    return $Call.ByID(236533040).then(($result: any) => {
+        return $$createType57($result);
+    });
+}
+
+/**
+ * ListVaultItemOverviews returns the decrypted item overviews in the given vault. Trashed items are only
+ * included if includeTrashed is set.
+ */
+export function ListVaultItemOverviews(vaultId: string, includeTrashed: boolean): $CancellablePromise<($models.DecryptedVaultItemOverview | null)[]> {
+    return $Call.ByID(2287033531, vaultId, includeTrashed).then(($result: any) => {
+        return $$createType44($result);
     });
 }
 
@@ -93,7 +482,7 @@ export function ListVaultItemOverviews(vaultId: string): $CancellablePromise<($m
  */
 export function ListVaultMetadatas(accountIds: string[]): $CancellablePromise<(structs$0.VaultMetadata | null)[]> {
     return $Call.ByID(2047389568, accountIds).then(($result: any) => {
-        return $$createType10($result);
+        return $$createType58($result);
     });
 }
 
@@ -101,19 +490,236 @@ export function Lock(): $CancellablePromise<void> {
     return $Call.ByID(769314425);
 }
 
+/**
+ * MoveItem moves an item, including its history, to another vault. The destination vault may belong to
+ * any unlocked account. The item keeps its ID and timestamps.
+ */
+export function MoveItem(itemId: string, destVaultId: string): $CancellablePromise<$models.DecryptedVaultItemOverview | null> {
+    return $Call.ByID(3661824140, itemId, destVaultId).then(($result: any) => {
+        return $$createType5($result);
+    });
+}
+
+/**
+ * ParseOTPMigration decodes an authenticator export QR payload (otpauth-migration://) or a single
+ * otpauth:// URI into the accounts it contains.
+ */
+export function ParseOTPMigration(payload: string): $CancellablePromise<($models.OTPAccount | null)[]> {
+    return $Call.ByID(3386740446, payload).then(($result: any) => {
+        return $$createType61($result);
+    });
+}
+
+/**
+ * PreviewItemShare verifies the share package at path and returns its sender without importing it, so the
+ * fingerprint can be confirmed with the sender before calling ImportItemShare
+ */
+export function PreviewItemShare(path: string): $CancellablePromise<$models.ShareSender | null> {
+    return $Call.ByID(2987482764, path).then(($result: any) => {
+        return $$createType63($result);
+    });
+}
+
+/**
+ * PurgeItem permanently deletes a trashed item along with its history
+ */
+export function PurgeItem(itemId: string): $CancellablePromise<void> {
+    return $Call.ByID(676645498, itemId);
+}
+
+/**
+ * PurgeVault permanently deletes a trashed vault and all of its items
+ */
+export function PurgeVault(vaultId: string): $CancellablePromise<void> {
+    return $Call.ByID(178595299, vaultId);
+}
+
+/**
+ * RemoveAttachment permanently deletes an attachment and its encrypted blob
+ */
+export function RemoveAttachment(attachmentId: string): $CancellablePromise<void> {
+    return $Call.ByID(2174191143, attachmentId);
+}
+
+/**
+ * RenameFolder changes the name of a folder in a vault
+ */
+export function RenameFolder(vaultId: string, folderId: string, name: string): $CancellablePromise<structs$0.Folder | null> {
+    return $Call.ByID(3916121468, vaultId, folderId, name).then(($result: any) => {
+        return $$createType9($result);
+    });
+}
+
+/**
+ * RenameTag renames a tag on every item in the vaults of unlocked accounts. Tags nested under it are moved along,
+ * so renaming "prod" to "production" turns "prod/db" into "production/db". It returns the number of changed items.
+ */
+export function RenameTag(tag: string, newTag: string): $CancellablePromise<number> {
+    return $Call.ByID(1283850504, tag, newTag);
+}
+
+/**
+ * RestoreItem moves an item out of the trash
+ */
+export function RestoreItem(itemId: string): $CancellablePromise<void> {
+    return $Call.ByID(323145859, itemId);
+}
+
+/**
+ * RestoreItemVersion makes a previous version of an item current again. The version being replaced
+ * is added to the history, so a restore can itself be undone.
+ */
+export function RestoreItemVersion(itemId: string, revisionId: string): $CancellablePromise<$models.DecryptedVaultItemOverview | null> {
+    return $Call.ByID(2773500989, itemId, revisionId).then(($result: any) => {
+        return $$createType5($result);
+    });
+}
+
+/**
+ * RestoreVault moves a vault out of the trash. Items which were trashed individually stay in the trash.
+ */
+export function RestoreVault(vaultId: string): $CancellablePromise<void> {
+    return $Call.ByID(328595672, vaultId);
+}
+
+/**
+ * RevokeAPIToken stops a token from working. Revoking does not need the app to be unlocked, so a leaked token
+ * can be shut off straight away.
+ */
+export function RevokeAPIToken(tokenId: string): $CancellablePromise<void> {
+    return $Call.ByID(315394145, tokenId);
+}
+
+/**
+ * SearchItems searches the titles, URLs, usernames, tags and notes of the unlocked items. Every word of the query
+ * must match a word of the item exactly, as a prefix or, for longer words, with a typo. Results are ranked by how
+ * well and where they match; an empty query returns every item sorted by title.
+ */
+export function SearchItems(query: string, filters: $models.SearchFilters): $CancellablePromise<$models.SearchResults | null> {
+    return $Call.ByID(3301891610, query, filters).then(($result: any) => {
+        return $$createType65($result);
+    });
+}
+
+/**
+ * SetItemFavorite marks or unmarks an item as a favorite
+ */
+export function SetItemFavorite(itemId: string, favorite: boolean): $CancellablePromise<$models.DecryptedVaultItemOverview | null> {
+    return $Call.ByID(1613232781, itemId, favorite).then(($result: any) => {
+        return $$createType5($result);
+    });
+}
+
+/**
+ * SetItemFolder files an item into a folder of its vault, or unfiles it if folderId is empty
+ */
+export function SetItemFolder(itemId: string, folderId: string): $CancellablePromise<$models.DecryptedVaultItemOverview | null> {
+    return $Call.ByID(2237518237, itemId, folderId).then(($result: any) => {
+        return $$createType5($result);
+    });
+}
+
+/**
+ * SetItemTags replaces the tags of an item. Tags are stored in the encrypted item overview.
+ */
+export function SetItemTags(itemId: string, tags: string[]): $CancellablePromise<$models.DecryptedVaultItemOverview | null> {
+    return $Call.ByID(1409608800, itemId, tags).then(($result: any) => {
+        return $$createType5($result);
+    });
+}
+
 export function TryUnlock(password: string): $CancellablePromise<void> {
     return $Call.ByID(2015788031, password);
 }
 
+/**
+ * UnpairBrowserExtension stops answering a browser extension until it is paired again
+ */
+export function UnpairBrowserExtension(extension: string): $CancellablePromise<void> {
+    return $Call.ByID(3642589348, extension);
+}
+
+/**
+ * UpdateItem replaces the overview and details of an item. The previous version is kept in the item's history.
+ * Tags, the favorite flag and the folder are kept unless the new overview sets them.
+ */
+export function UpdateItem(itemId: string, overview: structs$0.VaultItemOverview, details: structs$0.VaultItemDetails): $CancellablePromise<$models.DecryptedVaultItemOverview | null> {
+    return $Call.ByID(1808050272, itemId, overview, details).then(($result: any) => {
+        return $$createType5($result);
+    });
+}
+
+/**
+ * UpdateSettings validates and saves the application settings
+ */
+export function UpdateSettings(settings: structs$0.Settings): $CancellablePromise<void> {
+    return $Call.ByID(736772570, settings);
+}
+
 // Private type creation functions
-const $$createType0 = $models.AccountWithUnlockStatus.createFrom;
+const $$createType0 = $models.DecryptedAttachment.createFrom;
 const $$createType1 = $Create.Nullable($$createType0);
-const $$createType2 = $Create.Array($$createType1);
-const $$createType3 = $models.DecryptedVaultItemOverview.createFrom;
-const $$createType4 = $Create.Nullable($$createType3);
-const $$createType5 = $models.DecryptedVaultItemDetails.createFrom;
-const $$createType6 = $Create.Nullable($$createType5);
-const $$createType7 = structs$0.VaultMetadata.createFrom;
-const $$createType8 = $Create.Nullable($$createType7);
-const $$createType9 = $Create.Array($$createType4);
-const $$createType10 = $Create.Array($$createType8);
+const $$createType2 = $models.BreachReport.createFrom;
+const $$createType3 = $Create.Nullable($$createType2);
+const $$createType4 = $models.DecryptedVaultItemOverview.createFrom;
+const $$createType5 = $Create.Nullable($$createType4);
+const $$createType6 = $models.CreatedAPIToken.createFrom;
+const $$createType7 = $Create.Nullable($$createType6);
+const $$createType8 = structs$0.Folder.createFrom;
+const $$createType9 = $Create.Nullable($$createType8);
+const $$createType10 = $models.ExportReport.createFrom;
+const $$createType11 = $Create.Nullable($$createType10);
+const $$createType12 = generator$0.Result.createFrom;
+const $$createType13 = $Create.Nullable($$createType12);
+const $$createType14 = $models.APIServerStatus.createFrom;
+const $$createType15 = $Create.Nullable($$createType14);
+const $$createType16 = $models.AccountWithUnlockStatus.createFrom;
+const $$createType17 = $Create.Nullable($$createType16);
+const $$createType18 = $Create.Array($$createType17);
+const $$createType19 = generator$0.PassphraseOptions.createFrom;
+const $$createType20 = generator$0.PasswordOptions.createFrom;
+const $$createType21 = $Create.Array($Create.Any);
+const $$createType22 = $Create.Array($Create.Any);
+const $$createType23 = $models.PasswordHealthReport.createFrom;
+const $$createType24 = $Create.Nullable($$createType23);
+const $$createType25 = $models.SSHAgentStatus.createFrom;
+const $$createType26 = $Create.Nullable($$createType25);
+const $$createType27 = $models.SecretServiceStatus.createFrom;
+const $$createType28 = $Create.Nullable($$createType27);
+const $$createType29 = structs$0.Settings.createFrom;
+const $$createType30 = $Create.Nullable($$createType29);
+const $$createType31 = $models.TOTPCode.createFrom;
+const $$createType32 = $Create.Nullable($$createType31);
+const $$createType33 = $models.DecryptedVaultItemDetails.createFrom;
+const $$createType34 = $Create.Nullable($$createType33);
+const $$createType35 = structs$0.VaultMetadata.createFrom;
+const $$createType36 = $Create.Nullable($$createType35);
+const $$createType37 = $models.ShareImportResult.createFrom;
+const $$createType38 = $Create.Nullable($$createType37);
+const $$createType39 = $models.ImportReport.createFrom;
+const $$createType40 = $Create.Nullable($$createType39);
+const $$createType41 = structs$0.APIToken.createFrom;
+const $$createType42 = $Create.Nullable($$createType41);
+const $$createType43 = $Create.Array($$createType42);
+const $$createType44 = $Create.Array($$createType5);
+const $$createType45 = $Create.Array($$createType1);
+const $$createType46 = structs$0.BrowserPairing.createFrom;
+const $$createType47 = $Create.Nullable($$createType46);
+const $$createType48 = $Create.Array($$createType47);
+const $$createType49 = $Create.Array($Create.Any);
+const $$createType50 = $models.DecryptedItemRevision.createFrom;
+const $$createType51 = $Create.Nullable($$createType50);
+const $$createType52 = $Create.Array($$createType51);
+const $$createType53 = $models.TagCount.createFrom;
+const $$createType54 = $Create.Nullable($$createType53);
+const $$createType55 = $Create.Array($$createType54);
+const $$createType56 = $models.TrashContents.createFrom;
+const $$createType57 = $Create.Nullable($$createType56);
+const $$createType58 = $Create.Array($$createType36);
+const $$createType59 = $models.OTPAccount.createFrom;
+const $$createType60 = $Create.Nullable($$createType59);
+const $$createType61 = $Create.Array($$createType60);
+const $$createType62 = $models.ShareSender.createFrom;
+const $$createType63 = $Create.Nullable($$createType62);
+const $$createType64 = $models.SearchResults.createFrom;
+const $$createType65 = $Create.Nullable($$createType64);
@@ -7,7 +7,43 @@ export {
 };
 
 export {
+    APIServerStatus,
+    APITokenOptions,
     AccountWithUnlockStatus,
+    BreachReport,
+    BreachedPassword,
+    CreatedAPIToken,
+    DecryptedAttachment,
+    DecryptedItemRevision,
     DecryptedVaultItemDetails,
-    DecryptedVaultItemOverview
+    DecryptedVaultItemOverview,
+    ExportOptions,
+    ExportReport,
+    HealthItem,
+    ImportFolderMode,
+    ImportOptions,
+    ImportReport,
+    ImportReportItem,
+    InsecureLogin,
+    ItemDetailsOptions,
+    ItemFilter,
+    OTPAccount,
+    OldPassword,
+    PasswordHealthReport,
+    ReusedPassword,
+    SSHAgentStatus,
+    SSHKeyGenerateOptions,
+    SSHKeyImportOptions,
+    SearchFilters,
+    SearchResult,
+    SearchResults,
+    SecretServiceStatus,
+    ShareExportOptions,
+    ShareImportResult,
+    ShareSender,
+    TOTPCode,
+    TagCount,
+    TrashContents,
+    TrashedVault,
+    WeakPassword
 } from "./models.js";
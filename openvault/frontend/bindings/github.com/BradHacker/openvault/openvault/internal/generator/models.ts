@@ -0,0 +1,141 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Create as $Create } from "@wailsio/runtime";
+
+export class PassphraseOptions {
+    "words": number;
+    "separator": string;
+
+    /**
+     * Capitalize the first letter of each word
+     */
+    "capitalize": boolean;
+
+    /**
+     * Append a random digit to one of the words
+     */
+    "include_digit": boolean;
+
+    /** Creates a new PassphraseOptions instance. */
+    constructor($$source: Partial<PassphraseOptions> = {}) {
+        if (!("words" in $$source)) {
+            this["words"] = 0;
+        }
+        if (!("separator" in $$source)) {
+            this["separator"] = "";
+        }
+        if (!("capitalize" in $$source)) {
+            this["capitalize"] = false;
+        }
+        if (!("include_digit" in $$source)) {
+            this["include_digit"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new PassphraseOptions instance from a string or object.
+     */
+    static createFrom($$source: any = {}): PassphraseOptions {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new PassphraseOptions($$parsedSource as Partial<PassphraseOptions>);
+    }
+}
+
+export class PasswordOptions {
+    "length": number;
+    "lowercase": boolean;
+    "uppercase": boolean;
+    "digits": boolean;
+    "symbols": boolean;
+
+    /**
+     * Minimum number of characters from each enabled class
+     */
+    "min_lowercase": number;
+    "min_uppercase": number;
+    "min_digits": number;
+    "min_symbols": number;
+
+    /**
+     * Leave out characters which are easily confused with each other (see Ambiguous)
+     */
+    "exclude_ambiguous": boolean;
+
+    /** Creates a new PasswordOptions instance. */
+    constructor($$source: Partial<PasswordOptions> = {}) {
+        if (!("length" in $$source)) {
+            this["length"] = 0;
+        }
+        if (!("lowercase" in $$source)) {
+            this["lowercase"] = false;
+        }
+        if (!("uppercase" in $$source)) {
+            this["uppercase"] = false;
+        }
+        if (!("digits" in $$source)) {
+            this["digits"] = false;
+        }
+        if (!("symbols" in $$source)) {
+            this["symbols"] = false;
+        }
+        if (!("min_lowercase" in $$source)) {
+            this["min_lowercase"] = 0;
+        }
+        if (!("min_uppercase" in $$source)) {
+            this["min_uppercase"] = 0;
+        }
+        if (!("min_digits" in $$source)) {
+            this["min_digits"] = 0;
+        }
+        if (!("min_symbols" in $$source)) {
+            this["min_symbols"] = 0;
+        }
+        if (!("exclude_ambiguous" in $$source)) {
+            this["exclude_ambiguous"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new PasswordOptions instance from a string or object.
+     */
+    static createFrom($$source: any = {}): PasswordOptions {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new PasswordOptions($$parsedSource as Partial<PasswordOptions>);
+    }
+}
+
+export class Result {
+    "value": string;
+
+    /**
+     * Estimated entropy of the result in bits
+     */
+    "entropy": number;
+
+    /** Creates a new Result instance. */
+    constructor($$source: Partial<Result> = {}) {
+        if (!("value" in $$source)) {
+            this["value"] = "";
+        }
+        if (!("entropy" in $$source)) {
+            this["entropy"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Result instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Result {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new Result($$parsedSource as Partial<Result>);
+    }
+}
@@ -0,0 +1,16 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Create as $Create } from "@wailsio/runtime";
+
+/**
+ * CSVField is the item field a CSV column is imported into
+ */
+export type CSVField = string;
+
+/**
+ * Format identifies the export format of another password manager
+ */
+export type Format = string;
@@ -0,0 +1,43 @@
+// Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
+// This file is automatically generated. DO NOT EDIT
+
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import { Create as $Create } from "@wailsio/runtime";
+
+export class Result {
+    /**
+     * ScoreTooGuessable to ScoreVeryUnguessable
+     */
+    "score": number;
+
+    /**
+     * Base 10 logarithm of the estimated number of guesses
+     */
+    "guesses_log10": number;
+
+    /**
+     * Explains the weakest part of the password for scores below ScoreSafelyUnguessable
+     */
+    "warning"?: string;
+
+    /** Creates a new Result instance. */
+    constructor($$source: Partial<Result> = {}) {
+        if (!("score" in $$source)) {
+            this["score"] = 0;
+        }
+        if (!("guesses_log10" in $$source)) {
+            this["guesses_log10"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Result instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Result {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new Result($$parsedSource as Partial<Result>);
+    }
+}
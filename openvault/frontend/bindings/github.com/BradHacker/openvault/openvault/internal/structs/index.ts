@@ -2,5 +2,26 @@
 // This file is automatically generated. DO NOT EDIT
 
 export {
+    APICredentialDetails,
+    APIToken,
+    BrowserPairing,
+    CreditCardDetails,
+    Field,
+    Folder,
+    IdentityDetails,
+    SSHKeyDetails,
+    SSHKeyOverview,
+    Section,
+    Settings,
+    VaultItemDetails,
+    VaultItemOverview,
     VaultMetadata
 } from "./models.js";
+
+export type {
+    APIAccess,
+    FieldType,
+    ItemCategory,
+    ItemFlag,
+    SSHKeyType
+} from "./models.js";
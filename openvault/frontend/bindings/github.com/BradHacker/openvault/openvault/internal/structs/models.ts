@@ -5,6 +5,715 @@
 // @ts-ignore: Unused imports
 import { Create as $Create } from "@wailsio/runtime";
 
+/**
+ * APIAccess is what an API token may do in its vaults
+ */
+export type APIAccess = string;
+
+export class APICredentialDetails {
+    "key": string;
+    "secret": string;
+    "endpoint": string;
+
+    /** Creates a new APICredentialDetails instance. */
+    constructor($$source: Partial<APICredentialDetails> = {}) {
+        if (!("key" in $$source)) {
+            this["key"] = "";
+        }
+        if (!("secret" in $$source)) {
+            this["secret"] = "";
+        }
+        if (!("endpoint" in $$source)) {
+            this["endpoint"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new APICredentialDetails instance from a string or object.
+     */
+    static createFrom($$source: any = {}): APICredentialDetails {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new APICredentialDetails($$parsedSource as Partial<APICredentialDetails>);
+    }
+}
+
+/**
+ * APIToken is a bearer token for the local API. Only a hash of the secret is stored; the token itself is shown
+ * once when it is created.
+ */
+export class APIToken {
+    "token_id": string;
+    "name": string;
+
+    /**
+     * SHA-256 of the secret half of the token
+     */
+    "secret_hash": string;
+
+    /**
+     * Vaults the token can access
+     */
+    "vault_ids": string[];
+    "access": APIAccess;
+    "created_at": string;
+
+    /**
+     * When the token stops working (empty if it never expires)
+     */
+    "expires_at"?: string;
+
+    /**
+     * When the token was revoked (empty if it is still valid)
+     */
+    "revoked_at"?: string;
+
+    /** Creates a new APIToken instance. */
+    constructor($$source: Partial<APIToken> = {}) {
+        if (!("token_id" in $$source)) {
+            this["token_id"] = "";
+        }
+        if (!("name" in $$source)) {
+            this["name"] = "";
+        }
+        if (!("secret_hash" in $$source)) {
+            this["secret_hash"] = "";
+        }
+        if (!("vault_ids" in $$source)) {
+            this["vault_ids"] = [];
+        }
+        if (!("access" in $$source)) {
+            this["access"] = "";
+        }
+        if (!("created_at" in $$source)) {
+            this["created_at"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new APIToken instance from a string or object.
+     */
+    static createFrom($$source: any = {}): APIToken {
+        const $$createField3_0 = $$createType0;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("vault_ids" in $$parsedSource) {
+            $$parsedSource["vault_ids"] = $$createField3_0($$parsedSource["vault_ids"]);
+        }
+        return new APIToken($$parsedSource as Partial<APIToken>);
+    }
+}
+
+/**
+ * BrowserPairing is a browser extension the user has paired with. The extension stores the pairing secret and
+ * sends it with every request, since the extension ID the browser reports can be claimed by any local process.
+ * Only a hash of the secret is stored.
+ */
+export class BrowserPairing {
+    /**
+     * chrome-extension:// origin or Firefox extension ID, as reported by the browser
+     */
+    "extension": string;
+
+    /**
+     * SHA-256 of the pairing secret
+     */
+    "secret_hash": string;
+    "paired_at": string;
+
+    /** Creates a new BrowserPairing instance. */
+    constructor($$source: Partial<BrowserPairing> = {}) {
+        if (!("extension" in $$source)) {
+            this["extension"] = "";
+        }
+        if (!("secret_hash" in $$source)) {
+            this["secret_hash"] = "";
+        }
+        if (!("paired_at" in $$source)) {
+            this["paired_at"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new BrowserPairing instance from a string or object.
+     */
+    static createFrom($$source: any = {}): BrowserPairing {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new BrowserPairing($$parsedSource as Partial<BrowserPairing>);
+    }
+}
+
+export class CreditCardDetails {
+    "cardholder": string;
+    "number": string;
+
+    /**
+     * Expiry in MM/YYYY format
+     */
+    "expiry": string;
+    "cvv": string;
+    "pin"?: string;
+
+    /** Creates a new CreditCardDetails instance. */
+    constructor($$source: Partial<CreditCardDetails> = {}) {
+        if (!("cardholder" in $$source)) {
+            this["cardholder"] = "";
+        }
+        if (!("number" in $$source)) {
+            this["number"] = "";
+        }
+        if (!("expiry" in $$source)) {
+            this["expiry"] = "";
+        }
+        if (!("cvv" in $$source)) {
+            this["cvv"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new CreditCardDetails instance from a string or object.
+     */
+    static createFrom($$source: any = {}): CreditCardDetails {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new CreditCardDetails($$parsedSource as Partial<CreditCardDetails>);
+    }
+}
+
+/**
+ * Field is a single custom value stored on an item
+ */
+export class Field {
+    "id": string;
+    "label": string;
+    "type": FieldType;
+    "value": string;
+
+    /**
+     * Concealed values are only returned when explicitly requested
+     */
+    "concealed": boolean;
+
+    /**
+     * Set on fields returned with their concealed value removed
+     */
+    "redacted"?: boolean;
+
+    /** Creates a new Field instance. */
+    constructor($$source: Partial<Field> = {}) {
+        if (!("id" in $$source)) {
+            this["id"] = "";
+        }
+        if (!("label" in $$source)) {
+            this["label"] = "";
+        }
+        if (!("type" in $$source)) {
+            this["type"] = "";
+        }
+        if (!("value" in $$source)) {
+            this["value"] = "";
+        }
+        if (!("concealed" in $$source)) {
+            this["concealed"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Field instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Field {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new Field($$parsedSource as Partial<Field>);
+    }
+}
+
+/**
+ * FieldType determines how a custom field value is validated and displayed
+ */
+export type FieldType = string;
+
+/**
+ * Folder groups items within a single vault. Folders are stored in the encrypted vault metadata.
+ */
+export class Folder {
+    "id": string;
+    "name": string;
+
+    /** Creates a new Folder instance. */
+    constructor($$source: Partial<Folder> = {}) {
+        if (!("id" in $$source)) {
+            this["id"] = "";
+        }
+        if (!("name" in $$source)) {
+            this["name"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Folder instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Folder {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new Folder($$parsedSource as Partial<Folder>);
+    }
+}
+
+export class IdentityDetails {
+    "first_name": string;
+    "last_name": string;
+    "email": string;
+    "phone": string;
+    "address1": string;
+    "address2": string;
+    "city": string;
+    "state": string;
+    "postal_code": string;
+    "country": string;
+
+    /** Creates a new IdentityDetails instance. */
+    constructor($$source: Partial<IdentityDetails> = {}) {
+        if (!("first_name" in $$source)) {
+            this["first_name"] = "";
+        }
+        if (!("last_name" in $$source)) {
+            this["last_name"] = "";
+        }
+        if (!("email" in $$source)) {
+            this["email"] = "";
+        }
+        if (!("phone" in $$source)) {
+            this["phone"] = "";
+        }
+        if (!("address1" in $$source)) {
+            this["address1"] = "";
+        }
+        if (!("address2" in $$source)) {
+            this["address2"] = "";
+        }
+        if (!("city" in $$source)) {
+            this["city"] = "";
+        }
+        if (!("state" in $$source)) {
+            this["state"] = "";
+        }
+        if (!("postal_code" in $$source)) {
+            this["postal_code"] = "";
+        }
+        if (!("country" in $$source)) {
+            this["country"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new IdentityDetails instance from a string or object.
+     */
+    static createFrom($$source: any = {}): IdentityDetails {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new IdentityDetails($$parsedSource as Partial<IdentityDetails>);
+    }
+}
+
+/**
+ * ItemCategory is the kind of item, which determines the detail schema used
+ */
+export type ItemCategory = string;
+
+/**
+ * ItemFlag marks an item which needs attention. Flags are worked out while the app runs and are never saved.
+ */
+export type ItemFlag = string;
+
+export class SSHKeyDetails {
+    "key_type": SSHKeyType;
+
+    /**
+     * Unencrypted private key in OpenSSH format. It is protected by the vault key like every other detail.
+     */
+    "private_key": string;
+
+    /**
+     * Public key in authorized_keys format
+     */
+    "public_key": string;
+    "fingerprint": string;
+    "comment": string;
+
+    /**
+     * Whether the private key has been removed from this copy of the details
+     */
+    "redacted"?: boolean;
+
+    /** Creates a new SSHKeyDetails instance. */
+    constructor($$source: Partial<SSHKeyDetails> = {}) {
+        if (!("key_type" in $$source)) {
+            this["key_type"] = "";
+        }
+        if (!("private_key" in $$source)) {
+            this["private_key"] = "";
+        }
+        if (!("public_key" in $$source)) {
+            this["public_key"] = "";
+        }
+        if (!("fingerprint" in $$source)) {
+            this["fingerprint"] = "";
+        }
+        if (!("comment" in $$source)) {
+            this["comment"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new SSHKeyDetails instance from a string or object.
+     */
+    static createFrom($$source: any = {}): SSHKeyDetails {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new SSHKeyDetails($$parsedSource as Partial<SSHKeyDetails>);
+    }
+}
+
+/**
+ * SSHKeyOverview is the public half of an SSH key, stored in the overview so keys can be listed
+ * without decrypting their details.
+ */
+export class SSHKeyOverview {
+    "key_type": SSHKeyType;
+    "public_key": string;
+    "fingerprint": string;
+
+    /** Creates a new SSHKeyOverview instance. */
+    constructor($$source: Partial<SSHKeyOverview> = {}) {
+        if (!("key_type" in $$source)) {
+            this["key_type"] = "";
+        }
+        if (!("public_key" in $$source)) {
+            this["public_key"] = "";
+        }
+        if (!("fingerprint" in $$source)) {
+            this["fingerprint"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new SSHKeyOverview instance from a string or object.
+     */
+    static createFrom($$source: any = {}): SSHKeyOverview {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new SSHKeyOverview($$parsedSource as Partial<SSHKeyOverview>);
+    }
+}
+
+export type SSHKeyType = string;
+
+/**
+ * Section is an ordered, titled group of custom fields
+ */
+export class Section {
+    "id": string;
+    "title": string;
+    "fields": (Field | null)[];
+
+    /** Creates a new Section instance. */
+    constructor($$source: Partial<Section> = {}) {
+        if (!("id" in $$source)) {
+            this["id"] = "";
+        }
+        if (!("title" in $$source)) {
+            this["title"] = "";
+        }
+        if (!("fields" in $$source)) {
+            this["fields"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Section instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Section {
+        const $$createField2_0 = $$createType3;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("fields" in $$parsedSource) {
+            $$parsedSource["fields"] = $$createField2_0($$parsedSource["fields"]);
+        }
+        return new Section($$parsedSource as Partial<Section>);
+    }
+}
+
+/**
+ * Settings are the user-configurable application settings
+ */
+export class Settings {
+    /**
+     * Maximum number of previous versions kept per item (0 keeps every version)
+     */
+    "history_retention": number;
+
+    /**
+     * Number of days trashed items and vaults are kept before being purged (0 keeps them until the trash is emptied)
+     */
+    "trash_retention_days": number;
+
+    /**
+     * Serve the SSH keys in unlocked vaults over an ssh-agent socket
+     */
+    "ssh_agent_enabled": boolean;
+
+    /**
+     * Ask for approval every time the SSH agent is asked to sign with a key
+     */
+    "ssh_agent_confirm": boolean;
+
+    /**
+     * Run the requests of the git, docker, AWS and kubectl credential helpers once the user approves them
+     */
+    "credential_helpers_enabled": boolean;
+
+    /**
+     * Vault that credential helpers store new logins in. If empty, the only unlocked vault is used.
+     */
+    "credential_helper_vault_id": string;
+
+    /**
+     * Provide the freedesktop Secret Service on the session bus (Linux only)
+     */
+    "secret_service_enabled": boolean;
+
+    /**
+     * Vault that holds the items stored through the Secret Service
+     */
+    "secret_service_vault_id": string;
+
+    /**
+     * Answer autofill requests from paired browser extensions
+     */
+    "browser_integration_enabled": boolean;
+
+    /**
+     * Number of days after which the password health report flags unchanged passwords (0 never flags them)
+     */
+    "password_max_age_days": number;
+
+    /**
+     * Local copy of the Have I Been Pwned Pwned Passwords list: a file sorted by hash or a directory of range buckets
+     */
+    "pwned_passwords_path": string;
+
+    /**
+     * Check passwords with the Pwned Passwords range API when there is no local copy. Only the first five
+     * characters of the SHA-1 hash of each password are sent.
+     */
+    "pwned_passwords_online": boolean;
+
+    /**
+     * Serve the local automation API
+     */
+    "api_server_enabled": boolean;
+
+    /**
+     * Loopback address (e.g. 127.0.0.1:8420) the API listens on. If empty, it listens on a Unix socket instead.
+     */
+    "api_server_address": string;
+
+    /** Creates a new Settings instance. */
+    constructor($$source: Partial<Settings> = {}) {
+        if (!("history_retention" in $$source)) {
+            this["history_retention"] = 0;
+        }
+        if (!("trash_retention_days" in $$source)) {
+            this["trash_retention_days"] = 0;
+        }
+        if (!("ssh_agent_enabled" in $$source)) {
+            this["ssh_agent_enabled"] = false;
+        }
+        if (!("ssh_agent_confirm" in $$source)) {
+            this["ssh_agent_confirm"] = false;
+        }
+        if (!("credential_helpers_enabled" in $$source)) {
+            this["credential_helpers_enabled"] = false;
+        }
+        if (!("credential_helper_vault_id" in $$source)) {
+            this["credential_helper_vault_id"] = "";
+        }
+        if (!("secret_service_enabled" in $$source)) {
+            this["secret_service_enabled"] = false;
+        }
+        if (!("secret_service_vault_id" in $$source)) {
+            this["secret_service_vault_id"] = "";
+        }
+        if (!("browser_integration_enabled" in $$source)) {
+            this["browser_integration_enabled"] = false;
+        }
+        if (!("password_max_age_days" in $$source)) {
+            this["password_max_age_days"] = 0;
+        }
+        if (!("pwned_passwords_path" in $$source)) {
+            this["pwned_passwords_path"] = "";
+        }
+        if (!("pwned_passwords_online" in $$source)) {
+            this["pwned_passwords_online"] = false;
+        }
+        if (!("api_server_enabled" in $$source)) {
+            this["api_server_enabled"] = false;
+        }
+        if (!("api_server_address" in $$source)) {
+            this["api_server_address"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new Settings instance from a string or object.
+     */
+    static createFrom($$source: any = {}): Settings {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new Settings($$parsedSource as Partial<Settings>);
+    }
+}
+
+export class VaultItemDetails {
+    "version": number;
+    "category": ItemCategory;
+
+    /**
+     * Login fields
+     */
+    "username": string;
+    "password": string;
+
+    /**
+     * Notes are available for every category and hold the body of secure notes
+     */
+    "notes": string;
+    "card"?: CreditCardDetails | null;
+    "identity"?: IdentityDetails | null;
+    "api"?: APICredentialDetails | null;
+    "ssh"?: SSHKeyDetails | null;
+
+    /**
+     * Ordered sections of custom fields
+     */
+    "sections"?: (Section | null)[];
+
+    /** Creates a new VaultItemDetails instance. */
+    constructor($$source: Partial<VaultItemDetails> = {}) {
+        if (!("version" in $$source)) {
+            this["version"] = 0;
+        }
+        if (!("category" in $$source)) {
+            this["category"] = "";
+        }
+        if (!("username" in $$source)) {
+            this["username"] = "";
+        }
+        if (!("password" in $$source)) {
+            this["password"] = "";
+        }
+        if (!("notes" in $$source)) {
+            this["notes"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new VaultItemDetails instance from a string or object.
+     */
+    static createFrom($$source: any = {}): VaultItemDetails {
+        const $$createField5_0 = $$createType5;
+        const $$createField6_0 = $$createType7;
+        const $$createField7_0 = $$createType9;
+        const $$createField8_0 = $$createType11;
+        const $$createField9_0 = $$createType14;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("card" in $$parsedSource) {
+            $$parsedSource["card"] = $$createField5_0($$parsedSource["card"]);
+        }
+        if ("identity" in $$parsedSource) {
+            $$parsedSource["identity"] = $$createField6_0($$parsedSource["identity"]);
+        }
+        if ("api" in $$parsedSource) {
+            $$parsedSource["api"] = $$createField7_0($$parsedSource["api"]);
+        }
+        if ("ssh" in $$parsedSource) {
+            $$parsedSource["ssh"] = $$createField8_0($$parsedSource["ssh"]);
+        }
+        if ("sections" in $$parsedSource) {
+            $$parsedSource["sections"] = $$createField9_0($$parsedSource["sections"]);
+        }
+        return new VaultItemDetails($$parsedSource as Partial<VaultItemDetails>);
+    }
+}
+
+export class VaultItemOverview {
+    "category": ItemCategory;
+    "title": string;
+    "url": string;
+
+    /**
+     * Public half of the key for SSH key items
+     */
+    "ssh_key"?: SSHKeyOverview | null;
+
+    /**
+     * Hierarchical tags, e.g. "prod/db"
+     */
+    "tags"?: string[];
+    "favorite"?: boolean;
+
+    /**
+     * ID of the folder in the item's vault the item is filed into (empty if unfiled)
+     */
+    "folder_id"?: string;
+
+    /** Creates a new VaultItemOverview instance. */
+    constructor($$source: Partial<VaultItemOverview> = {}) {
+        if (!("category" in $$source)) {
+            this["category"] = "";
+        }
+        if (!("title" in $$source)) {
+            this["title"] = "";
+        }
+        if (!("url" in $$source)) {
+            this["url"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new VaultItemOverview instance from a string or object.
+     */
+    static createFrom($$source: any = {}): VaultItemOverview {
+        const $$createField3_0 = $$createType16;
+        const $$createField4_0 = $$createType0;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("ssh_key" in $$parsedSource) {
+            $$parsedSource["ssh_key"] = $$createField3_0($$parsedSource["ssh_key"]);
+        }
+        if ("tags" in $$parsedSource) {
+            $$parsedSource["tags"] = $$createField4_0($$parsedSource["tags"]);
+        }
+        return new VaultItemOverview($$parsedSource as Partial<VaultItemOverview>);
+    }
+}
+
 export class VaultMetadata {
     "account_id": string;
     "vault_id": string;
@@ -13,6 +722,11 @@ export class VaultMetadata {
     "created_at": string;
     "updated_at": string;
 
+    /**
+     * Folders items in the vault can be filed into
+     */
+    "folders"?: (Folder | null)[];
+
     /** Creates a new VaultMetadata instance. */
     constructor($$source: Partial<VaultMetadata> = {}) {
         if (!("account_id" in $$source)) {
@@ -41,7 +755,33 @@ export class VaultMetadata {
      * Creates a new VaultMetadata instance from a string or object.
      */
     static createFrom($$source: any = {}): VaultMetadata {
+        const $$createField6_0 = $$createType19;
         let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("folders" in $$parsedSource) {
+            $$parsedSource["folders"] = $$createField6_0($$parsedSource["folders"]);
+        }
         return new VaultMetadata($$parsedSource as Partial<VaultMetadata>);
     }
 }
+
+// Private type creation functions
+const $$createType0 = $Create.Array($Create.Any);
+const $$createType1 = Field.createFrom;
+const $$createType2 = $Create.Nullable($$createType1);
+const $$createType3 = $Create.Array($$createType2);
+const $$createType4 = CreditCardDetails.createFrom;
+const $$createType5 = $Create.Nullable($$createType4);
+const $$createType6 = IdentityDetails.createFrom;
+const $$createType7 = $Create.Nullable($$createType6);
+const $$createType8 = APICredentialDetails.createFrom;
+const $$createType9 = $Create.Nullable($$createType8);
+const $$createType10 = SSHKeyDetails.createFrom;
+const $$createType11 = $Create.Nullable($$createType10);
+const $$createType12 = Section.createFrom;
+const $$createType13 = $Create.Nullable($$createType12);
+const $$createType14 = $Create.Array($$createType13);
+const $$createType15 = SSHKeyOverview.createFrom;
+const $$createType16 = $Create.Nullable($$createType15);
+const $$createType17 = Folder.createFrom;
+const $$createType18 = $Create.Nullable($$createType17);
+const $$createType19 = $Create.Array($$createType18);
@@ -8,6 +8,100 @@ import { Create as $Create } from "@wailsio/runtime";
 // eslint-disable-next-line @typescript-eslint/ban-ts-comment
 // @ts-ignore: Unused imports
 import * as cryptolib$0 from "../cryptolib/models.js";
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as exporter$0 from "./internal/exporter/models.js";
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as importer$0 from "./internal/importer/models.js";
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as strength$0 from "./internal/strength/models.js";
+// eslint-disable-next-line @typescript-eslint/ban-ts-comment
+// @ts-ignore: Unused imports
+import * as structs$0 from "./internal/structs/models.js";
+
+export class APIServerStatus {
+    "running": boolean;
+
+    /**
+     * Socket path or loopback address the API listens on
+     */
+    "address": string;
+
+    /**
+     * Path prefix of the current API version
+     */
+    "base_path": string;
+
+    /** Creates a new APIServerStatus instance. */
+    constructor($$source: Partial<APIServerStatus> = {}) {
+        if (!("running" in $$source)) {
+            this["running"] = false;
+        }
+        if (!("address" in $$source)) {
+            this["address"] = "";
+        }
+        if (!("base_path" in $$source)) {
+            this["base_path"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new APIServerStatus instance from a string or object.
+     */
+    static createFrom($$source: any = {}): APIServerStatus {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new APIServerStatus($$parsedSource as Partial<APIServerStatus>);
+    }
+}
+
+export class APITokenOptions {
+    "name": string;
+    "vault_ids": string[];
+
+    /**
+     * "read" or "write"
+     */
+    "access": structs$0.APIAccess;
+
+    /**
+     * RFC 3339 time after which the token stops working (empty for no expiry)
+     */
+    "expires_at": string;
+
+    /** Creates a new APITokenOptions instance. */
+    constructor($$source: Partial<APITokenOptions> = {}) {
+        if (!("name" in $$source)) {
+            this["name"] = "";
+        }
+        if (!("vault_ids" in $$source)) {
+            this["vault_ids"] = [];
+        }
+        if (!("access" in $$source)) {
+            this["access"] = "";
+        }
+        if (!("expires_at" in $$source)) {
+            this["expires_at"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new APITokenOptions instance from a string or object.
+     */
+    static createFrom($$source: any = {}): APITokenOptions {
+        const $$createField1_0 = $$createType0;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("vault_ids" in $$parsedSource) {
+            $$parsedSource["vault_ids"] = $$createField1_0($$parsedSource["vault_ids"]);
+        }
+        return new APITokenOptions($$parsedSource as Partial<APITokenOptions>);
+    }
+}
 
 export class AccountWithUnlockStatus {
     "id": string;
@@ -50,15 +144,320 @@ export class AccountWithUnlockStatus {
     }
 }
 
+export class BreachReport {
+    /**
+     * Number of logins with a password which were checked
+     */
+    "checked": number;
+    "breached": (BreachedPassword | null)[];
+
+    /** Creates a new BreachReport instance. */
+    constructor($$source: Partial<BreachReport> = {}) {
+        if (!("checked" in $$source)) {
+            this["checked"] = 0;
+        }
+        if (!("breached" in $$source)) {
+            this["breached"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new BreachReport instance from a string or object.
+     */
+    static createFrom($$source: any = {}): BreachReport {
+        const $$createField1_0 = $$createType3;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("breached" in $$parsedSource) {
+            $$parsedSource["breached"] = $$createField1_0($$parsedSource["breached"]);
+        }
+        return new BreachReport($$parsedSource as Partial<BreachReport>);
+    }
+}
+
+export class BreachedPassword {
+    "item_id": string;
+    "vault_id": string;
+    "title": string;
+
+    /**
+     * Number of times the password was seen in breaches
+     */
+    "count": number;
+
+    /** Creates a new BreachedPassword instance. */
+    constructor($$source: Partial<BreachedPassword> = {}) {
+        if (!("item_id" in $$source)) {
+            this["item_id"] = "";
+        }
+        if (!("vault_id" in $$source)) {
+            this["vault_id"] = "";
+        }
+        if (!("title" in $$source)) {
+            this["title"] = "";
+        }
+        if (!("count" in $$source)) {
+            this["count"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new BreachedPassword instance from a string or object.
+     */
+    static createFrom($$source: any = {}): BreachedPassword {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new BreachedPassword($$parsedSource as Partial<BreachedPassword>);
+    }
+}
+
+export class CreatedAPIToken {
+    "token_id": string;
+    "name": string;
+
+    /**
+     * SHA-256 of the secret half of the token
+     */
+    "secret_hash": string;
+
+    /**
+     * Vaults the token can access
+     */
+    "vault_ids": string[];
+    "access": structs$0.APIAccess;
+    "created_at": string;
+
+    /**
+     * When the token stops working (empty if it never expires)
+     */
+    "expires_at"?: string;
+
+    /**
+     * When the token was revoked (empty if it is still valid)
+     */
+    "revoked_at"?: string;
+
+    /**
+     * The bearer token. It is only available now, as only a hash of it is stored.
+     */
+    "token": string;
+
+    /** Creates a new CreatedAPIToken instance. */
+    constructor($$source: Partial<CreatedAPIToken> = {}) {
+        if (!("token_id" in $$source)) {
+            this["token_id"] = "";
+        }
+        if (!("name" in $$source)) {
+            this["name"] = "";
+        }
+        if (!("secret_hash" in $$source)) {
+            this["secret_hash"] = "";
+        }
+        if (!("vault_ids" in $$source)) {
+            this["vault_ids"] = [];
+        }
+        if (!("access" in $$source)) {
+            this["access"] = "";
+        }
+        if (!("created_at" in $$source)) {
+            this["created_at"] = "";
+        }
+        if (!("token" in $$source)) {
+            this["token"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new CreatedAPIToken instance from a string or object.
+     */
+    static createFrom($$source: any = {}): CreatedAPIToken {
+        const $$createField3_0 = $$createType0;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("vault_ids" in $$parsedSource) {
+            $$parsedSource["vault_ids"] = $$createField3_0($$parsedSource["vault_ids"]);
+        }
+        return new CreatedAPIToken($$parsedSource as Partial<CreatedAPIToken>);
+    }
+}
+
+export class DecryptedAttachment {
+    "attachment_id": string;
+    "item_id": string;
+    "vault_id": string;
+    "created_at": string;
+    "encrypted_file_key": cryptolib$0.JWE | null;
+    "encrypted_metadata": cryptolib$0.JWE | null;
+    "name": string;
+    "content_type": string;
+    "size": number;
+
+    /** Creates a new DecryptedAttachment instance. */
+    constructor($$source: Partial<DecryptedAttachment> = {}) {
+        if (!("attachment_id" in $$source)) {
+            this["attachment_id"] = "";
+        }
+        if (!("item_id" in $$source)) {
+            this["item_id"] = "";
+        }
+        if (!("vault_id" in $$source)) {
+            this["vault_id"] = "";
+        }
+        if (!("created_at" in $$source)) {
+            this["created_at"] = "";
+        }
+        if (!("encrypted_file_key" in $$source)) {
+            this["encrypted_file_key"] = null;
+        }
+        if (!("encrypted_metadata" in $$source)) {
+            this["encrypted_metadata"] = null;
+        }
+        if (!("name" in $$source)) {
+            this["name"] = "";
+        }
+        if (!("content_type" in $$source)) {
+            this["content_type"] = "";
+        }
+        if (!("size" in $$source)) {
+            this["size"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new DecryptedAttachment instance from a string or object.
+     */
+    static createFrom($$source: any = {}): DecryptedAttachment {
+        const $$createField4_0 = $$createType5;
+        const $$createField5_0 = $$createType5;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("encrypted_file_key" in $$parsedSource) {
+            $$parsedSource["encrypted_file_key"] = $$createField4_0($$parsedSource["encrypted_file_key"]);
+        }
+        if ("encrypted_metadata" in $$parsedSource) {
+            $$parsedSource["encrypted_metadata"] = $$createField5_0($$parsedSource["encrypted_metadata"]);
+        }
+        return new DecryptedAttachment($$parsedSource as Partial<DecryptedAttachment>);
+    }
+}
+
+export class DecryptedItemRevision {
+    "revision_id": string;
+    "item_id": string;
+    "vault_id": string;
+
+    /**
+     * The account which made the edit that replaced this version
+     */
+    "account_id": string;
+
+    /**
+     * When this version was originally written
+     */
+    "created_at": string;
+
+    /**
+     * When this version was replaced
+     */
+    "archived_at": string;
+    "encrypted_overview": cryptolib$0.JWE | null;
+    "encrypted_details": cryptolib$0.JWE | null;
+    "overview": structs$0.VaultItemOverview | null;
+    "details": structs$0.VaultItemDetails | null;
+
+    /** Creates a new DecryptedItemRevision instance. */
+    constructor($$source: Partial<DecryptedItemRevision> = {}) {
+        if (!("revision_id" in $$source)) {
+            this["revision_id"] = "";
+        }
+        if (!("item_id" in $$source)) {
+            this["item_id"] = "";
+        }
+        if (!("vault_id" in $$source)) {
+            this["vault_id"] = "";
+        }
+        if (!("account_id" in $$source)) {
+            this["account_id"] = "";
+        }
+        if (!("created_at" in $$source)) {
+            this["created_at"] = "";
+        }
+        if (!("archived_at" in $$source)) {
+            this["archived_at"] = "";
+        }
+        if (!("encrypted_overview" in $$source)) {
+            this["encrypted_overview"] = null;
+        }
+        if (!("encrypted_details" in $$source)) {
+            this["encrypted_details"] = null;
+        }
+        if (!("overview" in $$source)) {
+            this["overview"] = null;
+        }
+        if (!("details" in $$source)) {
+            this["details"] = null;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new DecryptedItemRevision instance from a string or object.
+     */
+    static createFrom($$source: any = {}): DecryptedItemRevision {
+        const $$createField6_0 = $$createType5;
+        const $$createField7_0 = $$createType5;
+        const $$createField8_0 = $$createType7;
+        const $$createField9_0 = $$createType9;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("encrypted_overview" in $$parsedSource) {
+            $$parsedSource["encrypted_overview"] = $$createField6_0($$parsedSource["encrypted_overview"]);
+        }
+        if ("encrypted_details" in $$parsedSource) {
+            $$parsedSource["encrypted_details"] = $$createField7_0($$parsedSource["encrypted_details"]);
+        }
+        if ("overview" in $$parsedSource) {
+            $$parsedSource["overview"] = $$createField8_0($$parsedSource["overview"]);
+        }
+        if ("details" in $$parsedSource) {
+            $$parsedSource["details"] = $$createField9_0($$parsedSource["details"]);
+        }
+        return new DecryptedItemRevision($$parsedSource as Partial<DecryptedItemRevision>);
+    }
+}
+
 export class DecryptedVaultItemDetails {
     "item_id": string;
     "vault_id": string;
     "created_at": string;
     "updated_at": string;
     "encrypted_details": cryptolib$0.JWE | null;
+    "version": number;
+    "category": structs$0.ItemCategory;
+
+    /**
+     * Login fields
+     */
     "username": string;
     "password": string;
+
+    /**
+     * Notes are available for every category and hold the body of secure notes
+     */
     "notes": string;
+    "card"?: structs$0.CreditCardDetails | null;
+    "identity"?: structs$0.IdentityDetails | null;
+    "api"?: structs$0.APICredentialDetails | null;
+    "ssh"?: structs$0.SSHKeyDetails | null;
+
+    /**
+     * Ordered sections of custom fields
+     */
+    "sections"?: (structs$0.Section | null)[];
 
     /** Creates a new DecryptedVaultItemDetails instance. */
     constructor($$source: Partial<DecryptedVaultItemDetails> = {}) {
@@ -77,6 +476,12 @@ export class DecryptedVaultItemDetails {
         if (!("encrypted_details" in $$source)) {
             this["encrypted_details"] = null;
         }
+        if (!("version" in $$source)) {
+            this["version"] = 0;
+        }
+        if (!("category" in $$source)) {
+            this["category"] = "";
+        }
         if (!("username" in $$source)) {
             this["username"] = "";
         }
@@ -94,11 +499,31 @@ export class DecryptedVaultItemDetails {
      * Creates a new DecryptedVaultItemDetails instance from a string or object.
      */
     static createFrom($$source: any = {}): DecryptedVaultItemDetails {
-        const $$createField4_0 = $$createType1;
+        const $$createField4_0 = $$createType5;
+        const $$createField10_0 = $$createType11;
+        const $$createField11_0 = $$createType13;
+        const $$createField12_0 = $$createType15;
+        const $$createField13_0 = $$createType17;
+        const $$createField14_0 = $$createType20;
         let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
         if ("encrypted_details" in $$parsedSource) {
             $$parsedSource["encrypted_details"] = $$createField4_0($$parsedSource["encrypted_details"]);
         }
+        if ("card" in $$parsedSource) {
+            $$parsedSource["card"] = $$createField10_0($$parsedSource["card"]);
+        }
+        if ("identity" in $$parsedSource) {
+            $$parsedSource["identity"] = $$createField11_0($$parsedSource["identity"]);
+        }
+        if ("api" in $$parsedSource) {
+            $$parsedSource["api"] = $$createField12_0($$parsedSource["api"]);
+        }
+        if ("ssh" in $$parsedSource) {
+            $$parsedSource["ssh"] = $$createField13_0($$parsedSource["ssh"]);
+        }
+        if ("sections" in $$parsedSource) {
+            $$parsedSource["sections"] = $$createField14_0($$parsedSource["sections"]);
+        }
         return new DecryptedVaultItemDetails($$parsedSource as Partial<DecryptedVaultItemDetails>);
     }
 }
@@ -109,9 +534,32 @@ export class DecryptedVaultItemOverview {
     "created_at": string;
     "updated_at": string;
     "encrypted_overview": cryptolib$0.JWE | null;
+
+    /**
+     * When the item was moved to the trash (empty if not trashed)
+     */
+    "trashed_at"?: string;
+    "category": structs$0.ItemCategory;
     "title": string;
     "url": string;
 
+    /**
+     * Public half of the key for SSH key items
+     */
+    "ssh_key"?: structs$0.SSHKeyOverview | null;
+
+    /**
+     * Hierarchical tags, e.g. "prod/db"
+     */
+    "tags"?: string[];
+    "favorite"?: boolean;
+
+    /**
+     * ID of the folder in the item's vault the item is filed into (empty if unfiled)
+     */
+    "folder_id"?: string;
+    "flags"?: structs$0.ItemFlag[];
+
     /** Creates a new DecryptedVaultItemOverview instance. */
     constructor($$source: Partial<DecryptedVaultItemOverview> = {}) {
         if (!("item_id" in $$source)) {
@@ -129,6 +577,9 @@ export class DecryptedVaultItemOverview {
         if (!("encrypted_overview" in $$source)) {
             this["encrypted_overview"] = null;
         }
+        if (!("category" in $$source)) {
+            this["category"] = "";
+        }
         if (!("title" in $$source)) {
             this["title"] = "";
         }
@@ -143,15 +594,1361 @@ export class DecryptedVaultItemOverview {
      * Creates a new DecryptedVaultItemOverview instance from a string or object.
      */
     static createFrom($$source: any = {}): DecryptedVaultItemOverview {
-        const $$createField4_0 = $$createType1;
+        const $$createField4_0 = $$createType5;
+        const $$createField9_0 = $$createType22;
+        const $$createField10_0 = $$createType0;
+        const $$createField13_0 = $$createType23;
         let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
         if ("encrypted_overview" in $$parsedSource) {
             $$parsedSource["encrypted_overview"] = $$createField4_0($$parsedSource["encrypted_overview"]);
         }
+        if ("ssh_key" in $$parsedSource) {
+            $$parsedSource["ssh_key"] = $$createField9_0($$parsedSource["ssh_key"]);
+        }
+        if ("tags" in $$parsedSource) {
+            $$parsedSource["tags"] = $$createField10_0($$parsedSource["tags"]);
+        }
+        if ("flags" in $$parsedSource) {
+            $$parsedSource["flags"] = $$createField13_0($$parsedSource["flags"]);
+        }
         return new DecryptedVaultItemOverview($$parsedSource as Partial<DecryptedVaultItemOverview>);
     }
 }
 
+export class ExportOptions {
+    "format": exporter$0.Format;
+
+    /**
+     * Vaults to export
+     */
+    "vault_ids": string[];
+
+    /**
+     * The file to write the export to
+     */
+    "path": string;
+
+    /**
+     * Password the archive is encrypted with (openvault format only)
+     */
+    "export_password": string;
+
+    /**
+     * The account password, re-entered to confirm plaintext exports
+     */
+    "master_password": string;
+
+    /** Creates a new ExportOptions instance. */
+    constructor($$source: Partial<ExportOptions> = {}) {
+        if (!("format" in $$source)) {
+            this["format"] = "";
+        }
+        if (!("vault_ids" in $$source)) {
+            this["vault_ids"] = [];
+        }
+        if (!("path" in $$source)) {
+            this["path"] = "";
+        }
+        if (!("export_password" in $$source)) {
+            this["export_password"] = "";
+        }
+        if (!("master_password" in $$source)) {
+            this["master_password"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ExportOptions instance from a string or object.
+     */
+    static createFrom($$source: any = {}): ExportOptions {
+        const $$createField1_0 = $$createType0;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("vault_ids" in $$parsedSource) {
+            $$parsedSource["vault_ids"] = $$createField1_0($$parsedSource["vault_ids"]);
+        }
+        return new ExportOptions($$parsedSource as Partial<ExportOptions>);
+    }
+}
+
+export class ExportReport {
+    "items": number;
+    "attachments": number;
+
+    /**
+     * Data which cannot be represented in the format and was not exported
+     */
+    "skipped": string[];
+
+    /** Creates a new ExportReport instance. */
+    constructor($$source: Partial<ExportReport> = {}) {
+        if (!("items" in $$source)) {
+            this["items"] = 0;
+        }
+        if (!("attachments" in $$source)) {
+            this["attachments"] = 0;
+        }
+        if (!("skipped" in $$source)) {
+            this["skipped"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ExportReport instance from a string or object.
+     */
+    static createFrom($$source: any = {}): ExportReport {
+        const $$createField2_0 = $$createType0;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("skipped" in $$parsedSource) {
+            $$parsedSource["skipped"] = $$createField2_0($$parsedSource["skipped"]);
+        }
+        return new ExportReport($$parsedSource as Partial<ExportReport>);
+    }
+}
+
+export class HealthItem {
+    "item_id": string;
+    "vault_id": string;
+    "title": string;
+
+    /** Creates a new HealthItem instance. */
+    constructor($$source: Partial<HealthItem> = {}) {
+        if (!("item_id" in $$source)) {
+            this["item_id"] = "";
+        }
+        if (!("vault_id" in $$source)) {
+            this["vault_id"] = "";
+        }
+        if (!("title" in $$source)) {
+            this["title"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new HealthItem instance from a string or object.
+     */
+    static createFrom($$source: any = {}): HealthItem {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new HealthItem($$parsedSource as Partial<HealthItem>);
+    }
+}
+
+/**
+ * ImportFolderMode is how the folders of an export which are not imported into a vault of their own are kept
+ */
+export enum ImportFolderMode {
+    /**
+     * The Go zero value for the underlying type of the enum.
+     */
+    $zero = "",
+
+    /**
+     * Each folder becomes a folder of the vault the item is imported into
+     */
+    ImportFoldersAsVaultFolders = "folder",
+
+    /**
+     * Each folder becomes a tag of its items, with nested folders becoming nested tags
+     */
+    ImportFoldersAsTags = "tag",
+
+    /**
+     * Folders are dropped
+     */
+    ImportFoldersIgnored = "none",
+};
+
+export class ImportOptions {
+    "format": importer$0.Format;
+
+    /**
+     * The export file to read
+     */
+    "path": string;
+
+    /**
+     * Password of a password protected export
+     */
+    "password": string;
+
+    /**
+     * Key file of a KeePass database (optional)
+     */
+    "key_file_path": string;
+
+    /**
+     * Field of each column of a generic CSV file, keyed by header name. Without a mapping, columns are recognized
+     * from common header names.
+     */
+    "csv_columns": { [_: string]: importer$0.CSVField };
+
+    /**
+     * Vault which receives the imported items
+     */
+    "vault_id": string;
+
+    /**
+     * Vaults which receive the items of specific folders, keyed by folder name (nested folders are joined with
+     * "/"). KeePass groups are keyed by their top level group, and the groups below it become folders of the
+     * vault. Items in other folders are imported into VaultID.
+     */
+    "folder_vaults": { [_: string]: string };
+
+    /**
+     * How the folders of items imported into VaultID are kept (ImportFoldersAsVaultFolders if empty)
+     */
+    "unmapped_folders": ImportFolderMode;
+
+    /**
+     * Only report what would be imported without changing any vault
+     */
+    "dry_run": boolean;
+
+    /** Creates a new ImportOptions instance. */
+    constructor($$source: Partial<ImportOptions> = {}) {
+        if (!("format" in $$source)) {
+            this["format"] = "";
+        }
+        if (!("path" in $$source)) {
+            this["path"] = "";
+        }
+        if (!("password" in $$source)) {
+            this["password"] = "";
+        }
+        if (!("key_file_path" in $$source)) {
+            this["key_file_path"] = "";
+        }
+        if (!("csv_columns" in $$source)) {
+            this["csv_columns"] = {};
+        }
+        if (!("vault_id" in $$source)) {
+            this["vault_id"] = "";
+        }
+        if (!("folder_vaults" in $$source)) {
+            this["folder_vaults"] = {};
+        }
+        if (!("unmapped_folders" in $$source)) {
+            this["unmapped_folders"] = ImportFolderMode.$zero;
+        }
+        if (!("dry_run" in $$source)) {
+            this["dry_run"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ImportOptions instance from a string or object.
+     */
+    static createFrom($$source: any = {}): ImportOptions {
+        const $$createField4_0 = $$createType24;
+        const $$createField6_0 = $$createType25;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("csv_columns" in $$parsedSource) {
+            $$parsedSource["csv_columns"] = $$createField4_0($$parsedSource["csv_columns"]);
+        }
+        if ("folder_vaults" in $$parsedSource) {
+            $$parsedSource["folder_vaults"] = $$createField6_0($$parsedSource["folder_vaults"]);
+        }
+        return new ImportOptions($$parsedSource as Partial<ImportOptions>);
+    }
+}
+
+export class ImportReport {
+    "dry_run": boolean;
+
+    /**
+     * Items in export order, including CSV rows which could not be read
+     */
+    "items": (ImportReportItem | null)[];
+
+    /**
+     * Number of items which were imported (or would be, for a dry run)
+     */
+    "imported": number;
+
+    /**
+     * Data in the export which cannot be represented and is not imported
+     */
+    "skipped": string[];
+
+    /**
+     * Folders without a vault in FolderVaults, whose items are imported into VaultID as set by
+     * ImportOptions.UnmappedFolders
+     */
+    "unmapped_folders": string[];
+
+    /** Creates a new ImportReport instance. */
+    constructor($$source: Partial<ImportReport> = {}) {
+        if (!("dry_run" in $$source)) {
+            this["dry_run"] = false;
+        }
+        if (!("items" in $$source)) {
+            this["items"] = [];
+        }
+        if (!("imported" in $$source)) {
+            this["imported"] = 0;
+        }
+        if (!("skipped" in $$source)) {
+            this["skipped"] = [];
+        }
+        if (!("unmapped_folders" in $$source)) {
+            this["unmapped_folders"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ImportReport instance from a string or object.
+     */
+    static createFrom($$source: any = {}): ImportReport {
+        const $$createField1_0 = $$createType28;
+        const $$createField3_0 = $$createType0;
+        const $$createField4_0 = $$createType0;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("items" in $$parsedSource) {
+            $$parsedSource["items"] = $$createField1_0($$parsedSource["items"]);
+        }
+        if ("skipped" in $$parsedSource) {
+            $$parsedSource["skipped"] = $$createField3_0($$parsedSource["skipped"]);
+        }
+        if ("unmapped_folders" in $$parsedSource) {
+            $$parsedSource["unmapped_folders"] = $$createField4_0($$parsedSource["unmapped_folders"]);
+        }
+        return new ImportReport($$parsedSource as Partial<ImportReport>);
+    }
+}
+
+/**
+ * ImportReportItem is an item found in an export, or a row of a CSV file which could not be read
+ */
+export class ImportReportItem {
+    /**
+     * Line of the CSV row the item was read from (zero for other formats)
+     */
+    "row"?: number;
+    "title": string;
+    "category": structs$0.ItemCategory;
+    "folder": string;
+
+    /**
+     * Vault the item is imported into
+     */
+    "vault_id": string;
+
+    /**
+     * Number of attachments and earlier versions imported with the item
+     */
+    "attachments": number;
+    "revisions": number;
+
+    /**
+     * Why the item cannot be imported (empty if it can)
+     */
+    "error"?: string;
+
+    /** Creates a new ImportReportItem instance. */
+    constructor($$source: Partial<ImportReportItem> = {}) {
+        if (!("title" in $$source)) {
+            this["title"] = "";
+        }
+        if (!("category" in $$source)) {
+            this["category"] = "";
+        }
+        if (!("folder" in $$source)) {
+            this["folder"] = "";
+        }
+        if (!("vault_id" in $$source)) {
+            this["vault_id"] = "";
+        }
+        if (!("attachments" in $$source)) {
+            this["attachments"] = 0;
+        }
+        if (!("revisions" in $$source)) {
+            this["revisions"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ImportReportItem instance from a string or object.
+     */
+    static createFrom($$source: any = {}): ImportReportItem {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new ImportReportItem($$parsedSource as Partial<ImportReportItem>);
+    }
+}
+
+/**
+ * InsecureLogin is a login used on a site over unencrypted HTTP
+ */
+export class InsecureLogin {
+    "item_id": string;
+    "vault_id": string;
+    "title": string;
+    "urls": string[];
+
+    /** Creates a new InsecureLogin instance. */
+    constructor($$source: Partial<InsecureLogin> = {}) {
+        if (!("item_id" in $$source)) {
+            this["item_id"] = "";
+        }
+        if (!("vault_id" in $$source)) {
+            this["vault_id"] = "";
+        }
+        if (!("title" in $$source)) {
+            this["title"] = "";
+        }
+        if (!("urls" in $$source)) {
+            this["urls"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new InsecureLogin instance from a string or object.
+     */
+    static createFrom($$source: any = {}): InsecureLogin {
+        const $$createField3_0 = $$createType0;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("urls" in $$parsedSource) {
+            $$parsedSource["urls"] = $$createField3_0($$parsedSource["urls"]);
+        }
+        return new InsecureLogin($$parsedSource as Partial<InsecureLogin>);
+    }
+}
+
+export class ItemDetailsOptions {
+    /**
+     * Return the values of concealed custom fields and SSH private keys
+     */
+    "reveal_concealed": boolean;
+
+    /**
+     * Only return the custom field with this ID (including its value, even if concealed)
+     */
+    "field_id": string;
+
+    /** Creates a new ItemDetailsOptions instance. */
+    constructor($$source: Partial<ItemDetailsOptions> = {}) {
+        if (!("reveal_concealed" in $$source)) {
+            this["reveal_concealed"] = false;
+        }
+        if (!("field_id" in $$source)) {
+            this["field_id"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ItemDetailsOptions instance from a string or object.
+     */
+    static createFrom($$source: any = {}): ItemDetailsOptions {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new ItemDetailsOptions($$parsedSource as Partial<ItemDetailsOptions>);
+    }
+}
+
+/**
+ * ItemFilter narrows down the items returned by ListAllItemOverviews. Empty fields match every item.
+ */
+export class ItemFilter {
+    /**
+     * Only return items with this tag or a tag nested under it
+     */
+    "tag": string;
+
+    /**
+     * Only return favorite items
+     */
+    "favorites": boolean;
+
+    /**
+     * Only return items filed into this folder
+     */
+    "folder_id": string;
+
+    /** Creates a new ItemFilter instance. */
+    constructor($$source: Partial<ItemFilter> = {}) {
+        if (!("tag" in $$source)) {
+            this["tag"] = "";
+        }
+        if (!("favorites" in $$source)) {
+            this["favorites"] = false;
+        }
+        if (!("folder_id" in $$source)) {
+            this["folder_id"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ItemFilter instance from a string or object.
+     */
+    static createFrom($$source: any = {}): ItemFilter {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new ItemFilter($$parsedSource as Partial<ItemFilter>);
+    }
+}
+
+export class OTPAccount {
+    "issuer": string;
+    "account_name": string;
+
+    /**
+     * otpauth:// URI to store in a one-time password field
+     */
+    "uri": string;
+
+    /** Creates a new OTPAccount instance. */
+    constructor($$source: Partial<OTPAccount> = {}) {
+        if (!("issuer" in $$source)) {
+            this["issuer"] = "";
+        }
+        if (!("account_name" in $$source)) {
+            this["account_name"] = "";
+        }
+        if (!("uri" in $$source)) {
+            this["uri"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new OTPAccount instance from a string or object.
+     */
+    static createFrom($$source: any = {}): OTPAccount {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new OTPAccount($$parsedSource as Partial<OTPAccount>);
+    }
+}
+
+export class OldPassword {
+    "item_id": string;
+    "vault_id": string;
+    "title": string;
+    "updated_at": string;
+    "age_days": number;
+
+    /** Creates a new OldPassword instance. */
+    constructor($$source: Partial<OldPassword> = {}) {
+        if (!("item_id" in $$source)) {
+            this["item_id"] = "";
+        }
+        if (!("vault_id" in $$source)) {
+            this["vault_id"] = "";
+        }
+        if (!("title" in $$source)) {
+            this["title"] = "";
+        }
+        if (!("updated_at" in $$source)) {
+            this["updated_at"] = "";
+        }
+        if (!("age_days" in $$source)) {
+            this["age_days"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new OldPassword instance from a string or object.
+     */
+    static createFrom($$source: any = {}): OldPassword {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new OldPassword($$parsedSource as Partial<OldPassword>);
+    }
+}
+
+export class PasswordHealthReport {
+    /**
+     * Number of logins with a password which were checked
+     */
+    "checked": number;
+
+    /**
+     * Passwords scoring below strength.ScoreSafelyUnguessable
+     */
+    "weak": (WeakPassword | null)[];
+    "reused": (ReusedPassword | null)[];
+
+    /**
+     * Passwords unchanged for longer than the PasswordMaxAgeDays setting
+     */
+    "old": (OldPassword | null)[];
+    "insecure": (InsecureLogin | null)[];
+
+    /** Creates a new PasswordHealthReport instance. */
+    constructor($$source: Partial<PasswordHealthReport> = {}) {
+        if (!("checked" in $$source)) {
+            this["checked"] = 0;
+        }
+        if (!("weak" in $$source)) {
+            this["weak"] = [];
+        }
+        if (!("reused" in $$source)) {
+            this["reused"] = [];
+        }
+        if (!("old" in $$source)) {
+            this["old"] = [];
+        }
+        if (!("insecure" in $$source)) {
+            this["insecure"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new PasswordHealthReport instance from a string or object.
+     */
+    static createFrom($$source: any = {}): PasswordHealthReport {
+        const $$createField1_0 = $$createType31;
+        const $$createField2_0 = $$createType34;
+        const $$createField3_0 = $$createType37;
+        const $$createField4_0 = $$createType40;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("weak" in $$parsedSource) {
+            $$parsedSource["weak"] = $$createField1_0($$parsedSource["weak"]);
+        }
+        if ("reused" in $$parsedSource) {
+            $$parsedSource["reused"] = $$createField2_0($$parsedSource["reused"]);
+        }
+        if ("old" in $$parsedSource) {
+            $$parsedSource["old"] = $$createField3_0($$parsedSource["old"]);
+        }
+        if ("insecure" in $$parsedSource) {
+            $$parsedSource["insecure"] = $$createField4_0($$parsedSource["insecure"]);
+        }
+        return new PasswordHealthReport($$parsedSource as Partial<PasswordHealthReport>);
+    }
+}
+
+/**
+ * ReusedPassword is a group of items which share the same password
+ */
+export class ReusedPassword {
+    "items": (HealthItem | null)[];
+
+    /** Creates a new ReusedPassword instance. */
+    constructor($$source: Partial<ReusedPassword> = {}) {
+        if (!("items" in $$source)) {
+            this["items"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ReusedPassword instance from a string or object.
+     */
+    static createFrom($$source: any = {}): ReusedPassword {
+        const $$createField0_0 = $$createType43;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("items" in $$parsedSource) {
+            $$parsedSource["items"] = $$createField0_0($$parsedSource["items"]);
+        }
+        return new ReusedPassword($$parsedSource as Partial<ReusedPassword>);
+    }
+}
+
+export class SSHAgentStatus {
+    "running": boolean;
+
+    /**
+     * Value to use for SSH_AUTH_SOCK
+     */
+    "socket_path": string;
+
+    /** Creates a new SSHAgentStatus instance. */
+    constructor($$source: Partial<SSHAgentStatus> = {}) {
+        if (!("running" in $$source)) {
+            this["running"] = false;
+        }
+        if (!("socket_path" in $$source)) {
+            this["socket_path"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new SSHAgentStatus instance from a string or object.
+     */
+    static createFrom($$source: any = {}): SSHAgentStatus {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new SSHAgentStatus($$parsedSource as Partial<SSHAgentStatus>);
+    }
+}
+
+export class SSHKeyGenerateOptions {
+    "title": string;
+    "key_type": structs$0.SSHKeyType;
+
+    /**
+     * RSA modulus size or ECDSA curve size (0 for the default)
+     */
+    "bits": number;
+    "comment": string;
+
+    /** Creates a new SSHKeyGenerateOptions instance. */
+    constructor($$source: Partial<SSHKeyGenerateOptions> = {}) {
+        if (!("title" in $$source)) {
+            this["title"] = "";
+        }
+        if (!("key_type" in $$source)) {
+            this["key_type"] = "";
+        }
+        if (!("bits" in $$source)) {
+            this["bits"] = 0;
+        }
+        if (!("comment" in $$source)) {
+            this["comment"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new SSHKeyGenerateOptions instance from a string or object.
+     */
+    static createFrom($$source: any = {}): SSHKeyGenerateOptions {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new SSHKeyGenerateOptions($$parsedSource as Partial<SSHKeyGenerateOptions>);
+    }
+}
+
+export class SSHKeyImportOptions {
+    "title": string;
+
+    /**
+     * Path to a private key file. Ignored if PrivateKey is set.
+     */
+    "path": string;
+
+    /**
+     * Private key in OpenSSH or PEM format
+     */
+    "private_key": string;
+
+    /**
+     * Passphrase of an encrypted private key
+     */
+    "passphrase": string;
+
+    /**
+     * Comment for the key. When importing from a file, defaults to the comment in the matching .pub file.
+     */
+    "comment": string;
+
+    /** Creates a new SSHKeyImportOptions instance. */
+    constructor($$source: Partial<SSHKeyImportOptions> = {}) {
+        if (!("title" in $$source)) {
+            this["title"] = "";
+        }
+        if (!("path" in $$source)) {
+            this["path"] = "";
+        }
+        if (!("private_key" in $$source)) {
+            this["private_key"] = "";
+        }
+        if (!("passphrase" in $$source)) {
+            this["passphrase"] = "";
+        }
+        if (!("comment" in $$source)) {
+            this["comment"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new SSHKeyImportOptions instance from a string or object.
+     */
+    static createFrom($$source: any = {}): SSHKeyImportOptions {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new SSHKeyImportOptions($$parsedSource as Partial<SSHKeyImportOptions>);
+    }
+}
+
+export class SearchFilters {
+    /**
+     * Only return items in these vaults (all vaults if empty)
+     */
+    "vault_ids": string[];
+
+    /**
+     * Only return items of these categories (all categories if empty)
+     */
+    "categories": structs$0.ItemCategory[];
+    "include_trashed": boolean;
+
+    /**
+     * Number of results to skip
+     */
+    "offset": number;
+
+    /**
+     * Maximum number of results to return (defaults to 50)
+     */
+    "limit": number;
+
+    /** Creates a new SearchFilters instance. */
+    constructor($$source: Partial<SearchFilters> = {}) {
+        if (!("vault_ids" in $$source)) {
+            this["vault_ids"] = [];
+        }
+        if (!("categories" in $$source)) {
+            this["categories"] = [];
+        }
+        if (!("include_trashed" in $$source)) {
+            this["include_trashed"] = false;
+        }
+        if (!("offset" in $$source)) {
+            this["offset"] = 0;
+        }
+        if (!("limit" in $$source)) {
+            this["limit"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new SearchFilters instance from a string or object.
+     */
+    static createFrom($$source: any = {}): SearchFilters {
+        const $$createField0_0 = $$createType0;
+        const $$createField1_0 = $$createType44;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("vault_ids" in $$parsedSource) {
+            $$parsedSource["vault_ids"] = $$createField0_0($$parsedSource["vault_ids"]);
+        }
+        if ("categories" in $$parsedSource) {
+            $$parsedSource["categories"] = $$createField1_0($$parsedSource["categories"]);
+        }
+        return new SearchFilters($$parsedSource as Partial<SearchFilters>);
+    }
+}
+
+export class SearchResult {
+    "item_id": string;
+    "vault_id": string;
+    "created_at": string;
+    "updated_at": string;
+    "encrypted_overview": cryptolib$0.JWE | null;
+
+    /**
+     * When the item was moved to the trash (empty if not trashed)
+     */
+    "trashed_at"?: string;
+    "category": structs$0.ItemCategory;
+    "title": string;
+    "url": string;
+
+    /**
+     * Public half of the key for SSH key items
+     */
+    "ssh_key"?: structs$0.SSHKeyOverview | null;
+
+    /**
+     * Hierarchical tags, e.g. "prod/db"
+     */
+    "tags"?: string[];
+    "favorite"?: boolean;
+
+    /**
+     * ID of the folder in the item's vault the item is filed into (empty if unfiled)
+     */
+    "folder_id"?: string;
+    "flags"?: structs$0.ItemFlag[];
+    "score": number;
+
+    /** Creates a new SearchResult instance. */
+    constructor($$source: Partial<SearchResult> = {}) {
+        if (!("item_id" in $$source)) {
+            this["item_id"] = "";
+        }
+        if (!("vault_id" in $$source)) {
+            this["vault_id"] = "";
+        }
+        if (!("created_at" in $$source)) {
+            this["created_at"] = "";
+        }
+        if (!("updated_at" in $$source)) {
+            this["updated_at"] = "";
+        }
+        if (!("encrypted_overview" in $$source)) {
+            this["encrypted_overview"] = null;
+        }
+        if (!("category" in $$source)) {
+            this["category"] = "";
+        }
+        if (!("title" in $$source)) {
+            this["title"] = "";
+        }
+        if (!("url" in $$source)) {
+            this["url"] = "";
+        }
+        if (!("score" in $$source)) {
+            this["score"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new SearchResult instance from a string or object.
+     */
+    static createFrom($$source: any = {}): SearchResult {
+        const $$createField4_0 = $$createType5;
+        const $$createField9_0 = $$createType22;
+        const $$createField10_0 = $$createType0;
+        const $$createField13_0 = $$createType23;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("encrypted_overview" in $$parsedSource) {
+            $$parsedSource["encrypted_overview"] = $$createField4_0($$parsedSource["encrypted_overview"]);
+        }
+        if ("ssh_key" in $$parsedSource) {
+            $$parsedSource["ssh_key"] = $$createField9_0($$parsedSource["ssh_key"]);
+        }
+        if ("tags" in $$parsedSource) {
+            $$parsedSource["tags"] = $$createField10_0($$parsedSource["tags"]);
+        }
+        if ("flags" in $$parsedSource) {
+            $$parsedSource["flags"] = $$createField13_0($$parsedSource["flags"]);
+        }
+        return new SearchResult($$parsedSource as Partial<SearchResult>);
+    }
+}
+
+export class SearchResults {
+    /**
+     * Number of matching items across every page
+     */
+    "total": number;
+    "items": (SearchResult | null)[];
+
+    /** Creates a new SearchResults instance. */
+    constructor($$source: Partial<SearchResults> = {}) {
+        if (!("total" in $$source)) {
+            this["total"] = 0;
+        }
+        if (!("items" in $$source)) {
+            this["items"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new SearchResults instance from a string or object.
+     */
+    static createFrom($$source: any = {}): SearchResults {
+        const $$createField1_0 = $$createType47;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("items" in $$parsedSource) {
+            $$parsedSource["items"] = $$createField1_0($$parsedSource["items"]);
+        }
+        return new SearchResults($$parsedSource as Partial<SearchResults>);
+    }
+}
+
+export class SecretServiceStatus {
+    "running": boolean;
+
+    /** Creates a new SecretServiceStatus instance. */
+    constructor($$source: Partial<SecretServiceStatus> = {}) {
+        if (!("running" in $$source)) {
+            this["running"] = false;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new SecretServiceStatus instance from a string or object.
+     */
+    static createFrom($$source: any = {}): SecretServiceStatus {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new SecretServiceStatus($$parsedSource as Partial<SecretServiceStatus>);
+    }
+}
+
+export class ShareExportOptions {
+    /**
+     * The recipient's public encryption key as a JWK (see GetPublicKey)
+     */
+    "recipient_public_key": string;
+
+    /**
+     * Optional RFC 3339 time after which the package can no longer be imported
+     */
+    "expires_at": string;
+
+    /**
+     * Whether the package may only be imported once
+     */
+    "one_time": boolean;
+
+    /**
+     * The file to write the share package to
+     */
+    "path": string;
+
+    /** Creates a new ShareExportOptions instance. */
+    constructor($$source: Partial<ShareExportOptions> = {}) {
+        if (!("recipient_public_key" in $$source)) {
+            this["recipient_public_key"] = "";
+        }
+        if (!("expires_at" in $$source)) {
+            this["expires_at"] = "";
+        }
+        if (!("one_time" in $$source)) {
+            this["one_time"] = false;
+        }
+        if (!("path" in $$source)) {
+            this["path"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ShareExportOptions instance from a string or object.
+     */
+    static createFrom($$source: any = {}): ShareExportOptions {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new ShareExportOptions($$parsedSource as Partial<ShareExportOptions>);
+    }
+}
+
+export class ShareImportResult {
+    "item": DecryptedVaultItemOverview | null;
+
+    /**
+     * The email address the sender claimed in the package
+     */
+    "sender_email": string;
+
+    /**
+     * SHA-256 thumbprint of the sender's signing key, to be compared out of band
+     */
+    "sender_fingerprint": string;
+
+    /** Creates a new ShareImportResult instance. */
+    constructor($$source: Partial<ShareImportResult> = {}) {
+        if (!("item" in $$source)) {
+            this["item"] = null;
+        }
+        if (!("sender_email" in $$source)) {
+            this["sender_email"] = "";
+        }
+        if (!("sender_fingerprint" in $$source)) {
+            this["sender_fingerprint"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ShareImportResult instance from a string or object.
+     */
+    static createFrom($$source: any = {}): ShareImportResult {
+        const $$createField0_0 = $$createType49;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("item" in $$parsedSource) {
+            $$parsedSource["item"] = $$createField0_0($$parsedSource["item"]);
+        }
+        return new ShareImportResult($$parsedSource as Partial<ShareImportResult>);
+    }
+}
+
+/**
+ * ShareSender identifies who signed a share package
+ */
+export class ShareSender {
+    /**
+     * The email address the sender claimed in the package
+     */
+    "sender_email": string;
+
+    /**
+     * SHA-256 thumbprint of the sender's signing key, to be compared out of band
+     */
+    "sender_fingerprint": string;
+
+    /** Creates a new ShareSender instance. */
+    constructor($$source: Partial<ShareSender> = {}) {
+        if (!("sender_email" in $$source)) {
+            this["sender_email"] = "";
+        }
+        if (!("sender_fingerprint" in $$source)) {
+            this["sender_fingerprint"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new ShareSender instance from a string or object.
+     */
+    static createFrom($$source: any = {}): ShareSender {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new ShareSender($$parsedSource as Partial<ShareSender>);
+    }
+}
+
+export class TOTPCode {
+    "code": string;
+    "seconds_remaining": number;
+    "period": number;
+
+    /** Creates a new TOTPCode instance. */
+    constructor($$source: Partial<TOTPCode> = {}) {
+        if (!("code" in $$source)) {
+            this["code"] = "";
+        }
+        if (!("seconds_remaining" in $$source)) {
+            this["seconds_remaining"] = 0;
+        }
+        if (!("period" in $$source)) {
+            this["period"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new TOTPCode instance from a string or object.
+     */
+    static createFrom($$source: any = {}): TOTPCode {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new TOTPCode($$parsedSource as Partial<TOTPCode>);
+    }
+}
+
+export class TagCount {
+    "tag": string;
+
+    /**
+     * Number of non-trashed items with the tag or a tag nested under it
+     */
+    "count": number;
+
+    /** Creates a new TagCount instance. */
+    constructor($$source: Partial<TagCount> = {}) {
+        if (!("tag" in $$source)) {
+            this["tag"] = "";
+        }
+        if (!("count" in $$source)) {
+            this["count"] = 0;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new TagCount instance from a string or object.
+     */
+    static createFrom($$source: any = {}): TagCount {
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        return new TagCount($$parsedSource as Partial<TagCount>);
+    }
+}
+
+export class TrashContents {
+    "vaults": (TrashedVault | null)[];
+    "items": (DecryptedVaultItemOverview | null)[];
+
+    /** Creates a new TrashContents instance. */
+    constructor($$source: Partial<TrashContents> = {}) {
+        if (!("vaults" in $$source)) {
+            this["vaults"] = [];
+        }
+        if (!("items" in $$source)) {
+            this["items"] = [];
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new TrashContents instance from a string or object.
+     */
+    static createFrom($$source: any = {}): TrashContents {
+        const $$createField0_0 = $$createType52;
+        const $$createField1_0 = $$createType53;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("vaults" in $$parsedSource) {
+            $$parsedSource["vaults"] = $$createField0_0($$parsedSource["vaults"]);
+        }
+        if ("items" in $$parsedSource) {
+            $$parsedSource["items"] = $$createField1_0($$parsedSource["items"]);
+        }
+        return new TrashContents($$parsedSource as Partial<TrashContents>);
+    }
+}
+
+export class TrashedVault {
+    "account_id": string;
+    "vault_id": string;
+    "name": string;
+    "description": string;
+    "created_at": string;
+    "updated_at": string;
+
+    /**
+     * Folders items in the vault can be filed into
+     */
+    "folders"?: (structs$0.Folder | null)[];
+    "trashed_at": string;
+
+    /** Creates a new TrashedVault instance. */
+    constructor($$source: Partial<TrashedVault> = {}) {
+        if (!("account_id" in $$source)) {
+            this["account_id"] = "";
+        }
+        if (!("vault_id" in $$source)) {
+            this["vault_id"] = "";
+        }
+        if (!("name" in $$source)) {
+            this["name"] = "";
+        }
+        if (!("description" in $$source)) {
+            this["description"] = "";
+        }
+        if (!("created_at" in $$source)) {
+            this["created_at"] = "";
+        }
+        if (!("updated_at" in $$source)) {
+            this["updated_at"] = "";
+        }
+        if (!("trashed_at" in $$source)) {
+            this["trashed_at"] = "";
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new TrashedVault instance from a string or object.
+     */
+    static createFrom($$source: any = {}): TrashedVault {
+        const $$createField6_0 = $$createType56;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("folders" in $$parsedSource) {
+            $$parsedSource["folders"] = $$createField6_0($$parsedSource["folders"]);
+        }
+        return new TrashedVault($$parsedSource as Partial<TrashedVault>);
+    }
+}
+
+export class WeakPassword {
+    "item_id": string;
+    "vault_id": string;
+    "title": string;
+    "strength": strength$0.Result | null;
+
+    /** Creates a new WeakPassword instance. */
+    constructor($$source: Partial<WeakPassword> = {}) {
+        if (!("item_id" in $$source)) {
+            this["item_id"] = "";
+        }
+        if (!("vault_id" in $$source)) {
+            this["vault_id"] = "";
+        }
+        if (!("title" in $$source)) {
+            this["title"] = "";
+        }
+        if (!("strength" in $$source)) {
+            this["strength"] = null;
+        }
+
+        Object.assign(this, $$source);
+    }
+
+    /**
+     * Creates a new WeakPassword instance from a string or object.
+     */
+    static createFrom($$source: any = {}): WeakPassword {
+        const $$createField3_0 = $$createType58;
+        let $$parsedSource = typeof $$source === 'string' ? JSON.parse($$source) : $$source;
+        if ("strength" in $$parsedSource) {
+            $$parsedSource["strength"] = $$createField3_0($$parsedSource["strength"]);
+        }
+        return new WeakPassword($$parsedSource as Partial<WeakPassword>);
+    }
+}
+
 // Private type creation functions
-const $$createType0 = cryptolib$0.JWE.createFrom;
-const $$createType1 = $Create.Nullable($$createType0);
+const $$createType0 = $Create.Array($Create.Any);
+const $$createType1 = BreachedPassword.createFrom;
+const $$createType2 = $Create.Nullable($$createType1);
+const $$createType3 = $Create.Array($$createType2);
+const $$createType4 = cryptolib$0.JWE.createFrom;
+const $$createType5 = $Create.Nullable($$createType4);
+const $$createType6 = structs$0.VaultItemOverview.createFrom;
+const $$createType7 = $Create.Nullable($$createType6);
+const $$createType8 = structs$0.VaultItemDetails.createFrom;
+const $$createType9 = $Create.Nullable($$createType8);
+const $$createType10 = structs$0.CreditCardDetails.createFrom;
+const $$createType11 = $Create.Nullable($$createType10);
+const $$createType12 = structs$0.IdentityDetails.createFrom;
+const $$createType13 = $Create.Nullable($$createType12);
+const $$createType14 = structs$0.APICredentialDetails.createFrom;
+const $$createType15 = $Create.Nullable($$createType14);
+const $$createType16 = structs$0.SSHKeyDetails.createFrom;
+const $$createType17 = $Create.Nullable($$createType16);
+const $$createType18 = structs$0.Section.createFrom;
+const $$createType19 = $Create.Nullable($$createType18);
+const $$createType20 = $Create.Array($$createType19);
+const $$createType21 = structs$0.SSHKeyOverview.createFrom;
+const $$createType22 = $Create.Nullable($$createType21);
+const $$createType23 = $Create.Array($Create.Any);
+const $$createType24 = $Create.Map($Create.Any, $Create.Any);
+const $$createType25 = $Create.Map($Create.Any, $Create.Any);
+const $$createType26 = ImportReportItem.createFrom;
+const $$createType27 = $Create.Nullable($$createType26);
+const $$createType28 = $Create.Array($$createType27);
+const $$createType29 = WeakPassword.createFrom;
+const $$createType30 = $Create.Nullable($$createType29);
+const $$createType31 = $Create.Array($$createType30);
+const $$createType32 = ReusedPassword.createFrom;
+const $$createType33 = $Create.Nullable($$createType32);
+const $$createType34 = $Create.Array($$createType33);
+const $$createType35 = OldPassword.createFrom;
+const $$createType36 = $Create.Nullable($$createType35);
+const $$createType37 = $Create.Array($$createType36);
+const $$createType38 = InsecureLogin.createFrom;
+const $$createType39 = $Create.Nullable($$createType38);
+const $$createType40 = $Create.Array($$createType39);
+const $$createType41 = HealthItem.createFrom;
+const $$createType42 = $Create.Nullable($$createType41);
+const $$createType43 = $Create.Array($$createType42);
+const $$createType44 = $Create.Array($Create.Any);
+const $$createType45 = SearchResult.createFrom;
+const $$createType46 = $Create.Nullable($$createType45);
+const $$createType47 = $Create.Array($$createType46);
+const $$createType48 = DecryptedVaultItemOverview.createFrom;
+const $$createType49 = $Create.Nullable($$createType48);
+const $$createType50 = TrashedVault.createFrom;
+const $$createType51 = $Create.Nullable($$createType50);
+const $$createType52 = $Create.Array($$createType51);
+const $$createType53 = $Create.Array($$createType49);
+const $$createType54 = structs$0.Folder.createFrom;
+const $$createType55 = $Create.Nullable($$createType54);
+const $$createType56 = $Create.Array($$createType55);
+const $$createType57 = strength$0.Result.createFrom;
+const $$createType58 = $Create.Nullable($$createType57);
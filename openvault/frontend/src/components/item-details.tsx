@@ -42,18 +42,53 @@ interface DetailsButtonProps {
   label: string;
   content: string;
   conceal?: boolean;
+  // Loads the value of a field which was returned redacted
+  reveal?: () => Promise<string>;
 }
 
-export function DetailsButton({ label, content, conceal }: DetailsButtonProps) {
+export function DetailsButton({
+  label,
+  content,
+  conceal,
+  reveal
+}: DetailsButtonProps) {
   const [isRevealed, setIsRevealed] = useState(false);
+  const [revealedContent, setRevealedContent] = useState<string | null>(null);
+  const value = revealedContent ?? content;
+
+  const loadContent = async () => {
+    if (!reveal || revealedContent !== null) {
+      return value;
+    }
+    const loaded = await reveal();
+    setRevealedContent(loaded);
+    return loaded;
+  };
 
   const copyToClipboard = () => {
-    Clipboard.SetText(content).then(
-      () => {
-        toast.success(`Copied ${label.toLowerCase()} to clipboard`);
-      },
+    loadContent()
+      .then((text) => Clipboard.SetText(text))
+      .then(
+        () => {
+          toast.success(`Copied ${label.toLowerCase()} to clipboard`);
+        },
+        (err) => {
+          toast.error(`Failed to copy ${label.toLowerCase()} to clipboard`, {
+            description: err.message
+          });
+        }
+      );
+  };
+
+  const toggleRevealed = () => {
+    if (isRevealed) {
+      setIsRevealed(false);
+      return;
+    }
+    loadContent().then(
+      () => setIsRevealed(true),
       (err) => {
-        toast.error(`Failed to copy ${label.toLowerCase()} to clipboard`, {
+        toast.error(`Failed to reveal ${label.toLowerCase()}`, {
           description: err.message
         });
       }
@@ -82,7 +117,7 @@ export function DetailsButton({ label, content, conceal }: DetailsButtonProps) {
                   ))}
               </span>
             ) : (
-              content
+              value
             )}
           </span>
         </div>
@@ -101,7 +136,7 @@ export function DetailsButton({ label, content, conceal }: DetailsButtonProps) {
         </DropdownMenuTrigger>
         <DropdownMenuContent>
           {conceal && (
-            <DropdownMenuItem onClick={() => setIsRevealed((prev) => !prev)}>
+            <DropdownMenuItem onClick={toggleRevealed}>
               {isRevealed ? 'Hide' : 'Reveal'}
             </DropdownMenuItem>
           )}
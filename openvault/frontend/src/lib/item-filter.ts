@@ -0,0 +1,13 @@
+// Item list route params start with this prefix to show the items with a tag
+const TAG_FILTER_PREFIX = 'tag:';
+
+export function tagItemFilter(tag: string) {
+  return TAG_FILTER_PREFIX + tag;
+}
+
+// Returns the tag shown by an item list route param, or null if it does not filter by tag
+export function parseTagItemFilter(itemFilter: string) {
+  return itemFilter.startsWith(TAG_FILTER_PREFIX)
+    ? itemFilter.slice(TAG_FILTER_PREFIX.length)
+    : null;
+}
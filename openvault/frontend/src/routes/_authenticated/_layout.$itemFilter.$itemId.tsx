@@ -5,16 +5,40 @@ import {
 } from '@/components/item-details';
 import { Avatar, AvatarFallback } from '@/components/ui/avatar';
 
-import { CoreService } from '@openvault/openvault';
+import { CoreService, ItemDetailsOptions } from '@openvault/openvault';
 import { createFileRoute } from '@tanstack/react-router';
 
+// Loads the value of a single custom field, which is returned even if it is concealed
+async function revealField(itemId: string, fieldId: string) {
+  const details = await CoreService.GetVaultItemDetails(
+    itemId,
+    new ItemDetailsOptions({ field_id: fieldId })
+  );
+  const field = details?.sections
+    ?.flatMap((section) => section?.fields ?? [])
+    .find((f) => f?.id === fieldId);
+  return field?.value ?? '';
+}
+
+// Loads the private key of an SSH key item
+async function revealPrivateKey(itemId: string) {
+  const details = await CoreService.GetVaultItemDetails(
+    itemId,
+    new ItemDetailsOptions({ reveal_concealed: true })
+  );
+  return details?.ssh?.private_key ?? '';
+}
+
 export const Route = createFileRoute(
   '/_authenticated/_layout/$itemFilter/$itemId'
 )({
   loader: async ({ params }) => {
     const { itemId } = params;
     const overview = await CoreService.GetItemOverview(itemId);
-    const details = await CoreService.GetVaultItemDetails(itemId);
+    const details = await CoreService.GetVaultItemDetails(
+      itemId,
+      new ItemDetailsOptions()
+    );
     if (!details || !overview) {
       throw new Error('Failed to load vault item');
     }
@@ -53,6 +77,43 @@ function RouteComponent() {
         <DetailsButton label="Username" content={details.username} />
         <DetailsButton label="Password" content={details.password} conceal />
       </div>
+      {details.ssh && (
+        <div className="flex flex-col">
+          <DetailsButton label="Public Key" content={details.ssh.public_key} />
+          <DetailsButton
+            label="Private Key"
+            content={details.ssh.private_key}
+            conceal
+            reveal={
+              details.ssh.redacted
+                ? () => revealPrivateKey(overview.item_id)
+                : undefined
+            }
+          />
+        </div>
+      )}
+      {details.sections?.filterNullish().map((section) => (
+        <div key={section.id} className="flex flex-col">
+          {section.title && (
+            <span className="px-4 pb-1 text-xs font-medium text-muted-foreground">
+              {section.title}
+            </span>
+          )}
+          {section.fields.filterNullish().map((field) => (
+            <DetailsButton
+              key={field.id}
+              label={field.label}
+              content={field.value}
+              conceal={field.concealed}
+              reveal={
+                field.redacted
+                  ? () => revealField(overview.item_id, field.id)
+                  : undefined
+              }
+            />
+          ))}
+        </div>
+      ))}
       <div className="flex flex-col">
         <DetailsMarkdown label="Notes" content={details.notes} />
       </div>
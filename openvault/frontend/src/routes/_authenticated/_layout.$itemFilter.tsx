@@ -5,7 +5,8 @@ import {
   ResizablePanel,
   ResizablePanelGroup
 } from '@/components/ui/resizable';
-import { CoreService } from '@openvault/openvault';
+import { parseTagItemFilter } from '@/lib/item-filter';
+import { CoreService, ItemFilter } from '@openvault/openvault';
 import {
   createFileRoute,
   Link,
@@ -17,15 +18,25 @@ import { StickyNote } from 'lucide-react';
 export const Route = createFileRoute('/_authenticated/_layout/$itemFilter')({
   loader: async ({ params }) => {
     const { itemFilter } = params;
+    const tag = parseTagItemFilter(itemFilter);
     let overviews = [];
     if (itemFilter === 'all') {
-      overviews = await CoreService.ListAllItemOverviews();
+      overviews = await CoreService.ListAllItemOverviews(
+        false,
+        new ItemFilter()
+      );
     } else if (itemFilter === 'favorites') {
-      // overviews = await CoreService.GetAllVaultItemOverviews();
-      // TODO: implement favorites
-      throw new Error('Favorites not implemented yet');
+      overviews = await CoreService.ListAllItemOverviews(
+        false,
+        new ItemFilter({ favorites: true })
+      );
+    } else if (tag !== null) {
+      overviews = await CoreService.ListAllItemOverviews(
+        false,
+        new ItemFilter({ tag })
+      );
     } else {
-      overviews = await CoreService.ListVaultItemOverviews(itemFilter);
+      overviews = await CoreService.ListVaultItemOverviews(itemFilter, false);
     }
     return { overviews: overviews.filterNullish() };
   },
@@ -24,8 +24,9 @@ import {
   SidebarTrigger
 } from '@/components/ui/sidebar';
 import { useAccountFilter } from '@/context/account-filter';
+import { tagItemFilter } from '@/lib/item-filter';
 import { cn } from '@/lib/utils';
-import { CoreService } from '@openvault/openvault';
+import { CoreService, TagCount } from '@openvault/openvault';
 import { VaultMetadata } from '@openvault/openvault/internal/structs';
 import { createFileRoute, Link, Outlet } from '@tanstack/react-router';
 import {
@@ -34,6 +35,7 @@ import {
   Lock,
   Plus,
   Star,
+  Tag,
   Users,
   Vault,
   WalletCards
@@ -49,6 +51,7 @@ function RouteComponent() {
   const { activeAccount, allAccounts, setActiveAccountId } = useAccountFilter();
   const [vaultMetadatas, setVaultMetadatas] = useState<VaultMetadata[]>([]);
   const [vaultMetadatasLoading, setVaultMetadatasLoading] = useState(false);
+  const [tags, setTags] = useState<TagCount[]>([]);
 
   useEffect(() => {
     setVaultMetadatasLoading(true);
@@ -66,6 +69,12 @@ function RouteComponent() {
         }
       )
       .finally(() => setVaultMetadatasLoading(false));
+    CoreService.ListTags().then(
+      (tagCounts) => setTags(tagCounts.filterNullish()),
+      (err) => {
+        toast.error(`Failed to load tags: ${err.message}`);
+      }
+    );
   }, [activeAccount, allAccounts]);
 
   return (
@@ -143,6 +152,38 @@ function RouteComponent() {
               </SidebarMenu>
             </SidebarGroupContent>
           </SidebarGroup>
+          {tags.length > 0 && (
+            <SidebarGroup>
+              <SidebarGroupLabel>Tags</SidebarGroupLabel>
+              <SidebarGroupContent>
+                <SidebarMenu>
+                  {tags.map((tag) => (
+                    <SidebarMenuItem key={tag.tag}>
+                      <SidebarMenuButton
+                        tooltip={{
+                          children: tag.tag
+                        }}
+                        asChild
+                      >
+                        <Link
+                          to="/$itemFilter"
+                          params={{ itemFilter: tagItemFilter(tag.tag) }}
+                          activeProps={{ 'data-active': true }}
+                          className="data-[active=true]:bg-accent/50"
+                        >
+                          <Tag />
+                          <span>{tag.tag}</span>
+                          <span className="ml-auto text-xs text-muted-foreground">
+                            {tag.count}
+                          </span>
+                        </Link>
+                      </SidebarMenuButton>
+                    </SidebarMenuItem>
+                  ))}
+                </SidebarMenu>
+              </SidebarGroupContent>
+            </SidebarGroup>
+          )}
         </SidebarContent>
         <SidebarFooter>
           <div className="flex w-full justify-end">
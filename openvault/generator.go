@@ -0,0 +1,93 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/BradHacker/openvault/openvault/internal/generator"
+)
+
+// GetDefaultPasswordOptions returns the options the password generator starts with
+func (a *CoreService) GetDefaultPasswordOptions() generator.PasswordOptions {
+	return generator.DefaultPasswordOptions()
+}
+
+// GetDefaultPassphraseOptions returns the options the passphrase generator starts with
+func (a *CoreService) GetDefaultPassphraseOptions() generator.PassphraseOptions {
+	return generator.DefaultPassphraseOptions()
+}
+
+// GeneratePassword returns a random password along with its estimated entropy
+func (a *CoreService) GeneratePassword(opts generator.PasswordOptions) (*generator.Result, error) {
+	return generator.Password(opts)
+}
+
+// GeneratePassphrase returns a random diceware passphrase along with its estimated entropy. Words are drawn from
+// the EFF large word list.
+func (a *CoreService) GeneratePassphrase(opts generator.PassphraseOptions) (*generator.Result, error) {
+	wordlist, err := generator.EFFWordlist()
+	if err != nil {
+		return nil, fmt.Errorf("invalid passphrase word list: %w", err)
+	}
+	return generator.Passphrase(wordlist, opts)
+}
+
+// GeneratePIN returns a random numeric PIN code along with its estimated entropy
+func (a *CoreService) GeneratePIN(length int) (*generator.Result, error) {
+	return generator.PIN(length)
+}
+
+const generateUsage = "usage: openvault generate <password|passphrase|pin> [options]"
+
+// runGenerate prints a generated password, passphrase or PIN code to stdout and its entropy to stderr
+func runGenerate(core *CoreService, args []string, getenv func(string) string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) == 0 {
+		return fmt.Errorf(generateUsage)
+	}
+	flags := flag.NewFlagSet("generate "+args[0], flag.ContinueOnError)
+	flags.SetOutput(os.Stderr)
+	var generate func() (*generator.Result, error)
+	switch args[0] {
+	case "password":
+		opts := generator.DefaultPasswordOptions()
+		flags.IntVar(&opts.Length, "length", opts.Length, "number of characters")
+		flags.BoolVar(&opts.Lowercase, "lowercase", opts.Lowercase, "include lowercase letters")
+		flags.BoolVar(&opts.Uppercase, "uppercase", opts.Uppercase, "include uppercase letters")
+		flags.BoolVar(&opts.Digits, "digits", opts.Digits, "include digits")
+		flags.BoolVar(&opts.Symbols, "symbols", opts.Symbols, "include symbols")
+		flags.IntVar(&opts.MinLowercase, "min-lowercase", opts.MinLowercase, "minimum number of lowercase letters")
+		flags.IntVar(&opts.MinUppercase, "min-uppercase", opts.MinUppercase, "minimum number of uppercase letters")
+		flags.IntVar(&opts.MinDigits, "min-digits", opts.MinDigits, "minimum number of digits")
+		flags.IntVar(&opts.MinSymbols, "min-symbols", opts.MinSymbols, "minimum number of symbols")
+		flags.BoolVar(&opts.ExcludeAmbiguous, "exclude-ambiguous", opts.ExcludeAmbiguous, "leave out easily confused characters")
+		generate = func() (*generator.Result, error) { return core.GeneratePassword(opts) }
+	case "passphrase":
+		opts := generator.DefaultPassphraseOptions()
+		flags.IntVar(&opts.Words, "words", opts.Words, "number of words")
+		flags.StringVar(&opts.Separator, "separator", opts.Separator, "text between words")
+		flags.BoolVar(&opts.Capitalize, "capitalize", opts.Capitalize, "capitalize each word")
+		flags.BoolVar(&opts.IncludeDigit, "digit", opts.IncludeDigit, "append a digit to one of the words")
+		generate = func() (*generator.Result, error) { return core.GeneratePassphrase(opts) }
+	case "pin":
+		length := flags.Int("length", 6, "number of digits")
+		generate = func() (*generator.Result, error) { return core.GeneratePIN(*length) }
+	default:
+		return fmt.Errorf(generateUsage)
+	}
+	if err := flags.Parse(args[1:]); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf(generateUsage)
+	}
+	result, err := generate()
+	if err != nil {
+		return err
+	}
+	// Only the secret goes to stdout so it can be piped
+	fmt.Fprintln(stdout, result.Value)
+	fmt.Fprintf(os.Stderr, "entropy: %.1f bits\n", result.Entropy)
+	return nil
+}
@@ -0,0 +1,182 @@
+package main
+
+import (
+	"cmp"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/BradHacker/openvault/openvault/internal/strength"
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+)
+
+type HealthItem struct {
+	ItemID  string `json:"item_id"`
+	VaultID string `json:"vault_id"`
+	Title   string `json:"title"`
+}
+
+type WeakPassword struct {
+	HealthItem
+	Strength *strength.Result `json:"strength"`
+}
+
+// ReusedPassword is a group of items which share the same password
+type ReusedPassword struct {
+	Items []*HealthItem `json:"items"`
+}
+
+type OldPassword struct {
+	HealthItem
+	UpdatedAt string `json:"updated_at"`
+	AgeDays   int    `json:"age_days"`
+}
+
+// InsecureLogin is a login used on a site over unencrypted HTTP
+type InsecureLogin struct {
+	HealthItem
+	URLs []string `json:"urls"`
+}
+
+type PasswordHealthReport struct {
+	// Number of logins with a password which were checked
+	Checked int `json:"checked"`
+	// Passwords scoring below strength.ScoreSafelyUnguessable
+	Weak   []*WeakPassword   `json:"weak"`
+	Reused []*ReusedPassword `json:"reused"`
+	// Passwords unchanged for longer than the PasswordMaxAgeDays setting
+	Old      []*OldPassword   `json:"old"`
+	Insecure []*InsecureLogin `json:"insecure"`
+}
+
+// GetPasswordHealthReport audits the logins in every unlocked vault for weak, reused and old passwords and for
+// URLs without HTTPS. Reuse is found by comparing HMACs of the passwords under a random key which only exists for
+// the duration of the call, so no comparable form of a password outlives the report or is written to disk.
+func (a *CoreService) GetPasswordHealthReport() (*PasswordHealthReport, error) {
+	a.state.mu.RLock()
+	defer a.state.mu.RUnlock()
+	if a.isLocked() {
+		return nil, fmt.Errorf("application not unlocked")
+	}
+	reuseKey := make([]byte, 32)
+	if _, err := rand.Read(reuseKey); err != nil {
+		return nil, fmt.Errorf("failed to generate password comparison key: %w", err)
+	}
+	defer clear(reuseKey)
+
+	report := &PasswordHealthReport{
+		Weak:     make([]*WeakPassword, 0),
+		Reused:   make([]*ReusedPassword, 0),
+		Old:      make([]*OldPassword, 0),
+		Insecure: make([]*InsecureLogin, 0),
+	}
+	maxAge := time.Duration(a.state.Settings.PasswordMaxAgeDays) * 24 * time.Hour
+	now := time.Now()
+	byPassword := make(map[string][]*HealthItem)
+	for _, overview := range a.unlockedItemOverviews() {
+		if overview.Category != structs.CategoryLogin {
+			continue
+		}
+		details, err := a.getVaultItemDetails(overview.ItemID, ItemDetailsOptions{})
+		if err != nil {
+			return nil, err
+		}
+		item := &HealthItem{ItemID: overview.ItemID, VaultID: overview.VaultID, Title: overview.Title}
+		if urls := insecureURLs(overview.VaultItemOverview, details.VaultItemDetails); len(urls) > 0 {
+			report.Insecure = append(report.Insecure, &InsecureLogin{HealthItem: *item, URLs: urls})
+		}
+		if details.Password == "" {
+			continue
+		}
+		report.Checked++
+
+		userInputs := []string{overview.Title, details.Username}
+		if u, err := url.Parse(overview.URL); err == nil && u.Hostname() != "" {
+			userInputs = append(userInputs, u.Hostname())
+		}
+		if result := strength.Estimate(details.Password, userInputs...); result.Score < strength.ScoreSafelyUnguessable {
+			report.Weak = append(report.Weak, &WeakPassword{HealthItem: *item, Strength: result})
+		}
+
+		mac := hmac.New(sha256.New, reuseKey)
+		mac.Write([]byte(details.Password))
+		sum := string(mac.Sum(nil))
+		byPassword[sum] = append(byPassword[sum], item)
+
+		if maxAge > 0 {
+			updatedAt := details.UpdatedAt
+			if updatedAt == "" {
+				updatedAt = details.CreatedAt
+			}
+			if t, err := time.Parse(time.RFC3339, updatedAt); err == nil && now.Sub(t) > maxAge {
+				report.Old = append(report.Old, &OldPassword{
+					HealthItem: *item,
+					UpdatedAt:  updatedAt,
+					AgeDays:    int(now.Sub(t).Hours() / 24),
+				})
+			}
+		}
+	}
+	for _, items := range byPassword {
+		if len(items) > 1 {
+			slices.SortFunc(items, compareHealthItems)
+			report.Reused = append(report.Reused, &ReusedPassword{Items: items})
+		}
+	}
+
+	// Worst first, then by title so the report is stable
+	slices.SortFunc(report.Weak, func(x, y *WeakPassword) int {
+		if x.Strength.GuessesLog10 != y.Strength.GuessesLog10 {
+			return cmp.Compare(x.Strength.GuessesLog10, y.Strength.GuessesLog10)
+		}
+		return compareHealthItems(&x.HealthItem, &y.HealthItem)
+	})
+	slices.SortFunc(report.Reused, func(x, y *ReusedPassword) int {
+		if len(x.Items) != len(y.Items) {
+			return len(y.Items) - len(x.Items)
+		}
+		return compareHealthItems(x.Items[0], y.Items[0])
+	})
+	slices.SortFunc(report.Old, func(x, y *OldPassword) int {
+		if x.AgeDays != y.AgeDays {
+			return y.AgeDays - x.AgeDays
+		}
+		return compareHealthItems(&x.HealthItem, &y.HealthItem)
+	})
+	slices.SortFunc(report.Insecure, func(x, y *InsecureLogin) int {
+		return compareHealthItems(&x.HealthItem, &y.HealthItem)
+	})
+	return report, nil
+}
+
+// insecureURLs returns the http:// URLs of a login, from its overview and its URL custom fields
+func insecureURLs(overview *structs.VaultItemOverview, details *structs.VaultItemDetails) []string {
+	var urls []string
+	isInsecure := func(rawURL string) bool {
+		u, err := url.Parse(strings.TrimSpace(rawURL))
+		return err == nil && strings.EqualFold(u.Scheme, "http") && u.Host != ""
+	}
+	if isInsecure(overview.URL) {
+		urls = append(urls, overview.URL)
+	}
+	for _, section := range details.Sections {
+		for _, field := range section.Fields {
+			if field.Type == structs.FieldTypeURL && isInsecure(field.Value) && !slices.Contains(urls, field.Value) {
+				urls = append(urls, field.Value)
+			}
+		}
+	}
+	return urls
+}
+
+func compareHealthItems(x, y *HealthItem) int {
+	if c := strings.Compare(strings.ToLower(x.Title), strings.ToLower(y.Title)); c != 0 {
+		return c
+	}
+	return strings.Compare(x.ItemID, y.ItemID)
+}
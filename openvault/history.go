@@ -0,0 +1,120 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+)
+
+type DecryptedItemRevision struct {
+	*structs.EncryptedItemRevision
+	Overview *structs.VaultItemOverview `json:"overview"`
+	Details  *structs.VaultItemDetails  `json:"details"`
+}
+
+// ListItemHistory returns the previous versions of an item, newest first. Concealed custom fields and SSH private
+// keys are redacted as in GetVaultItemDetails. With opts.FieldID set, only versions containing that field are returned.
+func (a *CoreService) ListItemHistory(itemId string, opts ItemDetailsOptions) ([]*DecryptedItemRevision, error) {
+	a.state.mu.RLock()
+	defer a.state.mu.RUnlock()
+	if a.isLocked() {
+		return nil, fmt.Errorf("application not unlocked")
+	}
+	encOverview, ok := a.state.ItemOverviews[itemId]
+	if !ok {
+		return nil, fmt.Errorf("no item overview found for item %s", itemId)
+	}
+	vaultKey, err := a.state.VaultKey(encOverview.VaultID)
+	if err != nil {
+		return nil, err
+	}
+	defer vaultKey.Close()
+	var revisions []*DecryptedItemRevision
+	for _, rev := range slices.Backward(a.state.ItemHistory[itemId]) {
+		overview, details, err := rev.Read(vaultKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt revision %s of item %s: %w", rev.RevisionID, itemId, err)
+		}
+		if details = opts.apply(details); details == nil {
+			continue
+		}
+		revisions = append(revisions, &DecryptedItemRevision{
+			EncryptedItemRevision: rev,
+			Overview:              overview,
+			Details:               details,
+		})
+	}
+	return revisions, nil
+}
+
+// RestoreItemVersion makes a previous version of an item current again. The version being replaced
+// is added to the history, so a restore can itself be undone.
+func (a *CoreService) RestoreItemVersion(itemId string, revisionId string) (*DecryptedVaultItemOverview, error) {
+	a.state.mu.Lock()
+	defer a.state.mu.Unlock()
+	if a.isLocked() {
+		return nil, fmt.Errorf("application not unlocked")
+	}
+	encOverview, ok := a.state.ItemOverviews[itemId]
+	if !ok {
+		return nil, fmt.Errorf("no item overview found for item %s", itemId)
+	}
+	encDetails, ok := a.state.ItemDetails[itemId]
+	if !ok {
+		return nil, fmt.Errorf("no item details found for item %s", itemId)
+	}
+	idx := slices.IndexFunc(a.state.ItemHistory[itemId], func(rev *structs.EncryptedItemRevision) bool {
+		return rev.RevisionID == revisionId
+	})
+	if idx < 0 {
+		return nil, fmt.Errorf("revision %s not found for item %s", revisionId, itemId)
+	}
+	rev := a.state.ItemHistory[itemId][idx]
+	vault, ok := a.state.Vaults[encOverview.VaultID]
+	if !ok {
+		return nil, fmt.Errorf("vault %s not found", encOverview.VaultID)
+	}
+	vaultKey, err := a.state.VaultKey(vault.VaultID)
+	if err != nil {
+		return nil, err
+	}
+	defer vaultKey.Close()
+	// Make sure the revision still decrypts before replacing the current version
+	overview, _, err := rev.Read(vaultKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt revision %s of item %s: %w", revisionId, itemId, err)
+	}
+	current, err := encOverview.Read(vaultKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt item overview for item %s: %w", itemId, err)
+	}
+	// Tags, the favorite flag and the folder are not versioned, so the item keeps its current ones
+	overview.Tags, overview.Favorite, overview.FolderID = nil, false, ""
+	overview.RestoreOrganization(current)
+	// The folder may have been deleted, or belong to the vault the item was moved from
+	if a.checkFolder(vault.VaultID, overview.FolderID) != nil {
+		overview.FolderID = ""
+	}
+	restoredOverview := *encOverview
+	if err := restoredOverview.Update(vaultKey, overview); err != nil {
+		return nil, fmt.Errorf("failed to encrypt item overview: %w", err)
+	}
+	if err := a.state.ArchiveItem(itemId, vault.AccountID); err != nil {
+		return nil, err
+	}
+	*encOverview = restoredOverview
+	encDetails.EncryptedDetails = rev.EncryptedDetails
+	encDetails.UpdatedAt = encOverview.UpdatedAt
+	if err := a.state.SaveItemHistory(); err != nil {
+		return nil, err
+	}
+	if err := a.state.SaveItems(); err != nil {
+		return nil, err
+	}
+	return &DecryptedVaultItemOverview{
+		EncryptedVaultItemOverview: encOverview,
+		VaultItemOverview:          overview,
+		Flags:                      a.itemFlags(itemId),
+	}, nil
+}
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+
+	"github.com/BradHacker/openvault/openvault/internal/importer"
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+
+	"github.com/BradHacker/openvault/cryptolib"
+	"github.com/google/uuid"
+)
+
+// ImportFolderMode is how the folders of an export which are not imported into a vault of their own are kept
+type ImportFolderMode string
+
+const (
+	// Each folder becomes a folder of the vault the item is imported into
+	ImportFoldersAsVaultFolders ImportFolderMode = "folder"
+	// Each folder becomes a tag of its items, with nested folders becoming nested tags
+	ImportFoldersAsTags ImportFolderMode = "tag"
+	// Folders are dropped
+	ImportFoldersIgnored ImportFolderMode = "none"
+)
+
+type ImportOptions struct {
+	Format importer.Format `json:"format"`
+	// The export file to read
+	Path string `json:"path"`
+	// Password of a password protected export
+	Password string `json:"password"`
+	// Key file of a KeePass database (optional)
+	KeyFilePath string `json:"key_file_path"`
+	// Field of each column of a generic CSV file, keyed by header name. Without a mapping, columns are recognized
+	// from common header names.
+	CSVColumns map[string]importer.CSVField `json:"csv_columns"`
+	// Vault which receives the imported items
+	VaultID string `json:"vault_id"`
+	// Vaults which receive the items of specific folders, keyed by folder name (nested folders are joined with
+	// "/"). KeePass groups are keyed by their top level group, and the groups below it become folders of the
+	// vault. Items in other folders are imported into VaultID.
+	FolderVaults map[string]string `json:"folder_vaults"`
+	// How the folders of items imported into VaultID are kept (ImportFoldersAsVaultFolders if empty)
+	UnmappedFolders ImportFolderMode `json:"unmapped_folders"`
+	// Only report what would be imported without changing any vault
+	DryRun bool `json:"dry_run"`
+}
+
+// ImportReportItem is an item found in an export, or a row of a CSV file which could not be read
+type ImportReportItem struct {
+	// Line of the CSV row the item was read from (zero for other formats)
+	Row      int                  `json:"row,omitempty"`
+	Title    string               `json:"title"`
+	Category structs.ItemCategory `json:"category"`
+	Folder   string               `json:"folder"`
+	// Vault the item is imported into
+	VaultID string `json:"vault_id"`
+	// Number of attachments and earlier versions imported with the item
+	Attachments int `json:"attachments"`
+	Revisions   int `json:"revisions"`
+	// Why the item cannot be imported (empty if it can)
+	Error string `json:"error,omitempty"`
+}
+
+type ImportReport struct {
+	DryRun bool `json:"dry_run"`
+	// Items in export order, including CSV rows which could not be read
+	Items []*ImportReportItem `json:"items"`
+	// Number of items which were imported (or would be, for a dry run)
+	Imported int `json:"imported"`
+	// Data in the export which cannot be represented and is not imported
+	Skipped []string `json:"skipped"`
+	// Folders without a vault in FolderVaults, whose items are imported into VaultID as set by
+	// ImportOptions.UnmappedFolders
+	UnmappedFolders []string `json:"unmapped_folders"`
+}
+
+// GetImportFormats returns the export formats which can be imported
+func (a *CoreService) GetImportFormats() []importer.Format {
+	return importer.Formats()
+}
+
+// ImportItems reads an export from another password manager and adds its items to the chosen vaults. Each item
+// is encrypted with the key of the vault it is imported into. With opts.DryRun set, the report describes what
+// would be imported and nothing is saved. Folders kept within a vault by the export, as in OpenVault archives, are
+// created in the destination vault if it has none with the same name.
+func (a *CoreService) ImportItems(opts ImportOptions) (*ImportReport, error) {
+	a.state.mu.RLock()
+	locked := a.isLocked()
+	a.state.mu.RUnlock()
+	if locked {
+		return nil, fmt.Errorf("application not unlocked")
+	}
+	// Parsing may run the export's key derivation, which takes a while, so the state is only locked afterwards
+	data, err := os.ReadFile(opts.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read export: %w", err)
+	}
+	parseOpts := importer.Options{Password: opts.Password, CSVColumns: opts.CSVColumns}
+	if opts.KeyFilePath != "" {
+		if parseOpts.KeyFile, err = os.ReadFile(opts.KeyFilePath); err != nil {
+			return nil, fmt.Errorf("failed to read key file: %w", err)
+		}
+		defer clear(parseOpts.KeyFile)
+	}
+	result, err := importer.Parse(opts.Format, data, parseOpts)
+	if err != nil {
+		return nil, err
+	}
+	clear(data)
+
+	a.state.mu.Lock()
+	defer a.state.mu.Unlock()
+	if a.isLocked() {
+		return nil, fmt.Errorf("application not unlocked")
+	}
+	// Check every destination vault is unlocked before importing anything
+	vaultKeys := make(map[string]*cryptolib.JWK)
+	defer func() {
+		for _, vaultKey := range vaultKeys {
+			vaultKey.Close()
+		}
+	}()
+	for _, vaultId := range append([]string{opts.VaultID}, slices.Collect(maps.Values(opts.FolderVaults))...) {
+		if _, ok := vaultKeys[vaultId]; ok {
+			continue
+		}
+		vault, ok := a.state.Vaults[vaultId]
+		if !ok || vault.IsTrashed() {
+			return nil, fmt.Errorf("vault %s not found", vaultId)
+		}
+		if vaultKeys[vaultId], err = a.state.VaultKey(vaultId); err != nil {
+			return nil, err
+		}
+	}
+
+	report := &ImportReport{
+		DryRun:          opts.DryRun,
+		Items:           make([]*ImportReportItem, 0, len(result.Items)),
+		Skipped:         append([]string{}, result.Skipped...),
+		UnmappedFolders: make([]string, 0),
+	}
+	// Folders created or found in the destination vaults, keyed by vault ID and folder name
+	folderIds := make(map[[2]string]string)
+	for _, item := range result.Items {
+		vaultId, ok := opts.FolderVaults[item.Folder]
+		if !ok {
+			vaultId = opts.VaultID
+			if item.Folder != "" && !slices.Contains(report.UnmappedFolders, item.Folder) {
+				report.UnmappedFolders = append(report.UnmappedFolders, item.Folder)
+			}
+			keepUnmappedFolder(item, opts.UnmappedFolders)
+		}
+		reportItem := &ImportReportItem{
+			Row:         item.Row,
+			Title:       item.Overview.Title,
+			Category:    item.Details.Category,
+			Folder:      item.Folder,
+			VaultID:     vaultId,
+			Attachments: len(item.Attachments),
+			Revisions:   len(item.History),
+		}
+		report.Items = append(report.Items, reportItem)
+		if err := structs.NormalizeItem(&item.Overview, &item.Details); err != nil {
+			reportItem.Error = err.Error()
+			continue
+		}
+		reportItem.Category = item.Details.Category
+		if !opts.DryRun {
+			if item.VaultFolder != "" {
+				if item.Overview.FolderID, err = a.importFolder(vaultId, item.VaultFolder, folderIds); err != nil {
+					report.Skipped = append(report.Skipped, fmt.Sprintf("%q: folder %q: %v", reportItem.Title, item.VaultFolder, err))
+				}
+			}
+			encOverview, _, err := a.state.CreateItem(vaultId, vaultKeys[vaultId], &item.Overview, &item.Details)
+			if err != nil {
+				reportItem.Error = err.Error()
+				continue
+			}
+			a.importItemExtras(encOverview, vaultKeys[vaultId], item, reportItem, report)
+		}
+		report.Imported++
+	}
+	for _, rowErr := range result.Errors {
+		report.Items = append(report.Items, &ImportReportItem{Row: rowErr.Row, Error: rowErr.Message})
+	}
+	slices.SortStableFunc(report.Items, func(x, y *ImportReportItem) int {
+		return cmp.Compare(x.Row, y.Row)
+	})
+	if !opts.DryRun && report.Imported > 0 {
+		if err := a.state.SaveItems(); err != nil {
+			return nil, err
+		}
+		if err := a.state.SaveAttachments(); err != nil {
+			return nil, err
+		}
+		if err := a.state.SaveItemHistory(); err != nil {
+			return nil, err
+		}
+	}
+	return report, nil
+}
+
+// keepUnmappedFolder files an item whose folder has no vault of its own by mode, so its organization is not lost
+func keepUnmappedFolder(item *importer.Item, mode ImportFolderMode) {
+	if item.Folder == "" {
+		return
+	}
+	switch mode {
+	case ImportFoldersAsTags:
+		item.Overview.Tags = append(item.Overview.Tags, item.Folder)
+	case ImportFoldersIgnored:
+	default:
+		if item.VaultFolder == "" {
+			item.VaultFolder = item.Folder
+		} else {
+			item.VaultFolder = item.Folder + "/" + item.VaultFolder
+		}
+	}
+}
+
+// importFolder returns the ID of the folder with the given name in a vault, creating the folder if the vault has
+// none by that name
+func (a *CoreService) importFolder(vaultId string, name string, folderIds map[[2]string]string) (string, error) {
+	key := [2]string{vaultId, name}
+	if folderId, ok := folderIds[key]; ok {
+		return folderId, nil
+	}
+	name, err := structs.NormalizeFolderName(name)
+	if err != nil {
+		return "", err
+	}
+	meta, err := a.getVaultMetadata(vaultId)
+	if err != nil {
+		return "", err
+	}
+	if i := slices.IndexFunc(meta.Folders, func(folder *structs.Folder) bool { return folder.Name == name }); i >= 0 {
+		folderIds[key] = meta.Folders[i].ID
+		return folderIds[key], nil
+	}
+	folder := &structs.Folder{ID: uuid.New().String(), Name: name}
+	if _, err := a.editVaultMetadata(vaultId, func(meta *structs.VaultMetadata) error {
+		meta.Folders = append(meta.Folders, folder)
+		return nil
+	}); err != nil {
+		return "", err
+	}
+	folderIds[key] = folder.ID
+	return folder.ID, nil
+}
+
+// importItemExtras adds the attachments and earlier versions of an imported item. Those which cannot be imported
+// are reported as skipped rather than failing the item.
+func (a *CoreService) importItemExtras(encOverview *structs.EncryptedVaultItemOverview, vaultKey *cryptolib.JWK, item *importer.Item, reportItem *ImportReportItem, report *ImportReport) {
+	for _, attachment := range item.Attachments {
+		if _, err := a.addAttachment(vaultKey, encOverview, attachment.Name, bytes.NewReader(attachment.Data)); err != nil {
+			reportItem.Attachments--
+			report.Skipped = append(report.Skipped, fmt.Sprintf("%q: attachment %q: %v", reportItem.Title, attachment.Name, err))
+		}
+	}
+	accountId := a.state.Vaults[encOverview.VaultID].AccountID
+	for _, revision := range item.History {
+		if err := a.state.ImportRevision(encOverview.ItemID, accountId, vaultKey, &revision.Overview, &revision.Details, revision.ModifiedAt); err != nil {
+			reportItem.Revisions--
+			report.Skipped = append(report.Skipped, fmt.Sprintf("%q: earlier version: %v", reportItem.Title, err))
+		}
+	}
+}
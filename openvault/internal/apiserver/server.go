@@ -0,0 +1,413 @@
+// Package apiserver serves a versioned REST API over a local socket, so scripts can read and change items
+// without linking against openvault. Every request carries a bearer token which is limited to a set of vaults and
+// to either read or write access; the limits are enforced here, before the backend is called.
+package apiserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+)
+
+// Version is the path prefix of every endpoint. Breaking changes to the API get a new version.
+const Version = "v1"
+
+// Largest request body accepted, which is plenty for an item
+const maxBodySize = 1 << 20
+
+var (
+	// ErrNotFound is returned by the backend for vaults and items which do not exist
+	ErrNotFound = errors.New("not found")
+	// ErrLocked is returned by the backend for vaults and items of locked accounts
+	ErrLocked = errors.New("openvault is locked")
+)
+
+// Vault is a vault the token can access
+type Vault struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// Item is an item and, when a single item is read, its details
+type Item struct {
+	ID        string                     `json:"id"`
+	VaultID   string                     `json:"vault_id"`
+	CreatedAt string                     `json:"created_at"`
+	UpdatedAt string                     `json:"updated_at"`
+	TrashedAt string                     `json:"trashed_at,omitempty"`
+	Overview  *structs.VaultItemOverview `json:"overview"`
+	Details   *structs.VaultItemDetails  `json:"details,omitempty"`
+}
+
+// ItemFilter narrows down the items listed in a vault
+type ItemFilter struct {
+	Category       structs.ItemCategory
+	Tag            string
+	Favorites      bool
+	IncludeTrashed bool
+}
+
+// Backend reads and changes the items the API serves. The server checks every call is within the scope of the
+// request's token before making it.
+type Backend interface {
+	// IsLocked returns whether the app is locked. Every request except the token check fails while locked.
+	IsLocked() bool
+	// Token returns the stored token with the given ID
+	Token(tokenId string) (*structs.APIToken, bool)
+	// Vault returns a vault which is not in the trash
+	Vault(vaultId string) (*Vault, error)
+	// Items returns the overviews of the items in a vault which match the filter
+	Items(vaultId string, filter ItemFilter) ([]*Item, error)
+	// ItemVault returns the ID of the vault an item belongs to
+	ItemVault(itemId string) (string, error)
+	// Item returns an item with its details. Concealed values are only included if reveal is set.
+	Item(itemId string, reveal bool) (*Item, error)
+	// CreateItem adds an item to a vault and returns its overview
+	CreateItem(vaultId string, overview *structs.VaultItemOverview, details *structs.VaultItemDetails) (*Item, error)
+	// UpdateItem replaces the overview and details of an item and returns its overview
+	UpdateItem(itemId string, overview *structs.VaultItemOverview, details *structs.VaultItemDetails) (*Item, error)
+	// TrashItem moves an item to the trash
+	TrashItem(itemId string) error
+}
+
+// Server is the HTTP API. It implements http.Handler so it can be tested without a listener.
+type Server struct {
+	backend Backend
+	mux     *http.ServeMux
+	server  *http.Server
+	// Held while a request uses the backend, for writing unless the request only reads
+	lock *sync.RWMutex
+}
+
+// apiError is an error with the HTTP status it is reported with
+type apiError struct {
+	status  int
+	message string
+}
+
+func (e *apiError) Error() string {
+	return e.message
+}
+
+var (
+	errUnauthorized = &apiError{http.StatusUnauthorized, "missing, invalid, expired or revoked token"}
+	errReadOnly     = &apiError{http.StatusForbidden, "the token is read-only"}
+	errVaultScope   = &apiError{http.StatusForbidden, "the token cannot access this vault"}
+	errBrowser      = &apiError{http.StatusForbidden, "requests from web pages are not allowed"}
+)
+
+// tokenResponse describes the token a request was made with
+type tokenResponse struct {
+	Name      string            `json:"name"`
+	Access    structs.APIAccess `json:"access"`
+	VaultIDs  []string          `json:"vault_ids"`
+	ExpiresAt string            `json:"expires_at,omitempty"`
+	Locked    bool              `json:"locked"`
+}
+
+type itemRequest struct {
+	Overview structs.VaultItemOverview `json:"overview"`
+	Details  structs.VaultItemDetails  `json:"details"`
+}
+
+// handlerFunc handles an authenticated request. It returns the value to encode as the response body.
+type handlerFunc func(token *structs.APIToken, r *http.Request) (any, error)
+
+// NewServer creates the API on top of a backend, which is only called while holding lock. It does not listen until
+// Serve is called.
+func NewServer(backend Backend, lock *sync.RWMutex) *Server {
+	s := &Server{backend: backend, mux: http.NewServeMux(), lock: lock}
+	s.route("GET /token", false, s.getToken)
+	s.route("GET /vaults", true, s.listVaults)
+	s.route("GET /vaults/{vault_id}", true, s.getVault)
+	s.route("GET /vaults/{vault_id}/items", true, s.listItems)
+	s.route("POST /vaults/{vault_id}/items", true, s.createItem)
+	s.route("GET /items/{item_id}", true, s.getItem)
+	s.route("PUT /items/{item_id}", true, s.updateItem)
+	s.route("DELETE /items/{item_id}", true, s.trashItem)
+	s.server = &http.Server{Handler: s, ReadHeaderTimeout: 10 * time.Second}
+	return s
+}
+
+// Serve accepts connections until Close is called
+func (s *Server) Serve(listener net.Listener) error {
+	if err := s.server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// Close stops the listener and every open connection
+func (s *Server) Close() error {
+	return s.server.Close()
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// route registers a handler under the API version. Every request must carry a valid token; if unlocked is set
+// the app must also be unlocked.
+func (s *Server) route(pattern string, unlocked bool, handler handlerFunc) {
+	method, path, _ := strings.Cut(pattern, " ")
+	s.mux.HandleFunc(method+" /"+Version+path, func(w http.ResponseWriter, r *http.Request) {
+		// Browsers send an Origin with cross-site requests, which scripts have no reason to do
+		if r.Header.Get("Origin") != "" {
+			writeError(w, errBrowser)
+			return
+		}
+		// The body is read before taking the lock, so a slow client cannot hold up the app
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBodySize))
+		if err != nil {
+			writeError(w, &apiError{http.StatusBadRequest, fmt.Sprintf("failed to read request body: %v", err)})
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		if r.Method == http.MethodGet {
+			s.lock.RLock()
+			defer s.lock.RUnlock()
+		} else {
+			s.lock.Lock()
+			defer s.lock.Unlock()
+		}
+		token, err := s.authenticate(r)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, err)
+			return
+		}
+		if unlocked && s.backend.IsLocked() {
+			writeError(w, ErrLocked)
+			return
+		}
+		resp, err := handler(token, r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+		status := http.StatusOK
+		if r.Method == http.MethodPost {
+			status = http.StatusCreated
+		}
+		if resp == nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		writeJSON(w, status, resp)
+	})
+}
+
+// authenticate returns the token of a request if it is known, active and its secret matches
+func (s *Server) authenticate(r *http.Request) (*structs.APIToken, error) {
+	bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return nil, errUnauthorized
+	}
+	tokenId, secret, err := structs.ParseAPIToken(strings.TrimSpace(bearer))
+	if err != nil {
+		return nil, errUnauthorized
+	}
+	token, ok := s.backend.Token(tokenId)
+	if !ok || !token.Verify(secret) || token.IsRevoked() || token.IsExpired(time.Now()) {
+		return nil, errUnauthorized
+	}
+	return token, nil
+}
+
+// authorizeVault checks the token grants the access to a vault which exists
+func (s *Server) authorizeVault(token *structs.APIToken, vaultId string, access structs.APIAccess) (*Vault, error) {
+	if !token.Allows(vaultId, structs.APIAccessRead) {
+		return nil, errVaultScope
+	}
+	if !token.Allows(vaultId, access) {
+		return nil, errReadOnly
+	}
+	return s.backend.Vault(vaultId)
+}
+
+// authorizeItem checks the token grants the access to the vault of an item. Items in other vaults are reported
+// as not found, so a token cannot be used to find out which items exist.
+func (s *Server) authorizeItem(token *structs.APIToken, itemId string, access structs.APIAccess) error {
+	vaultId, err := s.backend.ItemVault(itemId)
+	if err != nil {
+		return err
+	}
+	if !token.Allows(vaultId, structs.APIAccessRead) {
+		return ErrNotFound
+	}
+	if !token.Allows(vaultId, access) {
+		return errReadOnly
+	}
+	return nil
+}
+
+func (s *Server) getToken(token *structs.APIToken, r *http.Request) (any, error) {
+	return &tokenResponse{
+		Name:      token.Name,
+		Access:    token.Access,
+		VaultIDs:  token.VaultIDs,
+		ExpiresAt: token.ExpiresAt,
+		Locked:    s.backend.IsLocked(),
+	}, nil
+}
+
+func (s *Server) listVaults(token *structs.APIToken, r *http.Request) (any, error) {
+	vaults := make([]*Vault, 0, len(token.VaultIDs))
+	for _, vaultId := range token.VaultIDs {
+		vault, err := s.backend.Vault(vaultId)
+		if errors.Is(err, ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		vaults = append(vaults, vault)
+	}
+	return map[string][]*Vault{"vaults": vaults}, nil
+}
+
+func (s *Server) getVault(token *structs.APIToken, r *http.Request) (any, error) {
+	return s.authorizeVault(token, r.PathValue("vault_id"), structs.APIAccessRead)
+}
+
+func (s *Server) listItems(token *structs.APIToken, r *http.Request) (any, error) {
+	vaultId := r.PathValue("vault_id")
+	if _, err := s.authorizeVault(token, vaultId, structs.APIAccessRead); err != nil {
+		return nil, err
+	}
+	query := r.URL.Query()
+	filter := ItemFilter{
+		Category: structs.ItemCategory(query.Get("category")),
+		Tag:      query.Get("tag"),
+	}
+	if filter.Category != "" && !filter.Category.IsValid() {
+		return nil, fmt.Errorf("%w: %q", structs.ErrInvalidCategory, filter.Category)
+	}
+	var err error
+	if filter.Favorites, err = boolParam(query.Get("favorites")); err != nil {
+		return nil, err
+	}
+	if filter.IncludeTrashed, err = boolParam(query.Get("include_trashed")); err != nil {
+		return nil, err
+	}
+	items, err := s.backend.Items(vaultId, filter)
+	if err != nil {
+		return nil, err
+	}
+	return map[string][]*Item{"items": items}, nil
+}
+
+func (s *Server) createItem(token *structs.APIToken, r *http.Request) (any, error) {
+	vaultId := r.PathValue("vault_id")
+	if _, err := s.authorizeVault(token, vaultId, structs.APIAccessWrite); err != nil {
+		return nil, err
+	}
+	var req itemRequest
+	if err := decodeBody(r, &req); err != nil {
+		return nil, err
+	}
+	return s.backend.CreateItem(vaultId, &req.Overview, &req.Details)
+}
+
+func (s *Server) getItem(token *structs.APIToken, r *http.Request) (any, error) {
+	itemId := r.PathValue("item_id")
+	if err := s.authorizeItem(token, itemId, structs.APIAccessRead); err != nil {
+		return nil, err
+	}
+	reveal, err := boolParam(r.URL.Query().Get("reveal"))
+	if err != nil {
+		return nil, err
+	}
+	return s.backend.Item(itemId, reveal)
+}
+
+func (s *Server) updateItem(token *structs.APIToken, r *http.Request) (any, error) {
+	itemId := r.PathValue("item_id")
+	if err := s.authorizeItem(token, itemId, structs.APIAccessWrite); err != nil {
+		return nil, err
+	}
+	var req itemRequest
+	if err := decodeBody(r, &req); err != nil {
+		return nil, err
+	}
+	return s.backend.UpdateItem(itemId, &req.Overview, &req.Details)
+}
+
+func (s *Server) trashItem(token *structs.APIToken, r *http.Request) (any, error) {
+	itemId := r.PathValue("item_id")
+	if err := s.authorizeItem(token, itemId, structs.APIAccessWrite); err != nil {
+		return nil, err
+	}
+	return nil, s.backend.TrashItem(itemId)
+}
+
+// boolParam parses an optional boolean query parameter
+func boolParam(value string) (bool, error) {
+	if value == "" {
+		return false, nil
+	}
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, &apiError{http.StatusBadRequest, fmt.Sprintf("invalid boolean %q", value)}
+	}
+	return b, nil
+}
+
+func decodeBody(r *http.Request, v any) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return &apiError{http.StatusBadRequest, fmt.Sprintf("invalid request body: %v", err)}
+	}
+	return nil
+}
+
+// writeError reports an error as JSON with the status matching its cause
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	var apiErr *apiError
+	switch {
+	case errors.As(err, &apiErr):
+		status = apiErr.status
+	case errors.Is(err, ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, ErrLocked):
+		status = http.StatusLocked
+	case errors.Is(err, structs.ErrInvalidDetails), errors.Is(err, structs.ErrInvalidCategory), errors.Is(err, structs.ErrInvalidTag):
+		status = http.StatusBadRequest
+	}
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	// Responses may contain secrets
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// CheckLoopbackAddress checks an address to listen on is a port on a loopback IP, e.g. 127.0.0.1:8420. Host
+// names are not accepted since they may resolve to other interfaces.
+func CheckLoopbackAddress(address string) error {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("invalid API server address %q: %w", address, err)
+	}
+	if ip := net.ParseIP(host); ip == nil || !ip.IsLoopback() {
+		return fmt.Errorf("the API server can only listen on a loopback IP address, not %q", host)
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		return fmt.Errorf("invalid API server port %q", port)
+	}
+	return nil
+}
@@ -0,0 +1,256 @@
+package apiserver
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+)
+
+// fakeBackend serves two vaults with one item each
+type fakeBackend struct {
+	locked  bool
+	tokens  map[string]*structs.APIToken
+	items   map[string]*Item
+	trashed []string
+}
+
+func newFakeBackend() *fakeBackend {
+	return &fakeBackend{
+		tokens: make(map[string]*structs.APIToken),
+		items: map[string]*Item{
+			"item-a": {ID: "item-a", VaultID: "vault-a", Overview: &structs.VaultItemOverview{Title: "A", Tags: []string{"prod/db"}}},
+			"item-b": {ID: "item-b", VaultID: "vault-b", Overview: &structs.VaultItemOverview{Title: "B"}},
+		},
+	}
+}
+
+func (b *fakeBackend) IsLocked() bool { return b.locked }
+
+func (b *fakeBackend) Token(tokenId string) (*structs.APIToken, bool) {
+	token, ok := b.tokens[tokenId]
+	return token, ok
+}
+
+func (b *fakeBackend) Vault(vaultId string) (*Vault, error) {
+	if vaultId != "vault-a" && vaultId != "vault-b" {
+		return nil, ErrNotFound
+	}
+	return &Vault{ID: vaultId, Name: vaultId}, nil
+}
+
+func (b *fakeBackend) Items(vaultId string, filter ItemFilter) ([]*Item, error) {
+	items := make([]*Item, 0)
+	for _, item := range b.items {
+		if item.VaultID == vaultId {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+func (b *fakeBackend) ItemVault(itemId string) (string, error) {
+	item, ok := b.items[itemId]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return item.VaultID, nil
+}
+
+func (b *fakeBackend) Item(itemId string, reveal bool) (*Item, error) {
+	return b.items[itemId], nil
+}
+
+func (b *fakeBackend) CreateItem(vaultId string, overview *structs.VaultItemOverview, details *structs.VaultItemDetails) (*Item, error) {
+	if overview.Title == "" {
+		return nil, structs.ErrInvalidDetails
+	}
+	item := &Item{ID: "item-new", VaultID: vaultId, Overview: overview}
+	b.items[item.ID] = item
+	return item, nil
+}
+
+func (b *fakeBackend) UpdateItem(itemId string, overview *structs.VaultItemOverview, details *structs.VaultItemDetails) (*Item, error) {
+	b.items[itemId].Overview = overview
+	return b.items[itemId], nil
+}
+
+func (b *fakeBackend) TrashItem(itemId string) error {
+	b.trashed = append(b.trashed, itemId)
+	return nil
+}
+
+func (b *fakeBackend) addToken(t *testing.T, access structs.APIAccess, expiresAt time.Time, vaultIds ...string) (*structs.APIToken, string) {
+	token, secret, err := structs.NewAPIToken("test", vaultIds, access, expiresAt)
+	if err != nil {
+		t.Fatalf("failed to create token: %v", err)
+	}
+	b.tokens[token.TokenID] = token
+	return token, secret
+}
+
+func request(s *Server, method string, path string, token string, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/"+Version+path, strings.NewReader(body))
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAuthentication(t *testing.T) {
+	backend := newFakeBackend()
+	s := NewServer(backend, new(sync.RWMutex))
+	_, valid := backend.addToken(t, structs.APIAccessRead, time.Time{}, "vault-a")
+	_, expired := backend.addToken(t, structs.APIAccessRead, time.Now().Add(-time.Minute), "vault-a")
+	revokedToken, revoked := backend.addToken(t, structs.APIAccessRead, time.Time{}, "vault-a")
+	revokedToken.RevokedAt = time.Now().Format(time.RFC3339)
+	tokenId, _, _ := structs.ParseAPIToken(valid)
+
+	tests := []struct {
+		name   string
+		token  string
+		status int
+	}{
+		{"valid", valid, http.StatusOK},
+		{"missing", "", http.StatusUnauthorized},
+		{"wrong secret", structs.APITokenPrefix + tokenId + "_00", http.StatusUnauthorized},
+		{"malformed", "not-a-token", http.StatusUnauthorized},
+		{"expired", expired, http.StatusUnauthorized},
+		{"revoked", revoked, http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		if rec := request(s, "GET", "/vaults", tt.token, ""); rec.Code != tt.status {
+			t.Fatalf("%s: expected status %d, got %d: %s", tt.name, tt.status, rec.Code, rec.Body)
+		}
+	}
+
+	backend.locked = true
+	if rec := request(s, "GET", "/vaults", valid, ""); rec.Code != http.StatusLocked {
+		t.Fatalf("expected requests to fail while locked, got %d", rec.Code)
+	}
+	if rec := request(s, "GET", "/token", valid, ""); rec.Code != http.StatusOK {
+		t.Fatalf("expected the token to be checkable while locked, got %d", rec.Code)
+	}
+}
+
+func TestBrowserRequestsRejected(t *testing.T) {
+	backend := newFakeBackend()
+	s := NewServer(backend, new(sync.RWMutex))
+	_, token := backend.addToken(t, structs.APIAccessRead, time.Time{}, "vault-a")
+	req := httptest.NewRequest("GET", "/"+Version+"/vaults", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Origin", "https://evil.example")
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Fatalf("expected requests with an origin to be rejected, got %d", rec.Code)
+	}
+}
+
+func TestScope(t *testing.T) {
+	backend := newFakeBackend()
+	s := NewServer(backend, new(sync.RWMutex))
+	_, readOnly := backend.addToken(t, structs.APIAccessRead, time.Time{}, "vault-a")
+	_, write := backend.addToken(t, structs.APIAccessWrite, time.Time{}, "vault-a")
+	item := `{"overview":{"title":"New"},"details":{}}`
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		body   string
+		status int
+	}{
+		{"read in scope", "GET", "/items/item-a", readOnly, "", http.StatusOK},
+		{"read out of scope", "GET", "/items/item-b", readOnly, "", http.StatusNotFound},
+		{"read missing", "GET", "/items/item-z", readOnly, "", http.StatusNotFound},
+		{"list out of scope", "GET", "/vaults/vault-b/items", write, "", http.StatusForbidden},
+		{"create read-only", "POST", "/vaults/vault-a/items", readOnly, item, http.StatusForbidden},
+		{"update read-only", "PUT", "/items/item-a", readOnly, item, http.StatusForbidden},
+		{"trash read-only", "DELETE", "/items/item-a", readOnly, "", http.StatusForbidden},
+		{"update out of scope", "PUT", "/items/item-b", write, item, http.StatusNotFound},
+		{"create out of scope", "POST", "/vaults/vault-b/items", write, item, http.StatusForbidden},
+		{"create invalid", "POST", "/vaults/vault-a/items", write, `{"overview":{}}`, http.StatusBadRequest},
+		{"create bad body", "POST", "/vaults/vault-a/items", write, `{`, http.StatusBadRequest},
+		{"bad filter", "GET", "/vaults/vault-a/items?favorites=maybe", readOnly, "", http.StatusBadRequest},
+		{"create", "POST", "/vaults/vault-a/items", write, item, http.StatusCreated},
+		{"update", "PUT", "/items/item-a", write, item, http.StatusOK},
+		{"trash", "DELETE", "/items/item-a", write, "", http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		if rec := request(s, tt.method, tt.path, tt.token, tt.body); rec.Code != tt.status {
+			t.Fatalf("%s: expected status %d, got %d: %s", tt.name, tt.status, rec.Code, rec.Body)
+		}
+	}
+	if len(backend.trashed) != 1 || backend.trashed[0] != "item-a" {
+		t.Fatalf("expected only item-a to be trashed, got %q", backend.trashed)
+	}
+	if backend.items["item-b"].Overview.Title != "B" {
+		t.Fatalf("expected the out of scope item to be unchanged")
+	}
+}
+
+func TestListVaults(t *testing.T) {
+	backend := newFakeBackend()
+	s := NewServer(backend, new(sync.RWMutex))
+	_, token := backend.addToken(t, structs.APIAccessRead, time.Time{}, "vault-b", "vault-gone")
+	rec := request(s, "GET", "/vaults", token, "")
+	var resp struct {
+		Vaults []*Vault `json:"vaults"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(resp.Vaults) != 1 || resp.Vaults[0].ID != "vault-b" {
+		t.Fatalf("expected only vault-b, got %+v", resp.Vaults)
+	}
+}
+
+func TestCheckLoopbackAddress(t *testing.T) {
+	tests := []struct {
+		address   string
+		expectErr bool
+	}{
+		{"127.0.0.1:8420", false},
+		{"[::1]:8420", false},
+		{"0.0.0.0:8420", true},
+		{"localhost:8420", true},
+		{"192.168.1.10:8420", true},
+		{"127.0.0.1", true},
+		{"127.0.0.1:0", true},
+	}
+	for _, tt := range tests {
+		if err := CheckLoopbackAddress(tt.address); (err != nil) != tt.expectErr {
+			t.Fatalf("CheckLoopbackAddress(%q): expected error %v, got %v", tt.address, tt.expectErr, err)
+		}
+	}
+}
+
+func TestRequestsHoldLock(t *testing.T) {
+	backend := newFakeBackend()
+	lock := new(sync.RWMutex)
+	s := NewServer(backend, lock)
+	_, token := backend.addToken(t, structs.APIAccessRead, time.Time{}, "vault-a")
+	lock.Lock()
+	done := make(chan int)
+	go func() {
+		done <- request(s, "GET", "/vaults", token, "").Code
+	}()
+	select {
+	case <-done:
+		t.Fatalf("expected the request to wait for the lock")
+	case <-time.After(50 * time.Millisecond):
+	}
+	lock.Unlock()
+	if code := <-done; code != http.StatusOK {
+		t.Fatalf("expected status %d once unlocked, got %d", http.StatusOK, code)
+	}
+}
@@ -0,0 +1,71 @@
+// Package breach checks passwords against the Have I Been Pwned Pwned Passwords list, which is published as
+// SHA-1 hashes of breached passwords with the number of times each was seen. Passwords are only ever handled as
+// SHA-1 hashes. Local copies of the list are searched offline; the range API is only sent the first five
+// characters of a hash (k-anonymity).
+package breach
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// PrefixLength is the number of hex characters of a hash which select a range bucket
+const PrefixLength = 5
+
+var ErrInvalidLine = errors.New("invalid pwned passwords line")
+
+// Hash is the SHA-1 hash of a password
+type Hash [sha1.Size]byte
+
+// HashPassword returns the SHA-1 hash of a password
+func HashPassword(password string) Hash {
+	return sha1.Sum([]byte(password))
+}
+
+// String returns the hash as uppercase hex, as it appears in the Pwned Passwords list
+func (h Hash) String() string {
+	return strings.ToUpper(hex.EncodeToString(h[:]))
+}
+
+// Prefix returns the range bucket of the hash
+func (h Hash) Prefix() string {
+	return h.String()[:PrefixLength]
+}
+
+// Source looks up how often passwords have been seen in breaches
+type Source interface {
+	// Count returns the number of times the password with the hash was seen in breaches (0 if never)
+	Count(hash Hash) (int, error)
+	Close() error
+}
+
+// Open opens a local copy of the Pwned Passwords list: either a single file of every hash sorted by hash, or a
+// directory of range bucket files as written by the official downloader.
+func Open(path string) (Source, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open pwned passwords data: %w", err)
+	}
+	if info.IsDir() {
+		return OpenRangeDirectory(path)
+	}
+	return OpenSortedFile(path)
+}
+
+// parseLine splits a "HASH:COUNT" line. Lines may end with a carriage return.
+func parseLine(line string) (hash string, count int, err error) {
+	hash, countStr, ok := strings.Cut(strings.TrimRight(line, "\r\n"), ":")
+	if !ok {
+		return "", 0, fmt.Errorf("%w %q", ErrInvalidLine, line)
+	}
+	count, err = strconv.Atoi(strings.TrimSpace(countStr))
+	if err != nil {
+		return "", 0, fmt.Errorf("%w %q", ErrInvalidLine, line)
+	}
+	return strings.ToUpper(hash), count, nil
+}
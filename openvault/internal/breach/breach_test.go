@@ -0,0 +1,152 @@
+package breach
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+var breached = map[string]int{
+	"password":    9545824,
+	"123456":      37359195,
+	"hunter2":     17043,
+	"letmein":     482381,
+	"correcthors": 3,
+}
+
+// writeSortedFile writes the breached passwords, and filler hashes, as a sorted Pwned Passwords file
+func writeSortedFile(t *testing.T, lineEnding string) string {
+	t.Helper()
+	lines := make([]string, 0)
+	for password, count := range breached {
+		lines = append(lines, fmt.Sprintf("%s:%d", HashPassword(password), count))
+	}
+	for i := 0; i < 500; i++ {
+		lines = append(lines, fmt.Sprintf("%s:%d", HashPassword(fmt.Sprintf("filler-%d", i)), i+1))
+	}
+	slices.Sort(lines)
+	path := filepath.Join(t.TempDir(), "pwned-passwords-sha1-ordered-by-hash.txt")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, lineEnding)+lineEnding), 0600); err != nil {
+		t.Fatalf("failed to write sorted file: %v", err)
+	}
+	return path
+}
+
+// rangeBuckets groups the breached passwords by range bucket, as served by the range API
+func rangeBuckets() map[string]string {
+	buckets := make(map[string][]string)
+	for password, count := range breached {
+		hash := HashPassword(password).String()
+		buckets[hash[:PrefixLength]] = append(buckets[hash[:PrefixLength]], fmt.Sprintf("%s:%d", hash[PrefixLength:], count))
+	}
+	result := make(map[string]string)
+	for prefix, lines := range buckets {
+		// Padding entries have a count of 0
+		lines = append(lines, strings.Repeat("F", 35)+":0", strings.Repeat("0", 35)+":0")
+		slices.Sort(lines)
+		result[prefix] = strings.Join(lines, "\r\n")
+	}
+	return result
+}
+
+func checkSource(t *testing.T, source Source) {
+	t.Helper()
+	for password, count := range breached {
+		got, err := source.Count(HashPassword(password))
+		if err != nil {
+			t.Fatalf("failed to look up %q: %v", password, err)
+		}
+		if got != count {
+			t.Fatalf("expected %q to be seen %d times, got %d", password, count, got)
+		}
+	}
+	if got, err := source.Count(HashPassword("not breached")); err != nil || got != 0 {
+		t.Fatalf("expected an unbreached password to have no count, got %d (%v)", got, err)
+	}
+}
+
+func TestSortedFile(t *testing.T) {
+	for _, lineEnding := range []string{"\n", "\r\n"} {
+		source, err := Open(writeSortedFile(t, lineEnding))
+		if err != nil {
+			t.Fatalf("failed to open sorted file: %v", err)
+		}
+		checkSource(t, source)
+		// The first and last hashes are at the edges of the search
+		for _, hash := range []string{strings.Repeat("0", 40), strings.Repeat("F", 40)} {
+			var h Hash
+			b, _ := hex.DecodeString(hash)
+			copy(h[:], b)
+			if count, err := source.Count(h); err != nil || count != 0 {
+				t.Fatalf("expected %s to have no count, got %d (%v)", hash, count, err)
+			}
+		}
+		source.Close()
+	}
+}
+
+func TestRangeDirectory(t *testing.T) {
+	dir := t.TempDir()
+	for prefix, bucket := range rangeBuckets() {
+		if err := os.WriteFile(filepath.Join(dir, prefix+".txt"), []byte(bucket), 0600); err != nil {
+			t.Fatalf("failed to write range bucket: %v", err)
+		}
+	}
+	source, err := Open(dir)
+	if err != nil {
+		t.Fatalf("failed to open range directory: %v", err)
+	}
+	defer source.Close()
+	for password, count := range breached {
+		got, err := source.Count(HashPassword(password))
+		if err != nil || got != count {
+			t.Fatalf("expected %q to be seen %d times, got %d (%v)", password, count, got, err)
+		}
+	}
+	// A bucket missing from the download is an error, not a clean result
+	if _, err := source.Count(HashPassword("not breached")); err == nil {
+		t.Fatalf("expected a missing range bucket to fail")
+	}
+}
+
+func TestRangeClient(t *testing.T) {
+	buckets := rangeBuckets()
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		prefix := strings.TrimPrefix(r.URL.Path, "/range/")
+		if len(prefix) != PrefixLength {
+			t.Errorf("expected only a %d character prefix to be sent, got %q", PrefixLength, prefix)
+		}
+		if r.Header.Get("Add-Padding") != "true" {
+			t.Errorf("expected padding to be requested")
+		}
+		bucket, ok := buckets[prefix]
+		if !ok {
+			bucket = strings.Repeat("A", 35) + ":0"
+		}
+		fmt.Fprint(w, bucket)
+	}))
+	defer server.Close()
+
+	client := NewRangeClient(server.URL)
+	checkSource(t, client)
+	before := requests
+	if _, err := client.Count(HashPassword("password")); err != nil || requests != before {
+		t.Fatalf("expected the range bucket to be cached")
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "rate limited", http.StatusTooManyRequests)
+	}))
+	defer failing.Close()
+	if _, err := NewRangeClient(failing.URL).Count(HashPassword("password")); err == nil {
+		t.Fatalf("expected an error response to fail")
+	}
+}
@@ -0,0 +1,79 @@
+package breach
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultRangeAPI is the Pwned Passwords range API
+const DefaultRangeAPI = "https://api.pwnedpasswords.com"
+
+// Range responses are a few dozen kilobytes; anything much larger is not a range response
+const maxRangeResponseSize = 4 << 20
+
+// RangeClient looks up hashes with the Pwned Passwords range API. Only the five character prefix of a hash is
+// sent, and responses are padded with fake entries so their size does not reveal the prefix either.
+type RangeClient struct {
+	// Base URL of the API, without the /range path
+	BaseURL    string
+	HTTPClient *http.Client
+	// Buckets already fetched, by prefix, so checking many passwords fetches each bucket once
+	buckets map[string]string
+}
+
+// NewRangeClient returns a client for the range API at baseURL, or DefaultRangeAPI if empty
+func NewRangeClient(baseURL string) *RangeClient {
+	if baseURL == "" {
+		baseURL = DefaultRangeAPI
+	}
+	return &RangeClient{
+		BaseURL:    strings.TrimSuffix(baseURL, "/"),
+		HTTPClient: &http.Client{Timeout: 15 * time.Second},
+		buckets:    make(map[string]string),
+	}
+}
+
+func (rc *RangeClient) Close() error {
+	rc.buckets = make(map[string]string)
+	return nil
+}
+
+// Count fetches the range bucket of the hash and searches it for the rest of the hash
+func (rc *RangeClient) Count(hash Hash) (int, error) {
+	prefix := hash.Prefix()
+	bucket, ok := rc.buckets[prefix]
+	if !ok {
+		var err error
+		if bucket, err = rc.fetchRange(prefix); err != nil {
+			return 0, err
+		}
+		rc.buckets[prefix] = bucket
+	}
+	// Padding entries have a count of 0, so they never match as breached
+	return searchRange(bucket, hash)
+}
+
+func (rc *RangeClient) fetchRange(prefix string) (string, error) {
+	req, err := http.NewRequest(http.MethodGet, rc.BaseURL+"/range/"+prefix, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create pwned passwords request: %w", err)
+	}
+	req.Header.Set("Add-Padding", "true")
+	req.Header.Set("User-Agent", "OpenVault")
+	resp, err := rc.HTTPClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to query pwned passwords: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("pwned passwords range %s returned %s", prefix, resp.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxRangeResponseSize))
+	if err != nil {
+		return "", fmt.Errorf("failed to read pwned passwords range %s: %w", prefix, err)
+	}
+	return string(body), nil
+}
@@ -0,0 +1,159 @@
+package breach
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// SortedFile is a file with one "HASH:COUNT" line per breached password, sorted by hash. The full list is tens of
+// gigabytes, so it is binary searched on disk rather than loaded.
+type SortedFile struct {
+	f    *os.File
+	size int64
+}
+
+// OpenSortedFile opens a sorted Pwned Passwords file
+func OpenSortedFile(path string) (*SortedFile, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open pwned passwords file: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to open pwned passwords file: %w", err)
+	}
+	return &SortedFile{f: f, size: info.Size()}, nil
+}
+
+func (sf *SortedFile) Close() error {
+	return sf.f.Close()
+}
+
+// Count binary searches the file for the hash
+func (sf *SortedFile) Count(hash Hash) (int, error) {
+	target := hash.String()
+	// lo is always the start of a line, and the line with the hash, if any, starts before hi
+	lo, hi := int64(0), sf.size
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		start, line, err := sf.lineAt(mid)
+		if err != nil {
+			return 0, err
+		}
+		// No line starts in [mid, hi), so the hash can only be before mid
+		if start >= hi || line == "" {
+			hi = mid
+			continue
+		}
+		lineHash, count, err := parseLine(line)
+		if err != nil {
+			return 0, err
+		}
+		switch {
+		case lineHash == target:
+			return count, nil
+		case lineHash < target:
+			lo = start + int64(len(line))
+		default:
+			// start is the first line at or after mid, so the hash starts before mid
+			hi = mid
+		}
+	}
+	return 0, nil
+}
+
+// lineAt returns the first line starting at or after offset, including its line ending
+func (sf *SortedFile) lineAt(offset int64) (int64, string, error) {
+	start := offset
+	var r *bufio.Reader
+	if offset == 0 {
+		r = bufio.NewReader(io.NewSectionReader(sf.f, 0, sf.size))
+	} else {
+		// Skip to the end of the line containing offset-1; if that byte is a newline, offset starts a line
+		r = bufio.NewReader(io.NewSectionReader(sf.f, offset-1, sf.size-offset+1))
+		skipped, err := r.ReadString('\n')
+		if errors.Is(err, io.EOF) {
+			return sf.size, "", nil
+		}
+		if err != nil {
+			return 0, "", fmt.Errorf("failed to read pwned passwords file: %w", err)
+		}
+		start = offset - 1 + int64(len(skipped))
+	}
+	line, err := r.ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return 0, "", fmt.Errorf("failed to read pwned passwords file: %w", err)
+	}
+	return start, line, nil
+}
+
+// RangeDirectory is a directory of range bucket files named by the first five characters of the hashes they hold
+// (e.g. "21BD1.txt"), each with one "SUFFIX:COUNT" line per hash sorted by suffix, as served by the range API.
+type RangeDirectory struct {
+	dir string
+}
+
+// OpenRangeDirectory opens a directory of Pwned Passwords range buckets
+func OpenRangeDirectory(dir string) (*RangeDirectory, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open pwned passwords directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+	return &RangeDirectory{dir: dir}, nil
+}
+
+func (rd *RangeDirectory) Close() error {
+	return nil
+}
+
+// Count binary searches the range bucket of the hash
+func (rd *RangeDirectory) Count(hash Hash) (int, error) {
+	prefix := hash.Prefix()
+	var data []byte
+	var err error
+	for _, name := range []string{prefix + ".txt", prefix, strings.ToLower(prefix) + ".txt", strings.ToLower(prefix)} {
+		data, err = os.ReadFile(filepath.Join(rd.dir, name))
+		if !errors.Is(err, fs.ErrNotExist) {
+			break
+		}
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, fmt.Errorf("pwned passwords range %s is missing from %s", prefix, rd.dir)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read pwned passwords range %s: %w", prefix, err)
+	}
+	return searchRange(string(data), hash)
+}
+
+// searchRange binary searches the lines of a range bucket for the suffix of the hash
+func searchRange(bucket string, hash Hash) (int, error) {
+	suffix := hash.String()[PrefixLength:]
+	lines := strings.Split(strings.TrimSpace(bucket), "\n")
+	i := sort.Search(len(lines), func(i int) bool {
+		lineSuffix, _, _ := strings.Cut(lines[i], ":")
+		return strings.ToUpper(lineSuffix) >= suffix
+	})
+	if i == len(lines) {
+		return 0, nil
+	}
+	lineSuffix, count, err := parseLine(lines[i])
+	if err != nil {
+		return 0, err
+	}
+	if lineSuffix != suffix {
+		return 0, nil
+	}
+	return count, nil
+}
@@ -9,5 +9,11 @@ import (
 var DATA_DIR = path.Join(xdg.DataHome, "openvault")
 var CONFIG_DIR = path.Join(xdg.ConfigHome, "openvault")
 var CACHE_DIR = path.Join(xdg.CacheHome, "openvault")
+var RUNTIME_DIR = path.Join(xdg.RuntimeDir, "openvault")
+
+var SSH_AGENT_SOCKET = path.Join(RUNTIME_DIR, "ssh-agent.sock")
+var BROWSER_SOCKET = path.Join(RUNTIME_DIR, "browser.sock")
+var API_SOCKET = path.Join(RUNTIME_DIR, "api.sock")
+var CREDENTIAL_HELPER_SOCKET = path.Join(RUNTIME_DIR, "credential-helper.sock")
 
 var PBKDF2_ROUNDS = 650000
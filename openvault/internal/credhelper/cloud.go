@@ -0,0 +1,141 @@
+package credhelper
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+)
+
+// awsCredentialProcessOutput is the output format of an AWS credential_process
+type awsCredentialProcessOutput struct {
+	Version         int    `json:"Version"`
+	AccessKeyId     string `json:"AccessKeyId"`
+	SecretAccessKey string `json:"SecretAccessKey"`
+	SessionToken    string `json:"SessionToken,omitempty"`
+	Expiration      string `json:"Expiration,omitempty"`
+}
+
+// itemDetails returns the revealed details of the item referenced by the command arguments
+func itemDetails(backend Backend, usage string, args []string) (*structs.VaultItemDetails, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("usage: %s", usage)
+	}
+	return backend.ItemDetails(args[0])
+}
+
+// credentialValue returns the value of the first custom field with one of the labels, falling back to the
+// given default
+func credentialValue(details *structs.VaultItemDetails, fallback string, labels ...string) string {
+	if field := details.FieldByLabel(labels...); field != nil && field.Value != "" {
+		return field.Value
+	}
+	return fallback
+}
+
+// normalizeExpiration parses an expiration field and formats it as RFC 3339 in UTC
+func normalizeExpiration(value string) (string, error) {
+	if value == "" {
+		return "", nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return "", fmt.Errorf("expiration %q is not an RFC 3339 timestamp", value)
+	}
+	return t.UTC().Format(time.RFC3339), nil
+}
+
+// DescribeAWSCredentialProcess returns the approval prompt for an AWS credential_process request
+func DescribeAWSCredentialProcess(args []string, stdin []byte) string {
+	return fmt.Sprintf("Allow the AWS CLI to read the credentials in %q?", strings.Join(args, " "))
+}
+
+// AWSCredentialProcess writes the credentials of an item in the AWS credential_process format. The access key
+// and secret come from an API credential's key and secret or from custom fields labelled "Access Key ID" and
+// "Secret Access Key"; "Session Token" and "Expiration" fields are included when present.
+func AWSCredentialProcess(backend Backend, args []string, stdout io.Writer) error {
+	details, err := itemDetails(backend, "openvault aws-credential-process <item>", args)
+	if err != nil {
+		return err
+	}
+	var key, secret string
+	if details.API != nil {
+		key, secret = details.API.Key, details.API.Secret
+	}
+	output := &awsCredentialProcessOutput{
+		Version:         1,
+		AccessKeyId:     credentialValue(details, key, "Access Key ID", "aws_access_key_id"),
+		SecretAccessKey: credentialValue(details, secret, "Secret Access Key", "aws_secret_access_key"),
+		SessionToken:    credentialValue(details, "", "Session Token", "aws_session_token"),
+	}
+	if output.AccessKeyId == "" || output.SecretAccessKey == "" {
+		return fmt.Errorf("item has no access key ID or secret access key")
+	}
+	if output.Expiration, err = normalizeExpiration(credentialValue(details, "", "Expiration")); err != nil {
+		return err
+	}
+	return json.NewEncoder(stdout).Encode(output)
+}
+
+const defaultExecCredentialAPIVersion = "client.authentication.k8s.io/v1"
+
+type execCredential struct {
+	APIVersion string                `json:"apiVersion"`
+	Kind       string                `json:"kind"`
+	Status     *execCredentialStatus `json:"status"`
+}
+
+type execCredentialStatus struct {
+	Token                 string `json:"token,omitempty"`
+	ClientCertificateData string `json:"clientCertificateData,omitempty"`
+	ClientKeyData         string `json:"clientKeyData,omitempty"`
+	ExpirationTimestamp   string `json:"expirationTimestamp,omitempty"`
+}
+
+// DescribeKubeExecCredential returns the approval prompt for a kubectl exec credential request
+func DescribeKubeExecCredential(args []string, stdin []byte) string {
+	return fmt.Sprintf("Allow kubectl to read the credentials in %q?", strings.Join(args, " "))
+}
+
+// KubeExecCredential writes the credentials of an item as a kubectl ExecCredential. The token comes from a custom
+// field labelled "Token", an API credential's secret or a login's password; client certificates come from
+// "Client Certificate" and "Client Key" fields.
+func KubeExecCredential(backend Backend, args []string, getenv func(string) string, stdout io.Writer) error {
+	details, err := itemDetails(backend, "openvault kube-exec-credential <item>", args)
+	if err != nil {
+		return err
+	}
+	token := details.Password
+	if details.API != nil {
+		token = details.API.Secret
+	}
+	status := &execCredentialStatus{
+		Token:                 credentialValue(details, token, "Token"),
+		ClientCertificateData: credentialValue(details, "", "Client Certificate", "Client Certificate Data"),
+		ClientKeyData:         credentialValue(details, "", "Client Key", "Client Key Data"),
+	}
+	if status.Token == "" && (status.ClientCertificateData == "" || status.ClientKeyData == "") {
+		return fmt.Errorf("item has no token or client certificate and key")
+	}
+	if status.ExpirationTimestamp, err = normalizeExpiration(credentialValue(details, "", "Expiration")); err != nil {
+		return err
+	}
+	// kubectl describes the API version it expects in KUBERNETES_EXEC_INFO
+	apiVersion := defaultExecCredentialAPIVersion
+	if info := getenv("KUBERNETES_EXEC_INFO"); info != "" {
+		var execInfo struct {
+			APIVersion string `json:"apiVersion"`
+		}
+		if err := json.Unmarshal([]byte(info), &execInfo); err == nil && execInfo.APIVersion != "" {
+			apiVersion = execInfo.APIVersion
+		}
+	}
+	return json.NewEncoder(stdout).Encode(&execCredential{
+		APIVersion: apiVersion,
+		Kind:       "ExecCredential",
+		Status:     status,
+	})
+}
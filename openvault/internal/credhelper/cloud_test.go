@@ -0,0 +1,142 @@
+package credhelper
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+)
+
+// fields returns a section of custom fields from label and value pairs
+func fields(labelsAndValues ...string) []*structs.Section {
+	section := &structs.Section{ID: "section"}
+	for i := 0; i+1 < len(labelsAndValues); i += 2 {
+		section.Fields = append(section.Fields, &structs.Field{Label: labelsAndValues[i], Value: labelsAndValues[i+1]})
+	}
+	return []*structs.Section{section}
+}
+
+func cloudBackend() *fakeBackend {
+	backend := newFakeBackend()
+	backend.items = map[string]*structs.VaultItemDetails{
+		"api": {
+			Category: structs.CategoryAPICredential,
+			API:      &structs.APICredentialDetails{Key: "AKIAEXAMPLE", Secret: "api-secret"},
+		},
+		"session": {
+			Category: structs.CategoryAPICredential,
+			API:      &structs.APICredentialDetails{Key: "AKIAEXAMPLE", Secret: "api-secret"},
+			Sections: fields("aws_access_key_id", "ASIAEXAMPLE", "Session Token", "session-token", "Expiration", "2030-01-02T03:04:05+02:00"),
+		},
+		"fields": {
+			Category: structs.CategorySecureNote,
+			Sections: fields("Access Key ID", "AKIAFIELD", "Secret Access Key", "field-secret"),
+		},
+		"login": {
+			Category: structs.CategoryLogin,
+			Username: "admin",
+			Password: "login-token",
+		},
+		"token": {
+			Category: structs.CategoryLogin,
+			Password: "login-token",
+			Sections: fields("Token", "field-token", "Expiration", "2030-01-02T03:04:05Z"),
+		},
+		"certificate": {
+			Category: structs.CategorySecureNote,
+			Sections: fields("Client Certificate Data", "cert-data", "Client Key", "key-data"),
+		},
+		"bad expiration": {
+			Category: structs.CategoryAPICredential,
+			API:      &structs.APICredentialDetails{Key: "AKIAEXAMPLE", Secret: "api-secret"},
+			Sections: fields("Expiration", "tomorrow"),
+		},
+		"empty": {
+			Category: structs.CategorySecureNote,
+		},
+	}
+	return backend
+}
+
+func TestAWSCredentialProcess(t *testing.T) {
+	tests := []struct {
+		args      []string
+		output    string
+		expectErr bool
+	}{
+		{[]string{"api"}, `{"Version":1,"AccessKeyId":"AKIAEXAMPLE","SecretAccessKey":"api-secret"}` + "\n", false},
+		{[]string{"session"}, `{"Version":1,"AccessKeyId":"ASIAEXAMPLE","SecretAccessKey":"api-secret","SessionToken":"session-token","Expiration":"2030-01-02T01:04:05Z"}` + "\n", false},
+		{[]string{"fields"}, `{"Version":1,"AccessKeyId":"AKIAFIELD","SecretAccessKey":"field-secret"}` + "\n", false},
+		{[]string{"login"}, "", true},
+		{[]string{"bad expiration"}, "", true},
+		{[]string{"missing"}, "", true},
+		{[]string{}, "", true},
+		{[]string{"api", "fields"}, "", true},
+	}
+	for _, tt := range tests {
+		var stdout strings.Builder
+		err := AWSCredentialProcess(cloudBackend(), tt.args, &stdout)
+		if (err != nil) != tt.expectErr {
+			t.Fatalf("%q: expected error %v, got %v", tt.args, tt.expectErr, err)
+		}
+		if stdout.String() != tt.output {
+			t.Fatalf("%q: expected output %q, got %q", tt.args, tt.output, stdout.String())
+		}
+	}
+}
+
+func TestKubeExecCredential(t *testing.T) {
+	tests := []struct {
+		args      []string
+		execInfo  string
+		output    string
+		expectErr bool
+	}{
+		{
+			args:   []string{"login"},
+			output: `{"apiVersion":"client.authentication.k8s.io/v1","kind":"ExecCredential","status":{"token":"login-token"}}` + "\n",
+		},
+		{
+			args:   []string{"api"},
+			output: `{"apiVersion":"client.authentication.k8s.io/v1","kind":"ExecCredential","status":{"token":"api-secret"}}` + "\n",
+		},
+		{
+			args:   []string{"token"},
+			output: `{"apiVersion":"client.authentication.k8s.io/v1","kind":"ExecCredential","status":{"token":"field-token","expirationTimestamp":"2030-01-02T03:04:05Z"}}` + "\n",
+		},
+		{
+			args:   []string{"certificate"},
+			output: `{"apiVersion":"client.authentication.k8s.io/v1","kind":"ExecCredential","status":{"clientCertificateData":"cert-data","clientKeyData":"key-data"}}` + "\n",
+		},
+		{
+			args:     []string{"login"},
+			execInfo: `{"apiVersion":"client.authentication.k8s.io/v1beta1","kind":"ExecCredential","spec":{"interactive":false}}`,
+			output:   `{"apiVersion":"client.authentication.k8s.io/v1beta1","kind":"ExecCredential","status":{"token":"login-token"}}` + "\n",
+		},
+		{
+			args:     []string{"login"},
+			execInfo: `not json`,
+			output:   `{"apiVersion":"client.authentication.k8s.io/v1","kind":"ExecCredential","status":{"token":"login-token"}}` + "\n",
+		},
+		{args: []string{"empty"}, expectErr: true},
+		{args: []string{"bad expiration"}, expectErr: true},
+		{args: []string{"missing"}, expectErr: true},
+		{args: []string{}, expectErr: true},
+	}
+	for _, tt := range tests {
+		getenv := func(key string) string {
+			if key == "KUBERNETES_EXEC_INFO" {
+				return tt.execInfo
+			}
+			return ""
+		}
+		var stdout strings.Builder
+		err := KubeExecCredential(cloudBackend(), tt.args, getenv, &stdout)
+		if (err != nil) != tt.expectErr {
+			t.Fatalf("%q: expected error %v, got %v", tt.args, tt.expectErr, err)
+		}
+		if stdout.String() != tt.output {
+			t.Fatalf("%q: expected output %q, got %q", tt.args, tt.output, stdout.String())
+		}
+	}
+}
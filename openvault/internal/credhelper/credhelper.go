@@ -0,0 +1,51 @@
+// Package credhelper implements the protocols of the credential helpers git, docker, the AWS CLI and kubectl run
+// to get credentials. The helpers use the logins of the unlocked vaults through a Backend.
+package credhelper
+
+import (
+	"net/url"
+
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+)
+
+// Login is a login item with its revealed details
+type Login struct {
+	ItemID   string
+	VaultID  string
+	Overview *structs.VaultItemOverview
+	Details  *structs.VaultItemDetails
+}
+
+// Backend gives the helpers access to the items of the unlocked vaults
+type Backend interface {
+	// FindLogins returns the logins whose URL matches target, most specific first. If username is set only logins
+	// with that username are returned.
+	FindLogins(target *url.URL, username string) ([]*Login, error)
+	// VaultLogins returns the logins in a vault
+	VaultLogins(vaultId string) ([]*Login, error)
+	// ItemDetails returns the revealed details of an item referenced by its ID or, if unique, its title
+	ItemDetails(ref string) (*structs.VaultItemDetails, error)
+	// DefaultVault returns the vault new logins are stored in
+	DefaultVault() (string, error)
+	// CreateItem adds an item to a vault
+	CreateItem(vaultId string, overview *structs.VaultItemOverview, details *structs.VaultItemDetails) error
+	// UpdateItem replaces the overview and details of an item
+	UpdateItem(itemId string, overview *structs.VaultItemOverview, details *structs.VaultItemDetails) error
+	// TrashItem moves an item to the trash
+	TrashItem(itemId string) error
+}
+
+// actions describes the actions of the git and docker credential helpers in approval prompts
+var actions = map[string]string{
+	"get":   "read",
+	"store": "save",
+	"erase": "remove",
+}
+
+// describeAction returns how an action is described in approval prompts
+func describeAction(args []string) string {
+	if len(args) == 1 && actions[args[0]] != "" {
+		return actions[args[0]]
+	}
+	return "use"
+}
@@ -0,0 +1,100 @@
+package credhelper
+
+import (
+	"fmt"
+	"net/url"
+	"slices"
+
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+)
+
+// fakeBackend keeps logins in memory. Matching uses the same URL scoring as the app.
+type fakeBackend struct {
+	logins []*Login
+	// Details of other items, by title
+	items   map[string]*structs.VaultItemDetails
+	vault   string
+	created int
+}
+
+func newFakeBackend(logins ...*Login) *fakeBackend {
+	return &fakeBackend{logins: logins, vault: "vault-a"}
+}
+
+func login(itemId string, vaultId string, itemURL string, username string, password string) *Login {
+	return &Login{
+		ItemID:   itemId,
+		VaultID:  vaultId,
+		Overview: &structs.VaultItemOverview{Title: itemId, URL: itemURL},
+		Details:  &structs.VaultItemDetails{Category: structs.CategoryLogin, Username: username, Password: password},
+	}
+}
+
+func (b *fakeBackend) FindLogins(target *url.URL, username string) ([]*Login, error) {
+	var matches []*Login
+	for _, login := range b.logins {
+		if structs.MatchURL(login.Overview.URL, target) == 0 || username != "" && login.Details.Username != username {
+			continue
+		}
+		matches = append(matches, login)
+	}
+	slices.SortStableFunc(matches, func(x, y *Login) int {
+		return structs.MatchURL(y.Overview.URL, target) - structs.MatchURL(x.Overview.URL, target)
+	})
+	return matches, nil
+}
+
+func (b *fakeBackend) VaultLogins(vaultId string) ([]*Login, error) {
+	var logins []*Login
+	for _, login := range b.logins {
+		if login.VaultID == vaultId {
+			logins = append(logins, login)
+		}
+	}
+	return logins, nil
+}
+
+func (b *fakeBackend) ItemDetails(ref string) (*structs.VaultItemDetails, error) {
+	details, ok := b.items[ref]
+	if !ok {
+		return nil, fmt.Errorf("no item found for %q", ref)
+	}
+	return details, nil
+}
+
+func (b *fakeBackend) DefaultVault() (string, error) {
+	if b.vault == "" {
+		return "", fmt.Errorf("no credential helper vault configured")
+	}
+	return b.vault, nil
+}
+
+func (b *fakeBackend) CreateItem(vaultId string, overview *structs.VaultItemOverview, details *structs.VaultItemDetails) error {
+	b.created++
+	b.logins = append(b.logins, &Login{ItemID: fmt.Sprintf("new-%d", b.created), VaultID: vaultId, Overview: overview, Details: details})
+	return nil
+}
+
+func (b *fakeBackend) UpdateItem(itemId string, overview *structs.VaultItemOverview, details *structs.VaultItemDetails) error {
+	login := b.login(itemId)
+	if login == nil {
+		return fmt.Errorf("item %s not found", itemId)
+	}
+	login.Overview = overview
+	login.Details = details
+	return nil
+}
+
+func (b *fakeBackend) TrashItem(itemId string) error {
+	b.logins = slices.DeleteFunc(b.logins, func(login *Login) bool { return login.ItemID == itemId })
+	return nil
+}
+
+func (b *fakeBackend) login(itemId string) *Login {
+	for _, login := range b.logins {
+		if login.ItemID == itemId {
+			return login
+		}
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package credhelper
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+)
+
+// errDockerCredentialsNotFound is the exact message docker expects when a helper has no credentials for a server
+var errDockerCredentialsNotFound = errors.New("credentials not found in native keychain")
+
+// dockerCredential is a credential in the docker-credential-helpers format
+type dockerCredential struct {
+	ServerURL string `json:"ServerURL"`
+	Username  string `json:"Username"`
+	Secret    string `json:"Secret"`
+}
+
+// parseRegistryURL parses a registry server URL, which docker may pass without a scheme
+func parseRegistryURL(serverURL string) (*url.URL, error) {
+	serverURL = strings.TrimSpace(serverURL)
+	if serverURL == "" {
+		return nil, fmt.Errorf("no server URL provided")
+	}
+	if !strings.Contains(serverURL, "://") {
+		serverURL = "https://" + serverURL
+	}
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid server URL: %w", err)
+	}
+	return u, nil
+}
+
+// DescribeDocker returns the approval prompt for a docker credential helper request
+func DescribeDocker(args []string, stdin []byte) string {
+	if len(args) == 1 && args[0] == "list" {
+		return "Allow docker to list the registries it has logins for?"
+	}
+	action := describeAction(args)
+	serverURL := string(stdin)
+	if len(args) == 1 && args[0] == "store" {
+		var cred dockerCredential
+		if err := json.Unmarshal(stdin, &cred); err != nil {
+			return fmt.Sprintf("Allow docker to %s a registry login?", action)
+		}
+		serverURL = cred.ServerURL
+	}
+	u, err := parseRegistryURL(serverURL)
+	if err != nil {
+		return fmt.Sprintf("Allow docker to %s a registry login?", action)
+	}
+	return fmt.Sprintf("Allow docker to %s the login for %s?", action, u.Host)
+}
+
+// Docker implements the get, store, erase and list actions of a docker credential helper. Only logins in the
+// default vault are used. Errors are also written to stdout, where docker reads them.
+func Docker(backend Backend, args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: docker-credential-openvault <get|store|erase|list>")
+	}
+	err := docker(backend, args[0], stdin, stdout)
+	if err != nil {
+		fmt.Fprintln(stdout, err)
+	}
+	return err
+}
+
+func docker(backend Backend, action string, stdin io.Reader, stdout io.Writer) error {
+	vaultId, err := backend.DefaultVault()
+	if err != nil {
+		return err
+	}
+	switch action {
+	case "get":
+		serverURL, err := io.ReadAll(stdin)
+		if err != nil {
+			return err
+		}
+		match, err := dockerLogin(backend, vaultId, string(serverURL))
+		if err != nil {
+			return err
+		}
+		if match == nil {
+			return errDockerCredentialsNotFound
+		}
+		return json.NewEncoder(stdout).Encode(&dockerCredential{
+			ServerURL: strings.TrimSpace(string(serverURL)),
+			Username:  match.Details.Username,
+			Secret:    match.Details.Password,
+		})
+	case "store":
+		var cred dockerCredential
+		if err := json.NewDecoder(stdin).Decode(&cred); err != nil {
+			return fmt.Errorf("invalid credential: %w", err)
+		}
+		return dockerStore(backend, vaultId, &cred)
+	case "erase":
+		serverURL, err := io.ReadAll(stdin)
+		if err != nil {
+			return err
+		}
+		match, err := dockerLogin(backend, vaultId, string(serverURL))
+		if err != nil {
+			return err
+		}
+		if match == nil {
+			return errDockerCredentialsNotFound
+		}
+		return backend.TrashItem(match.ItemID)
+	case "list":
+		logins, err := backend.VaultLogins(vaultId)
+		if err != nil {
+			return err
+		}
+		servers := make(map[string]string)
+		for _, login := range logins {
+			if login.Overview.URL != "" {
+				servers[login.Overview.URL] = login.Details.Username
+			}
+		}
+		return json.NewEncoder(stdout).Encode(servers)
+	default:
+		return fmt.Errorf("unknown action %q", action)
+	}
+}
+
+// dockerLogin returns the best matching login for a registry in the vault, or nil if there is none
+func dockerLogin(backend Backend, vaultId string, serverURL string) (*Login, error) {
+	u, err := parseRegistryURL(serverURL)
+	if err != nil {
+		return nil, err
+	}
+	matches, err := backend.FindLogins(u, "")
+	if err != nil {
+		return nil, err
+	}
+	for _, match := range matches {
+		if match.VaultID == vaultId {
+			return match, nil
+		}
+	}
+	return nil, nil
+}
+
+// dockerStore saves the credential from docker login, replacing the login stored for the registry
+func dockerStore(backend Backend, vaultId string, cred *dockerCredential) error {
+	match, err := dockerLogin(backend, vaultId, cred.ServerURL)
+	if err != nil {
+		return err
+	}
+	if match != nil {
+		if match.Details.Username == cred.Username && match.Details.Password == cred.Secret {
+			return nil
+		}
+		details := *match.Details
+		details.Username = cred.Username
+		details.Password = cred.Secret
+		return backend.UpdateItem(match.ItemID, match.Overview, &details)
+	}
+	u, err := parseRegistryURL(cred.ServerURL)
+	if err != nil {
+		return err
+	}
+	return backend.CreateItem(vaultId, &structs.VaultItemOverview{
+		Title: u.Host,
+		URL:   cred.ServerURL,
+	}, &structs.VaultItemDetails{
+		Category: structs.CategoryLogin,
+		Username: cred.Username,
+		Password: cred.Secret,
+	})
+}
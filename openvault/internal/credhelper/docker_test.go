@@ -0,0 +1,147 @@
+package credhelper
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseRegistryURL(t *testing.T) {
+	tests := []struct {
+		serverURL string
+		expected  string
+		expectErr bool
+	}{
+		{"https://index.docker.io/v1/", "https://index.docker.io/v1/", false},
+		{"ghcr.io", "https://ghcr.io", false},
+		{"  registry.example.com:5000\n", "https://registry.example.com:5000", false},
+		{"http://localhost:5000", "http://localhost:5000", false},
+		{"", "", true},
+		{" \n", "", true},
+		{"https://bad host", "", true},
+	}
+	for _, tt := range tests {
+		u, err := parseRegistryURL(tt.serverURL)
+		if (err != nil) != tt.expectErr {
+			t.Fatalf("parseRegistryURL(%q): expected error %v, got %v", tt.serverURL, tt.expectErr, err)
+		}
+		if err == nil && u.String() != tt.expected {
+			t.Fatalf("parseRegistryURL(%q): expected %q, got %q", tt.serverURL, tt.expected, u)
+		}
+	}
+}
+
+func TestDocker(t *testing.T) {
+	tests := []struct {
+		name      string
+		action    string
+		input     string
+		output    string
+		expectErr bool
+		// Username and password of every login afterwards, by URL
+		logins map[string]string
+	}{
+		{
+			name:   "get",
+			action: "get",
+			input:  "ghcr.io\n",
+			output: `{"ServerURL":"ghcr.io","Username":"octocat","Secret":"ghcr-token"}` + "\n",
+			logins: map[string]string{"https://ghcr.io": "octocat:ghcr-token", "https://quay.io": "other:quay-token"},
+		},
+		{
+			name:      "get without match",
+			action:    "get",
+			input:     "registry.example.com",
+			output:    "credentials not found in native keychain\n",
+			expectErr: true,
+			logins:    map[string]string{"https://ghcr.io": "octocat:ghcr-token", "https://quay.io": "other:quay-token"},
+		},
+		{
+			name:      "get from another vault",
+			action:    "get",
+			input:     "quay.io",
+			output:    "credentials not found in native keychain\n",
+			expectErr: true,
+			logins:    map[string]string{"https://ghcr.io": "octocat:ghcr-token", "https://quay.io": "other:quay-token"},
+		},
+		{
+			name:   "store replaces login",
+			action: "store",
+			input:  `{"ServerURL":"ghcr.io","Username":"hubot","Secret":"new-token"}`,
+			logins: map[string]string{"https://ghcr.io": "hubot:new-token", "https://quay.io": "other:quay-token"},
+		},
+		{
+			name:   "store new login",
+			action: "store",
+			input:  `{"ServerURL":"registry.example.com:5000","Username":"ci","Secret":"ci-token"}`,
+			logins: map[string]string{"https://ghcr.io": "octocat:ghcr-token", "https://quay.io": "other:quay-token", "registry.example.com:5000": "ci:ci-token"},
+		},
+		{
+			name:      "store invalid",
+			action:    "store",
+			input:     `{"ServerURL":`,
+			output:    "invalid credential: unexpected EOF\n",
+			expectErr: true,
+			logins:    map[string]string{"https://ghcr.io": "octocat:ghcr-token", "https://quay.io": "other:quay-token"},
+		},
+		{
+			name:   "erase",
+			action: "erase",
+			input:  "ghcr.io",
+			logins: map[string]string{"https://quay.io": "other:quay-token"},
+		},
+		{
+			name:      "erase without match",
+			action:    "erase",
+			input:     "quay.io",
+			output:    "credentials not found in native keychain\n",
+			expectErr: true,
+			logins:    map[string]string{"https://ghcr.io": "octocat:ghcr-token", "https://quay.io": "other:quay-token"},
+		},
+		{
+			name:   "list",
+			action: "list",
+			output: `{"https://ghcr.io":"octocat"}` + "\n",
+			logins: map[string]string{"https://ghcr.io": "octocat:ghcr-token", "https://quay.io": "other:quay-token"},
+		},
+		{
+			name:      "unknown action",
+			action:    "version",
+			output:    "unknown action \"version\"\n",
+			expectErr: true,
+			logins:    map[string]string{"https://ghcr.io": "octocat:ghcr-token", "https://quay.io": "other:quay-token"},
+		},
+	}
+	for _, tt := range tests {
+		backend := newFakeBackend(
+			login("item-a", "vault-a", "https://ghcr.io", "octocat", "ghcr-token"),
+			login("item-b", "vault-b", "https://quay.io", "other", "quay-token"),
+		)
+		var stdout strings.Builder
+		if err := Docker(backend, []string{tt.action}, strings.NewReader(tt.input), &stdout); (err != nil) != tt.expectErr {
+			t.Fatalf("%s: expected error %v, got %v", tt.name, tt.expectErr, err)
+		}
+		if stdout.String() != tt.output {
+			t.Fatalf("%s: expected output %q, got %q", tt.name, tt.output, stdout.String())
+		}
+		if len(backend.logins) != len(tt.logins) {
+			t.Fatalf("%s: expected %d logins, got %d", tt.name, len(tt.logins), len(backend.logins))
+		}
+		for _, login := range backend.logins {
+			if credential := login.Details.Username + ":" + login.Details.Password; tt.logins[login.Overview.URL] != credential {
+				t.Fatalf("%s: expected %s to hold %q, got %q", tt.name, login.Overview.URL, tt.logins[login.Overview.URL], credential)
+			}
+		}
+	}
+}
+
+func TestDockerWithoutVault(t *testing.T) {
+	backend := newFakeBackend()
+	backend.vault = ""
+	var stdout strings.Builder
+	if err := Docker(backend, []string{"list"}, strings.NewReader(""), &stdout); err == nil {
+		t.Fatalf("expected an error without a credential helper vault")
+	}
+	if stdout.String() != "no credential helper vault configured\n" {
+		t.Fatalf("expected the error on stdout, got %q", stdout.String())
+	}
+}
@@ -0,0 +1,163 @@
+package credhelper
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+)
+
+// gitCredential is a credential description in git's credential helper format (see gitcredentials(7))
+type gitCredential map[string]string
+
+// readGitCredential reads key=value lines until a blank line or the end of the input
+func readGitCredential(r io.Reader) (gitCredential, error) {
+	cred := make(gitCredential)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			break
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid credential line %q", line)
+		}
+		cred[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	// Newer versions of git may describe the remote as a single URL
+	if raw, ok := cred["url"]; ok && cred["host"] == "" {
+		u, err := url.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid credential url: %w", err)
+		}
+		cred["protocol"] = u.Scheme
+		cred["host"] = u.Host
+		cred["path"] = strings.TrimPrefix(u.Path, "/")
+		if u.User != nil && cred["username"] == "" {
+			cred["username"] = u.User.Username()
+		}
+	}
+	if cred["protocol"] == "" || cred["host"] == "" {
+		return nil, fmt.Errorf("credential is missing the protocol or host")
+	}
+	return cred, nil
+}
+
+// URL returns the remote the credential is for
+func (c gitCredential) URL() *url.URL {
+	return &url.URL{Scheme: c["protocol"], Host: c["host"], Path: "/" + c["path"]}
+}
+
+// DescribeGit returns the approval prompt for a git credential helper request
+func DescribeGit(args []string, stdin []byte) string {
+	action := describeAction(args)
+	cred, err := readGitCredential(bytes.NewReader(stdin))
+	if err != nil {
+		return fmt.Sprintf("Allow git to %s a login?", action)
+	}
+	return fmt.Sprintf("Allow git to %s the login for %s?", action, cred["host"])
+}
+
+// Git implements the get, store and erase actions of a git credential helper
+func Git(backend Backend, args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: git-credential-openvault <get|store|erase>")
+	}
+	cred, err := readGitCredential(stdin)
+	if err != nil {
+		return err
+	}
+	switch args[0] {
+	case "get":
+		return gitGet(backend, cred, stdout)
+	case "store":
+		return gitStore(backend, cred)
+	case "erase":
+		return gitErase(backend, cred)
+	default:
+		// Unknown actions must be ignored so newer versions of git keep working
+		return nil
+	}
+}
+
+// gitGet writes the username and password of the best matching login. Nothing is written if there is no match,
+// so git falls back to its other helpers or prompts.
+func gitGet(backend Backend, cred gitCredential, stdout io.Writer) error {
+	matches, err := backend.FindLogins(cred.URL(), cred["username"])
+	if err != nil {
+		return err
+	}
+	if len(matches) == 0 {
+		return nil
+	}
+	details := matches[0].Details
+	fmt.Fprintf(stdout, "username=%s\n", details.Username)
+	fmt.Fprintf(stdout, "password=%s\n", details.Password)
+	return nil
+}
+
+// gitStore saves a credential git has used successfully, updating the password of a matching login or creating a
+// new login in the default vault
+func gitStore(backend Backend, cred gitCredential) error {
+	if cred["username"] == "" || cred["password"] == "" {
+		return nil
+	}
+	matches, err := backend.FindLogins(cred.URL(), cred["username"])
+	if err != nil {
+		return err
+	}
+	if len(matches) > 0 {
+		match := matches[0]
+		if match.Details.Password == cred["password"] {
+			return nil
+		}
+		details := *match.Details
+		details.Password = cred["password"]
+		return backend.UpdateItem(match.ItemID, match.Overview, &details)
+	}
+	vaultId, err := backend.DefaultVault()
+	if err != nil {
+		return err
+	}
+	u := cred.URL()
+	if cred["path"] == "" {
+		u.Path = ""
+	}
+	return backend.CreateItem(vaultId, &structs.VaultItemOverview{
+		Title: cred["host"],
+		URL:   u.String(),
+	}, &structs.VaultItemDetails{
+		Category: structs.CategoryLogin,
+		Username: cred["username"],
+		Password: cred["password"],
+	})
+}
+
+// gitErase moves logins holding a credential git reports as rejected to the trash. Only logins with the same
+// username and password are removed, so a newer password is never discarded.
+func gitErase(backend Backend, cred gitCredential) error {
+	if cred["password"] == "" {
+		return nil
+	}
+	matches, err := backend.FindLogins(cred.URL(), cred["username"])
+	if err != nil {
+		return err
+	}
+	for _, match := range matches {
+		if match.Details.Password != cred["password"] {
+			continue
+		}
+		if err := backend.TrashItem(match.ItemID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package credhelper
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestReadGitCredential(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		expected  gitCredential
+		expectErr bool
+	}{
+		{
+			name:     "attributes",
+			input:    "protocol=https\nhost=github.com\nusername=octocat\n\n",
+			expected: gitCredential{"protocol": "https", "host": "github.com", "username": "octocat"},
+		},
+		{
+			name:     "stops at blank line",
+			input:    "protocol=https\nhost=github.com\n\nhost=example.com\n",
+			expected: gitCredential{"protocol": "https", "host": "github.com"},
+		},
+		{
+			name:     "value containing equals",
+			input:    "protocol=https\nhost=github.com\npassword=a=b\n",
+			expected: gitCredential{"protocol": "https", "host": "github.com", "password": "a=b"},
+		},
+		{
+			name:  "url",
+			input: "url=https://octocat@git.example.com:8443/org/repo.git\n",
+			expected: gitCredential{
+				"url":      "https://octocat@git.example.com:8443/org/repo.git",
+				"protocol": "https",
+				"host":     "git.example.com:8443",
+				"path":     "org/repo.git",
+				"username": "octocat",
+			},
+		},
+		{
+			name:     "url does not override host",
+			input:    "url=https://example.com/\nprotocol=https\nhost=github.com\n",
+			expected: gitCredential{"url": "https://example.com/", "protocol": "https", "host": "github.com"},
+		},
+		{name: "missing host", input: "protocol=https\n", expectErr: true},
+		{name: "invalid line", input: "protocol=https\nhost\n", expectErr: true},
+		{name: "empty", input: "", expectErr: true},
+	}
+	for _, tt := range tests {
+		cred, err := readGitCredential(strings.NewReader(tt.input))
+		if (err != nil) != tt.expectErr {
+			t.Fatalf("%s: expected error %v, got %v", tt.name, tt.expectErr, err)
+		}
+		if tt.expectErr {
+			continue
+		}
+		if len(cred) != len(tt.expected) {
+			t.Fatalf("%s: expected %v, got %v", tt.name, tt.expected, cred)
+		}
+		for key, value := range tt.expected {
+			if cred[key] != value {
+				t.Fatalf("%s: expected %s=%q, got %q", tt.name, key, value, cred[key])
+			}
+		}
+	}
+}
+
+func TestGit(t *testing.T) {
+	tests := []struct {
+		name   string
+		action string
+		input  string
+		output string
+		// Username and password of every login afterwards, by URL
+		logins map[string]string
+	}{
+		{
+			name:   "get",
+			action: "get",
+			input:  "protocol=https\nhost=github.com\npath=org/repo.git\n\n",
+			output: "username=octocat\npassword=org-token\n",
+			logins: map[string]string{"https://github.com": "octocat:hunter2", "https://github.com/org": "octocat:org-token"},
+		},
+		{
+			name:   "get with username",
+			action: "get",
+			input:  "protocol=https\nhost=github.com\nusername=hubot\n\n",
+			logins: map[string]string{"https://github.com": "octocat:hunter2", "https://github.com/org": "octocat:org-token"},
+		},
+		{
+			name:   "get without match",
+			action: "get",
+			input:  "protocol=https\nhost=gitlab.com\n\n",
+			logins: map[string]string{"https://github.com": "octocat:hunter2", "https://github.com/org": "octocat:org-token"},
+		},
+		{
+			name:   "store new password",
+			action: "store",
+			input:  "protocol=https\nhost=github.com\nusername=octocat\npassword=changed\n\n",
+			logins: map[string]string{"https://github.com": "octocat:changed", "https://github.com/org": "octocat:org-token"},
+		},
+		{
+			name:   "store new login",
+			action: "store",
+			input:  "protocol=https\nhost=gitlab.com\nusername=tanuki\npassword=secret\n\n",
+			logins: map[string]string{"https://github.com": "octocat:hunter2", "https://github.com/org": "octocat:org-token", "https://gitlab.com": "tanuki:secret"},
+		},
+		{
+			name:   "erase",
+			action: "erase",
+			input:  "protocol=https\nhost=github.com\nusername=octocat\npassword=hunter2\n\n",
+			logins: map[string]string{"https://github.com/org": "octocat:org-token"},
+		},
+		{
+			name:   "erase keeps newer password",
+			action: "erase",
+			input:  "protocol=https\nhost=github.com\nusername=octocat\npassword=outdated\n\n",
+			logins: map[string]string{"https://github.com": "octocat:hunter2", "https://github.com/org": "octocat:org-token"},
+		},
+		{
+			name:   "unknown action",
+			action: "capability",
+			input:  "protocol=https\nhost=github.com\n\n",
+			logins: map[string]string{"https://github.com": "octocat:hunter2", "https://github.com/org": "octocat:org-token"},
+		},
+	}
+	for _, tt := range tests {
+		backend := newFakeBackend(
+			login("item-a", "vault-a", "https://github.com", "octocat", "hunter2"),
+			login("item-b", "vault-a", "https://github.com/org", "octocat", "org-token"),
+		)
+		var stdout strings.Builder
+		if err := Git(backend, []string{tt.action}, strings.NewReader(tt.input), &stdout); err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if stdout.String() != tt.output {
+			t.Fatalf("%s: expected output %q, got %q", tt.name, tt.output, stdout.String())
+		}
+		if len(backend.logins) != len(tt.logins) {
+			t.Fatalf("%s: expected %d logins, got %d", tt.name, len(tt.logins), len(backend.logins))
+		}
+		for _, login := range backend.logins {
+			if credential := login.Details.Username + ":" + login.Details.Password; tt.logins[login.Overview.URL] != credential {
+				t.Fatalf("%s: expected %s to hold %q, got %q", tt.name, login.Overview.URL, tt.logins[login.Overview.URL], credential)
+			}
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package exporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+
+	"github.com/google/uuid"
+)
+
+// Bitwarden item types
+const (
+	bitwardenLogin      = 1
+	bitwardenSecureNote = 2
+	bitwardenCard       = 3
+	bitwardenIdentity   = 4
+	bitwardenSSHKey     = 5
+)
+
+// Bitwarden custom field types
+const (
+	bitwardenFieldText   = 0
+	bitwardenFieldHidden = 1
+)
+
+type bitwardenExport struct {
+	Encrypted bool               `json:"encrypted"`
+	Folders   []*bitwardenFolder `json:"folders"`
+	Items     []*bitwardenItem   `json:"items"`
+}
+
+type bitwardenFolder struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type bitwardenItem struct {
+	ID         string                   `json:"id"`
+	FolderID   string                   `json:"folderId"`
+	Type       int                      `json:"type"`
+	Reprompt   int                      `json:"reprompt"`
+	Name       string                   `json:"name"`
+	Notes      string                   `json:"notes,omitempty"`
+	Favorite   bool                     `json:"favorite"`
+	Fields     []*bitwardenField        `json:"fields,omitempty"`
+	Login      *bitwardenLoginData      `json:"login,omitempty"`
+	SecureNote *bitwardenSecureNoteData `json:"secureNote,omitempty"`
+	Card       *bitwardenCardData       `json:"card,omitempty"`
+	Identity   map[string]string        `json:"identity,omitempty"`
+	SSHKey     *bitwardenSSHKeyData     `json:"sshKey,omitempty"`
+}
+
+type bitwardenField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	Type  int    `json:"type"`
+}
+
+type bitwardenURI struct {
+	Match *int   `json:"match"`
+	URI   string `json:"uri"`
+}
+
+type bitwardenLoginData struct {
+	URIs     []*bitwardenURI `json:"uris"`
+	Username string          `json:"username"`
+	Password string          `json:"password"`
+	TOTP     string          `json:"totp,omitempty"`
+}
+
+type bitwardenSecureNoteData struct {
+	Type int `json:"type"`
+}
+
+type bitwardenCardData struct {
+	CardholderName string `json:"cardholderName"`
+	Number         string `json:"number"`
+	ExpMonth       string `json:"expMonth"`
+	ExpYear        string `json:"expYear"`
+	Code           string `json:"code"`
+}
+
+type bitwardenSSHKeyData struct {
+	PrivateKey     string `json:"privateKey"`
+	PublicKey      string `json:"publicKey"`
+	KeyFingerprint string `json:"keyFingerprint"`
+}
+
+// WriteBitwarden writes an unencrypted Bitwarden JSON export. Each vault becomes a folder. API credentials are
+// written as secure notes with custom fields, as Bitwarden has no equivalent item type, and attachments are not
+// exported.
+func WriteBitwarden(w io.Writer, data *structs.ExportData) (*Result, error) {
+	export := &bitwardenExport{
+		Folders: make([]*bitwardenFolder, 0, len(data.Vaults)),
+		Items:   make([]*bitwardenItem, 0),
+	}
+	result := &Result{}
+	for _, vault := range data.Vaults {
+		folder := &bitwardenFolder{ID: uuid.New().String(), Name: vault.Name}
+		export.Folders = append(export.Folders, folder)
+		for _, item := range vault.Items {
+			export.Items = append(export.Items, bitwardenItemFrom(item, folder.ID))
+			result.Items++
+			if len(item.Attachments) > 0 {
+				result.skip("%q: %d attachments", item.Overview.Title, len(item.Attachments))
+			}
+		}
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(export); err != nil {
+		return nil, fmt.Errorf("failed to write bitwarden export: %w", err)
+	}
+	return result, nil
+}
+
+func bitwardenItemFrom(item *structs.ExportItem, folderId string) *bitwardenItem {
+	details := item.Details
+	bwItem := &bitwardenItem{
+		ID:       uuid.New().String(),
+		FolderID: folderId,
+		Name:     item.Overview.Title,
+		Notes:    details.Notes,
+	}
+	// The first one-time password of a login fills its TOTP field; every other custom field is kept as is
+	var totp *structs.Field
+	switch details.Category {
+	case structs.CategoryLogin:
+		bwItem.Type = bitwardenLogin
+		bwItem.Login = &bitwardenLoginData{
+			URIs:     make([]*bitwardenURI, 0),
+			Username: details.Username,
+			Password: details.Password,
+		}
+		if item.Overview.URL != "" {
+			bwItem.Login.URIs = append(bwItem.Login.URIs, &bitwardenURI{URI: item.Overview.URL})
+		}
+		for _, section := range details.Sections {
+			for _, field := range section.Fields {
+				if field.Type == structs.FieldTypeOTP && totp == nil {
+					totp = field
+					bwItem.Login.TOTP = field.Value
+				}
+			}
+		}
+	case structs.CategoryCreditCard:
+		bwItem.Type = bitwardenCard
+		card := details.Card
+		if card == nil {
+			card = &structs.CreditCardDetails{}
+		}
+		bwItem.Card = &bitwardenCardData{
+			CardholderName: card.Cardholder,
+			Number:         card.Number,
+			Code:           card.CVV,
+		}
+		if month, year, ok := strings.Cut(card.Expiry, "/"); ok {
+			bwItem.Card.ExpMonth = strings.TrimPrefix(month, "0")
+			bwItem.Card.ExpYear = year
+		}
+		bwItem.addField("PIN", card.PIN, bitwardenFieldHidden)
+	case structs.CategoryIdentity:
+		bwItem.Type = bitwardenIdentity
+		identity := details.Identity
+		if identity == nil {
+			identity = &structs.IdentityDetails{}
+		}
+		bwItem.Identity = map[string]string{
+			"firstName":  identity.FirstName,
+			"lastName":   identity.LastName,
+			"email":      identity.Email,
+			"phone":      identity.Phone,
+			"address1":   identity.Address1,
+			"address2":   identity.Address2,
+			"city":       identity.City,
+			"state":      identity.State,
+			"postalCode": identity.PostalCode,
+			"country":    identity.Country,
+		}
+	case structs.CategorySSHKey:
+		bwItem.Type = bitwardenSSHKey
+		bwItem.SSHKey = &bitwardenSSHKeyData{}
+		if details.SSH != nil {
+			bwItem.SSHKey.PrivateKey = details.SSH.PrivateKey
+		}
+		if item.Overview.SSHKey != nil {
+			bwItem.SSHKey.PublicKey = item.Overview.SSHKey.PublicKey
+			bwItem.SSHKey.KeyFingerprint = item.Overview.SSHKey.Fingerprint
+		}
+	case structs.CategoryAPICredential:
+		bwItem.Type = bitwardenSecureNote
+		bwItem.SecureNote = &bitwardenSecureNoteData{}
+		if api := details.API; api != nil {
+			bwItem.addField("Key", api.Key, bitwardenFieldText)
+			bwItem.addField("Secret", api.Secret, bitwardenFieldHidden)
+			bwItem.addField("Endpoint", api.Endpoint, bitwardenFieldText)
+		}
+	default:
+		bwItem.Type = bitwardenSecureNote
+		bwItem.SecureNote = &bitwardenSecureNoteData{}
+	}
+	for _, section := range details.Sections {
+		for _, field := range section.Fields {
+			if field == totp {
+				continue
+			}
+			fieldType := bitwardenFieldText
+			if field.Concealed {
+				fieldType = bitwardenFieldHidden
+			}
+			bwItem.addField(field.Label, field.Value, fieldType)
+		}
+	}
+	return bwItem
+}
+
+// addField appends a custom field, ignoring empty values
+func (bwItem *bitwardenItem) addField(name string, value string, fieldType int) {
+	if value == "" {
+		return
+	}
+	bwItem.Fields = append(bwItem.Fields, &bitwardenField{Name: name, Value: value, Type: fieldType})
+}
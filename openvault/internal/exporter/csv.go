@@ -0,0 +1,58 @@
+package exporter
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+)
+
+// csvHeader names the columns of CSV exports. The names match the headers the CSV importer recognizes.
+var csvHeader = []string{"folder", "title", "url", "username", "password", "otp", "notes"}
+
+// WriteCSV writes logins and secure notes as CSV, one row per item, with the vault name as the folder. Other
+// categories and custom fields other than one-time passwords have no column and are not exported.
+func WriteCSV(w io.Writer, data *structs.ExportData) (*Result, error) {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return nil, fmt.Errorf("failed to write CSV export: %w", err)
+	}
+	result := &Result{}
+	for _, vault := range data.Vaults {
+		for _, item := range vault.Items {
+			details := item.Details
+			if details.Category != structs.CategoryLogin && details.Category != structs.CategorySecureNote {
+				result.skip("%q: %s items", item.Overview.Title, details.Category)
+				continue
+			}
+			otp := ""
+			fields := 0
+			for _, section := range details.Sections {
+				for _, field := range section.Fields {
+					if field.Type == structs.FieldTypeOTP && otp == "" {
+						otp = field.Value
+						continue
+					}
+					fields++
+				}
+			}
+			if fields > 0 {
+				result.skip("%q: %d custom fields", item.Overview.Title, fields)
+			}
+			if len(item.Attachments) > 0 {
+				result.skip("%q: %d attachments", item.Overview.Title, len(item.Attachments))
+			}
+			row := []string{vault.Name, item.Overview.Title, item.Overview.URL, details.Username, details.Password, otp, details.Notes}
+			if err := cw.Write(row); err != nil {
+				return nil, fmt.Errorf("failed to write CSV export: %w", err)
+			}
+			result.Items++
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return nil, fmt.Errorf("failed to write CSV export: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,94 @@
+// Package exporter writes the decrypted contents of vaults to export files: an encrypted OpenVault archive, or the
+// plaintext formats of other password managers.
+package exporter
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+)
+
+// Format identifies an export file format
+type Format string
+
+var (
+	// FormatOpenVault is an archive encrypted with an export password (see structs.ExportArchive)
+	FormatOpenVault Format = "openvault"
+	FormatBitwarden Format = "bitwarden"
+	FormatCSV       Format = "csv"
+)
+
+// Formats lists every supported export format
+var Formats = []Format{
+	FormatOpenVault,
+	FormatBitwarden,
+	FormatCSV,
+}
+
+// IsValid returns whether the format is supported
+func (f Format) IsValid() bool {
+	for _, format := range Formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// IsPlaintext returns whether the format writes secrets without encrypting them
+func (f Format) IsPlaintext() bool {
+	return f != FormatOpenVault
+}
+
+type Options struct {
+	// Password the archive is encrypted with (FormatOpenVault only)
+	Password string
+	// PBKDF2 rounds used to derive the archive key from the password (FormatOpenVault only)
+	Rounds int
+}
+
+// Result describes what was exported
+type Result struct {
+	Items       int
+	Attachments int
+	// Descriptions of data which cannot be represented in the format and was not exported
+	Skipped []string
+}
+
+func (r *Result) skip(format string, args ...any) {
+	r.Skipped = append(r.Skipped, fmt.Sprintf(format, args...))
+}
+
+// Export writes the data to w in the given format
+func Export(format Format, w io.Writer, data *structs.ExportData, opts Options) (*Result, error) {
+	switch format {
+	case FormatOpenVault:
+		return writeArchive(w, data, opts)
+	case FormatBitwarden:
+		return WriteBitwarden(w, data)
+	case FormatCSV:
+		return WriteCSV(w, data)
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+func writeArchive(w io.Writer, data *structs.ExportData, opts Options) (*Result, error) {
+	ea, err := structs.SealExportArchive(opts.Password, opts.Rounds, data)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.NewEncoder(w).Encode(ea); err != nil {
+		return nil, fmt.Errorf("failed to write export archive: %w", err)
+	}
+	result := &Result{}
+	for _, vault := range data.Vaults {
+		for _, item := range vault.Items {
+			result.Items++
+			result.Attachments += len(item.Attachments)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,118 @@
+package exporter
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/BradHacker/openvault/openvault/internal/importer"
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+)
+
+func sampleExportData(t *testing.T) *structs.ExportData {
+	t.Helper()
+	items := []*structs.ExportItem{
+		{
+			Overview: &structs.VaultItemOverview{Title: "GitHub", URL: "https://github.com"},
+			Details: &structs.VaultItemDetails{
+				Category: structs.CategoryLogin,
+				Username: "octocat",
+				Password: "hunter2",
+				Notes:    "2FA enabled",
+				Sections: []*structs.Section{{Title: "Custom Fields", Fields: []*structs.Field{
+					{Label: "One-Time Password", Type: structs.FieldTypeOTP, Value: "JBSWY3DPEHPK3PXP"},
+					{Label: "Recovery", Type: structs.FieldTypeConcealed, Value: "abcd-efgh"},
+				}}},
+			},
+			Attachments: []*structs.ExportAttachment{{Name: "codes.txt", ContentType: "text/plain", Data: []byte("1234")}},
+		},
+		{
+			Overview: &structs.VaultItemOverview{Title: "Visa"},
+			Details: &structs.VaultItemDetails{
+				Category: structs.CategoryCreditCard,
+				Card:     &structs.CreditCardDetails{Cardholder: "Jane Doe", Number: "4111111111111111", Expiry: "03/2030", CVV: "123"},
+			},
+		},
+		{
+			Overview: &structs.VaultItemOverview{Title: "Stripe"},
+			Details: &structs.VaultItemDetails{
+				Category: structs.CategoryAPICredential,
+				API:      &structs.APICredentialDetails{Key: "pk_test", Secret: "sk_test"},
+			},
+		},
+	}
+	for _, item := range items {
+		if err := structs.NormalizeItem(item.Overview, item.Details); err != nil {
+			t.Fatalf("item %q is invalid: %v", item.Overview.Title, err)
+		}
+	}
+	return &structs.ExportData{Vaults: []*structs.ExportVault{{Name: "Personal", Items: items}}}
+}
+
+func TestExportRoundTrip(t *testing.T) {
+	tests := []struct {
+		format       Format
+		importFormat importer.Format
+		items        int
+		attachments  int
+		skipped      int
+	}{
+		{FormatOpenVault, importer.FormatOpenVault, 3, 1, 0},
+		// Attachments are not exported
+		{FormatBitwarden, importer.FormatBitwarden, 3, 0, 1},
+		// Only logins are exported, without their other custom fields and attachments
+		{FormatCSV, importer.FormatCSV, 1, 0, 4},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var buf bytes.Buffer
+			result, err := Export(tt.format, &buf, sampleExportData(t), Options{Password: "correct horse", Rounds: 1000})
+			if err != nil {
+				t.Fatalf("failed to export: %v", err)
+			}
+			if result.Items != tt.items || len(result.Skipped) != tt.skipped {
+				t.Fatalf("exported %d items and skipped %q", result.Items, result.Skipped)
+			}
+			imported, err := importer.Parse(tt.importFormat, buf.Bytes(), importer.Options{Password: "correct horse"})
+			if err != nil {
+				t.Fatalf("failed to import export: %v", err)
+			}
+			if len(imported.Items) != tt.items {
+				t.Fatalf("imported %d items, expected %d", len(imported.Items), tt.items)
+			}
+			login := imported.Items[0]
+			if login.Folder != "Personal" || login.Details.Username != "octocat" || login.Details.Password != "hunter2" || login.Details.Notes != "2FA enabled" {
+				t.Fatalf("unexpected login in %q: %+v", login.Folder, login.Details)
+			}
+			if f := login.Details.FieldByLabel("One-Time Password"); f == nil || f.Value != "JBSWY3DPEHPK3PXP" {
+				t.Fatalf("expected the one-time password to be exported")
+			}
+			if len(login.Attachments) != tt.attachments {
+				t.Fatalf("imported %d attachments, expected %d", len(login.Attachments), tt.attachments)
+			}
+			for _, item := range imported.Items {
+				if err := structs.NormalizeItem(&item.Overview, &item.Details); err != nil {
+					t.Fatalf("item %q is invalid: %v", item.Overview.Title, err)
+				}
+			}
+			if tt.format != FormatCSV {
+				card := imported.Items[1].Details.Card
+				if card == nil || card.Number != "4111111111111111" || card.Expiry != "03/2030" {
+					t.Fatalf("unexpected card %+v", card)
+				}
+				if f := imported.Items[2].Details.FieldByLabel("Secret"); tt.format == FormatBitwarden && (f == nil || f.Value != "sk_test") {
+					t.Fatalf("expected the API secret to be a custom field")
+				}
+			}
+		})
+	}
+}
+
+func TestExportArchivePassword(t *testing.T) {
+	var buf bytes.Buffer
+	if _, err := Export(FormatOpenVault, &buf, sampleExportData(t), Options{Password: " ", Rounds: 1000}); err == nil {
+		t.Fatalf("expected an empty export password to fail")
+	}
+	if !FormatBitwarden.IsPlaintext() || !FormatCSV.IsPlaintext() || FormatOpenVault.IsPlaintext() {
+		t.Fatalf("unexpected plaintext formats")
+	}
+}
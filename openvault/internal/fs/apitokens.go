@@ -0,0 +1,32 @@
+package fs
+
+import (
+	"path"
+
+	"github.com/BradHacker/openvault/openvault/internal/constants"
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+)
+
+var apiTokensFile = path.Join(constants.DATA_DIR, "api_tokens.json")
+
+// APITokenStore is a map of local API tokens by their token IDs
+type APITokenStore map[string]*structs.APIToken
+
+// LoadAPITokens loads the API tokens from the filesystem.
+//
+// The file is created when the first token is created, so a missing file results in an empty store.
+func LoadAPITokens() (APITokenStore, error) {
+	ats := make(APITokenStore)
+	if !exists(apiTokensFile) {
+		return ats, nil
+	}
+	if err := load(apiTokensFile, &ats); err != nil {
+		return nil, err
+	}
+	return ats, nil
+}
+
+// SaveAPITokens saves the API tokens to the filesystem
+func SaveAPITokens(ats APITokenStore) error {
+	return save(apiTokensFile, ats)
+}
@@ -0,0 +1,81 @@
+package fs
+
+import (
+	"io"
+	"os"
+	"path"
+
+	"github.com/BradHacker/openvault/openvault/internal/constants"
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+)
+
+var attachmentsFile = path.Join(constants.DATA_DIR, "attachments.json")
+var attachmentsDir = path.Join(constants.DATA_DIR, "attachments")
+
+// AttachmentStore is a map of attachment records by their IDs
+type AttachmentStore map[string]*structs.EncryptedAttachment
+
+// LoadAttachments loads the attachment records from the filesystem.
+//
+// The file is created when the first attachment is added, so a missing file results in an empty store.
+func LoadAttachments() (AttachmentStore, error) {
+	as := make(AttachmentStore)
+	if !exists(attachmentsFile) {
+		return as, nil
+	}
+	if err := load(attachmentsFile, &as); err != nil {
+		return nil, err
+	}
+	return as, nil
+}
+
+// SaveAttachments saves the attachment records to the filesystem
+func SaveAttachments(as AttachmentStore) error {
+	return save(attachmentsFile, as)
+}
+
+// attachmentBlobPath returns the path of the encrypted blob for an attachment
+func attachmentBlobPath(attachmentId string) string {
+	return path.Join(attachmentsDir, attachmentId+".blob")
+}
+
+// CreateAttachmentBlob creates the file the encrypted blob of an attachment is written to
+func CreateAttachmentBlob(attachmentId string) (*os.File, error) {
+	if err := os.MkdirAll(attachmentsDir, 0700); err != nil {
+		return nil, err
+	}
+	return os.OpenFile(attachmentBlobPath(attachmentId), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+}
+
+// OpenAttachmentBlob opens the encrypted blob of an attachment for reading
+func OpenAttachmentBlob(attachmentId string) (*os.File, error) {
+	return os.Open(attachmentBlobPath(attachmentId))
+}
+
+// CopyAttachmentBlob copies the encrypted blob of an attachment to a new attachment ID
+func CopyAttachmentBlob(srcAttachmentId string, dstAttachmentId string) error {
+	src, err := OpenAttachmentBlob(srcAttachmentId)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := CreateAttachmentBlob(dstAttachmentId)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		RemoveAttachmentBlob(dstAttachmentId)
+		return err
+	}
+	return dst.Close()
+}
+
+// RemoveAttachmentBlob deletes the encrypted blob of an attachment. Missing blobs are not an error.
+func RemoveAttachmentBlob(attachmentId string) error {
+	err := os.Remove(attachmentBlobPath(attachmentId))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,32 @@
+package fs
+
+import (
+	"path"
+
+	"github.com/BradHacker/openvault/openvault/internal/constants"
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+)
+
+var browserPairingsFile = path.Join(constants.DATA_DIR, "browser_pairings.json")
+
+// BrowserPairingStore is a map of paired browser extensions by their extension IDs
+type BrowserPairingStore map[string]*structs.BrowserPairing
+
+// LoadBrowserPairings loads the browser extension pairings from the filesystem.
+//
+// The file is created when the first extension is paired, so a missing file results in an empty store.
+func LoadBrowserPairings() (BrowserPairingStore, error) {
+	bps := make(BrowserPairingStore)
+	if !exists(browserPairingsFile) {
+		return bps, nil
+	}
+	if err := load(browserPairingsFile, &bps); err != nil {
+		return nil, err
+	}
+	return bps, nil
+}
+
+// SaveBrowserPairings saves the browser extension pairings to the filesystem
+func SaveBrowserPairings(bps BrowserPairingStore) error {
+	return save(browserPairingsFile, bps)
+}
@@ -0,0 +1,32 @@
+package fs
+
+import (
+	"path"
+
+	"github.com/BradHacker/openvault/openvault/internal/constants"
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+)
+
+var itemHistoryFile = path.Join(constants.DATA_DIR, "item_history.json")
+
+// ItemHistoryStore is a map of item revisions (oldest first) by their item IDs
+type ItemHistoryStore map[string][]*structs.EncryptedItemRevision
+
+// LoadItemHistory loads the item history from the filesystem.
+//
+// The file is created on the first item edit, so a missing file results in an empty store.
+func LoadItemHistory() (ItemHistoryStore, error) {
+	ihs := make(ItemHistoryStore)
+	if !exists(itemHistoryFile) {
+		return ihs, nil
+	}
+	if err := load(itemHistoryFile, &ihs); err != nil {
+		return nil, err
+	}
+	return ihs, nil
+}
+
+// SaveItemHistory saves the item history to the filesystem
+func SaveItemHistory(ihs ItemHistoryStore) error {
+	return save(itemHistoryFile, ihs)
+}
@@ -155,8 +155,9 @@ func InitializeAccount(opts *InitOptions) (AccountStore, KeySetStore, VaultStore
 		UpdatedAt: time.Now().Format(time.RFC3339),
 	}
 	err = itemOverview.Update(vaultKey, &structs.VaultItemOverview{
-		Title: fmt.Sprintf("OpenVault (%s)", account.Email),
-		URL:   "https://openvault.io",
+		Category: structs.CategoryLogin,
+		Title:    fmt.Sprintf("OpenVault (%s)", account.Email),
+		URL:      "https://openvault.io",
 	})
 	if err != nil {
 		return nil, nil, nil, nil, nil, fmt.Errorf("failed to encrypt item overview: %w", err)
@@ -168,6 +169,8 @@ func InitializeAccount(opts *InitOptions) (AccountStore, KeySetStore, VaultStore
 		UpdatedAt: time.Now().Format(time.RFC3339),
 	}
 	err = itemDetails.Update(vaultKey, &structs.VaultItemDetails{
+		Version:  structs.VaultItemDetailsVersion,
+		Category: structs.CategoryLogin,
 		Username: opts.Email,
 		Password: opts.Password,
 		Notes:    "**Welcome to OpenVault!**\n\nThis is your first item. It contains your OpenVault login details.\n\nYou can edit or delete this item, and create new items in your vault. For more information, visit [OpenVault](https://openvault.io).\n\nEnjoy using OpenVault!",
@@ -0,0 +1,31 @@
+package fs
+
+import (
+	"os"
+	"path"
+
+	"github.com/BradHacker/openvault/openvault/internal/constants"
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+)
+
+var settingsFile = path.Join(constants.CONFIG_DIR, "settings.json")
+
+// LoadSettings loads the settings from the filesystem. Any settings missing from the file keep their default values.
+func LoadSettings() (*structs.Settings, error) {
+	s := structs.DefaultSettings()
+	if !exists(settingsFile) {
+		return s, nil
+	}
+	if err := load(settingsFile, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// SaveSettings saves the settings to the filesystem
+func SaveSettings(s *structs.Settings) error {
+	if err := os.MkdirAll(constants.CONFIG_DIR, 0755); err != nil {
+		return err
+	}
+	return save(settingsFile, s)
+}
@@ -0,0 +1,46 @@
+package fs
+
+import (
+	"path"
+
+	"github.com/BradHacker/openvault/openvault/internal/constants"
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+)
+
+var importedSharesFile = path.Join(constants.DATA_DIR, "imported_shares.json")
+
+// ImportedSharesStore is a map of import times by the IDs of one-time share packages which have been imported
+type ImportedSharesStore map[string]string
+
+// LoadImportedShares loads the imported share records from the filesystem.
+//
+// The file is created on the first one-time import, so a missing file results in an empty store.
+func LoadImportedShares() (ImportedSharesStore, error) {
+	iss := make(ImportedSharesStore)
+	if !exists(importedSharesFile) {
+		return iss, nil
+	}
+	if err := load(importedSharesFile, &iss); err != nil {
+		return nil, err
+	}
+	return iss, nil
+}
+
+// SaveImportedShares saves the imported share records to the filesystem
+func SaveImportedShares(iss ImportedSharesStore) error {
+	return save(importedSharesFile, iss)
+}
+
+// LoadSharePackage loads a share package from the given file
+func LoadSharePackage(filename string) (*structs.SharePackage, error) {
+	var sp structs.SharePackage
+	if err := load(filename, &sp); err != nil {
+		return nil, err
+	}
+	return &sp, nil
+}
+
+// SaveSharePackage saves a share package to the given file
+func SaveSharePackage(filename string, sp *structs.SharePackage) error {
+	return save(filename, sp)
+}
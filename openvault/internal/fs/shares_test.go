@@ -0,0 +1,75 @@
+package fs
+
+import (
+	"crypto/rand"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+
+	"github.com/BradHacker/openvault/cryptolib"
+)
+
+// newTestKeySet returns a key set with its account unlock key
+func newTestKeySet(t *testing.T) (*cryptolib.KeySet, *cryptolib.JWK) {
+	t.Helper()
+	aukBytes := make([]byte, 32)
+	rand.Read(aukBytes)
+	auk, err := cryptolib.NewKey(cryptolib.AccountUnlockKeyID, aukBytes, cryptolib.KeyUseEncryption)
+	if err != nil {
+		t.Fatalf("failed to create AUK: %v", err)
+	}
+	ks, err := cryptolib.GenerateKeySet(auk, &cryptolib.Salt{}, 1000)
+	if err != nil {
+		t.Fatalf("failed to generate keyset: %v", err)
+	}
+	return ks, auk
+}
+
+// The package is signed over its JSON encoding, so the signature must survive being written and read back
+func TestSharePackageSaveLoad(t *testing.T) {
+	sender, senderAUK := newTestKeySet(t)
+	recipient, recipientAUK := newTestKeySet(t)
+	signKey, err := sender.SigningKey(senderAUK)
+	if err != nil {
+		t.Fatalf("failed to decrypt signing key: %v", err)
+	}
+	defer signKey.Close()
+	privKey, err := recipient.PrivateKey(recipientAUK)
+	if err != nil {
+		t.Fatalf("failed to decrypt private key: %v", err)
+	}
+	defer privKey.Close()
+	sp, err := structs.SealSharePackage(&structs.Account{ID: "sender", Email: "sender@example.com"}, signKey, sender.PubSignKey, recipient.PubKey,
+		&structs.VaultItemOverview{Title: "Database", URL: "https://db.example.com"},
+		&structs.VaultItemDetails{Username: "admin", Password: "hunter2"},
+		structs.ShareOptions{ExpiresAt: time.Now().Add(time.Hour), OneTime: true},
+	)
+	if err != nil {
+		t.Fatalf("failed to seal share package: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "share.json")
+	if err := SaveSharePackage(path, sp); err != nil {
+		t.Fatalf("failed to save share package: %v", err)
+	}
+	loaded, err := LoadSharePackage(path)
+	if err != nil {
+		t.Fatalf("failed to load share package: %v", err)
+	}
+	overview, details, err := loaded.Open(privKey, time.Now())
+	if err != nil {
+		t.Fatalf("failed to open loaded share package: %v", err)
+	}
+	if overview.Title != "Database" || details.Password != "hunter2" || !loaded.OneTime || loaded.SenderEmail != "sender@example.com" {
+		t.Fatalf("unexpected loaded package contents: %+v %+v", overview, details)
+	}
+	want, err := sp.SenderFingerprint()
+	if err != nil {
+		t.Fatalf("failed to fingerprint sender: %v", err)
+	}
+	if got, err := loaded.SenderFingerprint(); err != nil || got != want {
+		t.Fatalf("expected sender fingerprint %s, got %s (%v)", want, got, err)
+	}
+}
@@ -0,0 +1,326 @@
+// Package generator creates random passwords, passphrases and PIN codes. Every choice is sampled uniformly with
+// crypto/rand, so the entropy of a result follows from the size of the sets it was drawn from.
+package generator
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"unicode"
+)
+
+// Character classes of generated passwords
+const (
+	Lowercase = "abcdefghijklmnopqrstuvwxyz"
+	Uppercase = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	Digits    = "0123456789"
+	Symbols   = "!@#$%^&*()-_=+[]{};:,.<>/?~"
+	// Ambiguous characters are easily confused with each other when read or typed by hand
+	Ambiguous = "0Oo1Il|5S2Z8B"
+)
+
+const (
+	MinPasswordLength = 4
+	MaxPasswordLength = 128
+	MinWords          = 3
+	MaxWords          = 20
+	MinPINLength      = 4
+	MaxPINLength      = 32
+	// Largest expected number of draws before a password meets the minimum character counts
+	maxPasswordDraws = 1000
+)
+
+var (
+	ErrNoCharacterClasses = errors.New("at least one character class must be enabled")
+	ErrEmptyWordlist      = errors.New("the word list is empty")
+)
+
+type Result struct {
+	Value string `json:"value"`
+	// Estimated entropy of the result in bits
+	Entropy float64 `json:"entropy"`
+}
+
+type PasswordOptions struct {
+	Length    int  `json:"length"`
+	Lowercase bool `json:"lowercase"`
+	Uppercase bool `json:"uppercase"`
+	Digits    bool `json:"digits"`
+	Symbols   bool `json:"symbols"`
+	// Minimum number of characters from each enabled class
+	MinLowercase int `json:"min_lowercase"`
+	MinUppercase int `json:"min_uppercase"`
+	MinDigits    int `json:"min_digits"`
+	MinSymbols   int `json:"min_symbols"`
+	// Leave out characters which are easily confused with each other (see Ambiguous)
+	ExcludeAmbiguous bool `json:"exclude_ambiguous"`
+}
+
+// DefaultPasswordOptions returns options for a 20 character password using every character class
+func DefaultPasswordOptions() PasswordOptions {
+	return PasswordOptions{
+		Length:           20,
+		Lowercase:        true,
+		Uppercase:        true,
+		Digits:           true,
+		Symbols:          true,
+		MinLowercase:     1,
+		MinUppercase:     1,
+		MinDigits:        1,
+		MinSymbols:       1,
+		ExcludeAmbiguous: false,
+	}
+}
+
+type PassphraseOptions struct {
+	Words     int    `json:"words"`
+	Separator string `json:"separator"`
+	// Capitalize the first letter of each word
+	Capitalize bool `json:"capitalize"`
+	// Append a random digit to one of the words
+	IncludeDigit bool `json:"include_digit"`
+}
+
+// DefaultPassphraseOptions returns options for a six word passphrase separated by hyphens
+func DefaultPassphraseOptions() PassphraseOptions {
+	return PassphraseOptions{
+		Words:        6,
+		Separator:    "-",
+		Capitalize:   false,
+		IncludeDigit: false,
+	}
+}
+
+// characterClass is a set of characters a password must contain at least min of
+type characterClass struct {
+	chars string
+	min   int
+}
+
+// Password generates a password of opts.Length characters drawn from the enabled character classes, containing
+// at least the minimum number of characters of each class. Every such password is equally likely.
+func Password(opts PasswordOptions) (*Result, error) {
+	if opts.Length < MinPasswordLength || opts.Length > MaxPasswordLength {
+		return nil, fmt.Errorf("password length must be between %d and %d", MinPasswordLength, MaxPasswordLength)
+	}
+	classes := make([]characterClass, 0, 4)
+	for _, class := range []struct {
+		enabled bool
+		chars   string
+		min     int
+	}{
+		{opts.Lowercase, Lowercase, opts.MinLowercase},
+		{opts.Uppercase, Uppercase, opts.MinUppercase},
+		{opts.Digits, Digits, opts.MinDigits},
+		{opts.Symbols, Symbols, opts.MinSymbols},
+	} {
+		if !class.enabled {
+			continue
+		}
+		if class.min < 0 {
+			return nil, fmt.Errorf("minimum character counts cannot be negative")
+		}
+		chars := class.chars
+		if opts.ExcludeAmbiguous {
+			chars = removeChars(chars, Ambiguous)
+		}
+		classes = append(classes, characterClass{chars: chars, min: class.min})
+	}
+	if len(classes) == 0 {
+		return nil, ErrNoCharacterClasses
+	}
+	alphabet := ""
+	required := 0
+	for _, class := range classes {
+		alphabet += class.chars
+		required += class.min
+	}
+	if required > opts.Length {
+		return nil, fmt.Errorf("minimum character counts add up to %d, more than the password length of %d", required, opts.Length)
+	}
+
+	entropy := passwordEntropy(classes, opts.Length)
+	// Every character is drawn from the whole alphabet and the password is drawn again until it meets the
+	// minimums, so each password meeting them is equally likely and entropy is exact. Minimums which almost no
+	// password meets are refused instead of drawing for a long time.
+	if entropy-float64(opts.Length)*math.Log2(float64(len(alphabet))) < -math.Log2(maxPasswordDraws) {
+		return nil, fmt.Errorf("minimum character counts are too high for a password of %d characters", opts.Length)
+	}
+	password := make([]byte, opts.Length)
+	for {
+		for i := range password {
+			c, err := randomChar(alphabet)
+			if err != nil {
+				return nil, err
+			}
+			password[i] = c
+		}
+		if meetsMinimums(password, classes) {
+			break
+		}
+	}
+	return &Result{
+		Value:   string(password),
+		Entropy: entropy,
+	}, nil
+}
+
+// meetsMinimums returns whether a password contains the minimum number of characters of each class
+func meetsMinimums(password []byte, classes []characterClass) bool {
+	for _, class := range classes {
+		count := 0
+		for _, c := range password {
+			if strings.IndexByte(class.chars, c) >= 0 {
+				count++
+			}
+		}
+		if count < class.min {
+			return false
+		}
+	}
+	return true
+}
+
+// passwordEntropy returns log2 of the number of passwords of the given length which contain the minimum number of
+// characters of each class, counted by the number of characters taken from each class.
+func passwordEntropy(classes []characterClass, length int) float64 {
+	// counts[n] is the number of distinct sequences of n characters from the classes seen so far which meet their
+	// minimums, not counting their order relative to characters of later classes
+	counts := make([]*big.Float, length+1)
+	for n := range counts {
+		counts[n] = new(big.Float)
+	}
+	counts[0].SetInt64(1)
+	for _, class := range classes {
+		next := make([]*big.Float, length+1)
+		for n := range next {
+			next[n] = new(big.Float)
+		}
+		size := big.NewFloat(float64(len(class.chars)))
+		for n := 0; n <= length; n++ {
+			if counts[n].Sign() == 0 {
+				continue
+			}
+			// Choose k characters of this class and interleave them with the n previous characters
+			perChar := new(big.Float).SetInt64(1)
+			for k := 0; n+k <= length; k++ {
+				if k > 0 {
+					perChar.Mul(perChar, size)
+				}
+				if k < class.min {
+					continue
+				}
+				positions := new(big.Float).SetInt(new(big.Int).Binomial(int64(n+k), int64(k)))
+				term := new(big.Float).Mul(counts[n], positions)
+				term.Mul(term, perChar)
+				next[n+k].Add(next[n+k], term)
+			}
+		}
+		counts = next
+	}
+	return log2(counts[length])
+}
+
+// Passphrase generates a passphrase of opts.Words words drawn from the word list
+func Passphrase(wordlist []string, opts PassphraseOptions) (*Result, error) {
+	if len(wordlist) == 0 {
+		return nil, ErrEmptyWordlist
+	}
+	if opts.Words < MinWords || opts.Words > MaxWords {
+		return nil, fmt.Errorf("passphrases must have between %d and %d words", MinWords, MaxWords)
+	}
+	words := make([]string, opts.Words)
+	for i := range words {
+		n, err := randomIndex(len(wordlist))
+		if err != nil {
+			return nil, err
+		}
+		words[i] = wordlist[n]
+		if opts.Capitalize {
+			words[i] = capitalize(words[i])
+		}
+	}
+	entropy := float64(opts.Words) * math.Log2(float64(len(wordlist)))
+	if opts.IncludeDigit {
+		word, err := randomIndex(len(words))
+		if err != nil {
+			return nil, err
+		}
+		digit, err := randomChar(Digits)
+		if err != nil {
+			return nil, err
+		}
+		words[word] += string(digit)
+		entropy += math.Log2(float64(len(words) * len(Digits)))
+	}
+	return &Result{
+		Value:   strings.Join(words, opts.Separator),
+		Entropy: entropy,
+	}, nil
+}
+
+// PIN generates a numeric PIN code of the given length
+func PIN(length int) (*Result, error) {
+	if length < MinPINLength || length > MaxPINLength {
+		return nil, fmt.Errorf("PIN length must be between %d and %d", MinPINLength, MaxPINLength)
+	}
+	pin := make([]byte, length)
+	for i := range pin {
+		c, err := randomChar(Digits)
+		if err != nil {
+			return nil, err
+		}
+		pin[i] = c
+	}
+	return &Result{
+		Value:   string(pin),
+		Entropy: float64(length) * math.Log2(float64(len(Digits))),
+	}, nil
+}
+
+// randomIndex returns a uniformly random integer in [0, n). rand.Int rejects out of range samples instead of
+// reducing them modulo n, so there is no bias towards low values.
+func randomIndex(n int) (int, error) {
+	i, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, fmt.Errorf("failed to read random bytes: %w", err)
+	}
+	return int(i.Int64()), nil
+}
+
+func randomChar(chars string) (byte, error) {
+	i, err := randomIndex(len(chars))
+	if err != nil {
+		return 0, err
+	}
+	return chars[i], nil
+}
+
+func removeChars(chars string, remove string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(remove, r) {
+			return -1
+		}
+		return r
+	}, chars)
+}
+
+func capitalize(word string) string {
+	for i, r := range word {
+		return word[:i] + string(unicode.ToUpper(r)) + word[i+len(string(r)):]
+	}
+	return word
+}
+
+func log2(f *big.Float) float64 {
+	if f.Sign() == 0 {
+		return 0
+	}
+	mant := new(big.Float)
+	exp := f.MantExp(mant)
+	m, _ := mant.Float64()
+	return float64(exp) + math.Log2(m)
+}
@@ -0,0 +1,169 @@
+package generator
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestPassword(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    PasswordOptions
+		entropy float64
+		wantErr bool
+	}{
+		{"digits", PasswordOptions{Length: 10, Digits: true}, 10 * math.Log2(10), false},
+		{"lowercase and digits", PasswordOptions{Length: 8, Lowercase: true, Digits: true}, 8 * math.Log2(36), false},
+		// Requiring a digit rules out the 26^8 passwords without one
+		{"minimum digits", PasswordOptions{Length: 8, Lowercase: true, Digits: true, MinDigits: 1}, math.Log2(math.Pow(36, 8) - math.Pow(26, 8)), false},
+		{"default", DefaultPasswordOptions(), 0, false},
+		{"ambiguous", PasswordOptions{Length: 64, Lowercase: true, Uppercase: true, Digits: true, ExcludeAmbiguous: true}, 0, false},
+		{"no classes", PasswordOptions{Length: 20}, 0, true},
+		{"too short", PasswordOptions{Length: 3, Digits: true}, 0, true},
+		{"minimums exceed length", PasswordOptions{Length: 4, Digits: true, Symbols: true, MinDigits: 3, MinSymbols: 2}, 0, true},
+		// Fewer than one in a thousand passwords drawn from every class would have nine symbols
+		{"minimums too high", PasswordOptions{Length: 10, Lowercase: true, Uppercase: true, Digits: true, Symbols: true, MinSymbols: 9}, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Password(tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Password() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(result.Value) != tt.opts.Length {
+				t.Fatalf("expected %d characters, got %q", tt.opts.Length, result.Value)
+			}
+			counts := map[string]int{}
+			for _, c := range result.Value {
+				for _, class := range []string{Lowercase, Uppercase, Digits, Symbols} {
+					if strings.ContainsRune(class, c) {
+						counts[class]++
+					}
+				}
+				if tt.opts.ExcludeAmbiguous && strings.ContainsRune(Ambiguous, c) {
+					t.Fatalf("password %q contains ambiguous character %q", result.Value, c)
+				}
+			}
+			for _, class := range []struct {
+				enabled bool
+				chars   string
+				min     int
+			}{
+				{tt.opts.Lowercase, Lowercase, tt.opts.MinLowercase},
+				{tt.opts.Uppercase, Uppercase, tt.opts.MinUppercase},
+				{tt.opts.Digits, Digits, tt.opts.MinDigits},
+				{tt.opts.Symbols, Symbols, tt.opts.MinSymbols},
+			} {
+				if !class.enabled && counts[class.chars] > 0 || counts[class.chars] < class.min {
+					t.Fatalf("password %q has %d characters of %q", result.Value, counts[class.chars], class.chars)
+				}
+			}
+			if tt.entropy != 0 && math.Abs(result.Entropy-tt.entropy) > 1e-6 {
+				t.Fatalf("expected %f bits of entropy, got %f", tt.entropy, result.Entropy)
+			}
+		})
+	}
+}
+
+func TestPIN(t *testing.T) {
+	result, err := PIN(6)
+	if err != nil {
+		t.Fatalf("failed to generate PIN: %v", err)
+	}
+	if len(result.Value) != 6 || strings.Trim(result.Value, Digits) != "" {
+		t.Fatalf("unexpected PIN %q", result.Value)
+	}
+	if math.Abs(result.Entropy-6*math.Log2(10)) > 1e-6 {
+		t.Fatalf("unexpected entropy %f", result.Entropy)
+	}
+	if _, err := PIN(3); err == nil {
+		t.Fatalf("expected a 3 digit PIN to fail")
+	}
+}
+
+// testWordlist returns a word list in the EFF format with a distinct word for every roll of five dice
+func testWordlist() string {
+	var b strings.Builder
+	for i := 0; i < EFFWordlistSize; i++ {
+		rolls := ""
+		for n := i; len(rolls) < 5; n /= 6 {
+			rolls = string(rune('1'+n%6)) + rolls
+		}
+		fmt.Fprintf(&b, "%s\tword%d\n", rolls, i)
+	}
+	return b.String()
+}
+
+func TestParseWordlist(t *testing.T) {
+	words, err := ParseWordlist(strings.NewReader(testWordlist()))
+	if err != nil {
+		t.Fatalf("failed to parse word list: %v", err)
+	}
+	if len(words) != EFFWordlistSize || words[0] != "word0" || words[EFFWordlistSize-1] != "word7775" {
+		t.Fatalf("unexpected words %q ... %q", words[0], words[len(words)-1])
+	}
+
+	invalid := map[string]string{
+		"short":          "11111\tabacus\n",
+		"bad rolls":      strings.Replace(testWordlist(), "11111\t", "11117\t", 1),
+		"duplicate word": strings.Replace(testWordlist(), "word1\n", "word0\n", 1),
+	}
+	for name, data := range invalid {
+		if _, err := ParseWordlist(strings.NewReader(data)); err == nil {
+			t.Fatalf("%s: expected the word list to be rejected", name)
+		}
+	}
+}
+
+func TestEFFWordlist(t *testing.T) {
+	words, err := EFFWordlist()
+	if err != nil {
+		t.Fatalf("failed to parse the embedded word list: %v", err)
+	}
+	if len(words) != EFFWordlistSize || words[0] != "abacus" || words[EFFWordlistSize-1] != "zoom" {
+		t.Fatalf("unexpected words %q ... %q", words[0], words[len(words)-1])
+	}
+}
+
+func TestPassphrase(t *testing.T) {
+	words, err := ParseWordlist(strings.NewReader(testWordlist()))
+	if err != nil {
+		t.Fatalf("failed to parse word list: %v", err)
+	}
+	result, err := Passphrase(words, PassphraseOptions{Words: 6, Separator: ".", Capitalize: true})
+	if err != nil {
+		t.Fatalf("failed to generate passphrase: %v", err)
+	}
+	parts := strings.Split(result.Value, ".")
+	if len(parts) != 6 {
+		t.Fatalf("expected 6 words, got %q", result.Value)
+	}
+	for _, part := range parts {
+		if !strings.HasPrefix(part, "Word") {
+			t.Fatalf("expected capitalized words, got %q", result.Value)
+		}
+	}
+	// log2(7776) is about 12.9 bits per word
+	if math.Abs(result.Entropy-6*math.Log2(EFFWordlistSize)) > 1e-6 {
+		t.Fatalf("unexpected entropy %f", result.Entropy)
+	}
+
+	withDigit, err := Passphrase(words, PassphraseOptions{Words: 4, Separator: " ", IncludeDigit: true})
+	if err != nil {
+		t.Fatalf("failed to generate passphrase: %v", err)
+	}
+	if withDigit.Entropy <= 4*math.Log2(EFFWordlistSize) {
+		t.Fatalf("expected the digit to add entropy, got %f", withDigit.Entropy)
+	}
+	if _, err := Passphrase(words, PassphraseOptions{Words: 2}); err == nil {
+		t.Fatalf("expected a 2 word passphrase to fail")
+	}
+	if _, err := Passphrase(nil, DefaultPassphraseOptions()); err == nil {
+		t.Fatalf("expected an empty word list to fail")
+	}
+}
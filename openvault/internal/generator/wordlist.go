@@ -0,0 +1,81 @@
+package generator
+
+import (
+	"bufio"
+	_ "embed"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// EFFWordlistSize is the number of words in the EFF large word list, one for each roll of five dice
+const EFFWordlistSize = 7776
+
+// The EFF large word list (https://www.eff.org/dice), published by the Electronic Frontier Foundation under the
+// Creative Commons Attribution 3.0 United States license
+//
+//go:embed eff_large_wordlist.txt
+var effLargeWordlist string
+
+// EFFWordlist returns the words of the embedded EFF large word list, indexed by their dice rolls. The list is
+// parsed with ParseWordlist the first time it is used.
+var EFFWordlist = sync.OnceValues(func() ([]string, error) {
+	return ParseWordlist(strings.NewReader(effLargeWordlist))
+})
+
+// ParseWordlist reads a diceware word list in the format of the EFF large word list: one word per line, each
+// preceded by the five dice rolls which select it (e.g. "11111\tabacus"). Every roll from 11111 to 66666 must
+// appear exactly once and words must be unique, so each word is chosen with the same probability.
+func ParseWordlist(r io.Reader) ([]string, error) {
+	words := make([]string, EFFWordlistSize)
+	seen := make(map[string]bool, EFFWordlistSize)
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		fields := strings.Fields(text)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: expected dice rolls and a word", line)
+		}
+		index, err := diceIndex(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		word := fields[1]
+		if words[index] != "" {
+			return nil, fmt.Errorf("line %d: dice rolls %s appear more than once", line, fields[0])
+		}
+		if seen[word] {
+			return nil, fmt.Errorf("line %d: word %q appears more than once", line, word)
+		}
+		words[index] = word
+		seen[word] = true
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read word list: %w", err)
+	}
+	if len(seen) != EFFWordlistSize {
+		return nil, fmt.Errorf("word list has %d words, expected %d", len(seen), EFFWordlistSize)
+	}
+	return words, nil
+}
+
+// diceIndex converts five dice rolls to an index in [0, EFFWordlistSize)
+func diceIndex(rolls string) (int, error) {
+	if len(rolls) != 5 {
+		return 0, fmt.Errorf("invalid dice rolls %q", rolls)
+	}
+	index := 0
+	for _, r := range rolls {
+		if r < '1' || r > '6' {
+			return 0, fmt.Errorf("invalid dice rolls %q", rolls)
+		}
+		index = index*6 + int(r-'1')
+	}
+	return index, nil
+}
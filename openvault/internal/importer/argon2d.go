@@ -0,0 +1,194 @@
+package importer
+
+import (
+	"encoding/binary"
+	"math/bits"
+
+	"golang.org/x/crypto/blake2b"
+)
+
+// Argon2 parameters fixed by RFC 9106
+const (
+	argon2Version    = 0x13
+	argon2TypeD      = 0
+	argon2BlockWords = 128
+	argon2SyncPoints = 4
+)
+
+type argon2Block [argon2BlockWords]uint64
+
+// argon2d derives a key with Argon2d, the default KDF of KDBX 4 databases, which golang.org/x/crypto/argon2 does
+// not expose. Memory is in KiB. Lanes are processed one after another, which gives the same result as
+// processing them in parallel.
+func argon2d(password, salt, secret, data []byte, time, memory, lanes, keyLen uint32) []byte {
+	h0 := make([]byte, blake2b.Size+8)
+	h, _ := blake2b.New512(nil)
+	for _, v := range []uint32{lanes, keyLen, memory, time, argon2Version, argon2TypeD} {
+		h.Write(binary.LittleEndian.AppendUint32(nil, v))
+	}
+	for _, input := range [][]byte{password, salt, secret, data} {
+		h.Write(binary.LittleEndian.AppendUint32(nil, uint32(len(input))))
+		h.Write(input)
+	}
+	h.Sum(h0[:0])
+
+	memory = max(memory/(argon2SyncPoints*lanes)*(argon2SyncPoints*lanes), 2*argon2SyncPoints*lanes)
+	laneLength := memory / lanes
+	segmentLength := laneLength / argon2SyncPoints
+	B := make([]argon2Block, memory)
+	buf := make([]byte, 1024)
+	for lane := uint32(0); lane < lanes; lane++ {
+		binary.LittleEndian.PutUint32(h0[blake2b.Size+4:], lane)
+		for i := uint32(0); i < 2; i++ {
+			binary.LittleEndian.PutUint32(h0[blake2b.Size:], i)
+			blake2bLong(buf, h0)
+			for j := range B[lane*laneLength+i] {
+				B[lane*laneLength+i][j] = binary.LittleEndian.Uint64(buf[j*8:])
+			}
+		}
+	}
+
+	for pass := uint32(0); pass < time; pass++ {
+		for slice := uint32(0); slice < argon2SyncPoints; slice++ {
+			for lane := uint32(0); lane < lanes; lane++ {
+				index := uint32(0)
+				if pass == 0 && slice == 0 {
+					index = 2
+				}
+				offset := lane*laneLength + slice*segmentLength + index
+				for ; index < segmentLength; index, offset = index+1, offset+1 {
+					prev := offset - 1
+					if index == 0 && slice == 0 {
+						prev += laneLength
+					}
+					// Argon2d picks the reference block from the previous block's contents
+					random := B[prev][0]
+					refLane := uint32(random>>32) % lanes
+					if pass == 0 && slice == 0 {
+						refLane = lane
+					}
+					ref := refLane*laneLength + argon2RefIndex(random, laneLength, segmentLength, pass, slice, index, refLane == lane)
+					argon2Compress(&B[offset], &B[prev], &B[ref], pass > 0)
+				}
+			}
+		}
+	}
+
+	final := B[laneLength-1]
+	for lane := uint32(1); lane < lanes; lane++ {
+		for i, v := range B[lane*laneLength+laneLength-1] {
+			final[i] ^= v
+		}
+	}
+	for i, v := range final {
+		binary.LittleEndian.PutUint64(buf[i*8:], v)
+	}
+	key := make([]byte, keyLen)
+	blake2bLong(key, buf)
+	return key
+}
+
+// argon2RefIndex maps the pseudo-random value to a block within the reference lane (RFC 9106 section 3.4.1.2)
+func argon2RefIndex(random uint64, laneLength, segmentLength, pass, slice, index uint32, sameLane bool) uint32 {
+	var area, start uint32
+	if pass == 0 {
+		area = slice * segmentLength
+		if slice == 0 || sameLane {
+			area += index
+		}
+	} else {
+		area = laneLength - segmentLength
+		if sameLane {
+			area += index
+		}
+		start = ((slice + 1) % argon2SyncPoints) * segmentLength
+	}
+	if index == 0 || sameLane {
+		area--
+	}
+	x := random & 0xFFFFFFFF
+	x = (x * x) >> 32
+	x = (x * uint64(area)) >> 32
+	return uint32((uint64(start) + uint64(area) - (x + 1)) % uint64(laneLength))
+}
+
+// argon2Compress sets out to G(x, y), or XORs G(x, y) into out for passes after the first
+func argon2Compress(out, x, y *argon2Block, xor bool) {
+	var r, z argon2Block
+	for i := range r {
+		r[i] = x[i] ^ y[i]
+	}
+	z = r
+	var v [16]uint64
+	// Rows of 16 words, then columns of 2 words from each row
+	for row := 0; row < 8; row++ {
+		copy(v[:], z[row*16:row*16+16])
+		blamka(&v)
+		copy(z[row*16:row*16+16], v[:])
+	}
+	for col := 0; col < 8; col++ {
+		for i := 0; i < 8; i++ {
+			v[2*i], v[2*i+1] = z[i*16+2*col], z[i*16+2*col+1]
+		}
+		blamka(&v)
+		for i := 0; i < 8; i++ {
+			z[i*16+2*col], z[i*16+2*col+1] = v[2*i], v[2*i+1]
+		}
+	}
+	for i := range out {
+		if xor {
+			out[i] ^= z[i] ^ r[i]
+		} else {
+			out[i] = z[i] ^ r[i]
+		}
+	}
+}
+
+// blamka is the permutation P: the BLAKE2b round with multiplications added
+func blamka(v *[16]uint64) {
+	g := func(a, b, c, d int) {
+		v[a] = v[a] + v[b] + 2*uint64(uint32(v[a]))*uint64(uint32(v[b]))
+		v[d] = bits.RotateLeft64(v[d]^v[a], -32)
+		v[c] = v[c] + v[d] + 2*uint64(uint32(v[c]))*uint64(uint32(v[d]))
+		v[b] = bits.RotateLeft64(v[b]^v[c], -24)
+		v[a] = v[a] + v[b] + 2*uint64(uint32(v[a]))*uint64(uint32(v[b]))
+		v[d] = bits.RotateLeft64(v[d]^v[a], -16)
+		v[c] = v[c] + v[d] + 2*uint64(uint32(v[c]))*uint64(uint32(v[d]))
+		v[b] = bits.RotateLeft64(v[b]^v[c], -63)
+	}
+	g(0, 4, 8, 12)
+	g(1, 5, 9, 13)
+	g(2, 6, 10, 14)
+	g(3, 7, 11, 15)
+	g(0, 5, 10, 15)
+	g(1, 6, 11, 12)
+	g(2, 7, 8, 13)
+	g(3, 4, 9, 14)
+}
+
+// blake2bLong is the variable length hash function H' of RFC 9106 section 3.3
+func blake2bLong(out []byte, in []byte) {
+	prefix := binary.LittleEndian.AppendUint32(nil, uint32(len(out)))
+	if len(out) <= blake2b.Size {
+		h, _ := blake2b.New(len(out), nil)
+		h.Write(prefix)
+		h.Write(in)
+		h.Sum(out[:0])
+		return
+	}
+	h, _ := blake2b.New512(nil)
+	h.Write(prefix)
+	h.Write(in)
+	v := h.Sum(nil)
+	copy(out, v[:32])
+	out = out[32:]
+	for len(out) > blake2b.Size {
+		sum := blake2b.Sum512(v)
+		v = sum[:]
+		copy(out, v[:32])
+		out = out[32:]
+	}
+	h, _ = blake2b.New(len(out), nil)
+	h.Write(v)
+	h.Sum(out[:0])
+}
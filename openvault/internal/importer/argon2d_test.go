@@ -0,0 +1,19 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestArgon2d(t *testing.T) {
+	// Test vector from RFC 9106 section 5.1
+	password := bytes.Repeat([]byte{0x01}, 32)
+	salt := bytes.Repeat([]byte{0x02}, 16)
+	secret := bytes.Repeat([]byte{0x03}, 8)
+	data := bytes.Repeat([]byte{0x04}, 12)
+	want := "512b391b6f1162975371d30919734294f868e3be3984f3c1a13a4db9fabe4acb"
+	if got := hex.EncodeToString(argon2d(password, salt, secret, data, 3, 32, 4, 32)); got != want {
+		t.Fatalf("argon2d = %s, expected %s", got, want)
+	}
+}
@@ -0,0 +1,366 @@
+package importer
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// Bitwarden item types
+const (
+	bitwardenLogin      = 1
+	bitwardenSecureNote = 2
+	bitwardenCard       = 3
+	bitwardenIdentity   = 4
+	bitwardenSSHKey     = 5
+)
+
+// Bitwarden custom field types
+const (
+	bitwardenFieldText    = 0
+	bitwardenFieldHidden  = 1
+	bitwardenFieldBoolean = 2
+	bitwardenFieldLinked  = 3
+)
+
+// Bitwarden key derivation functions
+const (
+	bitwardenPBKDF2   = 0
+	bitwardenArgon2id = 1
+)
+
+// Largest key derivation parameters accepted from a password protected export, matching the limits of the
+// Bitwarden clients, so a crafted export cannot stall the import or exhaust memory
+const (
+	bitwardenMaxPBKDF2Iterations  = 2_000_000
+	bitwardenMaxArgon2Iterations  = 10
+	bitwardenMaxArgon2Memory      = 1024 // MiB
+	bitwardenMaxArgon2Parallelism = 16
+)
+
+// bitwardenExport is a Bitwarden JSON export. Password protected exports only contain the encryption fields, with
+// the unencrypted export encrypted in Data.
+type bitwardenExport struct {
+	Encrypted         bool               `json:"encrypted"`
+	PasswordProtected bool               `json:"passwordProtected"`
+	Salt              string             `json:"salt"`
+	KdfType           int                `json:"kdfType"`
+	KdfIterations     int                `json:"kdfIterations"`
+	KdfMemory         int                `json:"kdfMemory"`
+	KdfParallelism    int                `json:"kdfParallelism"`
+	EncKeyValidation  string             `json:"encKeyValidation_DO_NOT_EDIT"`
+	Data              string             `json:"data"`
+	Folders           []*bitwardenFolder `json:"folders"`
+	Collections       []*bitwardenFolder `json:"collections"`
+	Items             []*bitwardenItem   `json:"items"`
+}
+
+type bitwardenFolder struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type bitwardenItem struct {
+	Type            int                  `json:"type"`
+	Name            string               `json:"name"`
+	Notes           string               `json:"notes"`
+	FolderID        string               `json:"folderId"`
+	CollectionIDs   []string             `json:"collectionIds"`
+	Fields          []*bitwardenField    `json:"fields"`
+	Login           *bitwardenLoginData  `json:"login"`
+	Card            *bitwardenCardData   `json:"card"`
+	Identity        map[string]string    `json:"identity"`
+	SSHKey          *bitwardenSSHKeyData `json:"sshKey"`
+	PasswordHistory []json.RawMessage    `json:"passwordHistory"`
+}
+
+type bitwardenField struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+	Type  int    `json:"type"`
+}
+
+type bitwardenLoginData struct {
+	URIs []struct {
+		URI string `json:"uri"`
+	} `json:"uris"`
+	Username         string            `json:"username"`
+	Password         string            `json:"password"`
+	TOTP             string            `json:"totp"`
+	Fido2Credentials []json.RawMessage `json:"fido2Credentials"`
+}
+
+type bitwardenCardData struct {
+	CardholderName string `json:"cardholderName"`
+	Brand          string `json:"brand"`
+	Number         string `json:"number"`
+	ExpMonth       string `json:"expMonth"`
+	ExpYear        string `json:"expYear"`
+	Code           string `json:"code"`
+}
+
+type bitwardenSSHKeyData struct {
+	PrivateKey string `json:"privateKey"`
+}
+
+// Identity fields without an equivalent in IdentityDetails, in the order they are added as custom fields
+var bitwardenIdentityFields = []struct {
+	key       string
+	label     string
+	concealed bool
+}{
+	{"title", "Title", false},
+	{"company", "Company", false},
+	{"username", "Username", false},
+	{"ssn", "Social Security Number", true},
+	{"passportNumber", "Passport Number", true},
+	{"licenseNumber", "License Number", true},
+}
+
+// ParseBitwarden reads a Bitwarden JSON export. Password protected exports are decrypted with the export
+// password; exports encrypted with the Bitwarden account key cannot be read outside Bitwarden.
+func ParseBitwarden(data []byte, opts Options) (*Result, error) {
+	var export bitwardenExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("failed to parse bitwarden export: %w", err)
+	}
+	if export.Encrypted {
+		if !export.PasswordProtected {
+			return nil, fmt.Errorf("%w: exports encrypted with the bitwarden account key are not supported; export with a file password instead", ErrUnsupportedFormat)
+		}
+		if opts.Password == "" {
+			return nil, ErrPasswordRequired
+		}
+		plaintext, err := export.decrypt(opts.Password)
+		if err != nil {
+			return nil, err
+		}
+		export = bitwardenExport{}
+		if err := json.Unmarshal(plaintext, &export); err != nil {
+			return nil, fmt.Errorf("failed to parse decrypted bitwarden export: %w", err)
+		}
+	}
+
+	folders := make(map[string]string)
+	for _, folder := range append(export.Folders, export.Collections...) {
+		folders[folder.ID] = folder.Name
+	}
+	result := &Result{}
+	for _, bwItem := range export.Items {
+		item := &Item{
+			Folder:   folders[bwItem.FolderID],
+			Overview: structs.VaultItemOverview{Title: bwItem.Name},
+			Details:  structs.VaultItemDetails{Notes: bwItem.Notes},
+		}
+		if item.Folder == "" && len(bwItem.CollectionIDs) > 0 {
+			item.Folder = folders[bwItem.CollectionIDs[0]]
+		}
+		if !bitwardenItemDetails(bwItem, item, result) {
+			continue
+		}
+		for _, field := range bwItem.Fields {
+			switch field.Type {
+			case bitwardenFieldText, bitwardenFieldBoolean:
+				addField(&item.Details, customSection, &structs.Field{Label: field.Name, Value: field.Value})
+			case bitwardenFieldHidden:
+				addField(&item.Details, customSection, &structs.Field{Label: field.Name, Type: structs.FieldTypeConcealed, Value: field.Value})
+			default:
+				result.skip("%q: linked field %q", bwItem.Name, field.Name)
+			}
+		}
+		if len(bwItem.PasswordHistory) > 0 {
+			result.skip("%q: password history", bwItem.Name)
+		}
+		result.Items = append(result.Items, item)
+	}
+	return result, nil
+}
+
+// bitwardenItemDetails fills in the category specific details of an item. It returns false if the item type is
+// not supported.
+func bitwardenItemDetails(bwItem *bitwardenItem, item *Item, result *Result) bool {
+	details := &item.Details
+	switch bwItem.Type {
+	case bitwardenLogin:
+		details.Category = structs.CategoryLogin
+		login := bwItem.Login
+		if login == nil {
+			return true
+		}
+		details.Username = login.Username
+		details.Password = login.Password
+		for i, uri := range login.URIs {
+			if i == 0 {
+				item.Overview.URL = uri.URI
+			} else {
+				addField(details, customSection, &structs.Field{Label: "Website", Type: structs.FieldTypeURL, Value: uri.URI})
+			}
+		}
+		if login.TOTP != "" {
+			addOTPField(details, login.TOTP)
+		}
+		if len(login.Fido2Credentials) > 0 {
+			result.skip("%q: passkey", bwItem.Name)
+		}
+	case bitwardenSecureNote:
+		details.Category = structs.CategorySecureNote
+	case bitwardenCard:
+		details.Category = structs.CategoryCreditCard
+		card := bwItem.Card
+		if card == nil {
+			card = &bitwardenCardData{}
+		}
+		details.Card = &structs.CreditCardDetails{
+			Cardholder: card.CardholderName,
+			Number:     card.Number,
+			Expiry:     cardExpiry(card.ExpMonth, card.ExpYear),
+			CVV:        card.Code,
+		}
+		addField(details, customSection, &structs.Field{Label: "Brand", Value: card.Brand})
+	case bitwardenIdentity:
+		details.Category = structs.CategoryIdentity
+		identity := bwItem.Identity
+		details.Identity = &structs.IdentityDetails{
+			FirstName:  strings.TrimSpace(identity["firstName"] + " " + identity["middleName"]),
+			LastName:   identity["lastName"],
+			Email:      identity["email"],
+			Phone:      identity["phone"],
+			Address1:   identity["address1"],
+			Address2:   strings.TrimSpace(identity["address2"] + " " + identity["address3"]),
+			City:       identity["city"],
+			State:      identity["state"],
+			PostalCode: identity["postalCode"],
+			Country:    identity["country"],
+		}
+		for _, field := range bitwardenIdentityFields {
+			fieldType := structs.FieldTypeText
+			if field.concealed {
+				fieldType = structs.FieldTypeConcealed
+			}
+			addField(details, customSection, &structs.Field{Label: field.label, Type: fieldType, Value: identity[field.key]})
+		}
+	case bitwardenSSHKey:
+		if bwItem.SSHKey == nil || bwItem.SSHKey.PrivateKey == "" {
+			result.skip("%q: SSH key without a private key", bwItem.Name)
+			return false
+		}
+		details.Category = structs.CategorySSHKey
+		details.SSH = &structs.SSHKeyDetails{PrivateKey: bwItem.SSHKey.PrivateKey}
+	default:
+		result.skip("%q: unsupported item type %d", bwItem.Name, bwItem.Type)
+		return false
+	}
+	return true
+}
+
+// cardExpiry formats an expiry month and year as MM/YYYY, or returns an empty string if either is missing
+func cardExpiry(month string, year string) string {
+	if month == "" || year == "" {
+		return ""
+	}
+	if len(month) == 1 {
+		month = "0" + month
+	}
+	if len(year) == 2 {
+		year = "20" + year
+	}
+	return month + "/" + year
+}
+
+// decrypt returns the unencrypted export held by a password protected export
+func (e *bitwardenExport) decrypt(password string) ([]byte, error) {
+	var key []byte
+	var err error
+	switch e.KdfType {
+	case bitwardenPBKDF2:
+		if e.KdfIterations <= 0 || e.KdfIterations > bitwardenMaxPBKDF2Iterations {
+			return nil, fmt.Errorf("%w: unsupported pbkdf2 iterations %d in bitwarden export", ErrUnsupportedFormat, e.KdfIterations)
+		}
+		key, err = pbkdf2.Key(sha256.New, password, []byte(e.Salt), e.KdfIterations, 32)
+		if err != nil {
+			return nil, fmt.Errorf("failed to derive export key: %w", err)
+		}
+	case bitwardenArgon2id:
+		if e.KdfIterations <= 0 || e.KdfIterations > bitwardenMaxArgon2Iterations || e.KdfMemory <= 0 || e.KdfMemory > bitwardenMaxArgon2Memory || e.KdfParallelism <= 0 || e.KdfParallelism > bitwardenMaxArgon2Parallelism {
+			return nil, fmt.Errorf("%w: unsupported argon2 parameters in bitwarden export", ErrUnsupportedFormat)
+		}
+		salt := sha256.Sum256([]byte(e.Salt))
+		memoryKiB := uint64(e.KdfMemory) * 1024
+		key = argon2.IDKey([]byte(password), salt[:], uint32(e.KdfIterations), uint32(memoryKiB), uint8(e.KdfParallelism), 32)
+	default:
+		return nil, fmt.Errorf("%w: unknown bitwarden kdf type %d", ErrUnsupportedFormat, e.KdfType)
+	}
+	defer clear(key)
+	// The derived key is stretched into separate encryption and MAC keys
+	encKey, err := hkdf.Expand(sha256.New, key, "enc", 32)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(encKey)
+	macKey, err := hkdf.Expand(sha256.New, key, "mac", 32)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(macKey)
+	if e.EncKeyValidation != "" {
+		if _, err := decryptBitwardenString(e.EncKeyValidation, encKey, macKey); err != nil {
+			return nil, err
+		}
+	}
+	return decryptBitwardenString(e.Data, encKey, macKey)
+}
+
+var errBitwardenEncString = errors.New("invalid encrypted data in bitwarden export")
+
+// decryptBitwardenString decrypts an AES-256-CBC with HMAC-SHA256 encrypted string ("2.iv|data|mac"). A MAC
+// mismatch means the password is wrong.
+func decryptBitwardenString(encString string, encKey []byte, macKey []byte) ([]byte, error) {
+	encType, rest, ok := strings.Cut(encString, ".")
+	if !ok || encType != "2" {
+		return nil, errBitwardenEncString
+	}
+	parts := strings.Split(rest, "|")
+	if len(parts) != 3 {
+		return nil, errBitwardenEncString
+	}
+	var decoded [3][]byte
+	for i, part := range parts {
+		var err error
+		if decoded[i], err = base64.StdEncoding.DecodeString(part); err != nil {
+			return nil, errBitwardenEncString
+		}
+	}
+	iv, ciphertext, mac := decoded[0], decoded[1], decoded[2]
+	h := hmac.New(sha256.New, macKey)
+	h.Write(iv)
+	h.Write(ciphertext)
+	if !hmac.Equal(h.Sum(nil), mac) {
+		return nil, ErrWrongPassword
+	}
+	if len(iv) != aes.BlockSize || len(ciphertext) == 0 || len(ciphertext)%aes.BlockSize != 0 {
+		return nil, errBitwardenEncString
+	}
+	block, err := aes.NewCipher(encKey)
+	if err != nil {
+		return nil, err
+	}
+	plaintext := make([]byte, len(ciphertext))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plaintext, ciphertext)
+	padding := int(plaintext[len(plaintext)-1])
+	if padding == 0 || padding > aes.BlockSize || padding > len(plaintext) {
+		return nil, errBitwardenEncString
+	}
+	return plaintext[:len(plaintext)-padding], nil
+}
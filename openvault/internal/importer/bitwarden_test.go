@@ -0,0 +1,192 @@
+package importer
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+
+	"golang.org/x/crypto/argon2"
+)
+
+const bitwardenSample = `{
+  "encrypted": false,
+  "folders": [{"id": "f1", "name": "Work"}],
+  "items": [
+    {
+      "type": 1, "name": "GitHub", "notes": "2FA enabled", "folderId": "f1",
+      "fields": [
+        {"name": "Recovery", "value": "abcd-efgh", "type": 1},
+        {"name": "Enterprise", "value": "true", "type": 2},
+        {"name": "Linked", "value": null, "type": 3, "linkedId": 100}
+      ],
+      "login": {
+        "uris": [{"match": null, "uri": "https://github.com"}, {"match": null, "uri": "https://gist.github.com"}],
+        "username": "octocat", "password": "hunter2", "totp": "JBSWY3DPEHPK3PXP",
+        "fido2Credentials": [{"credentialId": "x"}]
+      }
+    },
+    {"type": 2, "name": "Wifi", "notes": "password123", "folderId": null, "secureNote": {"type": 0}},
+    {
+      "type": 3, "name": "Visa", "folderId": null,
+      "card": {"cardholderName": "Jane Doe", "brand": "Visa", "number": "4111111111111111", "expMonth": "3", "expYear": "2030", "code": "123"}
+    },
+    {
+      "type": 4, "name": "Me", "folderId": "f1",
+      "identity": {"firstName": "Jane", "middleName": null, "lastName": "Doe", "email": "jane@example.com", "ssn": "123-45-6789", "city": "Springfield"}
+    },
+    {"type": 9, "name": "Future"}
+  ]
+}`
+
+func TestParseBitwarden(t *testing.T) {
+	result, err := ParseBitwarden([]byte(bitwardenSample), Options{})
+	if err != nil {
+		t.Fatalf("failed to parse export: %v", err)
+	}
+	checkBitwardenSample(t, result)
+}
+
+func checkBitwardenSample(t *testing.T, result *Result) {
+	t.Helper()
+	if len(result.Items) != 4 {
+		t.Fatalf("parsed %d items, expected 4", len(result.Items))
+	}
+	for _, item := range result.Items {
+		if err := structs.NormalizeItem(&item.Overview, &item.Details); err != nil {
+			t.Fatalf("item %q is invalid: %v", item.Overview.Title, err)
+		}
+	}
+
+	login := result.Items[0]
+	if login.Folder != "Work" || login.Overview.URL != "https://github.com" || login.Details.Username != "octocat" || login.Details.Password != "hunter2" {
+		t.Fatalf("unexpected login %+v %+v", login.Overview, login.Details)
+	}
+	if f := login.Details.FieldByLabel("One-Time Password"); f == nil || f.Type != structs.FieldTypeOTP {
+		t.Fatalf("expected the TOTP secret to be imported as an OTP field")
+	}
+	if f := login.Details.FieldByLabel("Recovery"); f == nil || !f.Concealed {
+		t.Fatalf("expected the hidden field to be concealed")
+	}
+	if f := login.Details.FieldByLabel("Website"); f == nil || f.Value != "https://gist.github.com" {
+		t.Fatalf("expected the second URI to be imported as a field")
+	}
+
+	if note := result.Items[1]; note.Details.Category != structs.CategorySecureNote || note.Details.Notes != "password123" || note.Folder != "" {
+		t.Fatalf("unexpected note %+v", note.Details)
+	}
+	if card := result.Items[2].Details.Card; card == nil || card.Expiry != "03/2030" || card.CVV != "123" {
+		t.Fatalf("unexpected card %+v", card)
+	}
+	identity := result.Items[3].Details
+	if identity.Identity == nil || identity.Identity.FirstName != "Jane" || identity.Identity.City != "Springfield" {
+		t.Fatalf("unexpected identity %+v", identity.Identity)
+	}
+	if f := identity.FieldByLabel("Social Security Number"); f == nil || !f.Concealed {
+		t.Fatalf("expected the SSN to be a concealed field")
+	}
+
+	// The linked field, the passkey and the unknown item type are reported
+	if len(result.Skipped) != 3 {
+		t.Fatalf("expected 3 skipped entries, got %q", result.Skipped)
+	}
+}
+
+// encryptBitwardenSample builds a password protected export the way the Bitwarden clients do
+func encryptBitwardenSample(t *testing.T, password string, kdfType int) []byte {
+	salt := base64.StdEncoding.EncodeToString([]byte("0123456789abcdef"))
+	export := map[string]any{
+		"encrypted":         true,
+		"passwordProtected": true,
+		"salt":              salt,
+		"kdfType":           kdfType,
+	}
+	var key []byte
+	if kdfType == bitwardenPBKDF2 {
+		export["kdfIterations"] = 1000
+		key, _ = pbkdf2.Key(sha256.New, password, []byte(salt), 1000, 32)
+	} else {
+		export["kdfIterations"] = 2
+		export["kdfMemory"] = 8
+		export["kdfParallelism"] = 1
+		saltHash := sha256.Sum256([]byte(salt))
+		key = argon2.IDKey([]byte(password), saltHash[:], 2, 8*1024, 1, 32)
+	}
+	encKey, _ := hkdf.Expand(sha256.New, key, "enc", 32)
+	macKey, _ := hkdf.Expand(sha256.New, key, "mac", 32)
+	encrypt := func(plaintext []byte) string {
+		block, _ := aes.NewCipher(encKey)
+		iv := make([]byte, aes.BlockSize)
+		rand.Read(iv)
+		padding := aes.BlockSize - len(plaintext)%aes.BlockSize
+		for range padding {
+			plaintext = append(plaintext, byte(padding))
+		}
+		ciphertext := make([]byte, len(plaintext))
+		cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, plaintext)
+		h := hmac.New(sha256.New, macKey)
+		h.Write(iv)
+		h.Write(ciphertext)
+		b64 := base64.StdEncoding.EncodeToString
+		return "2." + b64(iv) + "|" + b64(ciphertext) + "|" + b64(h.Sum(nil))
+	}
+	export["encKeyValidation_DO_NOT_EDIT"] = encrypt([]byte("5c0c4ad5-7d2b-4bde-8ad8-7d6ab5e3e1f4"))
+	export["data"] = encrypt([]byte(bitwardenSample))
+	data, err := json.Marshal(export)
+	if err != nil {
+		t.Fatalf("failed to marshal export: %v", err)
+	}
+	return data
+}
+
+func TestParseBitwardenEncrypted(t *testing.T) {
+	for _, kdfType := range []int{bitwardenPBKDF2, bitwardenArgon2id} {
+		data := encryptBitwardenSample(t, "correct horse", kdfType)
+		if _, err := ParseBitwarden(data, Options{}); !errors.Is(err, ErrPasswordRequired) {
+			t.Fatalf("kdf %d: expected ErrPasswordRequired, got %v", kdfType, err)
+		}
+		if _, err := ParseBitwarden(data, Options{Password: "wrong"}); !errors.Is(err, ErrWrongPassword) {
+			t.Fatalf("kdf %d: expected ErrWrongPassword, got %v", kdfType, err)
+		}
+		result, err := ParseBitwarden(data, Options{Password: "correct horse"})
+		if err != nil {
+			t.Fatalf("kdf %d: failed to parse export: %v", kdfType, err)
+		}
+		checkBitwardenSample(t, result)
+	}
+}
+
+func TestParseBitwardenAccountEncrypted(t *testing.T) {
+	data := []byte(`{"encrypted": true, "encKeyValidation_DO_NOT_EDIT": "2.a|b|c", "folders": [], "items": []}`)
+	if _, err := ParseBitwarden(data, Options{Password: "password"}); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Fatalf("expected ErrUnsupportedFormat for an account encrypted export, got %v", err)
+	}
+}
+
+func TestBitwardenKDFLimits(t *testing.T) {
+	tests := []struct {
+		name   string
+		export bitwardenExport
+	}{
+		{"pbkdf2 iterations", bitwardenExport{KdfType: bitwardenPBKDF2, KdfIterations: 1 << 62}},
+		{"pbkdf2 no iterations", bitwardenExport{KdfType: bitwardenPBKDF2}},
+		{"argon2 iterations", bitwardenExport{KdfType: bitwardenArgon2id, KdfIterations: 1 << 20, KdfMemory: 64, KdfParallelism: 4}},
+		{"argon2 memory", bitwardenExport{KdfType: bitwardenArgon2id, KdfIterations: 3, KdfMemory: 1_000_000, KdfParallelism: 4}},
+		{"argon2 wrapping memory", bitwardenExport{KdfType: bitwardenArgon2id, KdfIterations: 3, KdfMemory: 1 << 22, KdfParallelism: 4}},
+		{"argon2 parallelism", bitwardenExport{KdfType: bitwardenArgon2id, KdfIterations: 3, KdfMemory: 64, KdfParallelism: 200}},
+	}
+	for _, tt := range tests {
+		if _, err := tt.export.decrypt("password"); !errors.Is(err, ErrUnsupportedFormat) {
+			t.Fatalf("%s: expected ErrUnsupportedFormat, got %v", tt.name, err)
+		}
+	}
+}
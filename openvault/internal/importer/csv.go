@@ -0,0 +1,284 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+)
+
+// CSVField is the item field a CSV column is imported into
+type CSVField string
+
+var (
+	CSVFieldTitle    CSVField = "title"
+	CSVFieldURL      CSVField = "url"
+	CSVFieldUsername CSVField = "username"
+	CSVFieldPassword CSVField = "password"
+	CSVFieldNotes    CSVField = "notes"
+	CSVFieldOTP      CSVField = "otp"
+	CSVFieldFolder   CSVField = "folder"
+	// Columns mapped to CSVFieldCustom are imported as custom text fields labelled with the column name
+	CSVFieldCustom CSVField = "custom"
+	CSVFieldIgnore CSVField = "ignore"
+)
+
+// CSVFields lists every CSV field a column can be mapped to
+var CSVFields = []CSVField{
+	CSVFieldTitle,
+	CSVFieldURL,
+	CSVFieldUsername,
+	CSVFieldPassword,
+	CSVFieldNotes,
+	CSVFieldOTP,
+	CSVFieldFolder,
+	CSVFieldCustom,
+	CSVFieldIgnore,
+}
+
+// IsValid returns whether the field is supported
+func (f CSVField) IsValid() bool {
+	for _, field := range CSVFields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// csvHeaderFields maps common header names to fields, for CSV files imported without a column mapping. Headers
+// are compared case-insensitively.
+var csvHeaderFields = map[string]CSVField{
+	"title":          CSVFieldTitle,
+	"name":           CSVFieldTitle,
+	"url":            CSVFieldURL,
+	"website":        CSVFieldURL,
+	"login_uri":      CSVFieldURL,
+	"username":       CSVFieldUsername,
+	"login":          CSVFieldUsername,
+	"login_username": CSVFieldUsername,
+	"email":          CSVFieldUsername,
+	"password":       CSVFieldPassword,
+	"login_password": CSVFieldPassword,
+	"notes":          CSVFieldNotes,
+	"note":           CSVFieldNotes,
+	"extra":          CSVFieldNotes,
+	"comments":       CSVFieldNotes,
+	"otp":            CSVFieldOTP,
+	"totp":           CSVFieldOTP,
+	"login_totp":     CSVFieldOTP,
+	"folder":         CSVFieldFolder,
+	"group":          CSVFieldFolder,
+	"grouping":       CSVFieldFolder,
+}
+
+// Column mappings of the CSV files browsers and other password managers export
+var (
+	// chrome://password-manager/settings, "Download file"
+	chromeCSVColumns = map[string]CSVField{
+		"name":     CSVFieldTitle,
+		"url":      CSVFieldURL,
+		"username": CSVFieldUsername,
+		"password": CSVFieldPassword,
+		"note":     CSVFieldNotes,
+	}
+	// about:logins, "Export Passwords"
+	firefoxCSVColumns = map[string]CSVField{
+		"url":                 CSVFieldURL,
+		"username":            CSVFieldUsername,
+		"password":            CSVFieldPassword,
+		"httprealm":           CSVFieldIgnore,
+		"formactionorigin":    CSVFieldIgnore,
+		"guid":                CSVFieldIgnore,
+		"timecreated":         CSVFieldIgnore,
+		"timelastused":        CSVFieldIgnore,
+		"timepasswordchanged": CSVFieldIgnore,
+	}
+	// Account Options, Advanced, Export
+	lastPassCSVColumns = map[string]CSVField{
+		"url":      CSVFieldURL,
+		"username": CSVFieldUsername,
+		"password": CSVFieldPassword,
+		"totp":     CSVFieldOTP,
+		"extra":    CSVFieldNotes,
+		"name":     CSVFieldTitle,
+		"grouping": CSVFieldFolder,
+		"fav":      CSVFieldIgnore,
+	}
+)
+
+// LastPass exports secure notes as rows with this URL
+const lastPassSecureNoteURL = "http://sn"
+
+// ParseCSV reads a CSV file of logins with a header row. Columns are mapped to item fields by opts.CSVColumns,
+// keyed by header name, or recognized from common header names if no mapping is given. Columns which are not
+// mapped are imported as custom fields. Rows which cannot be read are reported in Result.Errors.
+func ParseCSV(data []byte, opts Options) (*Result, error) {
+	return parseCSV(data, opts.CSVColumns, true)
+}
+
+// ParseChromeCSV reads the passwords CSV exported by Chrome and other Chromium based browsers
+func ParseChromeCSV(data []byte, opts Options) (*Result, error) {
+	return parseCSV(data, chromeCSVColumns, false)
+}
+
+// ParseFirefoxCSV reads the logins CSV exported by Firefox. Its rows have no title, so the host of the URL is used.
+func ParseFirefoxCSV(data []byte, opts Options) (*Result, error) {
+	return parseCSV(data, firefoxCSVColumns, false)
+}
+
+// ParseLastPassCSV reads the CSV exported by LastPass. Secure notes are imported as secure note items.
+func ParseLastPassCSV(data []byte, opts Options) (*Result, error) {
+	result, err := parseCSV(data, lastPassCSVColumns, false)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range result.Items {
+		if item.Overview.URL != lastPassSecureNoteURL {
+			continue
+		}
+		item.Overview.URL = ""
+		item.Details.Category = structs.CategorySecureNote
+		if item.Details.Username == "" && item.Details.Password == "" {
+			continue
+		}
+		addField(&item.Details, customSection, &structs.Field{Label: "Username", Value: item.Details.Username})
+		addField(&item.Details, customSection, &structs.Field{Label: "Password", Type: structs.FieldTypeConcealed, Value: item.Details.Password})
+		item.Details.Username, item.Details.Password = "", ""
+	}
+	return result, nil
+}
+
+// parseCSV reads a CSV file with the given column mapping. With requireColumns set, every mapped column must be
+// present; the mappings of known layouts also cover columns which only some versions export.
+func parseCSV(data []byte, columns map[string]CSVField, requireColumns bool) (*Result, error) {
+	r := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(data, []byte("\ufeff"))))
+	// Rows with the wrong number of columns are reported rather than failing the whole file
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	header, err := r.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("the CSV file is empty")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CSV file: %w", err)
+	}
+	fields, err := csvColumnFields(header, columns, requireColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &Result{}
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				result.rowError(parseErr.StartLine, "%v", parseErr.Err)
+				continue
+			}
+			return nil, fmt.Errorf("failed to parse CSV file: %w", err)
+		}
+		row, _ := r.FieldPos(0)
+		if len(record) != len(header) {
+			result.rowError(row, "expected %d columns, found %d", len(header), len(record))
+			continue
+		}
+		item, err := csvItem(header, fields, record)
+		if err != nil {
+			result.rowError(row, "%v", err)
+			continue
+		}
+		if item != nil {
+			item.Row = row
+			result.Items = append(result.Items, item)
+		}
+	}
+	return result, nil
+}
+
+// csvColumnFields returns the field of each column in the header
+func csvColumnFields(header []string, columns map[string]CSVField, requireColumns bool) ([]CSVField, error) {
+	normalized := make(map[string]CSVField, len(columns))
+	for name, field := range columns {
+		if !field.IsValid() {
+			return nil, fmt.Errorf("invalid field %q for column %q", field, name)
+		}
+		normalized[strings.ToLower(strings.TrimSpace(name))] = field
+	}
+	fields := make([]CSVField, len(header))
+	found := make(map[string]bool, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		found[name] = true
+		field, ok := normalized[name]
+		if !ok && columns == nil {
+			field, ok = csvHeaderFields[name]
+		}
+		if !ok {
+			field = CSVFieldCustom
+		}
+		fields[i] = field
+	}
+	for name := range normalized {
+		if requireColumns && !found[name] {
+			return nil, fmt.Errorf("the CSV file has no column %q", name)
+		}
+	}
+	return fields, nil
+}
+
+// csvItem converts a row into a login, returning nil if the row is empty
+func csvItem(header []string, fields []CSVField, record []string) (*Item, error) {
+	item := &Item{Details: structs.VaultItemDetails{Category: structs.CategoryLogin}}
+	details := &item.Details
+	empty := true
+	for i, value := range record {
+		// Passwords, notes and custom values are kept exactly as exported, as spaces may be part of the secret
+		trimmed := strings.TrimSpace(value)
+		if trimmed == "" {
+			continue
+		}
+		switch fields[i] {
+		case CSVFieldTitle:
+			item.Overview.Title = trimmed
+		case CSVFieldURL:
+			item.Overview.URL = trimmed
+		case CSVFieldUsername:
+			details.Username = trimmed
+		case CSVFieldPassword:
+			details.Password = value
+		case CSVFieldNotes:
+			details.Notes = value
+		case CSVFieldOTP:
+			addOTPField(details, trimmed)
+		case CSVFieldFolder:
+			// LastPass separates nested folders with backslashes
+			item.Folder = strings.ReplaceAll(trimmed, "\\", "/")
+		case CSVFieldCustom:
+			addField(details, customSection, &structs.Field{Label: strings.TrimSpace(header[i]), Value: value})
+		case CSVFieldIgnore:
+			continue
+		}
+		empty = false
+	}
+	if empty {
+		return nil, nil
+	}
+	if item.Overview.Title == "" {
+		u, err := url.Parse(item.Overview.URL)
+		if err != nil || u.Hostname() == "" {
+			return nil, fmt.Errorf("row has no title or URL")
+		}
+		item.Overview.Title = u.Hostname()
+	}
+	return item, nil
+}
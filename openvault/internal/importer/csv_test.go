@@ -0,0 +1,137 @@
+package importer
+
+import (
+	"testing"
+
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+)
+
+func TestParseCSVLayouts(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		data   string
+		opts   Options
+		// Expected title, URL, username, folder and password of the first item
+		title, url, username, folder, password string
+		items                                  int
+		errors                                 []int
+	}{
+		{
+			name:   "chrome",
+			format: FormatChromeCSV,
+			data: "name,url,username,password,note\n" +
+				"github.com,https://github.com/login,octocat,hunter2,2FA enabled\n" +
+				"broken,https://example.com\n" +
+				"example.com,https://example.com,jane,secret,\n",
+			title: "github.com", url: "https://github.com/login", username: "octocat", password: "hunter2",
+			items: 2, errors: []int{3},
+		},
+		{
+			name:   "firefox",
+			format: FormatFirefoxCSV,
+			data: "\ufeff\"url\",\"username\",\"password\",\"httpRealm\",\"formActionOrigin\",\"guid\",\"timeCreated\",\"timeLastUsed\",\"timePasswordChanged\"\n" +
+				"\"https://github.com\",\"octocat\",\"hunter2\",,\"https://github.com\",\"{1}\",\"1700000000000\",\"1700000000000\",\"1700000000000\"\n" +
+				"\"\",\"nobody\",\"secret\",,,\"{2}\",\"1\",\"1\",\"1\"\n",
+			title: "github.com", url: "https://github.com", username: "octocat",
+			items: 1, errors: []int{3},
+		},
+		{
+			name:   "lastpass",
+			format: FormatLastPassCSV,
+			data: "url,username,password,totp,extra,name,grouping,fav\n" +
+				"https://github.com,octocat,hunter2,JBSWY3DPEHPK3PXP,2FA enabled,GitHub,Work\\Dev,1\n" +
+				"http://sn,,,,Wifi password is 123,Wifi,,0\n",
+			title: "GitHub", url: "https://github.com", username: "octocat", folder: "Work/Dev",
+			items: 2,
+		},
+		{
+			name:   "generic with header detection",
+			format: FormatCSV,
+			data: "Title,Website,Login,Password,Notes,Folder,Account Number\n" +
+				" Bank ,https://bank.example.com, jane ,\"  secret \",,Finance,12345\n" +
+				",,,,,,\n",
+			title: "Bank", url: "https://bank.example.com", username: "jane", folder: "Finance", password: "  secret ",
+			items: 1,
+		},
+		{
+			name:   "generic with column mapping",
+			format: FormatCSV,
+			data: "Site,User,Secret,Account\n" +
+				"Bank,jane,secret,https://bank.example.com\n" +
+				"\"unterminated,jane,secret,x\n",
+			opts: Options{CSVColumns: map[string]CSVField{
+				"site":    CSVFieldTitle,
+				"user":    CSVFieldUsername,
+				"secret":  CSVFieldPassword,
+				"Account": CSVFieldURL,
+			}},
+			title: "Bank", url: "https://bank.example.com", username: "jane",
+			items: 1, errors: []int{3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Parse(tt.format, []byte(tt.data), tt.opts)
+			if err != nil {
+				t.Fatalf("failed to parse CSV: %v", err)
+			}
+			if len(result.Items) != tt.items {
+				t.Fatalf("parsed %d items, expected %d (errors %+v)", len(result.Items), tt.items, result.Errors)
+			}
+			for _, item := range result.Items {
+				if err := structs.NormalizeItem(&item.Overview, &item.Details); err != nil {
+					t.Fatalf("item %q is invalid: %v", item.Overview.Title, err)
+				}
+			}
+			item := result.Items[0]
+			if item.Overview.Title != tt.title || item.Overview.URL != tt.url || item.Details.Username != tt.username || item.Folder != tt.folder || item.Row != 2 {
+				t.Fatalf("unexpected first item on row %d in %q: %+v %+v", item.Row, item.Folder, item.Overview, item.Details)
+			}
+			if tt.password != "" && item.Details.Password != tt.password {
+				t.Fatalf("expected password %q, got %q", tt.password, item.Details.Password)
+			}
+			if len(result.Errors) != len(tt.errors) {
+				t.Fatalf("expected errors on rows %v, got %+v", tt.errors, result.Errors)
+			}
+			for i, row := range tt.errors {
+				if result.Errors[i].Row != row {
+					t.Fatalf("expected an error on row %d, got %+v", row, result.Errors[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseCSVFields(t *testing.T) {
+	result, err := Parse(FormatLastPassCSV, []byte("url,username,password,totp,extra,name,grouping,fav\n"+
+		"http://sn,alice,wifi-pass,,Guest network,Wifi,,0\n"+
+		"https://github.com,octocat,hunter2,JBSWY3DPEHPK3PXP,,GitHub,,0\n"), Options{})
+	if err != nil {
+		t.Fatalf("failed to parse CSV: %v", err)
+	}
+	note := result.Items[0]
+	if note.Details.Category != structs.CategorySecureNote || note.Overview.URL != "" || note.Details.Notes != "Guest network" || note.Details.Password != "" {
+		t.Fatalf("unexpected secure note %+v %+v", note.Overview, note.Details)
+	}
+	if f := note.Details.FieldByLabel("Password"); f == nil || f.Value != "wifi-pass" || f.Type != structs.FieldTypeConcealed {
+		t.Fatalf("expected the secure note password to be a concealed field, got %+v", f)
+	}
+	if f := result.Items[1].Details.FieldByLabel("One-Time Password"); f == nil || f.Type != structs.FieldTypeOTP {
+		t.Fatalf("expected the totp column to be an OTP field")
+	}
+
+	if _, err := Parse(FormatCSV, []byte("name,url\nx,y\n"), Options{CSVColumns: map[string]CSVField{"password": CSVFieldPassword}}); err == nil {
+		t.Fatalf("expected a mapping for a missing column to fail")
+	}
+	if _, err := Parse(FormatCSV, []byte("name,url\nx,y\n"), Options{CSVColumns: map[string]CSVField{"name": "bogus"}}); err == nil {
+		t.Fatalf("expected an invalid field to fail")
+	}
+	result, err = Parse(FormatCSV, []byte("name,url,Account Number\nBank,,12345\n"), Options{})
+	if err != nil {
+		t.Fatalf("failed to parse CSV: %v", err)
+	}
+	if f := result.Items[0].Details.FieldByLabel("Account Number"); f == nil || f.Value != "12345" {
+		t.Fatalf("expected the unknown column to be a custom field")
+	}
+}
@@ -0,0 +1,163 @@
+// Package importer reads the exports of other password managers into OpenVault items. Parsers only map the
+// exported data; encrypting the items into a vault is left to the caller.
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+
+	"github.com/BradHacker/openvault/cryptolib"
+)
+
+// Format identifies the export format of another password manager
+type Format string
+
+var (
+	FormatBitwarden   Format = "bitwarden"
+	FormatKeePass     Format = "keepass"
+	FormatOnePassword Format = "1password"
+	FormatOpenVault   Format = "openvault"
+	FormatCSV         Format = "csv"
+	FormatChromeCSV   Format = "chrome_csv"
+	FormatFirefoxCSV  Format = "firefox_csv"
+	FormatLastPassCSV Format = "lastpass_csv"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported import format")
+	ErrPasswordRequired  = errors.New("the export is password protected")
+	ErrWrongPassword     = errors.New("the export password is incorrect")
+)
+
+// Options hold the credentials of encrypted exports and the column mapping of CSV files
+type Options struct {
+	Password string
+	// Contents of a KeePass key file
+	KeyFile []byte
+	// Field of each CSV column, keyed by header name (FormatCSV only)
+	CSVColumns map[string]CSVField
+}
+
+// Item is an item read from an export
+type Item struct {
+	// Folder the item was filed under in the other password manager (empty if none). Nested folders are joined
+	// with "/".
+	Folder string
+	// Folder within the destination vault to file the item into, created if the vault has none by that name
+	// (empty if none)
+	VaultFolder string
+	Overview    structs.VaultItemOverview
+	Details     structs.VaultItemDetails
+	// Files attached to the item
+	Attachments []*Attachment
+	// Earlier versions of the item, oldest first
+	History []*Revision
+	// Line of the row the item was read from, for formats with rows (zero otherwise)
+	Row int
+}
+
+// Attachment is a file attached to an item
+type Attachment struct {
+	Name string
+	Data []byte
+}
+
+// Revision is an earlier version of an item
+type Revision struct {
+	Overview structs.VaultItemOverview
+	Details  structs.VaultItemDetails
+	// When this version was saved (zero if unknown)
+	ModifiedAt time.Time
+}
+
+// Result is the contents of an export
+type Result struct {
+	Items []*Item
+	// Descriptions of exported data which cannot be represented in OpenVault and will not be imported
+	Skipped []string
+	// Rows which could not be read, for formats with rows
+	Errors []*RowError
+}
+
+// RowError describes a row which could not be read
+type RowError struct {
+	Row     int
+	Message string
+}
+
+func (r *Result) skip(format string, args ...any) {
+	r.Skipped = append(r.Skipped, fmt.Sprintf(format, args...))
+}
+
+func (r *Result) rowError(row int, format string, args ...any) {
+	r.Errors = append(r.Errors, &RowError{Row: row, Message: fmt.Sprintf(format, args...)})
+}
+
+// parsers maps each format to its parser. The options are only used by formats which support encryption.
+var parsers = map[Format]func(data []byte, opts Options) (*Result, error){
+	FormatBitwarden:   ParseBitwarden,
+	FormatKeePass:     ParseKDBX,
+	FormatOnePassword: ParseOnePassword,
+	FormatOpenVault:   ParseOpenVault,
+	FormatCSV:         ParseCSV,
+	FormatChromeCSV:   ParseChromeCSV,
+	FormatFirefoxCSV:  ParseFirefoxCSV,
+	FormatLastPassCSV: ParseLastPassCSV,
+}
+
+// Formats returns the supported import formats
+func Formats() []Format {
+	formats := make([]Format, 0, len(parsers))
+	for format := range parsers {
+		formats = append(formats, format)
+	}
+	sort.Slice(formats, func(i, j int) bool { return formats[i] < formats[j] })
+	return formats
+}
+
+// Parse reads an export in the given format
+func Parse(format Format, data []byte, opts Options) (*Result, error) {
+	parse, ok := parsers[format]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedFormat, format)
+	}
+	return parse(data, opts)
+}
+
+// addField appends a custom field to the item's section with the given title, creating the section if needed.
+// Empty values are ignored.
+func addField(details *structs.VaultItemDetails, sectionTitle string, field *structs.Field) {
+	if field.Value == "" {
+		return
+	}
+	if field.Type == "" {
+		field.Type = structs.FieldTypeText
+	}
+	for _, section := range details.Sections {
+		if section.Title == sectionTitle {
+			section.Fields = append(section.Fields, field)
+			return
+		}
+	}
+	details.Sections = append(details.Sections, &structs.Section{
+		Title:  sectionTitle,
+		Fields: []*structs.Field{field},
+	})
+}
+
+// Title of the section holding fields which have no equivalent in the item's category
+const customSection = "Custom Fields"
+
+// addOTPField adds a one-time password secret to the item. Secrets OpenVault cannot generate codes for (such as
+// Steam Guard) are kept as a concealed field instead.
+func addOTPField(details *structs.VaultItemDetails, value string) {
+	fieldType := structs.FieldTypeOTP
+	if _, err := cryptolib.ParseTOTP(value); err != nil {
+		fieldType = structs.FieldTypeConcealed
+	}
+	addField(details, customSection, &structs.Field{Label: "One-Time Password", Type: fieldType, Value: value})
+}
@@ -0,0 +1,699 @@
+package importer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/chacha20"
+)
+
+// KDBX file signature and supported major version
+const (
+	kdbxSignature1   = 0x9AA2D903
+	kdbxSignature2   = 0xB54BFB67
+	kdbxMajorVersion = 4
+)
+
+// Outer header field IDs
+const (
+	kdbxHeaderEnd           = 0
+	kdbxHeaderCipherID      = 2
+	kdbxHeaderCompression   = 3
+	kdbxHeaderMasterSeed    = 4
+	kdbxHeaderEncryptionIV  = 7
+	kdbxHeaderKdfParameters = 11
+)
+
+// Inner header field IDs
+const (
+	kdbxInnerEnd       = 0
+	kdbxInnerStreamID  = 1
+	kdbxInnerStreamKey = 2
+	kdbxInnerBinary    = 3
+)
+
+// The ChaCha20 inner random stream which protects values in the XML
+const kdbxInnerStreamChaCha20 = 3
+
+// Seconds from 0001-01-01, the epoch of KDBX 4 timestamps, to the Unix epoch
+const kdbxUnixEpoch = 62135596800
+
+// Upper bounds for the Argon2 parameters so a crafted database cannot exhaust memory or keep the import busy for
+// hours. KeePass and KeePassXC choose well below both (64 MiB and a handful of iterations by default).
+const (
+	kdbxMaxArgon2Memory     = 1 << 20 // 1 GiB, in KiB
+	kdbxMaxArgon2Iterations = 100
+)
+
+// Upper bound for AES-KDF rounds so a crafted database cannot keep the import busy for hours. This is well above
+// what KeePass and KeePassXC choose for a one second delay.
+const kdbxMaxAESRounds = 1 << 30
+
+// Upper bound for the decompressed payload so a crafted database cannot exhaust memory
+const kdbxMaxPayloadSize = 256 << 20
+
+var (
+	kdbxCipherAES256   = uuid.MustParse("31c1f2e6-bf71-4350-be58-05216afc5aff")
+	kdbxCipherChaCha20 = uuid.MustParse("d6038a2b-8b6f-4cb5-a524-339a31dbb59a")
+	kdbxKdfAES         = uuid.MustParse("c9d9f39a-628a-4460-bf74-0d08c18a4fea")
+	kdbxKdfAESKDBX4    = uuid.MustParse("7c02bb82-79a7-4ac0-927d-114a00648238")
+	kdbxKdfArgon2d     = uuid.MustParse("ef636ddf-8c29-444b-91f7-a9a403e30a0c")
+	kdbxKdfArgon2id    = uuid.MustParse("9e298b19-56db-4773-b23d-fc3ec6f0a1e6")
+)
+
+var errKDBXCorrupt = errors.New("the KeePass database is corrupt")
+
+// Entry strings with a dedicated place on login items. Every other string becomes a custom field.
+var kdbxStandardStrings = map[string]bool{
+	"Title":    true,
+	"UserName": true,
+	"Password": true,
+	"URL":      true,
+	"Notes":    true,
+}
+
+type kdbxFile struct {
+	Meta struct {
+		RecycleBinEnabled string `xml:"RecycleBinEnabled"`
+		RecycleBinUUID    string `xml:"RecycleBinUUID"`
+	} `xml:"Meta"`
+	Root struct {
+		Group kdbxGroup `xml:"Group"`
+	} `xml:"Root"`
+}
+
+type kdbxGroup struct {
+	UUID    string       `xml:"UUID"`
+	Name    string       `xml:"Name"`
+	Entries []*kdbxEntry `xml:"Entry"`
+	Groups  []*kdbxGroup `xml:"Group"`
+}
+
+type kdbxEntry struct {
+	Times struct {
+		LastModificationTime string `xml:"LastModificationTime"`
+	} `xml:"Times"`
+	Strings []struct {
+		Key   string `xml:"Key"`
+		Value struct {
+			Value string `xml:",chardata"`
+			// Set on values which were protected in the database (see kdbxInner.unprotect)
+			Protected string `xml:"ProtectInMemory,attr"`
+		} `xml:"Value"`
+	} `xml:"String"`
+	Binaries []struct {
+		Key   string `xml:"Key"`
+		Value struct {
+			Ref int `xml:"Ref,attr"`
+		} `xml:"Value"`
+	} `xml:"Binary"`
+	History struct {
+		Entries []*kdbxEntry `xml:"Entry"`
+	} `xml:"History"`
+}
+
+// ParseKDBX reads a KeePass KDBX 4 database unlocked with a password, a key file or both. Top level groups become
+// folders and the groups below them vault folders, entries become logins with their attachments and history, and
+// the recycle bin is skipped.
+func ParseKDBX(data []byte, opts Options) (*Result, error) {
+	if len(data) < 12 || binary.LittleEndian.Uint32(data[0:4]) != kdbxSignature1 || binary.LittleEndian.Uint32(data[4:8]) != kdbxSignature2 {
+		return nil, fmt.Errorf("%w: not a KeePass database", ErrUnsupportedFormat)
+	}
+	if major := binary.LittleEndian.Uint32(data[8:12]) >> 16; major != kdbxMajorVersion {
+		return nil, fmt.Errorf("%w: KDBX %d databases are not supported; save the database with a current version of KeePass to upgrade it to KDBX 4", ErrUnsupportedFormat, major)
+	}
+	header, err := readKDBXHeader(data)
+	if err != nil {
+		return nil, err
+	}
+	if opts.Password == "" && opts.KeyFile == nil {
+		return nil, ErrPasswordRequired
+	}
+	compositeKey, err := kdbxCompositeKey(opts)
+	if err != nil {
+		return nil, err
+	}
+	transformedKey, err := header.transformKey(compositeKey)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := header.decryptPayload(data, transformedKey)
+	if err != nil {
+		return nil, err
+	}
+	inner, xmlData, err := readKDBXInnerHeader(payload)
+	if err != nil {
+		return nil, err
+	}
+	xmlData, err = inner.unprotect(xmlData)
+	if err != nil {
+		return nil, err
+	}
+	var file kdbxFile
+	if err := xml.Unmarshal(xmlData, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse KeePass database: %w", err)
+	}
+
+	result := &Result{}
+	recycleBin := ""
+	if !strings.EqualFold(file.Meta.RecycleBinEnabled, "False") {
+		recycleBin = file.Meta.RecycleBinUUID
+	}
+	// Top level groups are the folders which can be imported into vaults of their own, and the groups below them
+	// are kept as vault folders
+	var walk func(group *kdbxGroup, folder string, vaultFolder string)
+	walk = func(group *kdbxGroup, folder string, vaultFolder string) {
+		if recycleBin != "" && group.UUID == recycleBin {
+			return
+		}
+		for _, entry := range group.Entries {
+			item := inner.entryItem(entry, folder, result)
+			item.VaultFolder = vaultFolder
+			result.Items = append(result.Items, item)
+		}
+		for _, child := range group.Groups {
+			switch {
+			case folder == "":
+				walk(child, child.Name, "")
+			case vaultFolder == "":
+				walk(child, folder, child.Name)
+			default:
+				walk(child, folder, vaultFolder+"/"+child.Name)
+			}
+		}
+	}
+	// Entries directly in the root group have no folder
+	walk(&file.Root.Group, "", "")
+	return result, nil
+}
+
+type kdbxHeader struct {
+	// The header bytes covered by the header hash and HMAC
+	raw []byte
+	// Offset of the encrypted payload in the file
+	payloadOffset int
+	cipherID      uuid.UUID
+	compressed    bool
+	masterSeed    []byte
+	encryptionIV  []byte
+	kdfParameters map[string]any
+}
+
+func readKDBXHeader(data []byte) (*kdbxHeader, error) {
+	header := &kdbxHeader{}
+	pos := 12
+	for {
+		if len(data) < pos+5 {
+			return nil, errKDBXCorrupt
+		}
+		id := data[pos]
+		size := int(binary.LittleEndian.Uint32(data[pos+1 : pos+5]))
+		pos += 5
+		if size < 0 || len(data) < pos+size {
+			return nil, errKDBXCorrupt
+		}
+		value := data[pos : pos+size]
+		pos += size
+		switch id {
+		case kdbxHeaderEnd:
+			header.raw = data[:pos]
+			header.payloadOffset = pos + sha256.Size + sha256.Size
+			if len(data) < header.payloadOffset {
+				return nil, errKDBXCorrupt
+			}
+			if sum := sha256.Sum256(header.raw); !bytes.Equal(sum[:], data[pos:pos+sha256.Size]) {
+				return nil, errKDBXCorrupt
+			}
+			if header.masterSeed == nil || header.encryptionIV == nil || header.kdfParameters == nil {
+				return nil, errKDBXCorrupt
+			}
+			return header, nil
+		case kdbxHeaderCipherID:
+			cipherID, err := uuid.FromBytes(value)
+			if err != nil {
+				return nil, errKDBXCorrupt
+			}
+			header.cipherID = cipherID
+		case kdbxHeaderCompression:
+			header.compressed = len(value) == 4 && binary.LittleEndian.Uint32(value) == 1
+		case kdbxHeaderMasterSeed:
+			header.masterSeed = value
+		case kdbxHeaderEncryptionIV:
+			header.encryptionIV = value
+		case kdbxHeaderKdfParameters:
+			params, err := readVariantDictionary(value)
+			if err != nil {
+				return nil, err
+			}
+			header.kdfParameters = params
+		}
+	}
+}
+
+// readVariantDictionary reads the typed key/value map KDBX 4 uses for KDF parameters
+func readVariantDictionary(data []byte) (map[string]any, error) {
+	if len(data) < 2 || data[1] != 0x01 {
+		return nil, errKDBXCorrupt
+	}
+	params := make(map[string]any)
+	pos := 2
+	for pos < len(data) {
+		valueType := data[pos]
+		pos++
+		if valueType == 0 {
+			return params, nil
+		}
+		var fields [2][]byte
+		for i := range fields {
+			if len(data) < pos+4 {
+				return nil, errKDBXCorrupt
+			}
+			size := int(int32(binary.LittleEndian.Uint32(data[pos:])))
+			pos += 4
+			if size < 0 || len(data) < pos+size {
+				return nil, errKDBXCorrupt
+			}
+			fields[i] = data[pos : pos+size]
+			pos += size
+		}
+		key, value := string(fields[0]), fields[1]
+		switch valueType {
+		case 0x04, 0x0C: // UInt32, Int32
+			if len(value) != 4 {
+				return nil, errKDBXCorrupt
+			}
+			params[key] = uint64(binary.LittleEndian.Uint32(value))
+		case 0x05, 0x0D: // UInt64, Int64
+			if len(value) != 8 {
+				return nil, errKDBXCorrupt
+			}
+			params[key] = binary.LittleEndian.Uint64(value)
+		case 0x08: // Bool
+			params[key] = len(value) == 1 && value[0] != 0
+		case 0x18: // String
+			params[key] = string(value)
+		case 0x42: // ByteArray
+			params[key] = value
+		}
+	}
+	return nil, errKDBXCorrupt
+}
+
+// kdbxCompositeKey combines the password and key file into the key the KDF is applied to
+func kdbxCompositeKey(opts Options) ([]byte, error) {
+	h := sha256.New()
+	if opts.Password != "" {
+		sum := sha256.Sum256([]byte(opts.Password))
+		h.Write(sum[:])
+	}
+	if opts.KeyFile != nil {
+		key, err := kdbxKeyFileKey(opts.KeyFile)
+		if err != nil {
+			return nil, err
+		}
+		h.Write(key)
+	}
+	return h.Sum(nil), nil
+}
+
+// kdbxKeyFileKey returns the key held by a key file. XML key files (versions 1 and 2), 32 byte binary files and
+// 64 character hex files hold the key directly; the SHA-256 hash of any other file is used as the key.
+func kdbxKeyFileKey(data []byte) ([]byte, error) {
+	var keyFile struct {
+		XMLName xml.Name `xml:"KeyFile"`
+		Meta    struct {
+			Version string `xml:"Version"`
+		} `xml:"Meta"`
+		Key struct {
+			Data struct {
+				Value string `xml:",chardata"`
+				Hash  string `xml:"Hash,attr"`
+			} `xml:"Data"`
+		} `xml:"Key"`
+	}
+	if err := xml.Unmarshal(data, &keyFile); err == nil && keyFile.Key.Data.Value != "" {
+		value := strings.Join(strings.Fields(keyFile.Key.Data.Value), "")
+		if strings.HasPrefix(keyFile.Meta.Version, "2.") {
+			key, err := hex.DecodeString(value)
+			if err != nil || len(key) != 32 {
+				return nil, fmt.Errorf("invalid key file")
+			}
+			sum := sha256.Sum256(key)
+			if keyFile.Key.Data.Hash != "" && !strings.EqualFold(hex.EncodeToString(sum[:4]), keyFile.Key.Data.Hash) {
+				return nil, fmt.Errorf("the key file checksum does not match")
+			}
+			return key, nil
+		}
+		key, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid key file")
+		}
+		return key, nil
+	}
+	if len(data) == 32 {
+		return data, nil
+	}
+	if len(data) == 64 {
+		if key, err := hex.DecodeString(string(data)); err == nil {
+			return key, nil
+		}
+	}
+	sum := sha256.Sum256(data)
+	return sum[:], nil
+}
+
+// transformKey applies the database's KDF to the composite key
+func (h *kdbxHeader) transformKey(compositeKey []byte) ([]byte, error) {
+	rawID, _ := h.kdfParameters["$UUID"].([]byte)
+	kdfID, err := uuid.FromBytes(rawID)
+	if err != nil {
+		return nil, errKDBXCorrupt
+	}
+	bytesParam := func(key string) []byte {
+		value, _ := h.kdfParameters[key].([]byte)
+		return value
+	}
+	uintParam := func(key string) uint64 {
+		value, _ := h.kdfParameters[key].(uint64)
+		return value
+	}
+	switch kdfID {
+	case kdbxKdfArgon2d, kdbxKdfArgon2id:
+		iterations, memory, parallelism := uintParam("I"), uintParam("M")/1024, uintParam("P")
+		if uintParam("V") != argon2Version || iterations == 0 || iterations > kdbxMaxArgon2Iterations || memory == 0 || memory > kdbxMaxArgon2Memory || parallelism == 0 || parallelism > 255 {
+			return nil, fmt.Errorf("%w: unsupported Argon2 parameters", ErrUnsupportedFormat)
+		}
+		if kdfID == kdbxKdfArgon2d {
+			return argon2d(compositeKey, bytesParam("S"), bytesParam("K"), bytesParam("A"), uint32(iterations), uint32(memory), uint32(parallelism), 32), nil
+		}
+		if len(bytesParam("K")) > 0 || len(bytesParam("A")) > 0 {
+			return nil, fmt.Errorf("%w: unsupported Argon2 parameters", ErrUnsupportedFormat)
+		}
+		return argon2.IDKey(compositeKey, bytesParam("S"), uint32(iterations), uint32(memory), uint8(parallelism), 32), nil
+	case kdbxKdfAES, kdbxKdfAESKDBX4:
+		block, err := aes.NewCipher(bytesParam("S"))
+		if err != nil {
+			return nil, errKDBXCorrupt
+		}
+		rounds := uintParam("R")
+		if rounds > kdbxMaxAESRounds {
+			return nil, fmt.Errorf("%w: unsupported AES-KDF parameters", ErrUnsupportedFormat)
+		}
+		key := bytes.Clone(compositeKey)
+		for range rounds {
+			block.Encrypt(key[0:16], key[0:16])
+			block.Encrypt(key[16:32], key[16:32])
+		}
+		sum := sha256.Sum256(key)
+		return sum[:], nil
+	default:
+		return nil, fmt.Errorf("%w: unknown KeePass KDF %s", ErrUnsupportedFormat, kdfID)
+	}
+}
+
+// kdbxHMACKey returns the HMAC key of a payload block, or of the header for index math.MaxUint64
+func kdbxHMACKey(baseKey []byte, index uint64) []byte {
+	h := sha512.New()
+	h.Write(binary.LittleEndian.AppendUint64(nil, index))
+	h.Write(baseKey)
+	return h.Sum(nil)
+}
+
+// decryptPayload verifies the header and payload HMACs and decrypts the payload. A header HMAC mismatch means the
+// credentials are wrong.
+func (h *kdbxHeader) decryptPayload(data []byte, transformedKey []byte) ([]byte, error) {
+	hmacBase := sha512.Sum512(append(append(bytes.Clone(h.masterSeed), transformedKey...), 0x01))
+	headerMAC := hmac.New(sha256.New, kdbxHMACKey(hmacBase[:], ^uint64(0)))
+	headerMAC.Write(h.raw)
+	if !hmac.Equal(headerMAC.Sum(nil), data[h.payloadOffset-sha256.Size:h.payloadOffset]) {
+		return nil, ErrWrongPassword
+	}
+
+	var ciphertext []byte
+	pos := h.payloadOffset
+	for index := uint64(0); ; index++ {
+		if len(data) < pos+sha256.Size+4 {
+			return nil, errKDBXCorrupt
+		}
+		blockMAC := data[pos : pos+sha256.Size]
+		sizeBytes := data[pos+sha256.Size : pos+sha256.Size+4]
+		size := int(int32(binary.LittleEndian.Uint32(sizeBytes)))
+		pos += sha256.Size + 4
+		if size < 0 || len(data) < pos+size {
+			return nil, errKDBXCorrupt
+		}
+		block := data[pos : pos+size]
+		pos += size
+		mac := hmac.New(sha256.New, kdbxHMACKey(hmacBase[:], index))
+		mac.Write(binary.LittleEndian.AppendUint64(nil, index))
+		mac.Write(sizeBytes)
+		mac.Write(block)
+		if !hmac.Equal(mac.Sum(nil), blockMAC) {
+			return nil, errKDBXCorrupt
+		}
+		if size == 0 {
+			break
+		}
+		ciphertext = append(ciphertext, block...)
+	}
+
+	masterKey := sha256.Sum256(append(bytes.Clone(h.masterSeed), transformedKey...))
+	var plaintext []byte
+	switch h.cipherID {
+	case kdbxCipherAES256:
+		block, err := aes.NewCipher(masterKey[:])
+		if err != nil {
+			return nil, err
+		}
+		if len(h.encryptionIV) != aes.BlockSize || len(ciphertext) == 0 || len(ciphertext)%aes.BlockSize != 0 {
+			return nil, errKDBXCorrupt
+		}
+		plaintext = make([]byte, len(ciphertext))
+		cipher.NewCBCDecrypter(block, h.encryptionIV).CryptBlocks(plaintext, ciphertext)
+		padding := int(plaintext[len(plaintext)-1])
+		if padding == 0 || padding > aes.BlockSize {
+			return nil, errKDBXCorrupt
+		}
+		plaintext = plaintext[:len(plaintext)-padding]
+	case kdbxCipherChaCha20:
+		stream, err := chacha20.NewUnauthenticatedCipher(masterKey[:], h.encryptionIV)
+		if err != nil {
+			return nil, errKDBXCorrupt
+		}
+		plaintext = make([]byte, len(ciphertext))
+		stream.XORKeyStream(plaintext, ciphertext)
+	default:
+		return nil, fmt.Errorf("%w: unsupported KeePass cipher %s", ErrUnsupportedFormat, h.cipherID)
+	}
+	if h.compressed {
+		gz, err := gzip.NewReader(bytes.NewReader(plaintext))
+		if err != nil {
+			return nil, errKDBXCorrupt
+		}
+		if plaintext, err = io.ReadAll(io.LimitReader(gz, kdbxMaxPayloadSize+1)); err != nil {
+			return nil, errKDBXCorrupt
+		}
+		if len(plaintext) > kdbxMaxPayloadSize {
+			return nil, fmt.Errorf("%w: the database exceeds %d bytes", ErrUnsupportedFormat, kdbxMaxPayloadSize)
+		}
+	}
+	return plaintext, nil
+}
+
+// kdbxInner holds the inner header: the stream protecting values in the XML and the attachment contents
+type kdbxInner struct {
+	stream   *chacha20.Cipher
+	binaries [][]byte
+}
+
+func readKDBXInnerHeader(payload []byte) (*kdbxInner, []byte, error) {
+	inner := &kdbxInner{}
+	var streamID uint32
+	var streamKey []byte
+	pos := 0
+	for {
+		if len(payload) < pos+5 {
+			return nil, nil, errKDBXCorrupt
+		}
+		id := payload[pos]
+		size := int(int32(binary.LittleEndian.Uint32(payload[pos+1 : pos+5])))
+		pos += 5
+		if size < 0 || len(payload) < pos+size {
+			return nil, nil, errKDBXCorrupt
+		}
+		value := payload[pos : pos+size]
+		pos += size
+		switch id {
+		case kdbxInnerEnd:
+			if streamID != kdbxInnerStreamChaCha20 {
+				return nil, nil, fmt.Errorf("%w: unsupported KeePass inner stream %d", ErrUnsupportedFormat, streamID)
+			}
+			keyHash := sha512.Sum512(streamKey)
+			stream, err := chacha20.NewUnauthenticatedCipher(keyHash[:32], keyHash[32:44])
+			if err != nil {
+				return nil, nil, errKDBXCorrupt
+			}
+			inner.stream = stream
+			return inner, payload[pos:], nil
+		case kdbxInnerStreamID:
+			if len(value) != 4 {
+				return nil, nil, errKDBXCorrupt
+			}
+			streamID = binary.LittleEndian.Uint32(value)
+		case kdbxInnerStreamKey:
+			streamKey = value
+		case kdbxInnerBinary:
+			if len(value) < 1 {
+				return nil, nil, errKDBXCorrupt
+			}
+			// The first byte holds flags
+			inner.binaries = append(inner.binaries, value[1:])
+		}
+	}
+}
+
+// unprotect decrypts the protected values in the XML. Values are encrypted with one stream in document order, so
+// they are decrypted while re-encoding the document. Decrypted values are marked with ProtectInMemory so they can
+// still be concealed.
+func (inner *kdbxInner) unprotect(xmlData []byte) ([]byte, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(xmlData))
+	var out bytes.Buffer
+	encoder := xml.NewEncoder(&out)
+	protected := false
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse KeePass database: %w", err)
+		}
+		switch t := token.(type) {
+		case xml.ProcInst:
+			// The encoder writes its own XML declaration
+			continue
+		case xml.StartElement:
+			protected = false
+			if t.Name.Local == "Value" {
+				for i, attr := range t.Attr {
+					if attr.Name.Local == "Protected" && strings.EqualFold(attr.Value, "True") {
+						protected = true
+						t.Attr = slices.Clone(t.Attr)
+						t.Attr[i] = xml.Attr{Name: xml.Name{Local: "ProtectInMemory"}, Value: "True"}
+						break
+					}
+				}
+			}
+			token = t
+		case xml.CharData:
+			if protected {
+				value, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(t)))
+				if err != nil {
+					return nil, errKDBXCorrupt
+				}
+				inner.stream.XORKeyStream(value, value)
+				token = xml.CharData(value)
+			}
+		case xml.EndElement:
+			protected = false
+		}
+		if err := encoder.EncodeToken(token); err != nil {
+			return nil, fmt.Errorf("failed to parse KeePass database: %w", err)
+		}
+	}
+	if err := encoder.Flush(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+// entryItem converts an entry and its history into an item
+func (inner *kdbxInner) entryItem(entry *kdbxEntry, folder string, result *Result) *Item {
+	item := &Item{Folder: folder}
+	item.Overview, item.Details = inner.entryVersion(entry)
+	for _, binary := range entry.Binaries {
+		if binary.Value.Ref < 0 || binary.Value.Ref >= len(inner.binaries) {
+			result.skip("%q: missing attachment %q", item.Overview.Title, binary.Key)
+			continue
+		}
+		item.Attachments = append(item.Attachments, &Attachment{Name: binary.Key, Data: inner.binaries[binary.Value.Ref]})
+	}
+	historyAttachments := false
+	for _, old := range entry.History.Entries {
+		revision := &Revision{ModifiedAt: kdbxTime(old.Times.LastModificationTime)}
+		revision.Overview, revision.Details = inner.entryVersion(old)
+		item.History = append(item.History, revision)
+		historyAttachments = historyAttachments || len(old.Binaries) > 0
+	}
+	if historyAttachments {
+		result.skip("%q: attachments of earlier versions", item.Overview.Title)
+	}
+	return item
+}
+
+// entryVersion converts the strings of an entry into login details
+func (inner *kdbxInner) entryVersion(entry *kdbxEntry) (structs.VaultItemOverview, structs.VaultItemDetails) {
+	overview := structs.VaultItemOverview{}
+	details := structs.VaultItemDetails{Category: structs.CategoryLogin}
+	for _, s := range entry.Strings {
+		value := s.Value.Value
+		switch s.Key {
+		case "Title":
+			overview.Title = value
+		case "UserName":
+			details.Username = value
+		case "Password":
+			details.Password = value
+		case "URL":
+			overview.URL = value
+		case "Notes":
+			details.Notes = value
+		}
+	}
+	for _, s := range entry.Strings {
+		if kdbxStandardStrings[s.Key] || s.Value.Value == "" {
+			continue
+		}
+		switch s.Key {
+		// KeePassXC stores an otpauth URI and KeePass a base32 secret
+		case "otp", "TimeOtp-Secret-Base32":
+			addOTPField(&details, s.Value.Value)
+			continue
+		}
+		fieldType := structs.FieldTypeText
+		if strings.EqualFold(s.Value.Protected, "True") {
+			fieldType = structs.FieldTypeConcealed
+		}
+		addField(&details, customSection, &structs.Field{Label: s.Key, Type: fieldType, Value: s.Value.Value})
+	}
+	return overview, details
+}
+
+// kdbxTime parses a KDBX 4 timestamp: the base64 encoded number of seconds since 0001-01-01 UTC
+func kdbxTime(value string) time.Time {
+	raw, err := base64.StdEncoding.DecodeString(value)
+	if err != nil || len(raw) != 8 {
+		t, _ := time.Parse(time.RFC3339, value)
+		return t
+	}
+	return time.Unix(int64(binary.LittleEndian.Uint64(raw))-kdbxUnixEpoch, 0).UTC()
+}
@@ -0,0 +1,347 @@
+package importer
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/chacha20"
+)
+
+// kdbxSampleXML is the database XML with %s placeholders for the protected values, which are encrypted in order
+const kdbxSampleXML = `<?xml version="1.0" encoding="utf-8" standalone="yes"?>
+<KeePassFile>
+	<Meta>
+		<RecycleBinEnabled>True</RecycleBinEnabled>
+		<RecycleBinUUID>cmVjeWNsZWJpbnV1aWQxMg==</RecycleBinUUID>
+	</Meta>
+	<Root>
+		<Group>
+			<UUID>cm9vdGdyb3VwdXVpZDEyMw==</UUID>
+			<Name>Database</Name>
+			<Entry>
+				<String><Key>Title</Key><Value>Router</Value></String>
+				<String><Key>Password</Key><Value Protected="True">%s</Value></String>
+			</Entry>
+			<Group>
+				<UUID>d29ya2dyb3VwdXVpZDEyMw==</UUID>
+				<Name>Work</Name>
+				<Group>
+					<UUID>c2VydmVyc3V1aWQxMjM0NQ==</UUID>
+					<Name>Servers</Name>
+					<Entry>
+						<Times><LastModificationTime>%s</LastModificationTime></Times>
+						<String><Key>Title</Key><Value>GitHub</Value></String>
+						<String><Key>UserName</Key><Value>octocat</Value></String>
+						<String><Key>Password</Key><Value Protected="True">%s</Value></String>
+						<String><Key>URL</Key><Value>https://github.com</Value></String>
+						<String><Key>Notes</Key><Value>2FA enabled</Value></String>
+						<String><Key>otp</Key><Value>otpauth://totp/GitHub?secret=JBSWY3DPEHPK3PXP</Value></String>
+						<String><Key>Recovery</Key><Value Protected="True">%s</Value></String>
+						<String><Key>Team</Key><Value>Platform</Value></String>
+						<Binary><Key>id_ed25519.pub</Key><Value Ref="0"/></Binary>
+						<History>
+							<Entry>
+								<Times><LastModificationTime>%s</LastModificationTime></Times>
+								<String><Key>Title</Key><Value>GitHub</Value></String>
+								<String><Key>UserName</Key><Value>octocat</Value></String>
+								<String><Key>Password</Key><Value Protected="True">%s</Value></String>
+							</Entry>
+						</History>
+					</Entry>
+				</Group>
+			</Group>
+			<Group>
+				<UUID>cmVjeWNsZWJpbnV1aWQxMg==</UUID>
+				<Name>Recycle Bin</Name>
+				<Entry>
+					<String><Key>Title</Key><Value>Deleted</Value></String>
+				</Entry>
+			</Group>
+		</Group>
+	</Root>
+</KeePassFile>`
+
+var kdbxSampleModified = time.Date(2024, 5, 1, 12, 30, 0, 0, time.UTC)
+
+// encodeKDBXTime encodes a KDBX 4 timestamp
+func encodeKDBXTime(t time.Time) string {
+	seconds := t.Unix() + kdbxUnixEpoch
+	return base64.StdEncoding.EncodeToString(binary.LittleEndian.AppendUint64(nil, uint64(seconds)))
+}
+
+func appendKDBXField(buf []byte, id byte, value []byte) []byte {
+	buf = append(buf, id)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(value)))
+	return append(buf, value...)
+}
+
+func appendVariant(buf []byte, valueType byte, key string, value []byte) []byte {
+	buf = append(buf, valueType)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(key)))
+	buf = append(buf, key...)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(value)))
+	return append(buf, value...)
+}
+
+// writeKDBX builds a KDBX 4 database containing kdbxSampleXML with a fast Argon2d KDF
+func writeKDBX(t *testing.T, cipherID uuid.UUID, opts Options) []byte {
+	t.Helper()
+	masterSeed := make([]byte, 32)
+	salt := make([]byte, 32)
+	streamKey := make([]byte, 64)
+	rand.Read(masterSeed)
+	rand.Read(salt)
+	rand.Read(streamKey)
+	iv := make([]byte, 16)
+	if cipherID == kdbxCipherChaCha20 {
+		iv = make([]byte, 12)
+	}
+	rand.Read(iv)
+
+	kdfParams := []byte{0x00, 0x01}
+	kdfParams = appendVariant(kdfParams, 0x42, "$UUID", kdbxKdfArgon2d[:])
+	kdfParams = appendVariant(kdfParams, 0x42, "S", salt)
+	kdfParams = appendVariant(kdfParams, 0x04, "P", binary.LittleEndian.AppendUint32(nil, 1))
+	kdfParams = appendVariant(kdfParams, 0x05, "M", binary.LittleEndian.AppendUint64(nil, 64*1024))
+	kdfParams = appendVariant(kdfParams, 0x05, "I", binary.LittleEndian.AppendUint64(nil, 2))
+	kdfParams = appendVariant(kdfParams, 0x04, "V", binary.LittleEndian.AppendUint32(nil, argon2Version))
+	kdfParams = append(kdfParams, 0x00)
+
+	header := binary.LittleEndian.AppendUint32(nil, kdbxSignature1)
+	header = binary.LittleEndian.AppendUint32(header, kdbxSignature2)
+	header = binary.LittleEndian.AppendUint32(header, kdbxMajorVersion<<16|1)
+	header = appendKDBXField(header, kdbxHeaderCipherID, cipherID[:])
+	header = appendKDBXField(header, kdbxHeaderCompression, binary.LittleEndian.AppendUint32(nil, 1))
+	header = appendKDBXField(header, kdbxHeaderMasterSeed, masterSeed)
+	header = appendKDBXField(header, kdbxHeaderEncryptionIV, iv)
+	header = appendKDBXField(header, kdbxHeaderKdfParameters, kdfParams)
+	header = appendKDBXField(header, kdbxHeaderEnd, []byte{'\r', '\n', '\r', '\n'})
+
+	compositeKey, err := kdbxCompositeKey(opts)
+	if err != nil {
+		t.Fatalf("failed to derive composite key: %v", err)
+	}
+	transformedKey := argon2d(compositeKey, salt, nil, nil, 2, 64, 1, 32)
+	hmacBase := sha512.Sum512(append(append(bytes.Clone(masterSeed), transformedKey...), 0x01))
+	masterKey := sha256.Sum256(append(bytes.Clone(masterSeed), transformedKey...))
+
+	// Protected values are encrypted with the inner stream in document order
+	keyHash := sha512.Sum512(streamKey)
+	stream, _ := chacha20.NewUnauthenticatedCipher(keyHash[:32], keyHash[32:44])
+	protect := func(value string) string {
+		out := []byte(value)
+		stream.XORKeyStream(out, out)
+		return base64.StdEncoding.EncodeToString(out)
+	}
+	xmlData := fmt.Sprintf(kdbxSampleXML,
+		protect("admin"),
+		encodeKDBXTime(kdbxSampleModified),
+		protect("hunter2"),
+		protect("abcd-efgh"),
+		encodeKDBXTime(kdbxSampleModified.Add(-24*time.Hour)),
+		protect("hunter1"),
+	)
+
+	inner := appendKDBXField(nil, kdbxInnerStreamID, binary.LittleEndian.AppendUint32(nil, kdbxInnerStreamChaCha20))
+	inner = appendKDBXField(inner, kdbxInnerStreamKey, streamKey)
+	inner = appendKDBXField(inner, kdbxInnerBinary, append([]byte{0x01}, "ssh-ed25519 AAAA"...))
+	inner = appendKDBXField(inner, kdbxInnerEnd, nil)
+	inner = append(inner, xmlData...)
+
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	gz.Write(inner)
+	gz.Close()
+	plaintext := compressed.Bytes()
+
+	var ciphertext []byte
+	switch cipherID {
+	case kdbxCipherAES256:
+		padding := aes.BlockSize - len(plaintext)%aes.BlockSize
+		plaintext = append(plaintext, bytes.Repeat([]byte{byte(padding)}, padding)...)
+		block, _ := aes.NewCipher(masterKey[:])
+		ciphertext = make([]byte, len(plaintext))
+		cipher.NewCBCEncrypter(block, iv).CryptBlocks(ciphertext, plaintext)
+	case kdbxCipherChaCha20:
+		stream, _ := chacha20.NewUnauthenticatedCipher(masterKey[:], iv)
+		ciphertext = make([]byte, len(plaintext))
+		stream.XORKeyStream(ciphertext, plaintext)
+	}
+
+	file := bytes.Clone(header)
+	headerHash := sha256.Sum256(header)
+	file = append(file, headerHash[:]...)
+	headerMAC := hmac.New(sha256.New, kdbxHMACKey(hmacBase[:], ^uint64(0)))
+	headerMAC.Write(header)
+	file = headerMAC.Sum(file)
+	// Split the payload into two blocks followed by the empty final block
+	blocks := [][]byte{ciphertext[:len(ciphertext)/2], ciphertext[len(ciphertext)/2:], nil}
+	for i, block := range blocks {
+		size := binary.LittleEndian.AppendUint32(nil, uint32(len(block)))
+		mac := hmac.New(sha256.New, kdbxHMACKey(hmacBase[:], uint64(i)))
+		mac.Write(binary.LittleEndian.AppendUint64(nil, uint64(i)))
+		mac.Write(size)
+		mac.Write(block)
+		file = mac.Sum(file)
+		file = append(file, size...)
+		file = append(file, block...)
+	}
+	return file
+}
+
+func TestParseKDBX(t *testing.T) {
+	keyFile := []byte(`<?xml version="1.0" encoding="utf-8"?>
+<KeyFile>
+	<Meta><Version>2.0</Version></Meta>
+	<Key>
+		<Data Hash="4b5ee4b3">
+			5B0C9E5B 6AFB9B8C 0DB23D1E 0C2A4E8A
+			5F1D4E6C 7A8B9C0D 1E2F3A4B 5C6D7E8F
+		</Data>
+	</Key>
+</KeyFile>`)
+	key, _ := hex.DecodeString("5B0C9E5B6AFB9B8C0DB23D1E0C2A4E8A5F1D4E6C7A8B9C0D1E2F3A4B5C6D7E8F")
+	sum := sha256.Sum256(key)
+	keyFile = bytes.Replace(keyFile, []byte("4b5ee4b3"), []byte(hex.EncodeToString(sum[:4])), 1)
+
+	tests := []struct {
+		name     string
+		cipherID uuid.UUID
+		opts     Options
+	}{
+		{"ChaCha20 password", kdbxCipherChaCha20, Options{Password: "correct horse"}},
+		{"AES password", kdbxCipherAES256, Options{Password: "correct horse"}},
+		{"AES password and key file", kdbxCipherAES256, Options{Password: "correct horse", KeyFile: keyFile}},
+		{"ChaCha20 key file", kdbxCipherChaCha20, Options{KeyFile: keyFile}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ParseKDBX(writeKDBX(t, tt.cipherID, tt.opts), tt.opts)
+			if err != nil {
+				t.Fatalf("failed to parse database: %v", err)
+			}
+			checkKDBXSample(t, result)
+		})
+	}
+}
+
+func checkKDBXSample(t *testing.T, result *Result) {
+	t.Helper()
+	if len(result.Items) != 2 {
+		t.Fatalf("parsed %d items, expected 2 (the recycle bin is skipped)", len(result.Items))
+	}
+	for _, item := range result.Items {
+		if err := structs.NormalizeItem(&item.Overview, &item.Details); err != nil {
+			t.Fatalf("item %q is invalid: %v", item.Overview.Title, err)
+		}
+	}
+
+	router := result.Items[0]
+	if router.Folder != "" || router.Overview.Title != "Router" || router.Details.Password != "admin" {
+		t.Fatalf("unexpected root entry %q in %q", router.Overview.Title, router.Folder)
+	}
+
+	login := result.Items[1]
+	if login.Folder != "Work" || login.VaultFolder != "Servers" || login.Overview.URL != "https://github.com" || login.Details.Username != "octocat" || login.Details.Password != "hunter2" || login.Details.Notes != "2FA enabled" {
+		t.Fatalf("unexpected login in %q/%q: %+v %+v", login.Folder, login.VaultFolder, login.Overview, login.Details)
+	}
+	if f := login.Details.FieldByLabel("One-Time Password"); f == nil || f.Type != structs.FieldTypeOTP {
+		t.Fatalf("expected the otp string to be imported as an OTP field")
+	}
+	if f := login.Details.FieldByLabel("Recovery"); f == nil || f.Value != "abcd-efgh" || !f.Concealed {
+		t.Fatalf("expected the protected string to be a concealed field, got %+v", f)
+	}
+	if f := login.Details.FieldByLabel("Team"); f == nil || f.Value != "Platform" || f.Concealed {
+		t.Fatalf("expected the string to be a text field, got %+v", f)
+	}
+	if len(login.Attachments) != 1 || login.Attachments[0].Name != "id_ed25519.pub" || string(login.Attachments[0].Data) != "ssh-ed25519 AAAA" {
+		t.Fatalf("unexpected attachments %+v", login.Attachments)
+	}
+	if len(login.History) != 1 || login.History[0].Details.Password != "hunter1" || !login.History[0].ModifiedAt.Equal(kdbxSampleModified.Add(-24*time.Hour)) {
+		t.Fatalf("unexpected history %+v", login.History)
+	}
+}
+
+func TestParseKDBXCredentials(t *testing.T) {
+	data := writeKDBX(t, kdbxCipherChaCha20, Options{Password: "correct horse"})
+	if _, err := ParseKDBX(data, Options{}); !errors.Is(err, ErrPasswordRequired) {
+		t.Fatalf("expected ErrPasswordRequired without credentials, got %v", err)
+	}
+	if _, err := ParseKDBX(data, Options{Password: "wrong"}); !errors.Is(err, ErrWrongPassword) {
+		t.Fatalf("expected ErrWrongPassword, got %v", err)
+	}
+	if _, err := ParseKDBX(data, Options{Password: "correct horse", KeyFile: []byte("extra key")}); !errors.Is(err, ErrWrongPassword) {
+		t.Fatalf("expected ErrWrongPassword with an unexpected key file, got %v", err)
+	}
+	if _, err := ParseKDBX(data[:40], Options{Password: "correct horse"}); err == nil {
+		t.Fatalf("expected a truncated database to fail")
+	}
+	if _, err := ParseKDBX([]byte("not a database"), Options{}); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Fatalf("expected ErrUnsupportedFormat, got %v", err)
+	}
+}
+
+func TestKDBXTime(t *testing.T) {
+	if got := kdbxTime(encodeKDBXTime(kdbxSampleModified)); !got.Equal(kdbxSampleModified) {
+		t.Fatalf("decoded %v, expected %v", got, kdbxSampleModified)
+	}
+	if got := kdbxTime("2024-05-01T12:30:00Z"); !got.Equal(kdbxSampleModified) {
+		t.Fatalf("decoded %v, expected %v", got, kdbxSampleModified)
+	}
+}
+
+func TestKDBXArgon2Limits(t *testing.T) {
+	tests := []struct {
+		name                          string
+		iterations, memory, parallels uint64
+	}{
+		{"iterations", kdbxMaxArgon2Iterations + 1, 64 << 20, 2},
+		{"memory", 2, (kdbxMaxArgon2Memory + 1) << 10, 2},
+		{"no iterations", 0, 64 << 20, 2},
+		{"no parallelism", 2, 64 << 20, 0},
+	}
+	for _, tt := range tests {
+		header := &kdbxHeader{kdfParameters: map[string]any{
+			"$UUID": kdbxKdfArgon2d[:],
+			"S":     make([]byte, 32),
+			"V":     uint64(argon2Version),
+			"I":     tt.iterations,
+			"M":     tt.memory,
+			"P":     tt.parallels,
+		}}
+		if _, err := header.transformKey(make([]byte, 32)); !errors.Is(err, ErrUnsupportedFormat) {
+			t.Fatalf("%s: expected ErrUnsupportedFormat, got %v", tt.name, err)
+		}
+	}
+}
+
+func TestKDBXAESRoundsLimit(t *testing.T) {
+	header := &kdbxHeader{kdfParameters: map[string]any{
+		"$UUID": kdbxKdfAES[:],
+		"S":     make([]byte, 32),
+		"R":     uint64(kdbxMaxAESRounds + 1),
+	}}
+	if _, err := header.transformKey(make([]byte, 32)); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Fatalf("expected ErrUnsupportedFormat for too many rounds, got %v", err)
+	}
+	header.kdfParameters["R"] = uint64(1000)
+	if _, err := header.transformKey(make([]byte, 32)); err != nil {
+		t.Fatalf("failed to transform key: %v", err)
+	}
+}
@@ -0,0 +1,422 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+)
+
+// 1Password item category UUIDs
+const (
+	onePasswordLogin         = "001"
+	onePasswordCreditCard    = "002"
+	onePasswordSecureNote    = "003"
+	onePasswordIdentity      = "004"
+	onePasswordPassword      = "005"
+	onePasswordDocument      = "006"
+	onePasswordAPICredential = "112"
+	onePasswordSSHKey        = "114"
+)
+
+// Upper bound for a single file in the archive so a crafted export cannot exhaust memory
+const onePasswordMaxFileSize = 256 << 20
+
+type onePasswordExport struct {
+	Accounts []struct {
+		Attrs struct {
+			AccountName string `json:"accountName"`
+		} `json:"attrs"`
+		Vaults []struct {
+			Attrs struct {
+				Name string `json:"name"`
+			} `json:"attrs"`
+			Items []*onePasswordItem `json:"items"`
+		} `json:"vaults"`
+	} `json:"accounts"`
+}
+
+type onePasswordItem struct {
+	CategoryUUID string `json:"categoryUuid"`
+	Details      struct {
+		LoginFields []struct {
+			Value       string `json:"value"`
+			Name        string `json:"name"`
+			FieldType   string `json:"fieldType"`
+			Designation string `json:"designation"`
+		} `json:"loginFields"`
+		NotesPlain         string                     `json:"notesPlain"`
+		Password           string                     `json:"password"`
+		Sections           []*onePasswordSection      `json:"sections"`
+		DocumentAttributes *onePasswordFile           `json:"documentAttributes"`
+		PasswordHistory    []*onePasswordHistoryEntry `json:"passwordHistory"`
+	} `json:"details"`
+	Overview struct {
+		Title string `json:"title"`
+		URL   string `json:"url"`
+		URLs  []struct {
+			URL string `json:"url"`
+		} `json:"urls"`
+	} `json:"overview"`
+}
+
+// onePasswordHistoryEntry is an earlier password of an item
+type onePasswordHistoryEntry struct {
+	Value string `json:"value"`
+	// Unix time the password was replaced
+	Time int64 `json:"time"`
+}
+
+type onePasswordSection struct {
+	Title  string `json:"title"`
+	Fields []struct {
+		Title string `json:"title"`
+		ID    string `json:"id"`
+		// A single key naming the value type, such as {"concealed": "..."}
+		Value map[string]json.RawMessage `json:"value"`
+	} `json:"fields"`
+}
+
+type onePasswordFile struct {
+	FileName   string `json:"fileName"`
+	DocumentID string `json:"documentId"`
+}
+
+type onePasswordAddress struct {
+	Street  string `json:"street"`
+	City    string `json:"city"`
+	Country string `json:"country"`
+	Zip     string `json:"zip"`
+	State   string `json:"state"`
+}
+
+// onePasswordArchive holds the export data and attached files of a 1PUX archive
+type onePasswordArchive struct {
+	export onePasswordExport
+	files  map[string]*zip.File
+}
+
+// ParseOnePassword reads a 1Password 1PUX export. Each 1Password vault becomes a folder, prefixed with the account
+// name when the export holds more than one account. Categories without an OpenVault equivalent are imported as
+// secure notes which keep all of their fields.
+func ParseOnePassword(data []byte, opts Options) (*Result, error) {
+	archive, err := readOnePasswordArchive(data)
+	if err != nil {
+		return nil, err
+	}
+	result := &Result{}
+	for _, account := range archive.export.Accounts {
+		for _, vault := range account.Vaults {
+			folder := vault.Attrs.Name
+			if len(archive.export.Accounts) > 1 {
+				folder = account.Attrs.AccountName + "/" + folder
+			}
+			for _, opItem := range vault.Items {
+				if item := archive.item(opItem, folder, result); item != nil {
+					result.Items = append(result.Items, item)
+				}
+			}
+		}
+	}
+	return result, nil
+}
+
+func readOnePasswordArchive(data []byte) (*onePasswordArchive, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: not a 1PUX archive", ErrUnsupportedFormat)
+	}
+	archive := &onePasswordArchive{files: make(map[string]*zip.File)}
+	var exportData *zip.File
+	for _, f := range zr.File {
+		switch {
+		case f.Name == "export.data":
+			exportData = f
+		case strings.HasPrefix(f.Name, "files/"):
+			archive.files[strings.TrimPrefix(f.Name, "files/")] = f
+		}
+	}
+	if exportData == nil {
+		return nil, fmt.Errorf("%w: the 1PUX archive has no export.data", ErrUnsupportedFormat)
+	}
+	raw, err := readZipFile(exportData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read 1PUX archive: %w", err)
+	}
+	if err := json.Unmarshal(raw, &archive.export); err != nil {
+		return nil, fmt.Errorf("failed to parse 1PUX archive: %w", err)
+	}
+	return archive, nil
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	if f.UncompressedSize64 > onePasswordMaxFileSize {
+		return nil, fmt.Errorf("%s is too large", f.Name)
+	}
+	r, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	data, err := io.ReadAll(io.LimitReader(r, onePasswordMaxFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > onePasswordMaxFileSize {
+		return nil, fmt.Errorf("%s is too large", f.Name)
+	}
+	return data, nil
+}
+
+// file returns the contents of an attached file. 1Password stores them as files/<documentId>__<fileName>.
+func (archive *onePasswordArchive) file(file *onePasswordFile) ([]byte, error) {
+	f, ok := archive.files[file.DocumentID+"__"+file.FileName]
+	if !ok {
+		f, ok = archive.files[file.DocumentID]
+	}
+	if !ok {
+		return nil, fmt.Errorf("file not found in archive")
+	}
+	return readZipFile(f)
+}
+
+// item converts a 1Password item, returning nil if it is skipped
+func (archive *onePasswordArchive) item(opItem *onePasswordItem, folder string, result *Result) *Item {
+	title := opItem.Overview.Title
+	item := &Item{
+		Folder:   folder,
+		Overview: structs.VaultItemOverview{Title: title, URL: opItem.Overview.URL},
+		Details:  structs.VaultItemDetails{Notes: opItem.Details.NotesPlain},
+	}
+	details := &item.Details
+	switch opItem.CategoryUUID {
+	case onePasswordLogin, onePasswordPassword:
+		details.Category = structs.CategoryLogin
+		details.Password = opItem.Details.Password
+		for _, field := range opItem.Details.LoginFields {
+			switch field.Designation {
+			case "username":
+				details.Username = field.Value
+			case "password":
+				details.Password = field.Value
+			}
+		}
+	case onePasswordCreditCard:
+		details.Category = structs.CategoryCreditCard
+		details.Card = &structs.CreditCardDetails{}
+	case onePasswordIdentity:
+		details.Category = structs.CategoryIdentity
+		details.Identity = &structs.IdentityDetails{}
+	case onePasswordAPICredential:
+		details.Category = structs.CategoryAPICredential
+		details.API = &structs.APICredentialDetails{}
+	case onePasswordSSHKey:
+		details.Category = structs.CategorySSHKey
+		details.SSH = &structs.SSHKeyDetails{}
+	default:
+		details.Category = structs.CategorySecureNote
+	}
+	for _, u := range opItem.Overview.URLs {
+		if u.URL != "" && u.URL != item.Overview.URL {
+			if item.Overview.URL == "" {
+				item.Overview.URL = u.URL
+				continue
+			}
+			addField(details, customSection, &structs.Field{Label: "Website", Type: structs.FieldTypeURL, Value: u.URL})
+		}
+	}
+	if opItem.CategoryUUID == onePasswordDocument && opItem.Details.DocumentAttributes != nil {
+		archive.attach(item, opItem.Details.DocumentAttributes, result)
+	}
+	for _, section := range opItem.Details.Sections {
+		archive.section(item, section, result)
+	}
+	if details.SSH != nil && details.SSH.PrivateKey == "" {
+		result.skip("%q: SSH key without a private key", title)
+		return nil
+	}
+	if details.API != nil && details.API.Key == "" && details.API.Secret == "" {
+		// An empty API credential is invalid, so keep its fields on a secure note instead
+		details.Category = structs.CategorySecureNote
+		details.API = nil
+	}
+
+	history := slices.Clone(opItem.Details.PasswordHistory)
+	slices.SortFunc(history, func(x, y *onePasswordHistoryEntry) int {
+		return cmp.Compare(x.Time, y.Time)
+	})
+	for _, old := range history {
+		// Earlier passwords are kept as earlier versions of the item which only differ in the password
+		revision := &Revision{Overview: item.Overview, Details: *details, ModifiedAt: time.Unix(old.Time, 0).UTC()}
+		revision.Details.Password = old.Value
+		item.History = append(item.History, revision)
+	}
+	return item
+}
+
+// section maps the fields of a 1Password section onto the item. Fields which have a dedicated place in the
+// item's category are moved there; the rest are kept as custom fields in a section with the same title.
+func (archive *onePasswordArchive) section(item *Item, section *onePasswordSection, result *Result) {
+	details := &item.Details
+	sectionTitle := section.Title
+	if sectionTitle == "" {
+		sectionTitle = customSection
+	}
+	for _, field := range section.Fields {
+		for valueType, raw := range field.Value {
+			var value string
+			switch valueType {
+			case "file":
+				var file onePasswordFile
+				if json.Unmarshal(raw, &file) == nil {
+					archive.attach(item, &file, result)
+				}
+				continue
+			case "address":
+				var address onePasswordAddress
+				if json.Unmarshal(raw, &address) != nil {
+					continue
+				}
+				if details.Identity != nil && field.ID == "address" {
+					details.Identity.Address1 = address.Street
+					details.Identity.City = address.City
+					details.Identity.State = address.State
+					details.Identity.PostalCode = address.Zip
+					details.Identity.Country = address.Country
+					continue
+				}
+				value = strings.Join(slices.DeleteFunc([]string{address.Street, address.City, address.State, address.Zip, address.Country}, func(s string) bool { return s == "" }), ", ")
+			case "sshKey":
+				var key struct {
+					PrivateKey string `json:"privateKey"`
+				}
+				if json.Unmarshal(raw, &key) == nil && details.SSH != nil {
+					details.SSH.PrivateKey = key.PrivateKey
+				}
+				continue
+			case "email":
+				var email struct {
+					Address string `json:"email_address"`
+				}
+				if json.Unmarshal(raw, &email) != nil {
+					// Older exports store the address as a string
+					json.Unmarshal(raw, &email.Address)
+				}
+				value = email.Address
+			case "date":
+				var seconds int64
+				if json.Unmarshal(raw, &seconds) == nil && seconds != 0 {
+					value = time.Unix(seconds, 0).UTC().Format(time.DateOnly)
+				}
+			case "monthYear":
+				var monthYear int
+				if json.Unmarshal(raw, &monthYear) == nil && monthYear != 0 {
+					value = fmt.Sprintf("%02d/%d", monthYear%100, monthYear/100)
+				}
+			default:
+				// Most value types (string, concealed, totp, url, phone, menu, creditCardNumber, ...) are plain strings
+				if json.Unmarshal(raw, &value) != nil {
+					result.skip("%q: field %q of type %s", item.Overview.Title, field.Title, valueType)
+					continue
+				}
+			}
+			if value == "" || onePasswordCategoryField(details, field.ID, valueType, value) {
+				continue
+			}
+			switch valueType {
+			case "totp":
+				addOTPField(details, value)
+				continue
+			}
+			addField(details, sectionTitle, &structs.Field{Label: field.Title, Type: onePasswordFieldType(valueType), Value: value})
+		}
+	}
+}
+
+// onePasswordCategoryField moves a field to its dedicated place in the item's category, returning false if it
+// has none
+func onePasswordCategoryField(details *structs.VaultItemDetails, id string, valueType string, value string) bool {
+	switch {
+	case details.Card != nil:
+		card := details.Card
+		switch id {
+		case "cardholder":
+			card.Cardholder = value
+		case "ccnum":
+			card.Number = value
+		case "cvv":
+			card.CVV = value
+		case "expiry":
+			card.Expiry = value
+		case "pin":
+			card.PIN = value
+		default:
+			return false
+		}
+	case details.Identity != nil:
+		identity := details.Identity
+		switch id {
+		case "firstname":
+			identity.FirstName = value
+		case "lastname":
+			identity.LastName = value
+		case "email":
+			identity.Email = value
+		case "defphone":
+			identity.Phone = value
+		default:
+			return false
+		}
+	case details.API != nil:
+		api := details.API
+		switch id {
+		case "username":
+			api.Key = value
+		case "credential":
+			api.Secret = value
+		case "hostname":
+			if !strings.Contains(value, "://") {
+				return false
+			}
+			api.Endpoint = value
+		default:
+			return false
+		}
+	case details.Category == structs.CategoryLogin && valueType == "concealed" && id == "password" && details.Password == "":
+		details.Password = value
+	default:
+		return false
+	}
+	return true
+}
+
+func onePasswordFieldType(valueType string) structs.FieldType {
+	switch valueType {
+	case "concealed", "creditCardNumber":
+		return structs.FieldTypeConcealed
+	case "url":
+		return structs.FieldTypeURL
+	case "email":
+		return structs.FieldTypeEmail
+	case "date":
+		return structs.FieldTypeDate
+	default:
+		return structs.FieldTypeText
+	}
+}
+
+func (archive *onePasswordArchive) attach(item *Item, file *onePasswordFile, result *Result) {
+	data, err := archive.file(file)
+	if err != nil {
+		result.skip("%q: file %q: %v", item.Overview.Title, file.FileName, err)
+		return
+	}
+	item.Attachments = append(item.Attachments, &Attachment{Name: file.FileName, Data: data})
+}
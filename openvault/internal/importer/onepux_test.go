@@ -0,0 +1,208 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+)
+
+const onePasswordSample = `{
+  "accounts": [{
+    "attrs": {"accountName": "Personal", "name": "Jane Doe", "email": "jane@example.com"},
+    "vaults": [{
+      "attrs": {"uuid": "v1", "name": "Private"},
+      "items": [
+        {
+          "uuid": "i1", "categoryUuid": "001", "state": "active",
+          "details": {
+            "loginFields": [
+              {"value": "octocat", "name": "username", "fieldType": "T", "designation": "username"},
+              {"value": "hunter2", "name": "password", "fieldType": "P", "designation": "password"}
+            ],
+            "notesPlain": "2FA enabled",
+            "sections": [{
+              "title": "Security", "name": "security",
+              "fields": [
+                {"title": "one-time password", "id": "TOTP_1", "value": {"totp": "otpauth://totp/GitHub?secret=JBSWY3DPEHPK3PXP"}},
+                {"title": "recovery code", "id": "r1", "value": {"concealed": "abcd-efgh"}},
+                {"title": "recovery email", "id": "r2", "value": {"email": {"email_address": "backup@example.com", "provider": null}}},
+                {"title": "renewal", "id": "r3", "value": {"date": 1714564800}},
+                {"title": "backup codes", "id": "r4", "value": {"file": {"fileName": "codes.txt", "documentId": "d1", "decryptedSize": 10}}},
+                {"title": "reference", "id": "r5", "value": {"reference": {"uuid": "x"}}}
+              ]
+            }],
+            "passwordHistory": [{"value": "hunter1", "time": 1700000000}, {"value": "hunter0", "time": 1600000000}]
+          },
+          "overview": {"title": "GitHub", "url": "https://github.com", "urls": [{"label": "", "url": "https://github.com"}, {"label": "", "url": "https://gist.github.com"}]}
+        },
+        {
+          "uuid": "i2", "categoryUuid": "002",
+          "details": {
+            "sections": [{
+              "title": "", "name": "",
+              "fields": [
+                {"title": "cardholder name", "id": "cardholder", "value": {"string": "Jane Doe"}},
+                {"title": "type", "id": "type", "value": {"creditCardType": "visa"}},
+                {"title": "number", "id": "ccnum", "value": {"creditCardNumber": "4111111111111111"}},
+                {"title": "verification number", "id": "cvv", "value": {"concealed": "123"}},
+                {"title": "expiry date", "id": "expiry", "value": {"monthYear": 203003}}
+              ]
+            }]
+          },
+          "overview": {"title": "Visa"}
+        },
+        {
+          "uuid": "i3", "categoryUuid": "004",
+          "details": {
+            "sections": [{
+              "title": "Identification", "name": "name",
+              "fields": [
+                {"title": "first name", "id": "firstname", "value": {"string": "Jane"}},
+                {"title": "last name", "id": "lastname", "value": {"string": "Doe"}},
+                {"title": "address", "id": "address", "value": {"address": {"street": "1 Main St", "city": "Springfield", "country": "us", "zip": "12345", "state": "IL"}}}
+              ]
+            }]
+          },
+          "overview": {"title": "Me"}
+        },
+        {
+          "uuid": "i4", "categoryUuid": "006",
+          "details": {"documentAttributes": {"fileName": "passport.pdf", "documentId": "d2", "decryptedSize": 4}},
+          "overview": {"title": "Passport scan"}
+        },
+        {
+          "uuid": "i5", "categoryUuid": "109",
+          "details": {
+            "sections": [{
+              "title": "", "name": "",
+              "fields": [
+                {"title": "base station name", "id": "name", "value": {"string": "Home"}},
+                {"title": "base station password", "id": "password", "value": {"concealed": "router-pass"}}
+              ]
+            }]
+          },
+          "overview": {"title": "Home router"}
+        },
+        {
+          "uuid": "i6", "categoryUuid": "114",
+          "details": {"sections": [{"title": "", "name": "", "fields": []}]},
+          "overview": {"title": "Empty key"}
+        }
+      ]
+    }]
+  }]
+}`
+
+// writeOnePasswordArchive builds a 1PUX archive from export data and attached files keyed by their archive name
+func writeOnePasswordArchive(t *testing.T, exportData string, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	write := func(name string, data string) {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatalf("failed to create %s: %v", name, err)
+		}
+		w.Write([]byte(data))
+	}
+	write("export.attributes", `{"version": 3, "description": "1Password Unencrypted Export"}`)
+	write("export.data", exportData)
+	for name, data := range files {
+		write("files/"+name, data)
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatalf("failed to write archive: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestParseOnePassword(t *testing.T) {
+	data := writeOnePasswordArchive(t, onePasswordSample, map[string]string{
+		"d1__codes.txt": "1234 5678",
+		// The passport scan (d2) is missing from the archive
+	})
+	result, err := ParseOnePassword(data, Options{})
+	if err != nil {
+		t.Fatalf("failed to parse export: %v", err)
+	}
+	if len(result.Items) != 5 {
+		t.Fatalf("parsed %d items, expected 5", len(result.Items))
+	}
+	for _, item := range result.Items {
+		if err := structs.NormalizeItem(&item.Overview, &item.Details); err != nil {
+			t.Fatalf("item %q is invalid: %v", item.Overview.Title, err)
+		}
+		if item.Folder != "Private" {
+			t.Fatalf("item %q is in folder %q, expected the vault name", item.Overview.Title, item.Folder)
+		}
+	}
+
+	login := result.Items[0]
+	if login.Overview.URL != "https://github.com" || login.Details.Username != "octocat" || login.Details.Password != "hunter2" || login.Details.Notes != "2FA enabled" {
+		t.Fatalf("unexpected login %+v %+v", login.Overview, login.Details)
+	}
+	if f := login.Details.FieldByLabel("Website"); f == nil || f.Value != "https://gist.github.com" {
+		t.Fatalf("expected the second URL to be a website field")
+	}
+	if f := login.Details.FieldByLabel("One-Time Password"); f == nil || f.Type != structs.FieldTypeOTP {
+		t.Fatalf("expected the totp field to be an OTP field")
+	}
+	if f := login.Details.FieldByLabel("recovery code"); f == nil || !f.Concealed {
+		t.Fatalf("expected the concealed field to be concealed")
+	}
+	if f := login.Details.FieldByLabel("recovery email"); f == nil || f.Type != structs.FieldTypeEmail || f.Value != "backup@example.com" {
+		t.Fatalf("unexpected email field %+v", f)
+	}
+	if f := login.Details.FieldByLabel("renewal"); f == nil || f.Type != structs.FieldTypeDate || f.Value != "2024-05-01" {
+		t.Fatalf("unexpected date field %+v", f)
+	}
+	if len(login.Attachments) != 1 || login.Attachments[0].Name != "codes.txt" || string(login.Attachments[0].Data) != "1234 5678" {
+		t.Fatalf("unexpected attachments %+v", login.Attachments)
+	}
+	if len(login.History) != 2 || login.History[0].Details.Password != "hunter0" || login.History[1].Details.Password != "hunter1" || !login.History[1].ModifiedAt.Equal(time.Unix(1700000000, 0)) {
+		t.Fatalf("expected the password history oldest first, got %+v", login.History)
+	}
+
+	card := result.Items[1].Details.Card
+	if card == nil || card.Cardholder != "Jane Doe" || card.Number != "4111111111111111" || card.CVV != "123" || card.Expiry != "03/2030" {
+		t.Fatalf("unexpected card %+v", card)
+	}
+	identity := result.Items[2].Details.Identity
+	if identity == nil || identity.FirstName != "Jane" || identity.LastName != "Doe" || identity.City != "Springfield" || identity.PostalCode != "12345" {
+		t.Fatalf("unexpected identity %+v", identity)
+	}
+	if document := result.Items[3]; document.Details.Category != structs.CategorySecureNote || len(document.Attachments) != 0 {
+		t.Fatalf("expected the document without its file to be a secure note")
+	}
+	router := result.Items[4]
+	if router.Details.Category != structs.CategorySecureNote {
+		t.Fatalf("expected an unsupported category to be a secure note, got %s", router.Details.Category)
+	}
+	if f := router.Details.FieldByLabel("base station password"); f == nil || f.Value != "router-pass" || !f.Concealed {
+		t.Fatalf("expected the fields of an unsupported category to be kept, got %+v", f)
+	}
+	if len(result.Skipped) != 3 {
+		t.Fatalf("expected the reference field, missing file and empty SSH key to be skipped, got %q", result.Skipped)
+	}
+}
+
+func TestParseOnePasswordAccounts(t *testing.T) {
+	data := writeOnePasswordArchive(t, `{"accounts": [
+		{"attrs": {"accountName": "Personal"}, "vaults": [{"attrs": {"name": "Private"}, "items": [{"categoryUuid": "003", "details": {"notesPlain": "a"}, "overview": {"title": "Note"}}]}]},
+		{"attrs": {"accountName": "Work"}, "vaults": [{"attrs": {"name": "Shared"}, "items": [{"categoryUuid": "003", "details": {"notesPlain": "b"}, "overview": {"title": "Note"}}]}]}
+	]}`, nil)
+	result, err := ParseOnePassword(data, Options{})
+	if err != nil {
+		t.Fatalf("failed to parse export: %v", err)
+	}
+	if len(result.Items) != 2 || result.Items[0].Folder != "Personal/Private" || result.Items[1].Folder != "Work/Shared" {
+		t.Fatalf("expected folders prefixed with the account name")
+	}
+	if _, err := ParseOnePassword([]byte("not a zip"), Options{}); !errors.Is(err, ErrUnsupportedFormat) {
+		t.Fatalf("expected ErrUnsupportedFormat, got %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+)
+
+// ParseOpenVault reads an OpenVault export archive, decrypting it with the export password. Each exported vault
+// becomes a folder, and the folders within it are kept as the items' vault folders.
+func ParseOpenVault(data []byte, opts Options) (*Result, error) {
+	var archive structs.ExportArchive
+	if err := json.Unmarshal(data, &archive); err != nil || archive.EncryptedData == nil {
+		return nil, fmt.Errorf("%w: not an OpenVault export archive", ErrUnsupportedFormat)
+	}
+	if opts.Password == "" {
+		return nil, ErrPasswordRequired
+	}
+	export, err := archive.Open(opts.Password)
+	if errors.Is(err, structs.ErrExportWrongPassword) {
+		return nil, ErrWrongPassword
+	}
+	if errors.Is(err, structs.ErrExportUnknownFormat) {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedFormat, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	result := &Result{}
+	for _, vault := range export.Vaults {
+		folderNames := make(map[string]string)
+		for _, folder := range vault.Folders {
+			folderNames[folder.ID] = folder.Name
+		}
+		for _, exported := range vault.Items {
+			if exported.Overview == nil || exported.Details == nil {
+				result.skip("%q: item without details", vault.Name)
+				continue
+			}
+			item := &Item{
+				Folder:      vault.Name,
+				VaultFolder: folderNames[exported.Overview.FolderID],
+				Overview:    *exported.Overview,
+				Details:     *exported.Details,
+			}
+			// Folder IDs belong to the exported vault and are assigned again on import
+			item.Overview.FolderID = ""
+			for _, attachment := range exported.Attachments {
+				item.Attachments = append(item.Attachments, &Attachment{Name: attachment.Name, Data: attachment.Data})
+			}
+			result.Items = append(result.Items, item)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,65 @@
+package importer
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+)
+
+func TestParseOpenVault(t *testing.T) {
+	data := &structs.ExportData{Vaults: []*structs.ExportVault{{
+		Name:    "Personal",
+		Folders: []*structs.Folder{{ID: "f1", Name: "Banking"}},
+		Items: []*structs.ExportItem{
+			{
+				Overview: &structs.VaultItemOverview{Category: structs.CategoryLogin, Title: "Bank", FolderID: "f1"},
+				Details:  &structs.VaultItemDetails{Category: structs.CategoryLogin, Password: "hunter2"},
+			},
+			{
+				Overview: &structs.VaultItemOverview{Category: structs.CategoryLogin, Title: "GitHub"},
+				Details:  &structs.VaultItemDetails{Category: structs.CategoryLogin, Password: "hunter3"},
+			},
+			{Overview: &structs.VaultItemOverview{Title: "Broken"}},
+		},
+	}}}
+	archive, err := structs.SealExportArchive("correct horse", 1000, data)
+	if err != nil {
+		t.Fatalf("failed to seal export archive: %v", err)
+	}
+	raw, err := json.Marshal(archive)
+	if err != nil {
+		t.Fatalf("failed to marshal export archive: %v", err)
+	}
+
+	if _, err := ParseOpenVault(raw, Options{}); !errors.Is(err, ErrPasswordRequired) {
+		t.Fatalf("expected ErrPasswordRequired, got %v", err)
+	}
+	if _, err := ParseOpenVault(raw, Options{Password: "wrong horse"}); !errors.Is(err, ErrWrongPassword) {
+		t.Fatalf("expected ErrWrongPassword, got %v", err)
+	}
+	result, err := ParseOpenVault(raw, Options{Password: "correct horse"})
+	if err != nil {
+		t.Fatalf("failed to parse export archive: %v", err)
+	}
+	if len(result.Items) != 2 || len(result.Skipped) != 1 {
+		t.Fatalf("expected 2 items and 1 skipped, got %d and %v", len(result.Items), result.Skipped)
+	}
+	tests := []struct {
+		title       string
+		vaultFolder string
+	}{
+		{"Bank", "Banking"},
+		{"GitHub", ""},
+	}
+	for i, tt := range tests {
+		item := result.Items[i]
+		if item.Overview.Title != tt.title || item.Folder != "Personal" || item.VaultFolder != tt.vaultFolder {
+			t.Fatalf("unexpected item %q in folder %q/%q", item.Overview.Title, item.Folder, item.VaultFolder)
+		}
+		if item.Overview.FolderID != "" {
+			t.Fatalf("expected the exported folder ID of %q to be cleared, got %q", tt.title, item.Overview.FolderID)
+		}
+	}
+}
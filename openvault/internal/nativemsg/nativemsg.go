@@ -0,0 +1,53 @@
+// Package nativemsg implements the framing of the WebExtensions native messaging protocol used by Chrome and
+// Firefox (https://developer.chrome.com/docs/extensions/develop/concepts/native-messaging): every message is
+// JSON prefixed with its length as a 32-bit unsigned integer in native byte order.
+package nativemsg
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// MaxMessageSize is the largest message a browser accepts from a native messaging host. Larger incoming messages
+// are rejected too since no request needs to be that large.
+const MaxMessageSize = 1 << 20
+
+var (
+	ErrMessageTooLarge = errors.New("native message exceeds the maximum size")
+)
+
+// ReadMessage reads one message and decodes it into v. io.EOF is returned if the input ends before a new message.
+func ReadMessage(r io.Reader, v any) error {
+	var length uint32
+	if err := binary.Read(r, binary.NativeEndian, &length); err != nil {
+		return err
+	}
+	if length > MaxMessageSize {
+		return ErrMessageTooLarge
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return fmt.Errorf("failed to read message: %w", err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		return fmt.Errorf("failed to decode message: %w", err)
+	}
+	return nil
+}
+
+// WriteMessage encodes v and writes it as one message
+func WriteMessage(w io.Writer, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to encode message: %w", err)
+	}
+	if len(data) > MaxMessageSize {
+		return ErrMessageTooLarge
+	}
+	message := binary.NativeEndian.AppendUint32(make([]byte, 0, 4+len(data)), uint32(len(data)))
+	_, err = w.Write(append(message, data...))
+	return err
+}
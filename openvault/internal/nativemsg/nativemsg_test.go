@@ -0,0 +1,67 @@
+package nativemsg
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+type message struct {
+	Type   string `json:"type"`
+	Origin string `json:"origin"`
+}
+
+func TestRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	messages := []message{{"logins", "https://example.com"}, {"status", ""}}
+	for _, m := range messages {
+		if err := WriteMessage(&buf, &m); err != nil {
+			t.Fatalf("failed to write message: %v", err)
+		}
+	}
+	for _, want := range messages {
+		var got message
+		if err := ReadMessage(&buf, &got); err != nil {
+			t.Fatalf("failed to read message: %v", err)
+		}
+		if got != want {
+			t.Fatalf("read %+v, expected %+v", got, want)
+		}
+	}
+	if err := ReadMessage(&buf, &message{}); err != io.EOF {
+		t.Fatalf("expected io.EOF after the last message, got %v", err)
+	}
+}
+
+func TestFraming(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteMessage(&buf, map[string]string{"a": "b"}); err != nil {
+		t.Fatalf("failed to write message: %v", err)
+	}
+	data := buf.Bytes()
+	if length := binary.NativeEndian.Uint32(data); int(length) != len(data)-4 || string(data[4:]) != `{"a":"b"}` {
+		t.Fatalf("unexpected frame %q", data)
+	}
+}
+
+func TestMessageTooLarge(t *testing.T) {
+	if err := WriteMessage(io.Discard, strings.Repeat("a", MaxMessageSize)); !errors.Is(err, ErrMessageTooLarge) {
+		t.Fatalf("expected ErrMessageTooLarge writing a large message, got %v", err)
+	}
+	header := binary.NativeEndian.AppendUint32(nil, MaxMessageSize+1)
+	if err := ReadMessage(bytes.NewReader(header), &message{}); !errors.Is(err, ErrMessageTooLarge) {
+		t.Fatalf("expected ErrMessageTooLarge reading a large message, got %v", err)
+	}
+}
+
+func TestTruncatedMessage(t *testing.T) {
+	var buf bytes.Buffer
+	WriteMessage(&buf, &message{Type: "status"})
+	truncated := bytes.NewReader(buf.Bytes()[:buf.Len()-2])
+	if err := ReadMessage(truncated, &message{}); err == nil || err == io.EOF {
+		t.Fatalf("expected an error for a truncated message, got %v", err)
+	}
+}
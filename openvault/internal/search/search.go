@@ -0,0 +1,279 @@
+// Package search is an in-memory inverted index over the decrypted text of items. It only lives while the vaults
+// are unlocked and is never written to disk.
+package search
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Field weights: matches in the title count the most, matches in notes the least
+const (
+	weightTitle    = 10
+	weightTags     = 8
+	weightUsername = 6
+	weightURL      = 6
+	weightNotes    = 2
+)
+
+// Match kinds multiply the field weight: exact terms rank above prefixes, which rank above typos
+const (
+	boostExact  = 3
+	boostPrefix = 2
+	boostFuzzy  = 1
+)
+
+// Parts of URLs which say nothing about the site
+var urlStopwords = []string{"http", "https", "www"}
+
+// Document is the searchable text of an item
+type Document struct {
+	Title    string
+	URL      string
+	Username string
+	Notes    string
+	Tags     []string
+	// Category is not searched but can be filtered on
+	Category string
+}
+
+type entry struct {
+	// Terms of the document, to remove them from the postings when it changes
+	terms    []string
+	title    string
+	category string
+}
+
+// Hit is an item matching a query
+type Hit struct {
+	ID       string
+	Category string
+	Score    float64
+}
+
+// Index maps the terms of documents to the IDs of the items they belong to. It is safe for concurrent use.
+type Index struct {
+	mu sync.RWMutex
+	// term -> item ID -> weight of the most important field the term appears in
+	postings map[string]map[string]float64
+	entries  map[string]*entry
+	// Sorted vocabulary for prefix and fuzzy queries, rebuilt on the next query after a change (nil when stale)
+	vocabulary []string
+}
+
+func NewIndex() *Index {
+	return &Index{
+		postings: make(map[string]map[string]float64),
+		entries:  make(map[string]*entry),
+	}
+}
+
+// Len returns the number of indexed items
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.entries)
+}
+
+// Add indexes a document, replacing the previous document of the item
+func (idx *Index) Add(id string, doc *Document) {
+	weights := make(map[string]float64)
+	addTerms := func(text string, weight float64, stopwords []string) {
+		for _, term := range Tokenize(text) {
+			if !slices.Contains(stopwords, term) && weights[term] < weight {
+				weights[term] = weight
+			}
+		}
+	}
+	addTerms(doc.Title, weightTitle, nil)
+	addTerms(strings.Join(doc.Tags, " "), weightTags, nil)
+	addTerms(doc.Username, weightUsername, nil)
+	addTerms(doc.URL, weightURL, urlStopwords)
+	addTerms(doc.Notes, weightNotes, nil)
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.remove(id)
+	e := &entry{terms: make([]string, 0, len(weights)), title: strings.ToLower(doc.Title), category: doc.Category}
+	for term, weight := range weights {
+		if idx.postings[term] == nil {
+			idx.postings[term] = make(map[string]float64)
+		}
+		idx.postings[term][id] = weight
+		e.terms = append(e.terms, term)
+	}
+	idx.entries[id] = e
+	idx.vocabulary = nil
+}
+
+// Remove drops an item from the index
+func (idx *Index) Remove(id string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.remove(id)
+}
+
+func (idx *Index) remove(id string) {
+	e, ok := idx.entries[id]
+	if !ok {
+		return
+	}
+	for _, term := range e.terms {
+		delete(idx.postings[term], id)
+		if len(idx.postings[term]) == 0 {
+			delete(idx.postings, term)
+		}
+	}
+	delete(idx.entries, id)
+	idx.vocabulary = nil
+}
+
+// Search returns the items matching every term of the query, best match first. Each term matches indexed terms
+// exactly, as a prefix, or with a typo or two for longer terms. An empty query matches every item, sorted by
+// title. include filters the items before they are ranked; nil includes every item.
+func (idx *Index) Search(query string, include func(id string, category string) bool) []*Hit {
+	queryTerms := Tokenize(query)
+	// Searching may rebuild the vocabulary, so it takes the write lock
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	if len(queryTerms) > 0 && idx.vocabulary == nil {
+		idx.buildVocabulary()
+	}
+
+	var scores map[string]float64
+	if len(queryTerms) == 0 {
+		scores = make(map[string]float64, len(idx.entries))
+		for id := range idx.entries {
+			scores[id] = 0
+		}
+	}
+	for i, queryTerm := range queryTerms {
+		termScores := idx.matchTerm(queryTerm)
+		if i == 0 {
+			scores = termScores
+			continue
+		}
+		// Items must match every term
+		for id, score := range scores {
+			if termScore, ok := termScores[id]; ok {
+				scores[id] = score + termScore
+			} else {
+				delete(scores, id)
+			}
+		}
+	}
+
+	hits := make([]*Hit, 0, len(scores))
+	for id, score := range scores {
+		e := idx.entries[id]
+		if include == nil || include(id, e.category) {
+			hits = append(hits, &Hit{ID: id, Category: e.category, Score: score})
+		}
+	}
+	slices.SortFunc(hits, func(x, y *Hit) int {
+		if c := cmp.Compare(y.Score, x.Score); c != 0 {
+			return c
+		}
+		if c := strings.Compare(idx.entries[x.ID].title, idx.entries[y.ID].title); c != 0 {
+			return c
+		}
+		return strings.Compare(x.ID, y.ID)
+	})
+	return hits
+}
+
+// matchTerm scores every item containing a term which matches the query term, keeping the best match per item
+func (idx *Index) matchTerm(queryTerm string) map[string]float64 {
+	scores := make(map[string]float64)
+	addPostings := func(term string, boost float64) {
+		for id, weight := range idx.postings[term] {
+			if score := weight * boost; score > scores[id] {
+				scores[id] = score
+			}
+		}
+	}
+	// Terms with the query term as a prefix are adjacent in the sorted vocabulary, starting with the exact term
+	start, _ := slices.BinarySearch(idx.vocabulary, queryTerm)
+	for _, term := range idx.vocabulary[start:] {
+		if !strings.HasPrefix(term, queryTerm) {
+			break
+		}
+		if term == queryTerm {
+			addPostings(term, boostExact)
+		} else {
+			addPostings(term, boostPrefix)
+		}
+	}
+	if maxEdits := maxEdits(queryTerm); maxEdits > 0 {
+		for _, term := range idx.vocabulary {
+			if strings.HasPrefix(term, queryTerm) {
+				continue
+			}
+			if distance := editDistance(queryTerm, term, maxEdits); distance <= maxEdits {
+				addPostings(term, boostFuzzy/float64(distance))
+			}
+		}
+	}
+	return scores
+}
+
+func (idx *Index) buildVocabulary() {
+	idx.vocabulary = make([]string, 0, len(idx.postings))
+	for term := range idx.postings {
+		idx.vocabulary = append(idx.vocabulary, term)
+	}
+	slices.Sort(idx.vocabulary)
+}
+
+// Tokenize splits text into lowercase terms of letters and digits
+func Tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// maxEdits is the number of typos allowed in a query term. Short terms must match exactly or as a prefix, as a
+// single edit would match most short terms.
+func maxEdits(term string) int {
+	switch n := len([]rune(term)); {
+	case n < 4:
+		return 0
+	case n < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// editDistance returns the Levenshtein distance between a and b, or limit+1 if it is larger than limit
+func editDistance(a string, b string, limit int) int {
+	ra, rb := []rune(a), []rune(b)
+	if diff := len(ra) - len(rb); diff > limit || -diff > limit {
+		return limit + 1
+	}
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			rowMin = min(rowMin, curr[j])
+		}
+		if rowMin > limit {
+			return limit + 1
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,105 @@
+package search
+
+import (
+	"slices"
+	"testing"
+)
+
+func testIndex() *Index {
+	idx := NewIndex()
+	idx.Add("github", &Document{Title: "GitHub", URL: "https://github.com/login", Username: "octocat", Category: "login"})
+	idx.Add("gitlab", &Document{Title: "GitLab", URL: "https://gitlab.com", Username: "tanuki", Category: "login"})
+	idx.Add("bank", &Document{Title: "First National Bank", URL: "https://www.fnb.example", Username: "jdoe", Category: "login", Tags: []string{"finance"}})
+	idx.Add("wifi", &Document{Title: "Home Wi-Fi", Notes: "Router admin is at 192.168.1.1, guest network is octopus", Category: "secure_note"})
+	idx.Add("card", &Document{Title: "Visa", Notes: "Backup card for the bank", Category: "credit_card", Tags: []string{"finance", "travel"}})
+	return idx
+}
+
+func hitIDs(hits []*Hit) []string {
+	ids := make([]string, 0, len(hits))
+	for _, hit := range hits {
+		ids = append(ids, hit.ID)
+	}
+	return ids
+}
+
+func TestSearch(t *testing.T) {
+	tests := []struct {
+		query string
+		ids   []string
+	}{
+		// Prefixes match, ranked by title
+		{"git", []string{"github", "gitlab"}},
+		{"github", []string{"github"}},
+		// Every term must match
+		{"git octo", []string{"github"}},
+		// Typos are allowed in longer terms
+		{"gitbub", []string{"github"}},
+		{"natoinal", []string{"bank"}},
+		// A title match ranks above a note match
+		{"bank", []string{"bank", "card"}},
+		{"finance", []string{"bank", "card"}},
+		// Usernames rank above notes
+		{"octo", []string{"github", "wifi"}},
+		{"192", []string{"wifi"}},
+		// URL boilerplate is not indexed
+		{"www", []string{}},
+		{"nothing", []string{}},
+		// An empty query lists everything by title
+		{"", []string{"bank", "github", "gitlab", "wifi", "card"}},
+	}
+	idx := testIndex()
+	for _, tt := range tests {
+		if ids := hitIDs(idx.Search(tt.query, nil)); !slices.Equal(ids, tt.ids) {
+			t.Fatalf("Search(%q) = %q, expected %q", tt.query, ids, tt.ids)
+		}
+	}
+}
+
+func TestSearchFilter(t *testing.T) {
+	idx := testIndex()
+	hits := idx.Search("finance", func(id string, category string) bool {
+		return category == "credit_card"
+	})
+	if ids := hitIDs(hits); !slices.Equal(ids, []string{"card"}) {
+		t.Fatalf("expected only the card, got %q", ids)
+	}
+}
+
+func TestIndexUpdate(t *testing.T) {
+	idx := testIndex()
+	idx.Add("github", &Document{Title: "GitHub Enterprise", Username: "monalisa"})
+	if ids := hitIDs(idx.Search("octocat", nil)); len(ids) != 0 {
+		t.Fatalf("expected the old username to be removed, got %q", ids)
+	}
+	if ids := hitIDs(idx.Search("enterprise", nil)); !slices.Equal(ids, []string{"github"}) {
+		t.Fatalf("expected the new title to be indexed, got %q", ids)
+	}
+	idx.Remove("github")
+	if ids := hitIDs(idx.Search("git", nil)); !slices.Equal(ids, []string{"gitlab"}) {
+		t.Fatalf("expected the removed item to be gone, got %q", ids)
+	}
+	if idx.Len() != 4 {
+		t.Fatalf("expected 4 indexed items, got %d", idx.Len())
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		limit    int
+		distance int
+	}{
+		{"github", "github", 1, 0},
+		{"gitbub", "github", 1, 1},
+		{"gthub", "github", 1, 1},
+		{"natoinal", "national", 2, 2},
+		{"github", "gitlab", 1, 2},
+		{"abc", "abcdef", 2, 3},
+	}
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b, tt.limit); got != tt.distance {
+			t.Fatalf("editDistance(%q, %q) = %d, expected %d", tt.a, tt.b, got, tt.distance)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package secretservice
+
+import (
+	"github.com/godbus/dbus/v5"
+)
+
+// collectionHandler implements org.freedesktop.Secret.Collection for the vault's collection
+type collectionHandler Server
+
+func (h *collectionHandler) Delete(msg dbus.Message) (dbus.ObjectPath, *dbus.Error) {
+	return noPrompt, errNotSupported
+}
+
+func (h *collectionHandler) SearchItems(msg dbus.Message, attributes map[string]string) ([]dbus.ObjectPath, *dbus.Error) {
+	if !isCollection(callPath(msg)) {
+		return nil, errNoSuchObject
+	}
+	return (*Server)(h).search(attributes)
+}
+
+// CreateItem stores a new item. With replace set, an existing item with exactly the same attributes is updated
+// instead.
+func (h *collectionHandler) CreateItem(msg dbus.Message, properties map[string]dbus.Variant, secret Secret, replace bool) (dbus.ObjectPath, dbus.ObjectPath, *dbus.Error) {
+	s := (*Server)(h)
+	if !isCollection(callPath(msg)) {
+		return noPrompt, noPrompt, errNoSuchObject
+	}
+	if s.backend.IsLocked() {
+		return noPrompt, noPrompt, errIsLocked
+	}
+	label, attributes, err := itemProperties(properties)
+	if err != nil {
+		return noPrompt, noPrompt, err
+	}
+	sess, err := s.session(secret.Session)
+	if err != nil {
+		return noPrompt, noPrompt, err
+	}
+	value, decodeErr := sess.decode(secret)
+	if decodeErr != nil {
+		return noPrompt, noPrompt, errInvalidArgs
+	}
+	if attributes == nil {
+		attributes = make(map[string]string)
+	}
+
+	if replace {
+		items, backendErr := s.backend.Items()
+		if backendErr != nil {
+			return noPrompt, noPrompt, dbus.MakeFailedError(backendErr)
+		}
+		for _, item := range items {
+			if len(item.Attributes) != len(attributes) || !matchAttributes(item.Attributes, attributes) {
+				continue
+			}
+			if backendErr := s.backend.UpdateItem(item.ID, &label, attributes, value); backendErr != nil {
+				return noPrompt, noPrompt, dbus.MakeFailedError(backendErr)
+			}
+			s.conn.Emit(collectionPath, collectionInterface+".ItemChanged", itemPath(item.ID))
+			return itemPath(item.ID), noPrompt, nil
+		}
+	}
+	id, backendErr := s.backend.CreateItem(label, attributes, value)
+	if backendErr != nil {
+		return noPrompt, noPrompt, dbus.MakeFailedError(backendErr)
+	}
+	s.conn.Emit(collectionPath, collectionInterface+".ItemCreated", itemPath(id))
+	return itemPath(id), noPrompt, nil
+}
+
+// itemProperties reads the label and attributes from the properties passed to CreateItem
+func itemProperties(properties map[string]dbus.Variant) (string, map[string]string, *dbus.Error) {
+	var label string
+	var attributes map[string]string
+	if v, ok := properties[itemInterface+".Label"]; ok {
+		if label, ok = v.Value().(string); !ok {
+			return "", nil, errInvalidArgs
+		}
+	}
+	if v, ok := properties[itemInterface+".Attributes"]; ok {
+		if err := v.Store(&attributes); err != nil {
+			return "", nil, errInvalidArgs
+		}
+	}
+	return label, attributes, nil
+}
+
+// collectionProperties returns the properties of the collection
+func (s *Server) collectionProperties() (map[string]dbus.Variant, *dbus.Error) {
+	items, err := s.search(nil)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]dbus.Variant{
+		"Items":    dbus.MakeVariant(items),
+		"Label":    dbus.MakeVariant(s.backend.Label()),
+		"Locked":   dbus.MakeVariant(s.backend.IsLocked()),
+		"Created":  dbus.MakeVariant(uint64(0)),
+		"Modified": dbus.MakeVariant(uint64(0)),
+	}, nil
+}
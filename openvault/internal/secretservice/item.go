@@ -0,0 +1,65 @@
+package secretservice
+
+import (
+	"github.com/godbus/dbus/v5"
+)
+
+// itemHandler implements org.freedesktop.Secret.Item for every item in the collection
+type itemHandler Server
+
+func (h *itemHandler) Delete(msg dbus.Message) (dbus.ObjectPath, *dbus.Error) {
+	s := (*Server)(h)
+	item, err := s.item(callPath(msg))
+	if err != nil {
+		return noPrompt, err
+	}
+	if err := s.backend.DeleteItem(item.ID); err != nil {
+		return noPrompt, dbus.MakeFailedError(err)
+	}
+	s.conn.Emit(collectionPath, collectionInterface+".ItemDeleted", itemPath(item.ID))
+	return noPrompt, nil
+}
+
+func (h *itemHandler) GetSecret(msg dbus.Message, sessionPath dbus.ObjectPath) (Secret, *dbus.Error) {
+	return (*Server)(h).getSecret(callPath(msg), sessionPath)
+}
+
+func (h *itemHandler) SetSecret(msg dbus.Message, secret Secret) *dbus.Error {
+	s := (*Server)(h)
+	item, err := s.item(callPath(msg))
+	if err != nil {
+		return err
+	}
+	sess, err := s.session(secret.Session)
+	if err != nil {
+		return err
+	}
+	value, decodeErr := sess.decode(secret)
+	if decodeErr != nil {
+		return errInvalidArgs
+	}
+	if err := s.backend.UpdateItem(item.ID, nil, nil, value); err != nil {
+		return dbus.MakeFailedError(err)
+	}
+	s.conn.Emit(collectionPath, collectionInterface+".ItemChanged", itemPath(item.ID))
+	return nil
+}
+
+// itemProperties returns the properties of an item
+func (s *Server) itemProperties(path dbus.ObjectPath) (map[string]dbus.Variant, *dbus.Error) {
+	item, err := s.item(path)
+	if err != nil {
+		return nil, err
+	}
+	attributes := item.Attributes
+	if attributes == nil {
+		attributes = make(map[string]string)
+	}
+	return map[string]dbus.Variant{
+		"Locked":     dbus.MakeVariant(false),
+		"Attributes": dbus.MakeVariant(attributes),
+		"Label":      dbus.MakeVariant(item.Label),
+		"Created":    dbus.MakeVariant(uint64(item.Created.Unix())),
+		"Modified":   dbus.MakeVariant(uint64(item.Modified.Unix())),
+	}, nil
+}
@@ -0,0 +1,74 @@
+package secretservice
+
+import (
+	"github.com/godbus/dbus/v5"
+)
+
+// propertiesHandler implements org.freedesktop.DBus.Properties for the service, collection and item objects
+type propertiesHandler Server
+
+func (h *propertiesHandler) GetAll(msg dbus.Message, iface string) (map[string]dbus.Variant, *dbus.Error) {
+	s := (*Server)(h)
+	path := callPath(msg)
+	switch {
+	case path == servicePath && iface == serviceInterface:
+		return map[string]dbus.Variant{
+			"Collections": dbus.MakeVariant([]dbus.ObjectPath{collectionPath}),
+		}, nil
+	case isCollection(path) && iface == collectionInterface:
+		return s.collectionProperties()
+	case iface == itemInterface:
+		return s.itemProperties(path)
+	default:
+		return map[string]dbus.Variant{}, nil
+	}
+}
+
+func (h *propertiesHandler) Get(msg dbus.Message, iface string, name string) (dbus.Variant, *dbus.Error) {
+	props, err := h.GetAll(msg, iface)
+	if err != nil {
+		return dbus.Variant{}, err
+	}
+	value, ok := props[name]
+	if !ok {
+		return dbus.Variant{}, dbus.NewError("org.freedesktop.DBus.Error.UnknownProperty", []interface{}{name})
+	}
+	return value, nil
+}
+
+// Set changes the label or attributes of an item. Collection properties are read-only.
+func (h *propertiesHandler) Set(msg dbus.Message, iface string, name string, value dbus.Variant) *dbus.Error {
+	s := (*Server)(h)
+	if iface != itemInterface {
+		return dbus.NewError("org.freedesktop.DBus.Error.PropertyReadOnly", []interface{}{name})
+	}
+	item, err := s.item(callPath(msg))
+	if err != nil {
+		return err
+	}
+	var backendErr error
+	switch name {
+	case "Label":
+		label, ok := value.Value().(string)
+		if !ok {
+			return errInvalidArgs
+		}
+		backendErr = s.backend.UpdateItem(item.ID, &label, nil, nil)
+	case "Attributes":
+		var attributes map[string]string
+		if err := value.Store(&attributes); err != nil {
+			return errInvalidArgs
+		}
+		if attributes == nil {
+			attributes = make(map[string]string)
+		}
+		backendErr = s.backend.UpdateItem(item.ID, nil, attributes, nil)
+	default:
+		return dbus.NewError("org.freedesktop.DBus.Error.PropertyReadOnly", []interface{}{name})
+	}
+	if backendErr != nil {
+		return dbus.MakeFailedError(backendErr)
+	}
+	s.conn.Emit(collectionPath, collectionInterface+".ItemChanged", itemPath(item.ID))
+	return nil
+}
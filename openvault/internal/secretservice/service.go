@@ -0,0 +1,338 @@
+// Package secretservice implements the freedesktop.org Secret Service D-Bus API
+// (https://specifications.freedesktop.org/secret-service-spec/latest/) on top of a single vault, so applications
+// using libsecret can store their secrets in OpenVault.
+package secretservice
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	BusName = "org.freedesktop.secrets"
+
+	servicePath    dbus.ObjectPath = "/org/freedesktop/secrets"
+	collectionPath dbus.ObjectPath = "/org/freedesktop/secrets/collection/openvault"
+	aliasPath      dbus.ObjectPath = "/org/freedesktop/secrets/aliases/default"
+	sessionPrefix                  = "/org/freedesktop/secrets/session/"
+	// The "no prompt" object path
+	noPrompt dbus.ObjectPath = "/"
+
+	serviceInterface    = "org.freedesktop.Secret.Service"
+	collectionInterface = "org.freedesktop.Secret.Collection"
+	itemInterface       = "org.freedesktop.Secret.Item"
+	sessionInterface    = "org.freedesktop.Secret.Session"
+	propertiesInterface = "org.freedesktop.DBus.Properties"
+)
+
+var (
+	errNoSuchObject = dbus.NewError("org.freedesktop.Secret.Error.NoSuchObject", []interface{}{"no such object"})
+	errIsLocked     = dbus.NewError("org.freedesktop.Secret.Error.IsLocked", []interface{}{"the vault is locked"})
+	errNoSession    = dbus.NewError("org.freedesktop.Secret.Error.NoSession", []interface{}{"no such session"})
+	errNotSupported = dbus.NewError("org.freedesktop.DBus.Error.NotSupported", []interface{}{"not supported"})
+	errInvalidArgs  = dbus.NewError("org.freedesktop.DBus.Error.InvalidArgs", []interface{}{"invalid arguments"})
+)
+
+var (
+	ErrNameTaken = errors.New("another secret service is already running")
+)
+
+// Item is a secret stored in the backing vault
+type Item struct {
+	ID         string
+	Label      string
+	Attributes map[string]string
+	Created    time.Time
+	Modified   time.Time
+}
+
+// Backend stores the items of the single collection exposed by the service
+type Backend interface {
+	// IsLocked returns whether the vault is locked. Items are neither listed nor readable while locked.
+	IsLocked() bool
+	// Lock locks the vault, failing if the user does not allow it. The owner of the server calls NotifyLockChanged
+	// whenever the lock state changes.
+	Lock() error
+	// Label returns the display name of the vault
+	Label() string
+	// Items returns every item in the vault. The items must not be modified.
+	Items() ([]*Item, error)
+	// Item returns an item, or nil if there is none with the ID
+	Item(id string) (*Item, error)
+	// Secret returns the secret of an item
+	Secret(id string) ([]byte, error)
+	// CreateItem stores a new item and returns its ID
+	CreateItem(label string, attributes map[string]string, secret []byte) (string, error)
+	// UpdateItem changes an item. Nil values are left unchanged.
+	UpdateItem(id string, label *string, attributes map[string]string, secret []byte) error
+	// DeleteItem removes an item
+	DeleteItem(id string) error
+}
+
+// Server exports the Secret Service API for a backend on a D-Bus connection
+type Server struct {
+	conn    *dbus.Conn
+	backend Backend
+
+	mu          sync.Mutex
+	sessions    map[dbus.ObjectPath]*session
+	nextSession int
+}
+
+// Serve claims the org.freedesktop.secrets name on the connection and serves the backend until Close is called.
+// It returns ErrNameTaken if another provider (e.g. gnome-keyring) already owns the name.
+func Serve(conn *dbus.Conn, backend Backend) (*Server, error) {
+	s := &Server{
+		conn:     conn,
+		backend:  backend,
+		sessions: make(map[dbus.ObjectPath]*session),
+	}
+	// Every interface is exported for the whole tree and dispatches on the object path of the call
+	exports := map[string]interface{}{
+		serviceInterface:    (*serviceHandler)(s),
+		collectionInterface: (*collectionHandler)(s),
+		itemInterface:       (*itemHandler)(s),
+		sessionInterface:    (*sessionHandler)(s),
+		propertiesInterface: (*propertiesHandler)(s),
+	}
+	for iface, handler := range exports {
+		if err := conn.ExportSubtree(handler, servicePath, iface); err != nil {
+			s.unexport()
+			return nil, fmt.Errorf("failed to export %s: %w", iface, err)
+		}
+	}
+	reply, err := conn.RequestName(BusName, dbus.NameFlagDoNotQueue)
+	if err != nil {
+		s.unexport()
+		return nil, fmt.Errorf("failed to request bus name: %w", err)
+	}
+	if reply != dbus.RequestNameReplyPrimaryOwner {
+		s.unexport()
+		return nil, ErrNameTaken
+	}
+	return s, nil
+}
+
+// Close releases the bus name and stops serving. The connection is left open.
+func (s *Server) Close() error {
+	s.unexport()
+	s.mu.Lock()
+	for _, sess := range s.sessions {
+		clear(sess.key)
+	}
+	s.sessions = make(map[dbus.ObjectPath]*session)
+	s.mu.Unlock()
+	_, err := s.conn.ReleaseName(BusName)
+	return err
+}
+
+func (s *Server) unexport() {
+	for _, iface := range []string{serviceInterface, collectionInterface, itemInterface, sessionInterface, propertiesInterface} {
+		s.conn.ExportSubtree(nil, servicePath, iface)
+	}
+}
+
+// NotifyLockChanged emits a property change for the collection's Locked property. The new state is passed in
+// rather than read from the backend, as the owner calls it while locking or unlocking.
+func (s *Server) NotifyLockChanged(locked bool) {
+	s.conn.Emit(collectionPath, propertiesInterface+".PropertiesChanged", collectionInterface,
+		map[string]dbus.Variant{"Locked": dbus.MakeVariant(locked)}, []string{})
+}
+
+// isCollection returns whether path refers to the collection, either directly or through the default alias
+func isCollection(path dbus.ObjectPath) bool {
+	return path == collectionPath || path == aliasPath
+}
+
+func itemPath(id string) dbus.ObjectPath {
+	return collectionPath + "/" + dbus.ObjectPath(strings.ReplaceAll(id, "-", "_"))
+}
+
+// itemID returns the item ID for an item object path
+func itemID(path dbus.ObjectPath) (string, bool) {
+	id, ok := strings.CutPrefix(string(path), string(collectionPath)+"/")
+	if !ok || id == "" || strings.Contains(id, "/") {
+		return "", false
+	}
+	return strings.ReplaceAll(id, "_", "-"), true
+}
+
+// item looks up an item by its object path
+func (s *Server) item(path dbus.ObjectPath) (*Item, *dbus.Error) {
+	id, ok := itemID(path)
+	if !ok {
+		return nil, errNoSuchObject
+	}
+	if s.backend.IsLocked() {
+		return nil, errIsLocked
+	}
+	item, err := s.backend.Item(id)
+	if err != nil {
+		return nil, dbus.MakeFailedError(err)
+	}
+	if item == nil {
+		return nil, errNoSuchObject
+	}
+	return item, nil
+}
+
+// search returns the paths of the items whose attributes include all the given attributes
+func (s *Server) search(attributes map[string]string) ([]dbus.ObjectPath, *dbus.Error) {
+	paths := make([]dbus.ObjectPath, 0)
+	if s.backend.IsLocked() {
+		return paths, nil
+	}
+	items, err := s.backend.Items()
+	if err != nil {
+		return nil, dbus.MakeFailedError(err)
+	}
+	for _, item := range items {
+		if matchAttributes(item.Attributes, attributes) {
+			paths = append(paths, itemPath(item.ID))
+		}
+	}
+	return paths, nil
+}
+
+func matchAttributes(itemAttributes map[string]string, query map[string]string) bool {
+	for key, value := range query {
+		if v, ok := itemAttributes[key]; !ok || v != value {
+			return false
+		}
+	}
+	return true
+}
+
+// serviceHandler implements org.freedesktop.Secret.Service
+type serviceHandler Server
+
+func (h *serviceHandler) OpenSession(msg dbus.Message, algorithm string, input dbus.Variant) (dbus.Variant, dbus.ObjectPath, *dbus.Error) {
+	s := (*Server)(h)
+	sess, output, err := newSession(algorithm, input)
+	if err != nil {
+		return dbus.MakeVariant(""), noPrompt, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextSession++
+	path := dbus.ObjectPath(fmt.Sprintf("%s%d", sessionPrefix, s.nextSession))
+	s.sessions[path] = sess
+	return output, path, nil
+}
+
+func (h *serviceHandler) CreateCollection(msg dbus.Message, properties map[string]dbus.Variant, alias string) (dbus.ObjectPath, dbus.ObjectPath, *dbus.Error) {
+	// Only the vault's collection exists; asking for the default collection returns it
+	if alias == "default" {
+		return collectionPath, noPrompt, nil
+	}
+	return noPrompt, noPrompt, errNotSupported
+}
+
+func (h *serviceHandler) SearchItems(msg dbus.Message, attributes map[string]string) ([]dbus.ObjectPath, []dbus.ObjectPath, *dbus.Error) {
+	unlocked, err := (*Server)(h).search(attributes)
+	return unlocked, []dbus.ObjectPath{}, err
+}
+
+// Unlock reports which objects are unlocked. Vaults can only be unlocked from the desktop app, so locked objects
+// are left locked without a prompt.
+func (h *serviceHandler) Unlock(msg dbus.Message, objects []dbus.ObjectPath) ([]dbus.ObjectPath, dbus.ObjectPath, *dbus.Error) {
+	if (*Server)(h).backend.IsLocked() {
+		return []dbus.ObjectPath{}, noPrompt, nil
+	}
+	return objects, noPrompt, nil
+}
+
+func (h *serviceHandler) Lock(msg dbus.Message, objects []dbus.ObjectPath) ([]dbus.ObjectPath, dbus.ObjectPath, *dbus.Error) {
+	s := (*Server)(h)
+	if err := s.backend.Lock(); err != nil {
+		return nil, noPrompt, dbus.MakeFailedError(err)
+	}
+	return objects, noPrompt, nil
+}
+
+func (h *serviceHandler) GetSecrets(msg dbus.Message, items []dbus.ObjectPath, sessionPath dbus.ObjectPath) (map[dbus.ObjectPath]Secret, *dbus.Error) {
+	s := (*Server)(h)
+	secrets := make(map[dbus.ObjectPath]Secret)
+	for _, path := range items {
+		secret, err := s.getSecret(path, sessionPath)
+		if err == errNoSuchObject {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		secrets[path] = secret
+	}
+	return secrets, nil
+}
+
+func (h *serviceHandler) ReadAlias(msg dbus.Message, name string) (dbus.ObjectPath, *dbus.Error) {
+	if name == "default" {
+		return collectionPath, nil
+	}
+	return noPrompt, nil
+}
+
+func (h *serviceHandler) SetAlias(msg dbus.Message, name string, collection dbus.ObjectPath) *dbus.Error {
+	return errNotSupported
+}
+
+// getSecret returns the secret of an item encoded for the session
+func (s *Server) getSecret(path dbus.ObjectPath, sessionPath dbus.ObjectPath) (Secret, *dbus.Error) {
+	sess, err := s.session(sessionPath)
+	if err != nil {
+		return Secret{}, err
+	}
+	item, err := s.item(path)
+	if err != nil {
+		return Secret{}, err
+	}
+	value, backendErr := s.backend.Secret(item.ID)
+	if backendErr != nil {
+		return Secret{}, dbus.MakeFailedError(backendErr)
+	}
+	secret, encErr := sess.encode(value)
+	if encErr != nil {
+		return Secret{}, dbus.MakeFailedError(encErr)
+	}
+	secret.Session = sessionPath
+	return secret, nil
+}
+
+func (s *Server) session(path dbus.ObjectPath) (*session, *dbus.Error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess, ok := s.sessions[path]
+	if !ok {
+		return nil, errNoSession
+	}
+	return sess, nil
+}
+
+// sessionHandler implements org.freedesktop.Secret.Session
+type sessionHandler Server
+
+func (h *sessionHandler) Close(msg dbus.Message) *dbus.Error {
+	s := (*Server)(h)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	path := callPath(msg)
+	sess, ok := s.sessions[path]
+	if !ok {
+		return errNoSession
+	}
+	clear(sess.key)
+	delete(s.sessions, path)
+	return nil
+}
+
+// callPath returns the object path a method was called on
+func callPath(msg dbus.Message) dbus.ObjectPath {
+	path, _ := msg.Headers[dbus.FieldPath].Value().(dbus.ObjectPath)
+	return path
+}
@@ -0,0 +1,334 @@
+package secretservice
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+	"math/big"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/godbus/dbus/v5"
+)
+
+type memoryItem struct {
+	Item
+	secret []byte
+}
+
+// memoryBackend is an in-memory Backend for tests
+type memoryBackend struct {
+	mu     sync.Mutex
+	locked bool
+	items  map[string]*memoryItem
+	nextID int
+}
+
+func (b *memoryBackend) IsLocked() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.locked
+}
+
+func (b *memoryBackend) Lock() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.locked = true
+	return nil
+}
+
+func (b *memoryBackend) Label() string { return "Test" }
+
+func (b *memoryBackend) Items() ([]*Item, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	items := make([]*Item, 0, len(b.items))
+	for _, item := range b.items {
+		i := item.Item
+		items = append(items, &i)
+	}
+	return items, nil
+}
+
+func (b *memoryBackend) Item(id string) (*Item, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	item, ok := b.items[id]
+	if !ok {
+		return nil, nil
+	}
+	i := item.Item
+	return &i, nil
+}
+
+func (b *memoryBackend) Secret(id string) ([]byte, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	item, ok := b.items[id]
+	if !ok {
+		return nil, fmt.Errorf("no item %s", id)
+	}
+	return item.secret, nil
+}
+
+func (b *memoryBackend) CreateItem(label string, attributes map[string]string, secret []byte) (string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.nextID++
+	id := fmt.Sprintf("item-%d", b.nextID)
+	b.items[id] = &memoryItem{
+		Item:   Item{ID: id, Label: label, Attributes: attributes, Created: time.Now(), Modified: time.Now()},
+		secret: secret,
+	}
+	return id, nil
+}
+
+func (b *memoryBackend) UpdateItem(id string, label *string, attributes map[string]string, secret []byte) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	item, ok := b.items[id]
+	if !ok {
+		return fmt.Errorf("no item %s", id)
+	}
+	if label != nil {
+		item.Label = *label
+	}
+	if attributes != nil {
+		item.Attributes = attributes
+	}
+	if secret != nil {
+		item.secret = secret
+	}
+	return nil
+}
+
+func (b *memoryBackend) DeleteItem(id string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.items, id)
+	return nil
+}
+
+// startBus starts a private session bus and returns a connection for the server and one for the client
+func startBus(t *testing.T) (*dbus.Conn, *dbus.Conn) {
+	daemon, err := exec.LookPath("dbus-daemon")
+	if err != nil {
+		t.Skip("dbus-daemon not found")
+	}
+	cmd := exec.Command(daemon, "--session", "--print-address", "--nofork")
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		t.Fatalf("failed to create pipe: %v", err)
+	}
+	if err := cmd.Start(); err != nil {
+		t.Fatalf("failed to start dbus-daemon: %v", err)
+	}
+	t.Cleanup(func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	})
+	address, err := bufio.NewReader(out).ReadString('\n')
+	if err != nil {
+		t.Fatalf("failed to read bus address: %v", err)
+	}
+	connect := func() *dbus.Conn {
+		conn, err := dbus.Connect(strings.TrimSpace(address))
+		if err != nil {
+			t.Fatalf("failed to connect to bus: %v", err)
+		}
+		t.Cleanup(func() { conn.Close() })
+		return conn
+	}
+	return connect(), connect()
+}
+
+func startServer(t *testing.T) (*memoryBackend, *Server, dbus.BusObject, *dbus.Conn) {
+	serverConn, clientConn := startBus(t)
+	backend := &memoryBackend{items: make(map[string]*memoryItem)}
+	server, err := Serve(serverConn, backend)
+	if err != nil {
+		t.Fatalf("failed to serve: %v", err)
+	}
+	t.Cleanup(func() { server.Close() })
+	return backend, server, clientConn.Object(BusName, servicePath), clientConn
+}
+
+// openSession opens a session with the algorithm and returns its path and a client-side session for encoding
+func openSession(t *testing.T, service dbus.BusObject, algorithm string) (dbus.ObjectPath, *session) {
+	if algorithm == algorithmPlain {
+		var output dbus.Variant
+		var path dbus.ObjectPath
+		if err := service.Call(serviceInterface+".OpenSession", 0, algorithm, dbus.MakeVariant("")).Store(&output, &path); err != nil {
+			t.Fatalf("failed to open plain session: %v", err)
+		}
+		return path, &session{}
+	}
+	private, err := rand.Int(rand.Reader, dhPrime)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	public := new(big.Int).Exp(dhGenerator, private, dhPrime).Bytes()
+	var output dbus.Variant
+	var path dbus.ObjectPath
+	if err := service.Call(serviceInterface+".OpenSession", 0, algorithm, dbus.MakeVariant(public)).Store(&output, &path); err != nil {
+		t.Fatalf("failed to open dh session: %v", err)
+	}
+	serverPublic, ok := output.Value().([]byte)
+	if !ok {
+		t.Fatalf("unexpected session output %v", output)
+	}
+	shared := new(big.Int).Exp(new(big.Int).SetBytes(serverPublic), private, dhPrime).FillBytes(make([]byte, dhPrimeBytes))
+	key, err := hkdf.Key(sha256.New, shared, nil, "", 16)
+	if err != nil {
+		t.Fatalf("failed to derive key: %v", err)
+	}
+	return path, &session{key: key}
+}
+
+func createItem(t *testing.T, collection dbus.BusObject, sessionPath dbus.ObjectPath, sess *session, label string, attributes map[string]string, value string, replace bool) dbus.ObjectPath {
+	secret, err := sess.encode([]byte(value))
+	if err != nil {
+		t.Fatalf("failed to encode secret: %v", err)
+	}
+	secret.Session = sessionPath
+	properties := map[string]dbus.Variant{
+		itemInterface + ".Label":      dbus.MakeVariant(label),
+		itemInterface + ".Attributes": dbus.MakeVariant(attributes),
+	}
+	var item, prompt dbus.ObjectPath
+	if err := collection.Call(collectionInterface+".CreateItem", 0, properties, secret, replace).Store(&item, &prompt); err != nil {
+		t.Fatalf("failed to create item: %v", err)
+	}
+	if prompt != noPrompt {
+		t.Fatalf("unexpected prompt %s", prompt)
+	}
+	return item
+}
+
+func TestSecretRoundTrip(t *testing.T) {
+	_, _, service, conn := startServer(t)
+	collection := conn.Object(BusName, aliasPath)
+
+	for _, algorithm := range []string{algorithmPlain, algorithmDH} {
+		sessionPath, sess := openSession(t, service, algorithm)
+		value := "secret for " + algorithm
+		attributes := map[string]string{"service": "test", "algorithm": algorithm}
+		path := createItem(t, collection, sessionPath, sess, algorithm, attributes, value, false)
+
+		var unlocked, locked []dbus.ObjectPath
+		if err := service.Call(serviceInterface+".SearchItems", 0, map[string]string{"algorithm": algorithm}).Store(&unlocked, &locked); err != nil {
+			t.Fatalf("%s: failed to search: %v", algorithm, err)
+		}
+		if len(unlocked) != 1 || unlocked[0] != path || len(locked) != 0 {
+			t.Fatalf("%s: SearchItems = %v, %v, want [%s]", algorithm, unlocked, locked, path)
+		}
+
+		var secret Secret
+		if err := conn.Object(BusName, path).Call(itemInterface+".GetSecret", 0, sessionPath).Store(&secret); err != nil {
+			t.Fatalf("%s: failed to get secret: %v", algorithm, err)
+		}
+		got, err := sess.decode(secret)
+		if err != nil {
+			t.Fatalf("%s: failed to decode secret: %v", algorithm, err)
+		}
+		if string(got) != value {
+			t.Fatalf("%s: secret = %q, want %q", algorithm, got, value)
+		}
+		if algorithm == algorithmDH && bytes.Contains(secret.Value, []byte(value)) {
+			t.Fatalf("%s: secret was sent in plaintext", algorithm)
+		}
+
+		label, err := conn.Object(BusName, path).GetProperty(itemInterface + ".Label")
+		if err != nil {
+			t.Fatalf("%s: failed to get label: %v", algorithm, err)
+		}
+		if label.Value() != algorithm {
+			t.Fatalf("%s: label = %v, want %q", algorithm, label.Value(), algorithm)
+		}
+	}
+}
+
+func TestCreateItemReplace(t *testing.T) {
+	backend, _, service, conn := startServer(t)
+	collection := conn.Object(BusName, collectionPath)
+	sessionPath, sess := openSession(t, service, algorithmPlain)
+	attributes := map[string]string{"service": "test", "user": "alice"}
+
+	first := createItem(t, collection, sessionPath, sess, "first", attributes, "one", true)
+	second := createItem(t, collection, sessionPath, sess, "second", attributes, "two", true)
+	if first != second {
+		t.Fatalf("replace created a new item %s, want %s", second, first)
+	}
+	// Without replace, or with different attributes, a new item is created
+	third := createItem(t, collection, sessionPath, sess, "third", attributes, "three", false)
+	fourth := createItem(t, collection, sessionPath, sess, "fourth", map[string]string{"service": "test"}, "four", true)
+	if third == first || fourth == first || fourth == third {
+		t.Fatalf("expected new items, got %s, %s for %s", third, fourth, first)
+	}
+	if len(backend.items) != 3 {
+		t.Fatalf("backend has %d items, want 3", len(backend.items))
+	}
+	id, _ := itemID(first)
+	if secret, _ := backend.Secret(id); string(secret) != "two" {
+		t.Fatalf("replaced secret = %q, want %q", secret, "two")
+	}
+}
+
+func TestLocked(t *testing.T) {
+	backend, _, service, conn := startServer(t)
+	sessionPath, sess := openSession(t, service, algorithmPlain)
+	path := createItem(t, conn.Object(BusName, collectionPath), sessionPath, sess, "item", map[string]string{"service": "test"}, "value", false)
+
+	var objects []dbus.ObjectPath
+	var prompt dbus.ObjectPath
+	if err := service.Call(serviceInterface+".Lock", 0, []dbus.ObjectPath{collectionPath}).Store(&objects, &prompt); err != nil {
+		t.Fatalf("failed to lock: %v", err)
+	}
+	if !backend.IsLocked() {
+		t.Fatalf("backend was not locked")
+	}
+
+	locked, err := conn.Object(BusName, collectionPath).GetProperty(collectionInterface + ".Locked")
+	if err != nil {
+		t.Fatalf("failed to get locked: %v", err)
+	}
+	if locked.Value() != true {
+		t.Fatalf("Locked = %v, want true", locked.Value())
+	}
+	var unlocked, stillLocked []dbus.ObjectPath
+	if err := service.Call(serviceInterface+".SearchItems", 0, map[string]string{}).Store(&unlocked, &stillLocked); err != nil {
+		t.Fatalf("failed to search: %v", err)
+	}
+	if len(unlocked) != 0 {
+		t.Fatalf("SearchItems returned %v while locked", unlocked)
+	}
+	var secret Secret
+	err = conn.Object(BusName, path).Call(itemInterface+".GetSecret", 0, sessionPath).Store(&secret)
+	if dbusErr, ok := err.(dbus.Error); !ok || dbusErr.Name != errIsLocked.Name {
+		t.Fatalf("GetSecret error = %v, want %s", err, errIsLocked.Name)
+	}
+	if err := service.Call(serviceInterface+".Unlock", 0, []dbus.ObjectPath{collectionPath}).Store(&objects, &prompt); err != nil {
+		t.Fatalf("failed to unlock: %v", err)
+	}
+	if len(objects) != 0 {
+		t.Fatalf("Unlock unlocked %v", objects)
+	}
+}
+
+func TestServeNameTaken(t *testing.T) {
+	serverConn, otherConn := startBus(t)
+	server, err := Serve(serverConn, &memoryBackend{items: make(map[string]*memoryItem)})
+	if err != nil {
+		t.Fatalf("failed to serve: %v", err)
+	}
+	defer server.Close()
+	if _, err := Serve(otherConn, &memoryBackend{}); err != ErrNameTaken {
+		t.Fatalf("second Serve error = %v, want ErrNameTaken", err)
+	}
+}
@@ -0,0 +1,120 @@
+package secretservice
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"math/big"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	algorithmPlain = "plain"
+	algorithmDH    = "dh-ietf1024-sha256-aes128-cbc-pkcs7"
+)
+
+// The 1024-bit MODP group from RFC 2409 section 6.2 used by the dh-ietf1024 algorithm
+var (
+	dhPrime, _     = new(big.Int).SetString("FFFFFFFFFFFFFFFFC90FDAA22168C234C4C6628B80DC1CD129024E088A67CC74020BBEA63B139B22514A08798E3404DDEF9519B3CD3A431B302B0A6DF25F14374FE1356D6D51C245E485B576625E7EC6F44C42E9A637ED6B0BFF5CB6F406B7EDEE386BFB5A899FA5AE9F24117C4B1FE649286651ECE65381FFFFFFFFFFFFFFFF", 16)
+	dhGenerator    = big.NewInt(2)
+	dhPrimeBytes   = 128
+	errBadPadding  = errors.New("invalid secret padding")
+	errBadSecretIV = errors.New("invalid secret parameters")
+)
+
+// Secret is the (oayays) secret structure of the Secret Service API
+type Secret struct {
+	Session     dbus.ObjectPath
+	Parameters  []byte
+	Value       []byte
+	ContentType string
+}
+
+// session is a negotiated transfer encoding for secrets. Plain sessions have no key.
+type session struct {
+	key []byte
+}
+
+// newSession negotiates a session for the algorithm, returning the output to send back to the client
+func newSession(algorithm string, input dbus.Variant) (*session, dbus.Variant, *dbus.Error) {
+	switch algorithm {
+	case algorithmPlain:
+		return &session{}, dbus.MakeVariant(""), nil
+	case algorithmDH:
+		clientPublic, ok := input.Value().([]byte)
+		if !ok || len(clientPublic) == 0 {
+			return nil, dbus.Variant{}, errInvalidArgs
+		}
+		private, err := rand.Int(rand.Reader, dhPrime)
+		if err != nil {
+			return nil, dbus.Variant{}, dbus.MakeFailedError(err)
+		}
+		public := new(big.Int).Exp(dhGenerator, private, dhPrime)
+		y := new(big.Int).SetBytes(clientPublic)
+		if y.Cmp(big.NewInt(1)) <= 0 || y.Cmp(new(big.Int).Sub(dhPrime, big.NewInt(1))) >= 0 {
+			return nil, dbus.Variant{}, errInvalidArgs
+		}
+		shared := new(big.Int).Exp(y, private, dhPrime).FillBytes(make([]byte, dhPrimeBytes))
+		key, err := hkdf.Key(sha256.New, shared, nil, "", 16)
+		clear(shared)
+		if err != nil {
+			return nil, dbus.Variant{}, dbus.MakeFailedError(err)
+		}
+		return &session{key: key}, dbus.MakeVariant(public.FillBytes(make([]byte, dhPrimeBytes))), nil
+	default:
+		return nil, dbus.Variant{}, errNotSupported
+	}
+}
+
+// encode prepares a secret value for transfer to the client
+func (s *session) encode(value []byte) (Secret, error) {
+	secret := Secret{ContentType: "text/plain"}
+	if s.key == nil {
+		secret.Value = value
+		return secret, nil
+	}
+	block, err := aes.NewCipher(s.key)
+	if err != nil {
+		return secret, err
+	}
+	iv := make([]byte, aes.BlockSize)
+	if _, err := rand.Read(iv); err != nil {
+		return secret, err
+	}
+	padding := aes.BlockSize - len(value)%aes.BlockSize
+	plaintext := append(append([]byte{}, value...), bytes.Repeat([]byte{byte(padding)}, padding)...)
+	secret.Parameters = iv
+	secret.Value = make([]byte, len(plaintext))
+	cipher.NewCBCEncrypter(block, iv).CryptBlocks(secret.Value, plaintext)
+	clear(plaintext)
+	return secret, nil
+}
+
+// decode returns the value of a secret sent by the client
+func (s *session) decode(secret Secret) ([]byte, error) {
+	if s.key == nil {
+		return secret.Value, nil
+	}
+	if len(secret.Parameters) != aes.BlockSize {
+		return nil, errBadSecretIV
+	}
+	if len(secret.Value) == 0 || len(secret.Value)%aes.BlockSize != 0 {
+		return nil, errBadPadding
+	}
+	block, err := aes.NewCipher(s.key)
+	if err != nil {
+		return nil, err
+	}
+	plaintext := make([]byte, len(secret.Value))
+	cipher.NewCBCDecrypter(block, secret.Parameters).CryptBlocks(plaintext, secret.Value)
+	padding := int(plaintext[len(plaintext)-1])
+	if padding == 0 || padding > aes.BlockSize || !bytes.Equal(plaintext[len(plaintext)-padding:], bytes.Repeat([]byte{byte(padding)}, padding)) {
+		return nil, errBadPadding
+	}
+	return plaintext[:len(plaintext)-padding], nil
+}
@@ -0,0 +1,43 @@
+package strength
+
+// commonPasswords are passwords and words seen most often in password leaks, roughly most common first. Only the
+// order matters: a word's rank is its estimated number of guesses.
+var commonPasswords = []string{
+	"123456", "password", "123456789", "12345678", "12345", "qwerty", "1234567", "111111", "1234567890", "123123",
+	"abc123", "1234", "password1", "iloveyou", "1q2w3e4r", "000000", "qwerty123", "zaq12wsx", "dragon", "sunshine",
+	"princess", "letmein", "654321", "monkey", "27653", "1qaz2wsx", "123321", "qwertyuiop", "superman", "asdfghjkl",
+	"trustno1", "football", "baseball", "welcome", "admin", "login", "master", "hello", "freedom", "whatever",
+	"qazwsx", "shadow", "michael", "jennifer", "hunter", "hunter2", "jordan", "harley", "ranger", "buster",
+	"soccer", "hockey", "killer", "george", "charlie", "andrew", "michelle", "love", "jessica", "pepper",
+	"daniel", "access", "joshua", "maggie", "starwars", "silver", "william", "dallas", "yankees", "orange",
+	"matrix", "thomas", "robert", "summer", "ashley", "nicole", "chelsea", "biteme", "matthew", "computer",
+	"amanda", "secret", "bailey", "cookie", "flower", "samsung", "mustang", "cheese", "purple", "taylor",
+	"batman", "tigger", "pokemon", "chocolate", "internet", "google", "facebook", "winter", "spring", "autumn",
+	"password123", "passw0rd", "p@ssw0rd", "admin123", "root", "toor", "changeme", "default", "guest", "test",
+	"test123", "qwe123", "asd123", "zxcvbnm", "asdf", "qwer", "1q2w3e", "1qaz", "q1w2e3r4", "a1b2c3",
+	"abcd1234", "aa123456", "112233", "121212", "131313", "159753", "666666", "696969", "777777", "888888",
+	"987654321", "11111111", "123qwe", "qwertyu", "asdfgh", "zxcvbn", "mypass", "mypassword", "letmein1", "welcome1",
+	"iloveu", "lovely", "loveme", "babygirl", "angel", "jesus", "blessed", "heaven", "lucky", "happy",
+	"smile", "money", "banana", "apple", "peanut", "butterfly", "dolphin", "tiger", "lion", "eagle",
+	"dragonfly", "phoenix", "wizard", "ninja", "pirate", "knight", "prince", "queen", "king", "star",
+	"sunflower", "rainbow", "diamond", "golden", "blue", "red", "green", "black", "white", "yellow",
+	"family", "friends", "forever", "mother", "father", "sister", "brother", "baby", "daddy", "mommy",
+	"jasmine", "jordan23", "michael1", "superstar", "rockstar", "player", "gamer", "minecraft", "fortnite", "roblox",
+	"liverpool", "arsenal", "barcelona", "realmadrid", "juventus", "chelsea1", "manchester", "lakers", "cowboys", "steelers",
+	"marvel", "spiderman", "ironman", "avengers", "pikachu", "naruto", "sasuke", "goku", "zelda", "mario",
+	"hello123", "hello1", "welcome123", "abc", "abcdef", "abcdefg", "qwertz", "azerty", "asdfasdf", "qweasd",
+	"january", "february", "march", "april", "may", "june", "july", "august", "september", "october",
+	"november", "december", "monday", "friday", "sunday", "london", "paris", "newyork", "america", "canada",
+	"secret1", "security", "private", "office", "company", "server", "system", "network", "windows", "linux",
+	"oracle", "cisco", "router", "wifi", "email", "account", "user", "username", "pass", "passwd",
+}
+
+var commonRanks = func() map[string]int {
+	ranks := make(map[string]int, len(commonPasswords))
+	for i, word := range commonPasswords {
+		if _, ok := ranks[word]; !ok {
+			ranks[word] = i + 1
+		}
+	}
+	return ranks
+}()
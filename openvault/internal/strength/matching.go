@@ -0,0 +1,505 @@
+package strength
+
+import (
+	"math"
+	"math/big"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const (
+	patternDictionary = "dictionary"
+	patternSpatial    = "spatial"
+	patternRepeat     = "repeat"
+	patternSequence   = "sequence"
+	patternDate       = "date"
+	patternBruteforce = "bruteforce"
+)
+
+// Minimum guesses of a match, so short patterns are not cheaper than brute forcing them
+const (
+	minGuessesSingleChar = 10
+	minGuessesMultiChar  = 50
+)
+
+// Years are guessed outward from the current year, and no closer than minYearSpace years
+const minYearSpace = 20
+
+var referenceYear = time.Now().Year()
+
+// match is a pattern found in runes[i:j+1] of the password
+type match struct {
+	i, j         int
+	pattern      string
+	guessesLog10 float64
+	// Dictionary matches
+	rank      int
+	userInput bool
+	l33t      bool
+	reversed  bool
+}
+
+func (m *match) length() int {
+	return m.j - m.i + 1
+}
+
+// newMatch returns a match, raising its guesses to the minimum for its length
+func newMatch(i, j int, pattern string, guesses float64) *match {
+	minGuesses := float64(minGuessesMultiChar)
+	if i == j {
+		minGuesses = minGuessesSingleChar
+	}
+	return &match{i: i, j: j, pattern: pattern, guessesLog10: math.Log10(max(guesses, minGuesses))}
+}
+
+// bruteforceMatch guesses every character of runes[i:j+1] from ten possibilities, as zxcvbn does. Real attacks on
+// random passwords need more guesses than this; the low estimate keeps patterns from being undervalued.
+func bruteforceMatch(runes []rune, i, j int) *match {
+	length := j - i + 1
+	m := &match{i: i, j: j, pattern: patternBruteforce, guessesLog10: float64(length)}
+	minGuesses := minGuessesMultiChar + 1
+	if length == 1 {
+		minGuesses = minGuessesSingleChar + 1
+	}
+	m.guessesLog10 = max(m.guessesLog10, math.Log10(float64(minGuesses)))
+	return m
+}
+
+func omnimatch(runes []rune, dicts *dictionaries) []*match {
+	var matches []*match
+	matches = append(matches, dictionaryMatches(runes, dicts)...)
+	matches = append(matches, spatialMatches(runes)...)
+	matches = append(matches, repeatMatches(runes, dicts)...)
+	matches = append(matches, sequenceMatches(runes)...)
+	matches = append(matches, dateMatches(runes)...)
+	return matches
+}
+
+// dictionaries rank words from most to least common, starting at 1
+type dictionaries struct {
+	ranks      map[string]int
+	userInputs map[string]int
+}
+
+func newDictionaries(userInputs []string) *dictionaries {
+	dicts := &dictionaries{ranks: commonRanks, userInputs: make(map[string]int)}
+	rank := 1
+	for _, input := range userInputs {
+		// Inputs are split into words, so a title like "My Bank" adds "my", "bank" and "mybank"
+		words := strings.FieldsFunc(strings.ToLower(input), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		for _, word := range append(words, strings.Join(words, "")) {
+			if len([]rune(word)) < 3 {
+				continue
+			}
+			if _, ok := dicts.userInputs[word]; !ok {
+				dicts.userInputs[word] = rank
+				rank++
+			}
+		}
+	}
+	return dicts
+}
+
+func (d *dictionaries) lookup(word string) (rank int, userInput bool, ok bool) {
+	if rank, ok := d.userInputs[word]; ok {
+		return rank, true, true
+	}
+	rank, ok = d.ranks[word]
+	return rank, false, ok
+}
+
+// l33tTables undo common character substitutions. Characters which stand for several letters get a table each.
+var l33tTables = []map[rune]rune{
+	{'4': 'a', '@': 'a', '8': 'b', '(': 'c', '3': 'e', '6': 'g', '1': 'i', '!': 'i', '|': 'l', '0': 'o', '$': 's', '5': 's', '7': 't', '+': 't', '2': 'z'},
+	{'1': 'l', '|': 'i', '9': 'g'},
+}
+
+func dictionaryMatches(runes []rune, dicts *dictionaries) []*match {
+	var matches []*match
+	lower := []rune(strings.ToLower(string(runes)))
+	// lower may differ in length from runes for a few special cases, in which case only the exact case is tried
+	if len(lower) != len(runes) {
+		lower = runes
+	}
+	add := func(word []rune, i, j int, l33t, reversed bool) {
+		rank, userInput, ok := dicts.lookup(string(word))
+		if !ok {
+			return
+		}
+		original := runes[i : j+1]
+		guesses := float64(rank) * uppercaseVariations(original)
+		if l33t {
+			guesses *= l33tVariations(original, word)
+		}
+		if reversed {
+			guesses *= 2
+		}
+		m := newMatch(i, j, patternDictionary, guesses)
+		m.rank, m.userInput, m.l33t, m.reversed = rank, userInput, l33t, reversed
+		matches = append(matches, m)
+	}
+	n := len(runes)
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			add(lower[i:j+1], i, j, false, false)
+			if j-i >= 2 {
+				reversed := slices.Clone(lower[i : j+1])
+				slices.Reverse(reversed)
+				add(reversed, i, j, false, true)
+			}
+		}
+	}
+	for _, table := range l33tTables {
+		unl33ted := slices.Clone(lower)
+		changed := false
+		for k, r := range unl33ted {
+			if letter, ok := table[r]; ok {
+				unl33ted[k] = letter
+				changed = true
+			}
+		}
+		if !changed {
+			continue
+		}
+		for i := 0; i < n; i++ {
+			for j := i; j < n; j++ {
+				// Only count words which contain a substitution, and are not a lone substituted character
+				if j > i && !slices.Equal(unl33ted[i:j+1], lower[i:j+1]) {
+					add(unl33ted[i:j+1], i, j, true, false)
+				}
+			}
+		}
+	}
+	return matches
+}
+
+// uppercaseVariations counts the ways a word could be capitalized like the original. Capitalizing only the first
+// or last letter, or every letter, is common and adds little.
+func uppercaseVariations(original []rune) float64 {
+	upper, lower := 0, 0
+	for _, r := range original {
+		if unicode.IsUpper(r) {
+			upper++
+		} else if unicode.IsLower(r) {
+			lower++
+		}
+	}
+	if upper == 0 {
+		return 1
+	}
+	if lower == 0 || upper == 1 && (unicode.IsUpper(original[0]) || unicode.IsUpper(original[len(original)-1])) {
+		return 2
+	}
+	return sumBinomials(upper+lower, min(upper, lower))
+}
+
+// l33tVariations counts the ways the substituted letters of a word could have been chosen
+func l33tVariations(original []rune, word []rune) float64 {
+	variations := 1.0
+	type substitution struct{ from, to rune }
+	subbed := make(map[substitution]int)
+	for k, r := range original {
+		if r = unicode.ToLower(r); r != word[k] {
+			subbed[substitution{r, word[k]}]++
+		}
+	}
+	for sub, count := range subbed {
+		unsubbed := 0
+		for _, r := range word {
+			if r == sub.to {
+				unsubbed++
+			}
+		}
+		// word holds the letter wherever it was substituted too
+		unsubbed -= count
+		if unsubbed == 0 {
+			variations *= 2
+			continue
+		}
+		variations *= sumBinomials(count+unsubbed, min(count, unsubbed))
+	}
+	return variations
+}
+
+// sumBinomials returns C(n, 1) + ... + C(n, k)
+func sumBinomials(n int, k int) float64 {
+	sum := new(big.Int)
+	for i := 1; i <= k; i++ {
+		sum.Add(sum, new(big.Int).Binomial(int64(n), int64(i)))
+	}
+	f, _ := new(big.Float).SetInt(sum).Float64()
+	return f
+}
+
+// keyboardRows are the rows of a US QWERTY keyboard, unshifted and shifted
+var keyboardRows = [][2]string{
+	{"`1234567890-=", "~!@#$%^&*()_+"},
+	{"qwertyuiop[]\\", "QWERTYUIOP{}|"},
+	{"asdfghjkl;'", "ASDFGHJKL:\""},
+	{"zxcvbnm,./", "ZXCVBNM<>?"},
+}
+
+type keyPosition struct {
+	row, col int
+	shifted  bool
+}
+
+var keyPositions = func() map[rune]keyPosition {
+	positions := make(map[rune]keyPosition)
+	for row, keys := range keyboardRows {
+		for shifted, chars := range keys {
+			for col, r := range chars {
+				positions[r] = keyPosition{row: row, col: col, shifted: shifted == 1}
+			}
+		}
+	}
+	return positions
+}()
+
+// spatialMatches finds runs of at least three neighbouring keys in a keyboard row, like "qwerty" or "lkjh"
+func spatialMatches(runes []rune) []*match {
+	var matches []*match
+	keys := 0
+	for _, row := range keyboardRows {
+		keys += len(row[0])
+	}
+	n := len(runes)
+	for i := 0; i < n; {
+		j, turns, direction := i, 0, 0
+		for j+1 < n {
+			prev, ok := keyPositions[runes[j]]
+			next, ok2 := keyPositions[runes[j+1]]
+			if !ok || !ok2 || prev.row != next.row || (next.col-prev.col)*(next.col-prev.col) != 1 {
+				break
+			}
+			if d := next.col - prev.col; d != direction {
+				turns++
+				direction = d
+			}
+			j++
+		}
+		if j-i >= 2 {
+			length := j - i + 1
+			// Each key has at most two neighbours in its row
+			guesses := 0.0
+			for l := 2; l <= length; l++ {
+				for t := 1; t <= min(turns, l-1); t++ {
+					binomial, _ := new(big.Float).SetInt(new(big.Int).Binomial(int64(l-1), int64(t-1))).Float64()
+					guesses += binomial * float64(keys) * math.Pow(2, float64(t))
+				}
+			}
+			shifted := 0
+			for _, r := range runes[i : j+1] {
+				if keyPositions[r].shifted {
+					shifted++
+				}
+			}
+			if shifted == length {
+				guesses *= 2
+			} else if shifted > 0 {
+				guesses *= sumBinomials(length, min(shifted, length-shifted))
+			}
+			matches = append(matches, newMatch(i, j, patternSpatial, guesses))
+			i = j + 1
+			continue
+		}
+		i++
+	}
+	return matches
+}
+
+// repeatMatches finds a substring repeated at least twice in a row, like "aaa" or "abcabc". Guessing a repeat
+// takes as many guesses as the repeated part, times the number of repeats.
+func repeatMatches(runes []rune, dicts *dictionaries) []*match {
+	var matches []*match
+	n := len(runes)
+	for i := 0; i < n; {
+		bestJ, bestUnit := -1, 0
+		for unit := 1; i+2*unit <= n; unit++ {
+			j := i + unit
+			for j < n && runes[j] == runes[j-unit] {
+				j++
+			}
+			// Only whole repeats of the unit count
+			repeats := (j - i) / unit
+			if repeats < 2 {
+				continue
+			}
+			end := i + repeats*unit - 1
+			if end > bestJ {
+				bestJ, bestUnit = end, unit
+			}
+		}
+		if bestJ < 0 {
+			i++
+			continue
+		}
+		base := runes[i : i+bestUnit]
+		baseLog10, _ := mostGuessableSequence(base, omnimatch(base, dicts))
+		repeats := (bestJ - i + 1) / bestUnit
+		m := newMatch(i, bestJ, patternRepeat, 0)
+		m.guessesLog10 = max(m.guessesLog10, baseLog10+math.Log10(float64(repeats)))
+		matches = append(matches, m)
+		i = bestJ + 1
+	}
+	return matches
+}
+
+// sequenceMatches finds runs of at least three consecutive letters or digits, like "abc", "XYZ" or "9876"
+func sequenceMatches(runes []rune) []*match {
+	var matches []*match
+	class := func(r rune) int {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return 1
+		case r >= 'A' && r <= 'Z':
+			return 2
+		case r >= '0' && r <= '9':
+			return 3
+		}
+		return 0
+	}
+	n := len(runes)
+	for i := 0; i < n; {
+		j := i
+		delta := rune(0)
+		if i+1 < n {
+			delta = runes[i+1] - runes[i]
+		}
+		if class(runes[i]) != 0 && (delta == 1 || delta == -1) {
+			for j+1 < n && runes[j+1]-runes[j] == delta && class(runes[j+1]) == class(runes[i]) {
+				j++
+			}
+		}
+		if j-i < 2 {
+			i++
+			continue
+		}
+		var base float64
+		switch {
+		case strings.ContainsRune("aAzZ019", runes[i]):
+			// Obvious starting points
+			base = 4
+		case class(runes[i]) == 3:
+			base = 10
+		default:
+			base = 26
+		}
+		guesses := base * float64(j-i+1)
+		if delta < 0 {
+			guesses *= 2
+		}
+		matches = append(matches, newMatch(i, j, patternSequence, guesses))
+		i = j + 1
+	}
+	return matches
+}
+
+var (
+	yearPattern          = regexp.MustCompile(`19\d\d|20\d\d`)
+	separatedDatePattern = regexp.MustCompile(`^(\d{1,4})([\s/\\_.-])(\d{1,2})([\s/\\_.-])(\d{1,4})$`)
+)
+
+// dateMatches finds years and dates, with or without separators, in day-month-year, month-day-year and
+// year-month-day order
+func dateMatches(runes []rune) []*match {
+	var matches []*match
+	s := string(runes)
+	// The patterns only match ASCII, so byte offsets of s can be converted back to rune offsets
+	runeIndex := func(byteIndex int) int {
+		return len([]rune(s[:byteIndex]))
+	}
+	for _, loc := range yearPattern.FindAllStringIndex(s, -1) {
+		year, _ := strconv.Atoi(s[loc[0]:loc[1]])
+		matches = append(matches, newMatch(runeIndex(loc[0]), runeIndex(loc[1])-1, patternDate, yearSpace(year)))
+	}
+	n := len(runes)
+	for i := 0; i < n; i++ {
+		for j := i + 3; j < n && j-i < 10; j++ {
+			token := string(runes[i : j+1])
+			if year, ok := parseDate(token); ok {
+				guesses := yearSpace(year) * 365
+				if separatedDatePattern.MatchString(token) {
+					guesses *= 4
+				}
+				matches = append(matches, newMatch(i, j, patternDate, guesses))
+			}
+		}
+	}
+	return matches
+}
+
+func yearSpace(year int) float64 {
+	return float64(max(abs(year-referenceYear), minYearSpace))
+}
+
+// parseDate returns the year of a date written as three numbers, either separated by the same separator or as
+// 4 to 8 digits
+func parseDate(token string) (int, bool) {
+	if parts := separatedDatePattern.FindStringSubmatch(token); parts != nil {
+		if parts[2] != parts[4] {
+			return 0, false
+		}
+		return dateYear(parts[1], parts[3], parts[5])
+	}
+	if len(token) < 4 || len(token) > 8 || strings.Trim(token, "0123456789") != "" {
+		return 0, false
+	}
+	for a := 1; a <= 4 && a < len(token)-1; a++ {
+		for b := a + 1; b-a <= 4 && b < len(token); b++ {
+			if year, ok := dateYear(token[:a], token[a:b], token[b:]); ok {
+				return year, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// dateYear returns the year if the three numbers form a date
+func dateYear(first, second, third string) (int, bool) {
+	for _, order := range [][3]string{
+		{first, second, third}, // day month year
+		{second, first, third}, // month day year
+		{third, second, first}, // year month day
+	} {
+		day, month, year := atoi(order[0]), atoi(order[1]), order[2]
+		if len(order[0]) > 2 || len(order[1]) > 2 || day < 1 || day > 31 || month < 1 || month > 12 {
+			continue
+		}
+		switch len(year) {
+		case 2:
+			y := atoi(year)
+			// Two digit years are read as the closest year to 2000
+			if y > 50 {
+				return 1900 + y, true
+			}
+			return 2000 + y, true
+		case 4:
+			if y := atoi(year); y >= 1000 && y <= 2050 {
+				return y, true
+			}
+		}
+	}
+	return 0, false
+}
+
+func atoi(s string) int {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,209 @@
+// Package strength estimates how many guesses an attacker would need to find a password, following the approach
+// of zxcvbn: the password is split into the sequence of patterns (common passwords, dictionary words, keyboard
+// rows, sequences, repeats and dates) which is cheapest to guess, and any part not covered by a pattern is
+// brute forced. The word lists are much smaller than zxcvbn's, so estimates for passwords made of uncommon words
+// are optimistic.
+package strength
+
+import (
+	"math"
+	"slices"
+)
+
+// Scores of the guess estimate, as in zxcvbn
+const (
+	// Fewer than 10^3 guesses: risky even against throttled online attacks
+	ScoreTooGuessable = iota
+	// Fewer than 10^6 guesses: protects against throttled online attacks
+	ScoreVeryGuessable
+	// Fewer than 10^8 guesses: protects against unthrottled online attacks
+	ScoreSomewhatGuessable
+	// Fewer than 10^10 guesses: moderate protection against offline attacks on slow hashes
+	ScoreSafelyUnguessable
+	// 10^10 guesses or more: strong protection against offline attacks on slow hashes
+	ScoreVeryUnguessable
+)
+
+// Passwords longer than this are only estimated by their beginning, which can only underestimate them
+const maxLength = 256
+
+// minGuessesBeforeGrowingSequence penalizes splitting a password into more patterns, so a long brute forced run is
+// not preferred over the patterns within it
+const minGuessesBeforeGrowingSequence = 10000
+
+type Result struct {
+	// ScoreTooGuessable to ScoreVeryUnguessable
+	Score int `json:"score"`
+	// Base 10 logarithm of the estimated number of guesses
+	GuessesLog10 float64 `json:"guesses_log10"`
+	// Explains the weakest part of the password for scores below ScoreSafelyUnguessable
+	Warning string `json:"warning,omitempty"`
+}
+
+// Estimate scores a password. userInputs are words an attacker could know, such as the username or site name,
+// which are treated as the most common words of all.
+func Estimate(password string, userInputs ...string) *Result {
+	runes := []rune(password)
+	if len(runes) > maxLength {
+		runes = runes[:maxLength]
+	}
+	if len(runes) == 0 {
+		return &Result{Score: ScoreTooGuessable, Warning: "The password is empty"}
+	}
+	matches := omnimatch(runes, newDictionaries(userInputs))
+	guessesLog10, sequence := mostGuessableSequence(runes, matches)
+	result := &Result{
+		Score:        score(guessesLog10),
+		GuessesLog10: guessesLog10,
+	}
+	if result.Score < ScoreSafelyUnguessable {
+		result.Warning = warning(sequence, len(runes))
+	}
+	return result
+}
+
+func score(guessesLog10 float64) int {
+	// The thresholds are offset by a few guesses so passwords right at a power of ten get the lower score
+	for i, threshold := range []float64{1e3 + 5, 1e6 + 5, 1e8 + 5, 1e10 + 5} {
+		if guessesLog10 < math.Log10(threshold) {
+			return i
+		}
+	}
+	return ScoreVeryUnguessable
+}
+
+// step is the cheapest way found to cover the password up to some position with a number of matches
+type step struct {
+	match *match
+	// log10 of the product of the guesses of the matches so far
+	productLog10 float64
+	// log10 of the guesses for the sequence, including the penalty for the number of matches
+	guessesLog10 float64
+}
+
+// mostGuessableSequence finds the sequence of non-overlapping matches covering the password which needs the
+// fewest guesses, with the gaps between matches brute forced. A sequence of l matches takes
+// l! * product(guesses) + minGuessesBeforeGrowingSequence^(l-1) guesses, as the attacker does not know the
+// order or number of patterns.
+func mostGuessableSequence(runes []rune, matches []*match) (float64, []*match) {
+	n := len(runes)
+	// optimal[k][l] is the best way to cover runes[:k+1] with l matches
+	optimal := make([]map[int]*step, n)
+	for k := range optimal {
+		optimal[k] = make(map[int]*step)
+	}
+	update := func(m *match, l int) {
+		k := m.j
+		productLog10 := m.guessesLog10
+		if l > 1 {
+			productLog10 += optimal[m.i-1][l-1].productLog10
+		}
+		guessesLog10 := logFactorial(l) + productLog10
+		if l > 1 {
+			guessesLog10 = logSum(guessesLog10, float64(l-1)*math.Log10(minGuessesBeforeGrowingSequence))
+		}
+		// Only keep the step if no sequence with as few matches is cheaper
+		for otherL, other := range optimal[k] {
+			if otherL <= l && other.guessesLog10 <= guessesLog10 {
+				return
+			}
+		}
+		optimal[k][l] = &step{match: m, productLog10: productLog10, guessesLog10: guessesLog10}
+	}
+	byEnd := make([][]*match, n)
+	for _, m := range matches {
+		byEnd[m.j] = append(byEnd[m.j], m)
+	}
+	for k := 0; k < n; k++ {
+		for _, m := range byEnd[k] {
+			if m.i == 0 {
+				update(m, 1)
+				continue
+			}
+			for l := range optimal[m.i-1] {
+				update(m, l+1)
+			}
+		}
+		// Brute force runes[i:k+1], never right after another brute forced run since one longer run is cheaper
+		for i := 0; i <= k; i++ {
+			bf := bruteforceMatch(runes, i, k)
+			if i == 0 {
+				update(bf, 1)
+				continue
+			}
+			for l, prev := range optimal[i-1] {
+				if prev.match.pattern != patternBruteforce {
+					update(bf, l+1)
+				}
+			}
+		}
+	}
+
+	// Walk back from the cheapest way to cover the whole password
+	bestL, best := 0, math.Inf(1)
+	for l, s := range optimal[n-1] {
+		if s.guessesLog10 < best || s.guessesLog10 == best && l < bestL {
+			bestL, best = l, s.guessesLog10
+		}
+	}
+	sequence := make([]*match, 0, bestL)
+	for k, l := n-1, bestL; k >= 0; l-- {
+		m := optimal[k][l].match
+		sequence = append(sequence, m)
+		k = m.i - 1
+	}
+	slices.Reverse(sequence)
+	return best, sequence
+}
+
+// warning describes the longest pattern in the sequence
+func warning(sequence []*match, length int) string {
+	var longest *match
+	for _, m := range sequence {
+		if m.pattern != patternBruteforce && (longest == nil || m.length() > longest.length()) {
+			longest = m
+		}
+	}
+	if longest == nil {
+		if length < 12 {
+			return "Use a longer password"
+		}
+		return ""
+	}
+	switch longest.pattern {
+	case patternDictionary:
+		switch {
+		case longest.userInput:
+			return "The password contains the name of the item or its username"
+		case longest.length() == length && longest.rank <= 100 && !longest.l33t && !longest.reversed:
+			return "This is a top-100 common password"
+		case longest.length() == length:
+			return "This is similar to a commonly used password"
+		default:
+			return "Common words and passwords are easy to guess"
+		}
+	case patternSpatial:
+		return "Rows of keys on the keyboard are easy to guess"
+	case patternRepeat:
+		return "Repeated characters like \"aaa\" or \"abcabc\" are easy to guess"
+	case patternSequence:
+		return "Sequences like \"abc\" or \"6543\" are easy to guess"
+	case patternDate:
+		return "Dates and years are easy to guess"
+	}
+	return ""
+}
+
+// logFactorial returns log10(n!)
+func logFactorial(n int) float64 {
+	lgamma, _ := math.Lgamma(float64(n + 1))
+	return lgamma / math.Ln10
+}
+
+// logSum returns log10(10^a + 10^b)
+func logSum(a float64, b float64) float64 {
+	if a < b {
+		a, b = b, a
+	}
+	return a + math.Log10(1+math.Pow(10, b-a))
+}
@@ -0,0 +1,69 @@
+package strength
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEstimate(t *testing.T) {
+	tests := []struct {
+		password   string
+		userInputs []string
+		maxScore   int
+		minScore   int
+		warning    string
+	}{
+		{"", nil, ScoreTooGuessable, ScoreTooGuessable, "empty"},
+		{"password", nil, ScoreTooGuessable, ScoreTooGuessable, "top-100"},
+		{"P@ssw0rd", nil, ScoreTooGuessable, ScoreTooGuessable, "common"},
+		{"drowssap", nil, ScoreTooGuessable, ScoreTooGuessable, "similar"},
+		{"qwertyuiop", nil, ScoreTooGuessable, ScoreTooGuessable, "top-100"},
+		{"zxcvbnm,./", nil, ScoreVeryGuessable, ScoreTooGuessable, "keys"},
+		{"aaaaaaaaaaaa", nil, ScoreTooGuessable, ScoreTooGuessable, "Repeated"},
+		{"abcdefghijk", nil, ScoreTooGuessable, ScoreTooGuessable, "Sequences"},
+		{"13/05/1987", nil, ScoreVeryGuessable, ScoreTooGuessable, "Dates"},
+		{"examplebank", []string{"Example Bank"}, ScoreTooGuessable, ScoreTooGuessable, "name of the item"},
+		{"Tr0ub4dor&3", nil, ScoreVeryUnguessable, ScoreSomewhatGuessable, ""},
+		{"correcthorsebatterystaple", nil, ScoreVeryUnguessable, ScoreVeryUnguessable, ""},
+		{"x7$Kq!p2Vz#9mL", nil, ScoreVeryUnguessable, ScoreVeryUnguessable, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.password, func(t *testing.T) {
+			result := Estimate(tt.password, tt.userInputs...)
+			if result.Score < tt.minScore || result.Score > tt.maxScore {
+				t.Fatalf("expected a score between %d and %d, got %d (10^%.1f guesses)", tt.minScore, tt.maxScore, result.Score, result.GuessesLog10)
+			}
+			if !strings.Contains(result.Warning, tt.warning) {
+				t.Fatalf("expected a warning containing %q, got %q", tt.warning, result.Warning)
+			}
+		})
+	}
+}
+
+func TestEstimateLongPassword(t *testing.T) {
+	// Long passwords must stay fast and never be scored lower than their beginning
+	password := strings.Repeat("x7$Kq!p2Vz#9mL", 40)
+	if result := Estimate(password); result.Score != ScoreVeryUnguessable {
+		t.Fatalf("expected a long password to be very unguessable, got %d", result.Score)
+	}
+}
+
+func TestUppercaseVariations(t *testing.T) {
+	tests := []struct {
+		word       string
+		variations float64
+	}{
+		{"password", 1},
+		{"Password", 2},
+		{"passworD", 2},
+		{"PASSWORD", 2},
+		// C(8,1) + C(8,2)
+		{"PaSsword", 36},
+		{"123456", 1},
+	}
+	for _, tt := range tests {
+		if got := uppercaseVariations([]rune(tt.word)); got != tt.variations {
+			t.Fatalf("uppercaseVariations(%q) = %v, expected %v", tt.word, got, tt.variations)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package structs
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APITokenPrefix starts every API token, so leaked tokens are easy to recognize
+const APITokenPrefix = "ovt_"
+
+// Number of random bytes in the secret half of an API token
+const apiTokenSecretSize = 32
+
+var ErrInvalidAPIToken = errors.New("invalid API token")
+
+// APIAccess is what an API token may do in its vaults
+type APIAccess string
+
+var (
+	// Read vaults and items
+	APIAccessRead APIAccess = "read"
+	// Read, create, update and trash items
+	APIAccessWrite APIAccess = "write"
+)
+
+var APIAccessLevels = []APIAccess{APIAccessRead, APIAccessWrite}
+
+func (a APIAccess) IsValid() bool {
+	return slices.Contains(APIAccessLevels, a)
+}
+
+// APIToken is a bearer token for the local API. Only a hash of the secret is stored; the token itself is shown
+// once when it is created.
+type APIToken struct {
+	TokenID string `json:"token_id"`
+	Name    string `json:"name"`
+	// SHA-256 of the secret half of the token
+	SecretHash string `json:"secret_hash"`
+	// Vaults the token can access
+	VaultIDs  []string  `json:"vault_ids"`
+	Access    APIAccess `json:"access"`
+	CreatedAt string    `json:"created_at"`
+	// When the token stops working (empty if it never expires)
+	ExpiresAt string `json:"expires_at,omitempty"`
+	// When the token was revoked (empty if it is still valid)
+	RevokedAt string `json:"revoked_at,omitempty"`
+}
+
+// NewAPIToken creates a token with a random secret. It returns the record to store and the token to hand to
+// the user, which cannot be recovered from the record.
+func NewAPIToken(name string, vaultIds []string, access APIAccess, expiresAt time.Time) (*APIToken, string, error) {
+	secret := make([]byte, apiTokenSecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", err
+	}
+	secretHex := hex.EncodeToString(secret)
+	token := &APIToken{
+		TokenID:    uuid.New().String(),
+		Name:       name,
+		SecretHash: hashSecret(secretHex),
+		VaultIDs:   vaultIds,
+		Access:     access,
+		CreatedAt:  time.Now().Format(time.RFC3339),
+	}
+	if !expiresAt.IsZero() {
+		token.ExpiresAt = expiresAt.Format(time.RFC3339)
+	}
+	return token, APITokenPrefix + token.TokenID + "_" + secretHex, nil
+}
+
+// ParseAPIToken splits a token into the ID of its record and its secret
+func ParseAPIToken(token string) (tokenId string, secret string, err error) {
+	rest, ok := strings.CutPrefix(token, APITokenPrefix)
+	if !ok {
+		return "", "", ErrInvalidAPIToken
+	}
+	tokenId, secret, ok = strings.Cut(rest, "_")
+	if !ok || tokenId == "" || secret == "" {
+		return "", "", ErrInvalidAPIToken
+	}
+	return tokenId, secret, nil
+}
+
+// hashSecret returns the hex SHA-256 of a secret. Secrets are random, so they need no salt or slow hash.
+func hashSecret(secret string) string {
+	hash := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(hash[:])
+}
+
+// Verify returns whether the secret belongs to this token
+func (t *APIToken) Verify(secret string) bool {
+	return subtle.ConstantTimeCompare([]byte(hashSecret(secret)), []byte(t.SecretHash)) == 1
+}
+
+// IsRevoked returns whether the token has been revoked
+func (t *APIToken) IsRevoked() bool {
+	return t.RevokedAt != ""
+}
+
+// IsExpired returns whether the token has expired at the given time
+func (t *APIToken) IsExpired(now time.Time) bool {
+	if t.ExpiresAt == "" {
+		return false
+	}
+	expiresAt, err := time.Parse(time.RFC3339, t.ExpiresAt)
+	// A token with an unreadable expiry is treated as expired
+	return err != nil || !now.Before(expiresAt)
+}
+
+// Allows returns whether the token grants the access to the vault. Write access includes read access.
+func (t *APIToken) Allows(vaultId string, access APIAccess) bool {
+	if !slices.Contains(t.VaultIDs, vaultId) {
+		return false
+	}
+	return t.Access == APIAccessWrite || access == APIAccessRead
+}
+
+// Validate checks the token grants valid access to at least one vault
+func (t *APIToken) Validate() error {
+	if strings.TrimSpace(t.Name) == "" {
+		return fmt.Errorf("%w: a name is required", ErrInvalidAPIToken)
+	}
+	if !t.Access.IsValid() {
+		return fmt.Errorf("%w: unknown access %q", ErrInvalidAPIToken, t.Access)
+	}
+	if len(t.VaultIDs) == 0 {
+		return fmt.Errorf("%w: at least one vault is required", ErrInvalidAPIToken)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package structs
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAPIToken(t *testing.T) {
+	token, secret, err := NewAPIToken("ci", []string{"vault-a"}, APIAccessRead, time.Time{})
+	if err != nil {
+		t.Fatalf("failed to create token: %v", err)
+	}
+	if strings.Contains(token.SecretHash, secret) {
+		t.Fatalf("expected only a hash of the secret to be stored")
+	}
+	tokenId, tokenSecret, err := ParseAPIToken(secret)
+	if err != nil {
+		t.Fatalf("failed to parse token: %v", err)
+	}
+	if tokenId != token.TokenID || !token.Verify(tokenSecret) {
+		t.Fatalf("expected the token to verify")
+	}
+	if token.Verify(tokenSecret + "0") {
+		t.Fatalf("expected a different secret not to verify")
+	}
+	if !token.Allows("vault-a", APIAccessRead) || token.Allows("vault-a", APIAccessWrite) || token.Allows("vault-b", APIAccessRead) {
+		t.Fatalf("expected read-only access to vault-a only")
+	}
+	if token.IsExpired(time.Now().AddDate(100, 0, 0)) {
+		t.Fatalf("expected a token without expiry never to expire")
+	}
+	for _, malformed := range []string{"", "ovt_", "ovt_id", "ovt__secret", "abc_id_secret"} {
+		if _, _, err := ParseAPIToken(malformed); !errors.Is(err, ErrInvalidAPIToken) {
+			t.Fatalf("ParseAPIToken(%q): expected ErrInvalidAPIToken, got %v", malformed, err)
+		}
+	}
+}
+
+func TestAPITokenExpiry(t *testing.T) {
+	expiresAt := time.Now().Add(time.Hour)
+	token, _, err := NewAPIToken("ci", []string{"vault-a"}, APIAccessWrite, expiresAt)
+	if err != nil {
+		t.Fatalf("failed to create token: %v", err)
+	}
+	if token.IsExpired(time.Now()) || !token.IsExpired(expiresAt.Add(time.Second)) {
+		t.Fatalf("expected the token to expire after an hour")
+	}
+	if !token.Allows("vault-a", APIAccessRead) {
+		t.Fatalf("expected write access to include read access")
+	}
+}
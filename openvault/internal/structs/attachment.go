@@ -0,0 +1,89 @@
+package structs
+
+import (
+	"time"
+
+	"github.com/BradHacker/openvault/cryptolib"
+	"github.com/google/uuid"
+)
+
+// EncryptedAttachment is the record of a file attached to an item.
+//
+// The file itself is stored as a separate blob encrypted with its own file key, which is wrapped with the vault key.
+type EncryptedAttachment struct {
+	AttachmentID      string         `json:"attachment_id"`
+	ItemID            string         `json:"item_id"`
+	VaultID           string         `json:"vault_id"`
+	CreatedAt         string         `json:"created_at"`
+	EncryptedFileKey  *cryptolib.JWE `json:"encrypted_file_key"`
+	EncryptedMetadata *cryptolib.JWE `json:"encrypted_metadata"`
+}
+
+type AttachmentMetadata struct {
+	Name        string `json:"name"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+}
+
+// NewAttachment creates an attachment record with a new random file key wrapped by the vault key.
+// The unwrapped file key is returned for encrypting the blob and must be closed by the caller.
+func NewAttachment(vaultKey *cryptolib.JWK, itemId string, vaultId string) (*EncryptedAttachment, *cryptolib.JWK, error) {
+	fileKey, err := cryptolib.GenerateContentKey()
+	if err != nil {
+		return nil, nil, err
+	}
+	encFileKey, err := fileKey.Wrap(vaultKey)
+	if err != nil {
+		fileKey.Close()
+		return nil, nil, err
+	}
+	return &EncryptedAttachment{
+		AttachmentID:     uuid.New().String(),
+		ItemID:           itemId,
+		VaultID:          vaultId,
+		CreatedAt:        time.Now().Format(time.RFC3339),
+		EncryptedFileKey: encFileKey,
+	}, fileKey, nil
+}
+
+// FileKey unwraps the attachment's file key using the vault key. The caller must close the returned key.
+func (ea *EncryptedAttachment) FileKey(vaultKey *cryptolib.JWK) (*cryptolib.JWK, error) {
+	return ea.EncryptedFileKey.Unwrap(vaultKey)
+}
+
+func (ea *EncryptedAttachment) Update(vaultKey *cryptolib.JWK, data *AttachmentMetadata) (err error) {
+	ea.EncryptedMetadata, err = vaultKey.EncryptJSON(data)
+	return err
+}
+
+func (ea *EncryptedAttachment) Read(vaultKey *cryptolib.JWK) (data *AttachmentMetadata, err error) {
+	data = &AttachmentMetadata{}
+	err = vaultKey.DecryptJSON(ea.EncryptedMetadata, &data)
+	return data, err
+}
+
+// Rewrap returns a copy of this attachment belonging to the given item and vault, with the file key and metadata
+// encrypted with the destination vault key. The blob does not need to be re-encrypted. If attachmentId differs
+// from the original, the caller is responsible for copying the blob.
+func (ea *EncryptedAttachment) Rewrap(srcKey *cryptolib.JWK, dstKey *cryptolib.JWK, attachmentId string, itemId string, vaultId string) (*EncryptedAttachment, error) {
+	fileKey, err := ea.FileKey(srcKey)
+	if err != nil {
+		return nil, err
+	}
+	defer fileKey.Close()
+	encFileKey, err := fileKey.Wrap(dstKey)
+	if err != nil {
+		return nil, err
+	}
+	encMetadata, err := reencrypt(ea.EncryptedMetadata, srcKey, dstKey)
+	if err != nil {
+		return nil, err
+	}
+	moved := *ea
+	moved.AttachmentID = attachmentId
+	moved.ItemID = itemId
+	moved.VaultID = vaultId
+	moved.EncryptedFileKey = encFileKey
+	moved.EncryptedMetadata = encMetadata
+	return &moved, nil
+}
@@ -0,0 +1,42 @@
+package structs
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"time"
+)
+
+// Number of random bytes in a browser pairing secret
+const browserPairingSecretSize = 32
+
+// BrowserPairing is a browser extension the user has paired with. The extension stores the pairing secret and
+// sends it with every request, since the extension ID the browser reports can be claimed by any local process.
+// Only a hash of the secret is stored.
+type BrowserPairing struct {
+	// chrome-extension:// origin or Firefox extension ID, as reported by the browser
+	Extension string `json:"extension"`
+	// SHA-256 of the pairing secret
+	SecretHash string `json:"secret_hash"`
+	PairedAt   string `json:"paired_at"`
+}
+
+// NewBrowserPairing pairs an extension with a random secret. It returns the record to store and the secret to
+// hand to the extension, which cannot be recovered from the record.
+func NewBrowserPairing(extension string) (*BrowserPairing, string, error) {
+	secret := make([]byte, browserPairingSecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", err
+	}
+	secretHex := hex.EncodeToString(secret)
+	return &BrowserPairing{
+		Extension:  extension,
+		SecretHash: hashSecret(secretHex),
+		PairedAt:   time.Now().Format(time.RFC3339),
+	}, secretHex, nil
+}
+
+// Verify returns whether the secret belongs to this pairing
+func (p *BrowserPairing) Verify(secret string) bool {
+	return subtle.ConstantTimeCompare([]byte(hashSecret(secret)), []byte(p.SecretHash)) == 1
+}
@@ -0,0 +1,31 @@
+package structs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBrowserPairing(t *testing.T) {
+	pairing, secret, err := NewBrowserPairing("chrome-extension://abc")
+	if err != nil {
+		t.Fatalf("failed to pair: %v", err)
+	}
+	if strings.Contains(pairing.SecretHash, secret) {
+		t.Fatalf("expected only a hash of the secret to be stored")
+	}
+	if !pairing.Verify(secret) {
+		t.Fatalf("expected the secret to verify")
+	}
+	for _, wrong := range []string{"", secret + "0", strings.ToUpper(secret)} {
+		if pairing.Verify(wrong) {
+			t.Fatalf("expected %q not to verify", wrong)
+		}
+	}
+	other, otherSecret, err := NewBrowserPairing("chrome-extension://abc")
+	if err != nil {
+		t.Fatalf("failed to pair: %v", err)
+	}
+	if otherSecret == secret || pairing.Verify(otherSecret) || !other.Verify(otherSecret) {
+		t.Fatalf("expected every pairing to get its own secret")
+	}
+}
@@ -0,0 +1,172 @@
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ItemCategory is the kind of item, which determines the detail schema used
+type ItemCategory string
+
+var (
+	CategoryLogin         ItemCategory = "login"
+	CategorySecureNote    ItemCategory = "secure_note"
+	CategoryCreditCard    ItemCategory = "credit_card"
+	CategoryIdentity      ItemCategory = "identity"
+	CategoryAPICredential ItemCategory = "api_credential"
+	CategorySSHKey        ItemCategory = "ssh_key"
+)
+
+// ItemCategories lists every supported item category
+var ItemCategories = []ItemCategory{
+	CategoryLogin,
+	CategorySecureNote,
+	CategoryCreditCard,
+	CategoryIdentity,
+	CategoryAPICredential,
+	CategorySSHKey,
+}
+
+// IsValid returns whether the category is supported
+func (c ItemCategory) IsValid() bool {
+	for _, category := range ItemCategories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+var (
+	ErrInvalidCategory = errors.New("invalid item category")
+	ErrInvalidDetails  = errors.New("invalid item details")
+)
+
+type CreditCardDetails struct {
+	Cardholder string `json:"cardholder"`
+	Number     string `json:"number"`
+	// Expiry in MM/YYYY format
+	Expiry string `json:"expiry"`
+	CVV    string `json:"cvv"`
+	PIN    string `json:"pin,omitempty"`
+}
+
+// Validate checks the card number checksum, expiry format and CVV length
+func (c *CreditCardDetails) Validate() error {
+	number := strings.NewReplacer(" ", "", "-", "").Replace(c.Number)
+	if number != "" && !luhnValid(number) {
+		return fmt.Errorf("%w: card number is invalid", ErrInvalidDetails)
+	}
+	if c.Expiry != "" {
+		if _, err := time.Parse("01/2006", c.Expiry); err != nil {
+			return fmt.Errorf("%w: card expiry must be in MM/YYYY format", ErrInvalidDetails)
+		}
+	}
+	if c.CVV != "" {
+		if _, err := strconv.Atoi(c.CVV); err != nil || len(c.CVV) < 3 || len(c.CVV) > 4 {
+			return fmt.Errorf("%w: card CVV must be 3 or 4 digits", ErrInvalidDetails)
+		}
+	}
+	return nil
+}
+
+// luhnValid checks a card number with the Luhn (mod 10) algorithm
+func luhnValid(number string) bool {
+	if len(number) < 12 || len(number) > 19 {
+		return false
+	}
+	sum := 0
+	for i := range number {
+		d := int(number[len(number)-1-i] - '0')
+		if d < 0 || d > 9 {
+			return false
+		}
+		if i%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+	}
+	return sum%10 == 0
+}
+
+type IdentityDetails struct {
+	FirstName  string `json:"first_name"`
+	LastName   string `json:"last_name"`
+	Email      string `json:"email"`
+	Phone      string `json:"phone"`
+	Address1   string `json:"address1"`
+	Address2   string `json:"address2"`
+	City       string `json:"city"`
+	State      string `json:"state"`
+	PostalCode string `json:"postal_code"`
+	Country    string `json:"country"`
+}
+
+// Validate checks the email address format
+func (i *IdentityDetails) Validate() error {
+	if i.Email != "" {
+		if _, err := mail.ParseAddress(i.Email); err != nil {
+			return fmt.Errorf("%w: identity email is invalid", ErrInvalidDetails)
+		}
+	}
+	return nil
+}
+
+type APICredentialDetails struct {
+	Key      string `json:"key"`
+	Secret   string `json:"secret"`
+	Endpoint string `json:"endpoint"`
+}
+
+// Validate checks that a key or secret is set and that the endpoint is an absolute URL
+func (a *APICredentialDetails) Validate() error {
+	if a.Key == "" && a.Secret == "" {
+		return fmt.Errorf("%w: API credential requires a key or secret", ErrInvalidDetails)
+	}
+	if a.Endpoint != "" {
+		u, err := url.Parse(a.Endpoint)
+		if err != nil || u.Scheme == "" || u.Host == "" {
+			return fmt.Errorf("%w: API endpoint must be an absolute URL", ErrInvalidDetails)
+		}
+	}
+	return nil
+}
+
+// NormalizeItem sets the current payload version, defaults the category to login, assigns IDs to new custom
+// fields, derives the public half of SSH keys, normalizes tags and validates the details.
+// The category and SSH public key are copied to the overview so items can be filtered and listed without
+// decrypting their details.
+func NormalizeItem(overview *VaultItemOverview, details *VaultItemDetails) error {
+	details.Version = VaultItemDetailsVersion
+	if details.Category == "" {
+		details.Category = CategoryLogin
+	}
+	normalizeSections(details.Sections)
+	if details.SSH != nil {
+		if err := details.SSH.normalize(); err != nil {
+			return err
+		}
+	}
+	if err := details.Validate(); err != nil {
+		return err
+	}
+	tags, err := NormalizeTags(overview.Tags)
+	if err != nil {
+		return err
+	}
+	overview.Tags = tags
+	overview.Category = details.Category
+	overview.SSHKey = nil
+	if details.SSH != nil {
+		overview.SSHKey = details.SSH.Overview()
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+package structs
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestLegacyDetailsDecodeAsLogin(t *testing.T) {
+	var details VaultItemDetails
+	if err := json.Unmarshal([]byte(`{"username":"admin","password":"hunter2","notes":""}`), &details); err != nil {
+		t.Fatalf("failed to decode details: %v", err)
+	}
+	if details.Version != VaultItemDetailsVersion || details.Category != CategoryLogin {
+		t.Fatalf("expected legacy details to decode as a login, got version %d category %q", details.Version, details.Category)
+	}
+	var overview VaultItemOverview
+	if err := json.Unmarshal([]byte(`{"title":"Example","url":"https://example.com"}`), &overview); err != nil {
+		t.Fatalf("failed to decode overview: %v", err)
+	}
+	if overview.Category != CategoryLogin {
+		t.Fatalf("expected legacy overview to decode as a login, got %q", overview.Category)
+	}
+}
+
+func TestDetailsKeepCategory(t *testing.T) {
+	var details VaultItemDetails
+	if err := json.Unmarshal([]byte(`{"version":1,"category":"secure_note","notes":"hello"}`), &details); err != nil {
+		t.Fatalf("failed to decode details: %v", err)
+	}
+	if details.Category != CategorySecureNote {
+		t.Fatalf("expected secure note, got %q", details.Category)
+	}
+}
+
+func TestValidateDetails(t *testing.T) {
+	tests := []struct {
+		name      string
+		details   VaultItemDetails
+		expectErr bool
+	}{
+		{
+			name:    "login",
+			details: VaultItemDetails{Category: CategoryLogin, Username: "admin"},
+		},
+		{
+			name:      "unknown category",
+			details:   VaultItemDetails{Category: "spaceship"},
+			expectErr: true,
+		},
+		{
+			name:    "valid card",
+			details: VaultItemDetails{Category: CategoryCreditCard, Card: &CreditCardDetails{Number: "4111 1111 1111 1111", Expiry: "12/2030", CVV: "123"}},
+		},
+		{
+			name:      "bad card checksum",
+			details:   VaultItemDetails{Category: CategoryCreditCard, Card: &CreditCardDetails{Number: "4111 1111 1111 1112"}},
+			expectErr: true,
+		},
+		{
+			name:      "bad card expiry",
+			details:   VaultItemDetails{Category: CategoryCreditCard, Card: &CreditCardDetails{Expiry: "2030-12"}},
+			expectErr: true,
+		},
+		{
+			name:      "bad card CVV",
+			details:   VaultItemDetails{Category: CategoryCreditCard, Card: &CreditCardDetails{CVV: "12a"}},
+			expectErr: true,
+		},
+		{
+			name:      "card fields on login",
+			details:   VaultItemDetails{Category: CategoryLogin, Card: &CreditCardDetails{}},
+			expectErr: true,
+		},
+		{
+			name:      "bad identity email",
+			details:   VaultItemDetails{Category: CategoryIdentity, Identity: &IdentityDetails{Email: "not-an-email"}},
+			expectErr: true,
+		},
+		{
+			name:    "API credential",
+			details: VaultItemDetails{Category: CategoryAPICredential, API: &APICredentialDetails{Key: "key", Endpoint: "https://api.example.com"}},
+		},
+		{
+			name:      "API credential with relative endpoint",
+			details:   VaultItemDetails{Category: CategoryAPICredential, API: &APICredentialDetails{Key: "key", Endpoint: "/v1"}},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.details.Validate()
+			if tt.expectErr && err == nil {
+				t.Fatalf("expected error but got none")
+			}
+			if !tt.expectErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestNormalizeItemCopiesCategory(t *testing.T) {
+	overview := &VaultItemOverview{Title: "Note"}
+	details := &VaultItemDetails{Category: CategorySecureNote, Notes: "hello"}
+	if err := NormalizeItem(overview, details); err != nil {
+		t.Fatalf("failed to normalize item: %v", err)
+	}
+	if overview.Category != CategorySecureNote || details.Version != VaultItemDetailsVersion {
+		t.Fatalf("unexpected normalized item: %+v %+v", overview, details)
+	}
+	err := NormalizeItem(&VaultItemOverview{}, &VaultItemDetails{Category: "spaceship"})
+	if !errors.Is(err, ErrInvalidCategory) {
+		t.Fatalf("expected ErrInvalidCategory, got %v", err)
+	}
+}
@@ -0,0 +1,119 @@
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/BradHacker/openvault/cryptolib"
+)
+
+// ExportArchiveVersion is the current version of the export archive format
+const ExportArchiveVersion = 1
+
+// MaxExportRounds caps the PBKDF2 rounds read from an archive, so a crafted archive cannot stall the import
+const MaxExportRounds = 10_000_000
+
+var (
+	ErrExportWrongPassword = errors.New("the export password is incorrect")
+	ErrExportUnknownFormat = errors.New("unsupported export archive version")
+)
+
+// ExportArchive holds the decrypted contents of one or more vaults, encrypted with a key derived from an export
+// password.
+//
+// The contents are encrypted with a random content key, which is wrapped with the export key. Like the account key
+// set, the PBKDF2 salt and rounds are stored in the p2s and p2c headers of the wrapped key.
+type ExportArchive struct {
+	Version       int            `json:"version"`
+	CreatedAt     string         `json:"created_at"`
+	EncryptedKey  *cryptolib.JWE `json:"encrypted_key"`
+	EncryptedData *cryptolib.JWE `json:"encrypted_data"`
+}
+
+// ExportData is the plaintext contents of an export archive
+type ExportData struct {
+	Vaults []*ExportVault `json:"vaults"`
+}
+
+type ExportVault struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Folders of the vault, which item overviews refer to by FolderID
+	Folders []*Folder     `json:"folders,omitempty"`
+	Items   []*ExportItem `json:"items"`
+}
+
+type ExportItem struct {
+	Overview    *VaultItemOverview  `json:"overview"`
+	Details     *VaultItemDetails   `json:"details"`
+	CreatedAt   string              `json:"created_at"`
+	UpdatedAt   string              `json:"updated_at"`
+	Attachments []*ExportAttachment `json:"attachments,omitempty"`
+}
+
+type ExportAttachment struct {
+	Name        string `json:"name"`
+	ContentType string `json:"content_type"`
+	Data        []byte `json:"data"`
+}
+
+// SealExportArchive encrypts the export data with a key derived from the export password
+func SealExportArchive(password string, rounds int, data *ExportData) (*ExportArchive, error) {
+	salt, err := cryptolib.NewSalt()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+	exportKey, err := cryptolib.DeriveExportKey(password, salt, rounds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive export key: %w", err)
+	}
+	defer exportKey.Close()
+	contentKey, err := cryptolib.GenerateContentKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate content key: %w", err)
+	}
+	defer contentKey.Close()
+	ea := &ExportArchive{
+		Version:   ExportArchiveVersion,
+		CreatedAt: time.Now().Format(time.RFC3339),
+	}
+	if ea.EncryptedKey, err = contentKey.Wrap(exportKey); err != nil {
+		return nil, fmt.Errorf("failed to wrap content key: %w", err)
+	}
+	ea.EncryptedKey.P2Salt = salt[:]
+	ea.EncryptedKey.P2Rounds = &rounds
+	if ea.EncryptedData, err = contentKey.EncryptJSON(data); err != nil {
+		return nil, fmt.Errorf("failed to encrypt export data: %w", err)
+	}
+	return ea, nil
+}
+
+// Open decrypts the export data with the export password
+func (ea *ExportArchive) Open(password string) (*ExportData, error) {
+	if ea.Version != ExportArchiveVersion {
+		return nil, fmt.Errorf("%w: %d", ErrExportUnknownFormat, ea.Version)
+	}
+	if ea.EncryptedKey == nil || ea.EncryptedData == nil || ea.EncryptedKey.P2Rounds == nil || len(ea.EncryptedKey.P2Salt) != len(cryptolib.Salt{}) {
+		return nil, fmt.Errorf("export archive is missing its key parameters")
+	}
+	if rounds := *ea.EncryptedKey.P2Rounds; rounds <= 0 || rounds > MaxExportRounds {
+		return nil, fmt.Errorf("export archive key rounds %d out of range", rounds)
+	}
+	salt := cryptolib.Salt(ea.EncryptedKey.P2Salt)
+	exportKey, err := cryptolib.DeriveExportKey(password, &salt, *ea.EncryptedKey.P2Rounds)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive export key: %w", err)
+	}
+	defer exportKey.Close()
+	contentKey, err := ea.EncryptedKey.Unwrap(exportKey)
+	if err != nil {
+		return nil, ErrExportWrongPassword
+	}
+	defer contentKey.Close()
+	data := &ExportData{}
+	if err := contentKey.DecryptJSON(ea.EncryptedData, data); err != nil {
+		return nil, fmt.Errorf("failed to decrypt export data: %w", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,66 @@
+package structs
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestExportArchiveRoundTrip(t *testing.T) {
+	data := &ExportData{Vaults: []*ExportVault{{
+		Name: "Personal",
+		Items: []*ExportItem{{
+			Overview: &VaultItemOverview{Category: CategoryLogin, Title: "GitHub", URL: "https://github.com"},
+			Details:  &VaultItemDetails{Category: CategoryLogin, Username: "octocat", Password: "hunter2"},
+			Attachments: []*ExportAttachment{
+				{Name: "codes.txt", ContentType: "text/plain", Data: []byte("1234 5678")},
+			},
+		}},
+	}}}
+	ea, err := SealExportArchive("correct horse", 1000, data)
+	if err != nil {
+		t.Fatalf("failed to seal export archive: %v", err)
+	}
+	// Archives are written to disk as JSON
+	raw, err := json.Marshal(ea)
+	if err != nil {
+		t.Fatalf("failed to marshal export archive: %v", err)
+	}
+	var loaded ExportArchive
+	if err := json.Unmarshal(raw, &loaded); err != nil {
+		t.Fatalf("failed to unmarshal export archive: %v", err)
+	}
+
+	if _, err := loaded.Open("wrong horse"); !errors.Is(err, ErrExportWrongPassword) {
+		t.Fatalf("expected ErrExportWrongPassword, got %v", err)
+	}
+	opened, err := loaded.Open("correct horse")
+	if err != nil {
+		t.Fatalf("failed to open export archive: %v", err)
+	}
+	if len(opened.Vaults) != 1 || len(opened.Vaults[0].Items) != 1 {
+		t.Fatalf("unexpected export data %+v", opened)
+	}
+	item := opened.Vaults[0].Items[0]
+	if item.Overview.Title != "GitHub" || item.Details.Password != "hunter2" || string(item.Attachments[0].Data) != "1234 5678" {
+		t.Fatalf("unexpected item %+v %+v", item.Overview, item.Details)
+	}
+
+	loaded.Version = ExportArchiveVersion + 1
+	if _, err := loaded.Open("correct horse"); !errors.Is(err, ErrExportUnknownFormat) {
+		t.Fatalf("expected ErrExportUnknownFormat, got %v", err)
+	}
+}
+
+func TestExportArchiveRoundsLimit(t *testing.T) {
+	ea, err := SealExportArchive("correct horse", 1000, &ExportData{})
+	if err != nil {
+		t.Fatalf("failed to seal export archive: %v", err)
+	}
+	for _, rounds := range []int{0, -1, MaxExportRounds + 1} {
+		ea.EncryptedKey.P2Rounds = &rounds
+		if _, err := ea.Open("correct horse"); err == nil {
+			t.Fatalf("expected an error opening an archive with %d rounds", rounds)
+		}
+	}
+}
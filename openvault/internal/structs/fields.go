@@ -0,0 +1,230 @@
+package structs
+
+import (
+	"fmt"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/BradHacker/openvault/cryptolib"
+	"github.com/google/uuid"
+)
+
+// FieldType determines how a custom field value is validated and displayed
+type FieldType string
+
+var (
+	FieldTypeText      FieldType = "text"
+	FieldTypeConcealed FieldType = "concealed"
+	FieldTypeURL       FieldType = "url"
+	FieldTypeEmail     FieldType = "email"
+	FieldTypeDate      FieldType = "date"
+	FieldTypeOTP       FieldType = "otp"
+)
+
+// FieldTypes lists every supported custom field type
+var FieldTypes = []FieldType{
+	FieldTypeText,
+	FieldTypeConcealed,
+	FieldTypeURL,
+	FieldTypeEmail,
+	FieldTypeDate,
+	FieldTypeOTP,
+}
+
+// IsValid returns whether the field type is supported
+func (t FieldType) IsValid() bool {
+	for _, fieldType := range FieldTypes {
+		if t == fieldType {
+			return true
+		}
+	}
+	return false
+}
+
+// Field is a single custom value stored on an item
+type Field struct {
+	ID    string    `json:"id"`
+	Label string    `json:"label"`
+	Type  FieldType `json:"type"`
+	Value string    `json:"value"`
+	// Concealed values are only returned when explicitly requested
+	Concealed bool `json:"concealed"`
+	// Set on fields returned with their concealed value removed
+	Redacted bool `json:"redacted,omitempty"`
+}
+
+// Validate checks the field type and the format of URL, email, date and one-time password values
+func (f *Field) Validate() error {
+	if !f.Type.IsValid() {
+		return fmt.Errorf("%w: field %q has unsupported type %q", ErrInvalidDetails, f.Label, f.Type)
+	}
+	if f.Value == "" {
+		return nil
+	}
+	switch f.Type {
+	case FieldTypeURL:
+		if u, err := url.Parse(f.Value); err != nil || u.Scheme == "" {
+			return fmt.Errorf("%w: field %q must be an absolute URL", ErrInvalidDetails, f.Label)
+		}
+	case FieldTypeEmail:
+		if _, err := mail.ParseAddress(f.Value); err != nil {
+			return fmt.Errorf("%w: field %q must be an email address", ErrInvalidDetails, f.Label)
+		}
+	case FieldTypeDate:
+		if _, err := time.Parse(time.DateOnly, f.Value); err != nil {
+			return fmt.Errorf("%w: field %q must be a YYYY-MM-DD date", ErrInvalidDetails, f.Label)
+		}
+	case FieldTypeOTP:
+		if _, err := cryptolib.ParseTOTP(f.Value); err != nil {
+			return fmt.Errorf("%w: field %q must be an otpauth URI or base32 secret: %v", ErrInvalidDetails, f.Label, err)
+		}
+	}
+	return nil
+}
+
+// Section is an ordered, titled group of custom fields
+type Section struct {
+	ID     string   `json:"id"`
+	Title  string   `json:"title"`
+	Fields []*Field `json:"fields"`
+}
+
+// normalizeSections assigns IDs to new sections and fields and conceals secret field types
+func normalizeSections(sections []*Section) {
+	for _, section := range sections {
+		if section.ID == "" {
+			section.ID = uuid.New().String()
+		}
+		for _, field := range section.Fields {
+			if field.ID == "" {
+				field.ID = uuid.New().String()
+			}
+			if field.Type == "" {
+				field.Type = FieldTypeText
+			}
+			if field.Type == FieldTypeConcealed || field.Type == FieldTypeOTP {
+				field.Concealed = true
+			}
+			field.Redacted = false
+		}
+	}
+}
+
+// validateSections validates every custom field and checks that field IDs are unique across all sections
+func validateSections(sections []*Section) error {
+	fieldIds := make(map[string]bool)
+	for _, section := range sections {
+		for _, field := range section.Fields {
+			if field.ID == "" || fieldIds[field.ID] {
+				return fmt.Errorf("%w: missing or duplicate field ID %q", ErrInvalidDetails, field.ID)
+			}
+			fieldIds[field.ID] = true
+			if err := field.Validate(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Field returns the custom field with the given ID, or nil if the item has no such field
+func (vd *VaultItemDetails) Field(fieldId string) *Field {
+	for _, section := range vd.Sections {
+		for _, field := range section.Fields {
+			if field.ID == fieldId {
+				return field
+			}
+		}
+	}
+	return nil
+}
+
+// FieldByLabel returns the first custom field whose label matches any of the given labels, or nil if there is none.
+// Labels are compared ignoring case, spaces and punctuation, so "Session Token" matches "session_token".
+func (vd *VaultItemDetails) FieldByLabel(labels ...string) *Field {
+	for _, section := range vd.Sections {
+		for _, field := range section.Fields {
+			for _, label := range labels {
+				if normalizeLabel(field.Label) == normalizeLabel(label) {
+					return field
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func normalizeLabel(label string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, label)
+}
+
+// Redacted returns a copy of the details with the values of concealed custom fields and SSH private keys removed
+func (vd *VaultItemDetails) Redacted() *VaultItemDetails {
+	redacted := *vd
+	redacted.Sections = make([]*Section, len(vd.Sections))
+	for i, section := range vd.Sections {
+		s := *section
+		s.Fields = make([]*Field, len(section.Fields))
+		for j, field := range section.Fields {
+			f := *field
+			if f.Concealed {
+				f.Value = ""
+				f.Redacted = true
+			}
+			s.Fields[j] = &f
+		}
+		redacted.Sections[i] = &s
+	}
+	if vd.SSH != nil {
+		ssh := *vd.SSH
+		ssh.PrivateKey = ""
+		ssh.Redacted = true
+		redacted.SSH = &ssh
+	}
+	return &redacted
+}
+
+// OnlyField returns details containing only the custom field with the given ID (in its section),
+// or nil if the item has no such field. The field value is returned even if it is concealed.
+func (vd *VaultItemDetails) OnlyField(fieldId string) *VaultItemDetails {
+	for _, section := range vd.Sections {
+		for _, field := range section.Fields {
+			if field.ID != fieldId {
+				continue
+			}
+			return &VaultItemDetails{
+				Version:  vd.Version,
+				Category: vd.Category,
+				Sections: []*Section{{ID: section.ID, Title: section.Title, Fields: []*Field{field}}},
+			}
+		}
+	}
+	return nil
+}
+
+// RestoreRedacted copies the stored values from current into fields which were returned redacted, so an item
+// can be updated without revealing its concealed fields first.
+func (vd *VaultItemDetails) RestoreRedacted(current *VaultItemDetails) {
+	for _, section := range vd.Sections {
+		for _, field := range section.Fields {
+			if !field.Redacted {
+				continue
+			}
+			if stored := current.Field(field.ID); stored != nil {
+				field.Value = stored.Value
+			}
+		}
+	}
+	if vd.SSH != nil && vd.SSH.Redacted && current.SSH != nil {
+		vd.SSH.PrivateKey = current.SSH.PrivateKey
+		vd.SSH.Redacted = false
+	}
+}
@@ -0,0 +1,133 @@
+package structs
+
+import (
+	"testing"
+)
+
+func testFieldDetails() *VaultItemDetails {
+	return &VaultItemDetails{
+		Category: CategoryLogin,
+		Sections: []*Section{
+			{
+				Title: "Database",
+				Fields: []*Field{
+					{ID: "host", Label: "Host", Type: FieldTypeText, Value: "db.example.com"},
+					{ID: "pin", Label: "PIN", Type: FieldTypeConcealed, Value: "1234"},
+				},
+			},
+		},
+	}
+}
+
+func TestNormalizeFields(t *testing.T) {
+	details := testFieldDetails()
+	details.Sections[0].Fields = append(details.Sections[0].Fields, &Field{Label: "Notes"})
+	if err := NormalizeItem(&VaultItemOverview{}, details); err != nil {
+		t.Fatalf("failed to normalize item: %v", err)
+	}
+	if details.Sections[0].ID == "" || details.Sections[0].Fields[2].ID == "" {
+		t.Fatal("expected IDs to be assigned to new sections and fields")
+	}
+	if details.Sections[0].Fields[2].Type != FieldTypeText {
+		t.Fatalf("expected untyped field to default to text, got %q", details.Sections[0].Fields[2].Type)
+	}
+	if !details.Field("pin").Concealed {
+		t.Fatal("expected concealed field type to set the concealed flag")
+	}
+}
+
+func TestValidateFields(t *testing.T) {
+	tests := []struct {
+		name      string
+		field     *Field
+		expectErr bool
+	}{
+		{name: "url", field: &Field{ID: "a", Type: FieldTypeURL, Value: "https://example.com"}},
+		{name: "relative url", field: &Field{ID: "a", Type: FieldTypeURL, Value: "example"}, expectErr: true},
+		{name: "email", field: &Field{ID: "a", Type: FieldTypeEmail, Value: "user@example.com"}},
+		{name: "bad email", field: &Field{ID: "a", Type: FieldTypeEmail, Value: "user"}, expectErr: true},
+		{name: "date", field: &Field{ID: "a", Type: FieldTypeDate, Value: "2024-02-29"}},
+		{name: "bad date", field: &Field{ID: "a", Type: FieldTypeDate, Value: "02/29/2024"}, expectErr: true},
+		{name: "otp uri", field: &Field{ID: "a", Type: FieldTypeOTP, Value: "otpauth://totp/Example?secret=JBSWY3DPEHPK3PXP"}},
+		{name: "otp secret", field: &Field{ID: "a", Type: FieldTypeOTP, Value: "JBSWY3DPEHPK3PXP"}},
+		{name: "bad otp", field: &Field{ID: "a", Type: FieldTypeOTP, Value: "otpauth://hotp/Example?secret=JBSWY3DPEHPK3PXP"}, expectErr: true},
+		{name: "unknown type", field: &Field{ID: "a", Type: "color", Value: "red"}, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			details := &VaultItemDetails{Category: CategoryLogin, Sections: []*Section{{Fields: []*Field{tt.field}}}}
+			err := details.Validate()
+			if tt.expectErr && err == nil {
+				t.Fatalf("expected error but got none")
+			}
+			if !tt.expectErr && err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestDuplicateFieldIDs(t *testing.T) {
+	details := testFieldDetails()
+	details.Sections = append(details.Sections, &Section{Fields: []*Field{{ID: "host", Type: FieldTypeText}}})
+	if err := details.Validate(); err == nil {
+		t.Fatal("expected error for duplicate field IDs, but got none")
+	}
+}
+
+func TestRedactedDetails(t *testing.T) {
+	details := testFieldDetails()
+	if err := NormalizeItem(&VaultItemOverview{}, details); err != nil {
+		t.Fatalf("failed to normalize item: %v", err)
+	}
+	redacted := details.Redacted()
+	if pin := redacted.Field("pin"); pin.Value != "" || !pin.Redacted {
+		t.Fatalf("expected concealed field to be redacted, got %+v", pin)
+	}
+	if host := redacted.Field("host"); host.Value != "db.example.com" {
+		t.Fatalf("expected plain field to be returned, got %+v", host)
+	}
+	if details.Field("pin").Value != "1234" {
+		t.Fatal("redacting modified the original details")
+	}
+	// Updating with the redacted copy keeps the stored value
+	redacted.RestoreRedacted(details)
+	if err := NormalizeItem(&VaultItemOverview{}, redacted); err != nil {
+		t.Fatalf("failed to normalize item: %v", err)
+	}
+	if pin := redacted.Field("pin"); pin.Value != "1234" || pin.Redacted {
+		t.Fatalf("expected redacted field to be restored, got %+v", pin)
+	}
+}
+
+func TestOnlyField(t *testing.T) {
+	details := testFieldDetails()
+	details.Password = "hunter2"
+	only := details.OnlyField("pin")
+	if only == nil {
+		t.Fatal("expected field to be found")
+	}
+	if only.Password != "" || len(only.Sections) != 1 || len(only.Sections[0].Fields) != 1 {
+		t.Fatalf("expected only the requested field, got %+v", only)
+	}
+	if only.Sections[0].Fields[0].Value != "1234" {
+		t.Fatal("expected requested concealed field to include its value")
+	}
+	if details.OnlyField("missing") != nil {
+		t.Fatal("expected nil for a missing field")
+	}
+}
+
+func TestFieldByLabel(t *testing.T) {
+	details := testFieldDetails()
+	if f := details.FieldByLabel("missing", "p.i.n"); f == nil || f.ID != "pin" {
+		t.Fatalf("expected the PIN field, got %+v", f)
+	}
+	if f := details.FieldByLabel("HOST"); f == nil || f.ID != "host" {
+		t.Fatalf("expected the host field, got %+v", f)
+	}
+	if details.FieldByLabel("password") != nil {
+		t.Fatal("expected nil for a missing label")
+	}
+}
@@ -0,0 +1,52 @@
+package structs
+
+import (
+	"time"
+
+	"github.com/BradHacker/openvault/cryptolib"
+	"github.com/google/uuid"
+)
+
+// EncryptedItemRevision is a previous version of an item's overview and details.
+//
+// Revisions hold the ciphertext of the version they replace, so they stay encrypted with the same vault key as the item.
+type EncryptedItemRevision struct {
+	RevisionID string `json:"revision_id"`
+	ItemID     string `json:"item_id"`
+	VaultID    string `json:"vault_id"`
+	// The account which made the edit that replaced this version
+	AccountID string `json:"account_id"`
+	// When this version was originally written
+	CreatedAt string `json:"created_at"`
+	// When this version was replaced
+	ArchivedAt        string         `json:"archived_at"`
+	EncryptedOverview *cryptolib.JWE `json:"encrypted_overview"`
+	EncryptedDetails  *cryptolib.JWE `json:"encrypted_details"`
+}
+
+// NewItemRevision snapshots the current version of an item before it is replaced by accountId
+func NewItemRevision(overview *EncryptedVaultItemOverview, details *EncryptedVaultItemDetails, accountId string) *EncryptedItemRevision {
+	return &EncryptedItemRevision{
+		RevisionID:        uuid.New().String(),
+		ItemID:            overview.ItemID,
+		VaultID:           overview.VaultID,
+		AccountID:         accountId,
+		CreatedAt:         details.UpdatedAt,
+		ArchivedAt:        time.Now().Format(time.RFC3339),
+		EncryptedOverview: overview.EncryptedOverview,
+		EncryptedDetails:  details.EncryptedDetails,
+	}
+}
+
+// Read decrypts the overview and details of this revision using the vault key
+func (r *EncryptedItemRevision) Read(vaultKey *cryptolib.JWK) (overview *VaultItemOverview, details *VaultItemDetails, err error) {
+	overview = &VaultItemOverview{}
+	if err = vaultKey.DecryptJSON(r.EncryptedOverview, overview); err != nil {
+		return nil, nil, err
+	}
+	details = &VaultItemDetails{}
+	if err = vaultKey.DecryptJSON(r.EncryptedDetails, details); err != nil {
+		return nil, nil, err
+	}
+	return overview, details, nil
+}
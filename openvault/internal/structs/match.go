@@ -0,0 +1,131 @@
+package structs
+
+import (
+	"net"
+	"net/url"
+	"strings"
+
+	"golang.org/x/net/publicsuffix"
+)
+
+var defaultPorts = map[string]string{
+	"http":  "80",
+	"https": "443",
+	"ssh":   "22",
+}
+
+// ParseItemURL parses a URL as entered on an item. URLs without a scheme, such as "github.com", are parsed
+// as a bare host which matches any scheme.
+func ParseItemURL(raw string) (*url.URL, error) {
+	raw = strings.TrimSpace(raw)
+	if !strings.Contains(raw, "://") {
+		u, err := url.Parse("//" + raw)
+		if err != nil {
+			return nil, err
+		}
+		return u, nil
+	}
+	return url.Parse(raw)
+}
+
+// MatchURL scores how closely the URL of an item matches a requested URL. A score of 0 means the URL does not
+// match. The host and port must match exactly (ignoring case and default ports) and the scheme must match unless
+// the item URL has none. If the item URL has a path, the requested path must be within it; longer matching
+// paths score higher so the most specific item can be chosen.
+func MatchURL(itemURL string, target *url.URL) int {
+	if itemURL == "" || target == nil {
+		return 0
+	}
+	u, err := ParseItemURL(itemURL)
+	if err != nil || u.Host == "" {
+		return 0
+	}
+	if u.Scheme != "" && !strings.EqualFold(u.Scheme, target.Scheme) {
+		return 0
+	}
+	if !strings.EqualFold(u.Hostname(), target.Hostname()) {
+		return 0
+	}
+	scheme := strings.ToLower(target.Scheme)
+	if normalizePort(scheme, u.Port()) != normalizePort(scheme, target.Port()) {
+		return 0
+	}
+	itemPath := strings.Trim(u.Path, "/")
+	targetPath := strings.Trim(target.Path, "/")
+	if itemPath == "" {
+		return 1
+	}
+	if targetPath != itemPath && !strings.HasPrefix(targetPath, itemPath+"/") {
+		return 0
+	}
+	return 1 + len(itemPath)
+}
+
+func normalizePort(scheme string, port string) string {
+	if port == "" {
+		return defaultPorts[scheme]
+	}
+	return port
+}
+
+// OriginMatch is how closely the URL of an item matches the origin of a web page
+type OriginMatch int
+
+const (
+	OriginMatchNone OriginMatch = iota
+	// The page is on another host with the same registrable domain (login.example.com for www.example.com)
+	OriginMatchDomain
+	// The page is on a subdomain of the item host (login.example.com for example.com)
+	OriginMatchSubdomain
+	// The page is on the item host
+	OriginMatchExact
+)
+
+// MatchOrigin scores how closely the URL of an item matches the origin of a web page. The port must match
+// (ignoring default ports) and an item with an https URL never matches an http page. Hosts match exactly, as a
+// subdomain of the item host or by sharing the registrable domain from the public suffix list. IP addresses
+// and hosts directly under a public suffix only match exactly.
+func MatchOrigin(itemURL string, origin *url.URL) OriginMatch {
+	if itemURL == "" || origin == nil || origin.Hostname() == "" {
+		return OriginMatchNone
+	}
+	u, err := ParseItemURL(itemURL)
+	if err != nil || u.Host == "" {
+		return OriginMatchNone
+	}
+	scheme := strings.ToLower(origin.Scheme)
+	switch strings.ToLower(u.Scheme) {
+	case "", scheme:
+	case "http":
+		// Logins saved for the http version of a site may be used once it moves to https
+		if scheme != "https" {
+			return OriginMatchNone
+		}
+	default:
+		return OriginMatchNone
+	}
+	// Ports are compared as if the item used the page's scheme, so example.com:443 matches https://example.com
+	if normalizePort(scheme, u.Port()) != normalizePort(scheme, origin.Port()) {
+		return OriginMatchNone
+	}
+	itemHost := strings.TrimSuffix(strings.ToLower(u.Hostname()), ".")
+	host := strings.TrimSuffix(strings.ToLower(origin.Hostname()), ".")
+	if itemHost == host {
+		return OriginMatchExact
+	}
+	if net.ParseIP(itemHost) != nil || net.ParseIP(host) != nil {
+		return OriginMatchNone
+	}
+	domain, err := publicsuffix.EffectiveTLDPlusOne(host)
+	if err != nil {
+		return OriginMatchNone
+	}
+	itemDomain, err := publicsuffix.EffectiveTLDPlusOne(itemHost)
+	if err != nil || itemDomain != domain {
+		return OriginMatchNone
+	}
+	if strings.HasSuffix(host, "."+itemHost) {
+		return OriginMatchSubdomain
+	}
+	return OriginMatchDomain
+}
@@ -0,0 +1,81 @@
+package structs
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestMatchURL(t *testing.T) {
+	tests := []struct {
+		item   string
+		target string
+		match  bool
+	}{
+		{"https://github.com", "https://github.com/org/repo.git", true},
+		{"github.com", "https://github.com", true},
+		{"github.com", "ssh://github.com", true},
+		{"GitHub.com", "https://github.com", true},
+		{"https://github.com", "http://github.com", false},
+		{"https://github.com:443", "https://github.com", true},
+		{"https://git.example.com:8443", "https://git.example.com", false},
+		{"https://github.com/org", "https://github.com/org/repo.git", true},
+		{"https://github.com/org", "https://github.com/organization", false},
+		{"https://github.com/org", "https://github.com", false},
+		{"https://gitlab.com", "https://github.com", false},
+		{"", "https://github.com", false},
+	}
+	for _, tt := range tests {
+		target, err := url.Parse(tt.target)
+		if err != nil {
+			t.Fatalf("failed to parse %q: %v", tt.target, err)
+		}
+		score := MatchURL(tt.item, target)
+		if (score > 0) != tt.match {
+			t.Fatalf("MatchURL(%q, %q) = %d, expected match %v", tt.item, tt.target, score, tt.match)
+		}
+	}
+}
+
+func TestMatchURLPrefersLongerPath(t *testing.T) {
+	target, _ := url.Parse("https://github.com/org/repo.git")
+	if MatchURL("https://github.com/org", target) <= MatchURL("https://github.com", target) {
+		t.Fatal("expected a path match to score higher than a host match")
+	}
+}
+
+func TestMatchOrigin(t *testing.T) {
+	tests := []struct {
+		item   string
+		origin string
+		match  OriginMatch
+	}{
+		{"https://example.com/login", "https://example.com", OriginMatchExact},
+		{"example.com", "https://example.com", OriginMatchExact},
+		{"EXAMPLE.com", "https://example.com:443", OriginMatchExact},
+		{"http://example.com", "https://example.com", OriginMatchExact},
+		{"https://example.com", "http://example.com", OriginMatchNone},
+		{"https://example.com", "https://login.example.com", OriginMatchSubdomain},
+		{"https://www.example.com", "https://login.example.com", OriginMatchDomain},
+		{"https://login.example.com", "https://example.com", OriginMatchDomain},
+		{"https://example.com", "https://example.org", OriginMatchNone},
+		{"https://example.com", "https://notexample.com", OriginMatchNone},
+		{"https://example.com", "https://example.com.evil.com", OriginMatchNone},
+		{"https://example.com:8443", "https://example.com", OriginMatchNone},
+		{"https://alice.github.io", "https://bob.github.io", OriginMatchNone},
+		{"https://www.example.co.uk", "https://shop.example.co.uk", OriginMatchDomain},
+		{"https://co.uk", "https://example.co.uk", OriginMatchNone},
+		{"https://192.168.1.1", "https://192.168.1.1", OriginMatchExact},
+		{"https://1.1.1.1", "https://10.1.1.1", OriginMatchNone},
+		{"ssh://example.com", "https://example.com", OriginMatchNone},
+		{"", "https://example.com", OriginMatchNone},
+	}
+	for _, tt := range tests {
+		origin, err := url.Parse(tt.origin)
+		if err != nil {
+			t.Fatalf("failed to parse %q: %v", tt.origin, err)
+		}
+		if match := MatchOrigin(tt.item, origin); match != tt.match {
+			t.Fatalf("MatchOrigin(%q, %q) = %d, expected %d", tt.item, tt.origin, match, tt.match)
+		}
+	}
+}
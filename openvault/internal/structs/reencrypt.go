@@ -0,0 +1,62 @@
+package structs
+
+import (
+	"github.com/BradHacker/openvault/cryptolib"
+)
+
+// reencrypt decrypts the data with srcKey and encrypts it again with dstKey
+func reencrypt(data *cryptolib.JWE, srcKey *cryptolib.JWK, dstKey *cryptolib.JWK) (*cryptolib.JWE, error) {
+	plaintext, err := srcKey.Decrypt(data)
+	if err != nil {
+		return nil, err
+	}
+	defer clear(plaintext)
+	return dstKey.Encrypt(plaintext)
+}
+
+// Reencrypt returns a copy of this overview belonging to the given item and vault, encrypted with the
+// destination vault key. All timestamps are preserved.
+func (vio *EncryptedVaultItemOverview) Reencrypt(srcKey *cryptolib.JWK, dstKey *cryptolib.JWK, itemId string, vaultId string) (*EncryptedVaultItemOverview, error) {
+	encOverview, err := reencrypt(vio.EncryptedOverview, srcKey, dstKey)
+	if err != nil {
+		return nil, err
+	}
+	moved := *vio
+	moved.ItemID = itemId
+	moved.VaultID = vaultId
+	moved.EncryptedOverview = encOverview
+	return &moved, nil
+}
+
+// Reencrypt returns a copy of these details belonging to the given item and vault, encrypted with the
+// destination vault key. All timestamps are preserved.
+func (vio *EncryptedVaultItemDetails) Reencrypt(srcKey *cryptolib.JWK, dstKey *cryptolib.JWK, itemId string, vaultId string) (*EncryptedVaultItemDetails, error) {
+	encDetails, err := reencrypt(vio.EncryptedDetails, srcKey, dstKey)
+	if err != nil {
+		return nil, err
+	}
+	moved := *vio
+	moved.ItemID = itemId
+	moved.VaultID = vaultId
+	moved.EncryptedDetails = encDetails
+	return &moved, nil
+}
+
+// Reencrypt returns a copy of this revision belonging to the given item and vault, encrypted with the
+// destination vault key. All timestamps are preserved.
+func (r *EncryptedItemRevision) Reencrypt(srcKey *cryptolib.JWK, dstKey *cryptolib.JWK, itemId string, vaultId string) (*EncryptedItemRevision, error) {
+	encOverview, err := reencrypt(r.EncryptedOverview, srcKey, dstKey)
+	if err != nil {
+		return nil, err
+	}
+	encDetails, err := reencrypt(r.EncryptedDetails, srcKey, dstKey)
+	if err != nil {
+		return nil, err
+	}
+	moved := *r
+	moved.ItemID = itemId
+	moved.VaultID = vaultId
+	moved.EncryptedOverview = encOverview
+	moved.EncryptedDetails = encDetails
+	return &moved, nil
+}
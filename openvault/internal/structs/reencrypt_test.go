@@ -0,0 +1,57 @@
+package structs
+
+import (
+	"testing"
+)
+
+func TestReencryptItem(t *testing.T) {
+	src, dst := newTestKeys(t), newTestKeys(t)
+	encOverview := &EncryptedVaultItemOverview{ItemID: "item", VaultID: "src", CreatedAt: "2024-01-01T00:00:00Z"}
+	if err := encOverview.Update(src.vaultKey, &VaultItemOverview{Title: "Database"}); err != nil {
+		t.Fatalf("failed to encrypt overview: %v", err)
+	}
+	moved, err := encOverview.Reencrypt(src.vaultKey, dst.vaultKey, "copy", "dst")
+	if err != nil {
+		t.Fatalf("failed to re-encrypt overview: %v", err)
+	}
+	if moved.ItemID != "copy" || moved.VaultID != "dst" || moved.CreatedAt != encOverview.CreatedAt {
+		t.Fatalf("unexpected re-encrypted overview: %+v", moved)
+	}
+	if encOverview.ItemID != "item" || encOverview.VaultID != "src" {
+		t.Fatal("re-encrypting modified the original overview")
+	}
+	if _, err := moved.Read(src.vaultKey); err == nil {
+		t.Fatal("expected error when decrypting with the source vault key, but got none")
+	}
+	overview, err := moved.Read(dst.vaultKey)
+	if err != nil {
+		t.Fatalf("failed to decrypt with the destination vault key: %v", err)
+	}
+	if overview.Title != "Database" {
+		t.Fatalf("unexpected title %q", overview.Title)
+	}
+}
+
+func TestReencryptRevision(t *testing.T) {
+	src, dst := newTestKeys(t), newTestKeys(t)
+	encOverview := &EncryptedVaultItemOverview{ItemID: "item", VaultID: "src"}
+	encDetails := &EncryptedVaultItemDetails{ItemID: "item", VaultID: "src"}
+	if err := encOverview.Update(src.vaultKey, &VaultItemOverview{Title: "Database"}); err != nil {
+		t.Fatalf("failed to encrypt overview: %v", err)
+	}
+	if err := encDetails.Update(src.vaultKey, &VaultItemDetails{Password: "hunter2"}); err != nil {
+		t.Fatalf("failed to encrypt details: %v", err)
+	}
+	rev := NewItemRevision(encOverview, encDetails, "account")
+	moved, err := rev.Reencrypt(src.vaultKey, dst.vaultKey, "item", "dst")
+	if err != nil {
+		t.Fatalf("failed to re-encrypt revision: %v", err)
+	}
+	_, details, err := moved.Read(dst.vaultKey)
+	if err != nil {
+		t.Fatalf("failed to decrypt revision: %v", err)
+	}
+	if details.Password != "hunter2" || moved.RevisionID != rev.RevisionID {
+		t.Fatalf("unexpected re-encrypted revision: %+v %+v", moved, details)
+	}
+}
@@ -0,0 +1,43 @@
+package structs
+
+// Settings are the user-configurable application settings
+type Settings struct {
+	// Maximum number of previous versions kept per item (0 keeps every version)
+	HistoryRetention int `json:"history_retention"`
+	// Number of days trashed items and vaults are kept before being purged (0 keeps them until the trash is emptied)
+	TrashRetentionDays int `json:"trash_retention_days"`
+	// Serve the SSH keys in unlocked vaults over an ssh-agent socket
+	SSHAgentEnabled bool `json:"ssh_agent_enabled"`
+	// Ask for approval every time the SSH agent is asked to sign with a key
+	SSHAgentConfirm bool `json:"ssh_agent_confirm"`
+	// Run the requests of the git, docker, AWS and kubectl credential helpers once the user approves them
+	CredentialHelpersEnabled bool `json:"credential_helpers_enabled"`
+	// Vault that credential helpers store new logins in. If empty, the only unlocked vault is used.
+	CredentialHelperVaultID string `json:"credential_helper_vault_id"`
+	// Provide the freedesktop Secret Service on the session bus (Linux only)
+	SecretServiceEnabled bool `json:"secret_service_enabled"`
+	// Vault that holds the items stored through the Secret Service
+	SecretServiceVaultID string `json:"secret_service_vault_id"`
+	// Answer autofill requests from paired browser extensions
+	BrowserIntegrationEnabled bool `json:"browser_integration_enabled"`
+	// Number of days after which the password health report flags unchanged passwords (0 never flags them)
+	PasswordMaxAgeDays int `json:"password_max_age_days"`
+	// Local copy of the Have I Been Pwned Pwned Passwords list: a file sorted by hash or a directory of range buckets
+	PwnedPasswordsPath string `json:"pwned_passwords_path"`
+	// Check passwords with the Pwned Passwords range API when there is no local copy. Only the first five
+	// characters of the SHA-1 hash of each password are sent.
+	PwnedPasswordsOnline bool `json:"pwned_passwords_online"`
+	// Serve the local automation API
+	APIServerEnabled bool `json:"api_server_enabled"`
+	// Loopback address (e.g. 127.0.0.1:8420) the API listens on. If empty, it listens on a Unix socket instead.
+	APIServerAddress string `json:"api_server_address"`
+}
+
+// DefaultSettings returns the settings used when no settings file exists
+func DefaultSettings() *Settings {
+	return &Settings{
+		HistoryRetention:   50,
+		TrashRetentionDays: 30,
+		PasswordMaxAgeDays: 365,
+	}
+}
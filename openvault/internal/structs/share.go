@@ -0,0 +1,171 @@
+package structs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/BradHacker/openvault/cryptolib"
+	"github.com/google/uuid"
+)
+
+// SharePackageVersion is the current version of the share package format
+const SharePackageVersion = 1
+
+var (
+	ErrShareExpired       = errors.New("share package has expired")
+	ErrShareWrongKey      = errors.New("share package was not encrypted for this key")
+	ErrShareBadSignature  = errors.New("share package signature is invalid")
+	ErrShareAlreadyUsed   = errors.New("share package has already been imported")
+	ErrShareBadRecipient  = errors.New("recipient key must be an RSA public key")
+	ErrShareUnknownFormat = errors.New("unsupported share package version")
+	ErrShareWrongSender   = errors.New("share package was not signed by the expected sender")
+)
+
+// SharePackage is a single vault item encrypted to a recipient's public key and signed by the sender.
+//
+// The overview and details are encrypted with a random content key, which is in turn wrapped with
+// the recipient's public key. Everything other than the signature is covered by the signature.
+type SharePackage struct {
+	Version           int            `json:"version"`
+	PackageID         string         `json:"package_id"`
+	SenderAccountID   string         `json:"sender_account_id"`
+	SenderEmail       string         `json:"sender_email"`
+	SenderSignKey     *cryptolib.JWK `json:"sender_sign_key"`
+	RecipientKeyID    string         `json:"recipient_key_id"`
+	CreatedAt         string         `json:"created_at"`
+	ExpiresAt         string         `json:"expires_at,omitempty"`
+	OneTime           bool           `json:"one_time,omitempty"`
+	EncryptedKey      *cryptolib.JWE `json:"encrypted_key"`
+	EncryptedOverview *cryptolib.JWE `json:"encrypted_overview"`
+	EncryptedDetails  *cryptolib.JWE `json:"encrypted_details"`
+	Signature         []byte         `json:"signature,omitempty"`
+}
+
+// ShareOptions controls the restrictions placed on a share package
+type ShareOptions struct {
+	// Time after which the package can no longer be imported (zero for no expiry)
+	ExpiresAt time.Time
+	// Whether the package may only be imported once
+	OneTime bool
+}
+
+// SealSharePackage encrypts the item overview and details to the recipient's public key and signs the
+// result with the sender's signing key.
+func SealSharePackage(sender *Account, signKey *cryptolib.JWK, pubSignKey *cryptolib.JWK, recipientKey *cryptolib.JWK, overview *VaultItemOverview, details *VaultItemDetails, opts ShareOptions) (*SharePackage, error) {
+	if !recipientKey.IsPublic() || recipientKey.Algorithm != "RSA-OAEP" {
+		return nil, ErrShareBadRecipient
+	}
+	contentKey, err := cryptolib.GenerateContentKey()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate content key: %w", err)
+	}
+	defer contentKey.Close()
+	sp := &SharePackage{
+		Version:         SharePackageVersion,
+		PackageID:       uuid.New().String(),
+		SenderAccountID: sender.ID,
+		SenderEmail:     sender.Email,
+		SenderSignKey:   pubSignKey,
+		RecipientKeyID:  recipientKey.KeyID,
+		CreatedAt:       time.Now().Format(time.RFC3339),
+		OneTime:         opts.OneTime,
+	}
+	if !opts.ExpiresAt.IsZero() {
+		sp.ExpiresAt = opts.ExpiresAt.Format(time.RFC3339)
+	}
+	sp.EncryptedKey, err = contentKey.Wrap(recipientKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to wrap content key: %w", err)
+	}
+	sp.EncryptedOverview, err = contentKey.EncryptJSON(overview)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt item overview: %w", err)
+	}
+	sp.EncryptedDetails, err = contentKey.EncryptJSON(details)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt item details: %w", err)
+	}
+	signedBytes, err := sp.signedBytes()
+	if err != nil {
+		return nil, err
+	}
+	sp.Signature, err = signKey.Sign(signedBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign share package: %w", err)
+	}
+	return sp, nil
+}
+
+// signedBytes returns the serialized package contents covered by the signature
+func (sp *SharePackage) signedBytes() ([]byte, error) {
+	unsigned := *sp
+	unsigned.Signature = nil
+	return json.Marshal(&unsigned)
+}
+
+// SenderFingerprint returns the SHA-256 thumbprint of the sender's public signing key
+func (sp *SharePackage) SenderFingerprint() (string, error) {
+	if sp.SenderSignKey == nil {
+		return "", ErrShareBadSignature
+	}
+	fp, err := sp.SenderSignKey.Fingerprint()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", fp), nil
+}
+
+// Verify checks the package version, signature and expiry. The sender signing key embedded in the package
+// is used for verification, so callers wanting to authenticate the sender should compare SenderFingerprint
+// against a known value.
+func (sp *SharePackage) Verify(now time.Time) error {
+	if sp.Version != SharePackageVersion {
+		return fmt.Errorf("%w: %d", ErrShareUnknownFormat, sp.Version)
+	}
+	if sp.SenderSignKey == nil || len(sp.Signature) == 0 {
+		return ErrShareBadSignature
+	}
+	signedBytes, err := sp.signedBytes()
+	if err != nil {
+		return err
+	}
+	if err := sp.SenderSignKey.Verify(signedBytes, sp.Signature); err != nil {
+		return fmt.Errorf("%w: %v", ErrShareBadSignature, err)
+	}
+	if sp.ExpiresAt != "" {
+		expiresAt, err := time.Parse(time.RFC3339, sp.ExpiresAt)
+		if err != nil {
+			return fmt.Errorf("invalid expiry %q: %w", sp.ExpiresAt, err)
+		}
+		if now.After(expiresAt) {
+			return ErrShareExpired
+		}
+	}
+	return nil
+}
+
+// Open verifies the package and decrypts the item overview and details using the recipient's private key.
+func (sp *SharePackage) Open(privKey *cryptolib.JWK, now time.Time) (*VaultItemOverview, *VaultItemDetails, error) {
+	if err := sp.Verify(now); err != nil {
+		return nil, nil, err
+	}
+	if sp.RecipientKeyID != privKey.KeyID {
+		return nil, nil, ErrShareWrongKey
+	}
+	contentKey, err := sp.EncryptedKey.Unwrap(privKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to unwrap content key: %w", err)
+	}
+	defer contentKey.Close()
+	overview := &VaultItemOverview{}
+	if err := contentKey.DecryptJSON(sp.EncryptedOverview, overview); err != nil {
+		return nil, nil, fmt.Errorf("failed to decrypt item overview: %w", err)
+	}
+	details := &VaultItemDetails{}
+	if err := contentKey.DecryptJSON(sp.EncryptedDetails, details); err != nil {
+		return nil, nil, fmt.Errorf("failed to decrypt item details: %w", err)
+	}
+	return overview, details, nil
+}
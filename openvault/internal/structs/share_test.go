@@ -0,0 +1,103 @@
+package structs
+
+import (
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/BradHacker/openvault/cryptolib"
+)
+
+type testKeys struct {
+	keySet   *cryptolib.KeySet
+	privKey  *cryptolib.JWK
+	signKey  *cryptolib.JWK
+	vaultKey *cryptolib.JWK
+}
+
+func newTestKeys(t *testing.T) *testKeys {
+	t.Helper()
+	aukBytes := make([]byte, 32)
+	rand.Read(aukBytes)
+	auk, err := cryptolib.NewKey(cryptolib.AccountUnlockKeyID, aukBytes, cryptolib.KeyUseEncryption)
+	if err != nil {
+		t.Fatalf("failed to create AUK: %v", err)
+	}
+	ks, err := cryptolib.GenerateKeySet(auk, &cryptolib.Salt{}, 1000)
+	if err != nil {
+		t.Fatalf("failed to generate keyset: %v", err)
+	}
+	privKey, err := ks.PrivateKey(auk)
+	if err != nil {
+		t.Fatalf("failed to decrypt private key: %v", err)
+	}
+	signKey, err := ks.SigningKey(auk)
+	if err != nil {
+		t.Fatalf("failed to decrypt signing key: %v", err)
+	}
+	vaultKey, err := cryptolib.GenerateVaultKey()
+	if err != nil {
+		t.Fatalf("failed to generate vault key: %v", err)
+	}
+	return &testKeys{keySet: ks, privKey: privKey, signKey: signKey, vaultKey: vaultKey}
+}
+
+func sealTestPackage(t *testing.T, sender, recipient *testKeys, opts ShareOptions) *SharePackage {
+	t.Helper()
+	account := &Account{ID: "sender", Email: "sender@example.com"}
+	sp, err := SealSharePackage(account, sender.signKey, sender.keySet.PubSignKey, recipient.keySet.PubKey,
+		&VaultItemOverview{Title: "Database", URL: "https://db.example.com"},
+		&VaultItemDetails{Username: "admin", Password: "hunter2"},
+		opts,
+	)
+	if err != nil {
+		t.Fatalf("failed to seal share package: %v", err)
+	}
+	return sp
+}
+
+func TestSharePackageRoundTrip(t *testing.T) {
+	sender, recipient := newTestKeys(t), newTestKeys(t)
+	sp := sealTestPackage(t, sender, recipient, ShareOptions{})
+	overview, details, err := sp.Open(recipient.privKey, time.Now())
+	if err != nil {
+		t.Fatalf("failed to open share package: %v", err)
+	}
+	if overview.Title != "Database" || details.Password != "hunter2" {
+		t.Fatalf("unexpected item contents: %+v %+v", overview, details)
+	}
+}
+
+func TestSharePackageWrongRecipient(t *testing.T) {
+	sender, recipient, other := newTestKeys(t), newTestKeys(t), newTestKeys(t)
+	sp := sealTestPackage(t, sender, recipient, ShareOptions{})
+	_, _, err := sp.Open(other.privKey, time.Now())
+	if !errors.Is(err, ErrShareWrongKey) {
+		t.Fatalf("expected ErrShareWrongKey, got %v", err)
+	}
+}
+
+func TestSharePackageExpired(t *testing.T) {
+	sender, recipient := newTestKeys(t), newTestKeys(t)
+	sp := sealTestPackage(t, sender, recipient, ShareOptions{ExpiresAt: time.Now().Add(time.Hour)})
+	if _, _, err := sp.Open(recipient.privKey, time.Now()); err != nil {
+		t.Fatalf("failed to open unexpired share package: %v", err)
+	}
+	_, _, err := sp.Open(recipient.privKey, time.Now().Add(2*time.Hour))
+	if !errors.Is(err, ErrShareExpired) {
+		t.Fatalf("expected ErrShareExpired, got %v", err)
+	}
+}
+
+func TestSharePackageTampered(t *testing.T) {
+	sender, recipient := newTestKeys(t), newTestKeys(t)
+	sp := sealTestPackage(t, sender, recipient, ShareOptions{ExpiresAt: time.Now().Add(time.Hour), OneTime: true})
+	// Removing the restrictions must invalidate the signature
+	sp.ExpiresAt = ""
+	sp.OneTime = false
+	_, _, err := sp.Open(recipient.privKey, time.Now())
+	if !errors.Is(err, ErrShareBadSignature) {
+		t.Fatalf("expected ErrShareBadSignature, got %v", err)
+	}
+}
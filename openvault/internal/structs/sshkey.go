@@ -0,0 +1,188 @@
+package structs
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/ssh"
+)
+
+type SSHKeyType string
+
+var (
+	SSHKeyTypeEd25519 SSHKeyType = "ed25519"
+	SSHKeyTypeECDSA   SSHKeyType = "ecdsa"
+	SSHKeyTypeRSA     SSHKeyType = "rsa"
+)
+
+const (
+	defaultRSABits   = 3072
+	minRSABits       = 2048
+	defaultECDSABits = 256
+)
+
+var (
+	ErrSSHPassphraseRequired = errors.New("ssh private key is protected by a passphrase")
+	ErrSSHKeyUnsupported     = errors.New("unsupported ssh key type")
+)
+
+type SSHKeyDetails struct {
+	KeyType SSHKeyType `json:"key_type"`
+	// Unencrypted private key in OpenSSH format. It is protected by the vault key like every other detail.
+	PrivateKey string `json:"private_key"`
+	// Public key in authorized_keys format
+	PublicKey   string `json:"public_key"`
+	Fingerprint string `json:"fingerprint"`
+	Comment     string `json:"comment"`
+	// Whether the private key has been removed from this copy of the details
+	Redacted bool `json:"redacted,omitempty"`
+}
+
+// SSHKeyOverview is the public half of an SSH key, stored in the overview so keys can be listed
+// without decrypting their details.
+type SSHKeyOverview struct {
+	KeyType     SSHKeyType `json:"key_type"`
+	PublicKey   string     `json:"public_key"`
+	Fingerprint string     `json:"fingerprint"`
+}
+
+// GenerateSSHKey generates a new SSH key pair. Bits selects the RSA modulus size (default 3072) or the
+// ECDSA curve (256, 384 or 521; default 256) and is ignored for Ed25519 keys.
+func GenerateSSHKey(keyType SSHKeyType, bits int, comment string) (*SSHKeyDetails, error) {
+	var key crypto.PrivateKey
+	var err error
+	switch keyType {
+	case SSHKeyTypeEd25519, "":
+		_, key, err = ed25519.GenerateKey(rand.Reader)
+	case SSHKeyTypeECDSA:
+		var curve elliptic.Curve
+		switch bits {
+		case 0, 256:
+			curve = elliptic.P256()
+		case 384:
+			curve = elliptic.P384()
+		case 521:
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("%w: ECDSA keys must be 256, 384 or 521 bits", ErrSSHKeyUnsupported)
+		}
+		key, err = ecdsa.GenerateKey(curve, rand.Reader)
+	case SSHKeyTypeRSA:
+		if bits == 0 {
+			bits = defaultRSABits
+		}
+		if bits < minRSABits {
+			return nil, fmt.Errorf("%w: RSA keys must be at least %d bits", ErrSSHKeyUnsupported, minRSABits)
+		}
+		key, err = rsa.GenerateKey(rand.Reader, bits)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrSSHKeyUnsupported, keyType)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate ssh key: %w", err)
+	}
+	return newSSHKeyDetails(key, comment)
+}
+
+// ImportSSHKey parses an existing private key in OpenSSH or PEM (PKCS#1, PKCS#8, SEC 1) format. The passphrase
+// is only used if the key is encrypted, in which case ErrSSHPassphraseRequired is returned if it is empty.
+// The key is re-encoded without a passphrase since it is stored encrypted by the vault key.
+func ImportSSHKey(data []byte, passphrase []byte, comment string) (*SSHKeyDetails, error) {
+	key, err := ssh.ParseRawPrivateKey(data)
+	var missing *ssh.PassphraseMissingError
+	if errors.As(err, &missing) {
+		if len(passphrase) == 0 {
+			return nil, ErrSSHPassphraseRequired
+		}
+		key, err = ssh.ParseRawPrivateKeyWithPassphrase(data, passphrase)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ssh private key: %w", err)
+	}
+	// Ed25519 keys are parsed as pointers but marshalled as values
+	if k, ok := key.(*ed25519.PrivateKey); ok {
+		key = *k
+	}
+	return newSSHKeyDetails(key, comment)
+}
+
+func newSSHKeyDetails(key crypto.PrivateKey, comment string) (*SSHKeyDetails, error) {
+	block, err := ssh.MarshalPrivateKey(key, comment)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSSHKeyUnsupported, err)
+	}
+	details := &SSHKeyDetails{
+		PrivateKey: string(pem.EncodeToMemory(block)),
+		Comment:    comment,
+	}
+	if err := details.normalize(); err != nil {
+		return nil, err
+	}
+	return details, nil
+}
+
+// Signer parses the private key into a signer
+func (s *SSHKeyDetails) Signer() (ssh.Signer, error) {
+	if s.PrivateKey == "" {
+		return nil, fmt.Errorf("%w: ssh private key is missing", ErrInvalidDetails)
+	}
+	signer, err := ssh.ParsePrivateKey([]byte(s.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid ssh private key: %v", ErrInvalidDetails, err)
+	}
+	return signer, nil
+}
+
+// normalize derives the key type, public key and fingerprint from the private key
+func (s *SSHKeyDetails) normalize() error {
+	signer, err := s.Signer()
+	if err != nil {
+		return err
+	}
+	pub := signer.PublicKey()
+	switch pub.Type() {
+	case ssh.KeyAlgoED25519:
+		s.KeyType = SSHKeyTypeEd25519
+	case ssh.KeyAlgoECDSA256, ssh.KeyAlgoECDSA384, ssh.KeyAlgoECDSA521:
+		s.KeyType = SSHKeyTypeECDSA
+	case ssh.KeyAlgoRSA:
+		s.KeyType = SSHKeyTypeRSA
+	default:
+		return fmt.Errorf("%w: %s", ErrSSHKeyUnsupported, pub.Type())
+	}
+	s.PublicKey = strings.TrimSpace(string(ssh.MarshalAuthorizedKey(pub)))
+	if s.Comment != "" {
+		s.PublicKey += " " + s.Comment
+	}
+	s.Fingerprint = ssh.FingerprintSHA256(pub)
+	return nil
+}
+
+// Validate checks that the private key can be parsed and matches the stored public key
+func (s *SSHKeyDetails) Validate() error {
+	signer, err := s.Signer()
+	if err != nil {
+		return err
+	}
+	if ssh.FingerprintSHA256(signer.PublicKey()) != s.Fingerprint {
+		return fmt.Errorf("%w: ssh fingerprint does not match the private key", ErrInvalidDetails)
+	}
+	return nil
+}
+
+// Overview returns the public half of the key
+func (s *SSHKeyDetails) Overview() *SSHKeyOverview {
+	return &SSHKeyOverview{
+		KeyType:     s.KeyType,
+		PublicKey:   s.PublicKey,
+		Fingerprint: s.Fingerprint,
+	}
+}
@@ -0,0 +1,110 @@
+package structs
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"strings"
+	"testing"
+
+	"golang.org/x/crypto/ssh"
+)
+
+func TestGenerateSSHKey(t *testing.T) {
+	tests := []struct {
+		keyType   SSHKeyType
+		bits      int
+		prefix    string
+		expectErr bool
+	}{
+		{keyType: SSHKeyTypeEd25519, prefix: "ssh-ed25519 "},
+		{keyType: SSHKeyTypeECDSA, bits: 384, prefix: "ecdsa-sha2-nistp384 "},
+		{keyType: SSHKeyTypeRSA, bits: 2048, prefix: "ssh-rsa "},
+		{keyType: SSHKeyTypeECDSA, bits: 512, expectErr: true},
+		{keyType: SSHKeyTypeRSA, bits: 1024, expectErr: true},
+		{keyType: "dsa", expectErr: true},
+	}
+	for _, tt := range tests {
+		key, err := GenerateSSHKey(tt.keyType, tt.bits, "alice@example")
+		if tt.expectErr {
+			if err == nil {
+				t.Fatalf("expected error generating %s/%d key, but got none", tt.keyType, tt.bits)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("failed to generate %s key: %v", tt.keyType, err)
+		}
+		if key.KeyType != tt.keyType {
+			t.Fatalf("expected key type %s, got %s", tt.keyType, key.KeyType)
+		}
+		if !strings.HasPrefix(key.PublicKey, tt.prefix) || !strings.HasSuffix(key.PublicKey, " alice@example") {
+			t.Fatalf("unexpected public key %q", key.PublicKey)
+		}
+		if !strings.HasPrefix(key.Fingerprint, "SHA256:") {
+			t.Fatalf("unexpected fingerprint %q", key.Fingerprint)
+		}
+		if err := key.Validate(); err != nil {
+			t.Fatalf("generated key failed validation: %v", err)
+		}
+	}
+}
+
+func TestImportSSHKeyWithPassphrase(t *testing.T) {
+	_, priv, _ := ed25519.GenerateKey(rand.Reader)
+	block, err := ssh.MarshalPrivateKeyWithPassphrase(priv, "", []byte("correct horse"))
+	if err != nil {
+		t.Fatalf("failed to marshal key: %v", err)
+	}
+	data := pem.EncodeToMemory(block)
+
+	if _, err := ImportSSHKey(data, nil, ""); !errors.Is(err, ErrSSHPassphraseRequired) {
+		t.Fatalf("expected ErrSSHPassphraseRequired, got %v", err)
+	}
+	if _, err := ImportSSHKey(data, []byte("wrong"), ""); err == nil {
+		t.Fatal("expected error importing with the wrong passphrase, but got none")
+	}
+	key, err := ImportSSHKey(data, []byte("correct horse"), "imported")
+	if err != nil {
+		t.Fatalf("failed to import key: %v", err)
+	}
+	signer, _ := ssh.NewSignerFromKey(priv)
+	if key.Fingerprint != ssh.FingerprintSHA256(signer.PublicKey()) {
+		t.Fatalf("imported fingerprint %s does not match original key", key.Fingerprint)
+	}
+	// The stored key must not need the passphrase
+	if _, err := key.Signer(); err != nil {
+		t.Fatalf("failed to parse stored key: %v", err)
+	}
+}
+
+func TestSSHKeyOverviewAndRedaction(t *testing.T) {
+	key, err := GenerateSSHKey(SSHKeyTypeEd25519, 0, "")
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	// A tampered fingerprint is recomputed from the private key
+	key.Fingerprint = "SHA256:bogus"
+	overview := &VaultItemOverview{Title: "deploy key"}
+	details := &VaultItemDetails{Category: CategorySSHKey, SSH: key}
+	if err := NormalizeItem(overview, details); err != nil {
+		t.Fatalf("failed to normalize item: %v", err)
+	}
+	if overview.SSHKey == nil || overview.SSHKey.Fingerprint != details.SSH.Fingerprint || overview.SSHKey.Fingerprint == "SHA256:bogus" {
+		t.Fatalf("expected overview to hold the derived fingerprint, got %+v", overview.SSHKey)
+	}
+
+	redacted := details.Redacted()
+	if redacted.SSH.PrivateKey != "" || !redacted.SSH.Redacted || details.SSH.PrivateKey == "" {
+		t.Fatal("expected only the redacted copy to lose its private key")
+	}
+	redacted.RestoreRedacted(details)
+	if redacted.SSH.PrivateKey != details.SSH.PrivateKey || redacted.SSH.Redacted {
+		t.Fatal("expected private key to be restored")
+	}
+
+	if err := (&VaultItemDetails{Category: CategoryLogin, SSH: key}).Validate(); !errors.Is(err, ErrInvalidDetails) {
+		t.Fatalf("expected ErrInvalidDetails for SSH fields on a login, got %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package structs
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// TagSeparator separates the levels of hierarchical tags, e.g. "prod/db" is nested under "prod"
+const TagSeparator = "/"
+
+// Limits on user-provided names
+const (
+	MaxTagLength        = 128
+	MaxFolderNameLength = 128
+)
+
+var ErrInvalidTag = errors.New("invalid tag")
+
+// Folder groups items within a single vault. Folders are stored in the encrypted vault metadata.
+type Folder struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// NormalizeTag trims the whitespace around each level of a tag and drops empty levels, so " prod//db " becomes
+// "prod/db". It returns an empty string if nothing is left.
+func NormalizeTag(tag string) (string, error) {
+	var levels []string
+	for _, level := range strings.Split(tag, TagSeparator) {
+		level = strings.TrimSpace(level)
+		if level == "" {
+			continue
+		}
+		if strings.ContainsFunc(level, unicode.IsControl) {
+			return "", fmt.Errorf("%w: %q contains control characters", ErrInvalidTag, tag)
+		}
+		levels = append(levels, level)
+	}
+	normalized := strings.Join(levels, TagSeparator)
+	if len(normalized) > MaxTagLength {
+		return "", fmt.Errorf("%w: tags cannot be longer than %d bytes", ErrInvalidTag, MaxTagLength)
+	}
+	return normalized, nil
+}
+
+// NormalizeTags normalizes each tag, drops empty tags and duplicates (ignoring case, keeping the first spelling)
+// and sorts the rest
+func NormalizeTags(tags []string) ([]string, error) {
+	var normalized []string
+	for _, tag := range tags {
+		tag, err := NormalizeTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		if tag == "" || slices.ContainsFunc(normalized, func(t string) bool { return strings.EqualFold(t, tag) }) {
+			continue
+		}
+		normalized = append(normalized, tag)
+	}
+	slices.SortFunc(normalized, func(x, y string) int {
+		return strings.Compare(strings.ToLower(x), strings.ToLower(y))
+	})
+	return normalized, nil
+}
+
+// TagWithin returns whether tag is parent or nested under it, ignoring case. Both must be normalized.
+func TagWithin(tag string, parent string) bool {
+	if len(tag) < len(parent) || !strings.EqualFold(tag[:len(parent)], parent) {
+		return false
+	}
+	return len(tag) == len(parent) || strings.HasPrefix(tag[len(parent):], TagSeparator)
+}
+
+// TagAncestors returns the tags a tag is nested under, outermost first, e.g. "prod" for "prod/db"
+func TagAncestors(tag string) []string {
+	var ancestors []string
+	for i, r := range tag {
+		if string(r) == TagSeparator {
+			ancestors = append(ancestors, tag[:i])
+		}
+	}
+	return ancestors
+}
+
+// NormalizeFolderName trims a folder name and checks it is usable
+func NormalizeFolderName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("folder name cannot be empty")
+	}
+	if len(name) > MaxFolderNameLength {
+		return "", fmt.Errorf("folder names cannot be longer than %d bytes", MaxFolderNameLength)
+	}
+	if strings.ContainsFunc(name, unicode.IsControl) {
+		return "", fmt.Errorf("folder name contains control characters")
+	}
+	return name, nil
+}
+
+// RestoreOrganization copies the tags, favorite flag and folder of current into an overview which leaves them
+// unset, so an item can be edited by a client which does not know about them. They are cleared with SetItemTags,
+// SetItemFavorite and SetItemFolder.
+func (vo *VaultItemOverview) RestoreOrganization(current *VaultItemOverview) {
+	if len(vo.Tags) == 0 {
+		vo.Tags = slices.Clone(current.Tags)
+	}
+	if !vo.Favorite {
+		vo.Favorite = current.Favorite
+	}
+	if vo.FolderID == "" {
+		vo.FolderID = current.FolderID
+	}
+}
@@ -0,0 +1,70 @@
+package structs
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tests := []struct {
+		tags      []string
+		expected  []string
+		expectErr bool
+	}{
+		{[]string{" prod / db ", "prod//web/", "/"}, []string{"prod/db", "prod/web"}, false},
+		{[]string{"Work", "work", "personal"}, []string{"personal", "Work"}, false},
+		{[]string{"", "  "}, nil, false},
+		{[]string{"bad\ttag"}, nil, true},
+	}
+	for _, tt := range tests {
+		tags, err := NormalizeTags(tt.tags)
+		if tt.expectErr {
+			if !errors.Is(err, ErrInvalidTag) {
+				t.Fatalf("NormalizeTags(%q): expected ErrInvalidTag, got %v", tt.tags, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("NormalizeTags(%q): unexpected error: %v", tt.tags, err)
+		}
+		if !slices.Equal(tags, tt.expected) {
+			t.Fatalf("NormalizeTags(%q) = %q, expected %q", tt.tags, tags, tt.expected)
+		}
+	}
+}
+
+func TestTagWithin(t *testing.T) {
+	tests := []struct {
+		tag, parent string
+		within      bool
+	}{
+		{"prod", "prod", true},
+		{"prod/db", "prod", true},
+		{"Prod/DB", "prod/db", true},
+		{"production", "prod", false},
+		{"prod", "prod/db", false},
+	}
+	for _, tt := range tests {
+		if got := TagWithin(tt.tag, tt.parent); got != tt.within {
+			t.Fatalf("TagWithin(%q, %q) = %v, expected %v", tt.tag, tt.parent, got, tt.within)
+		}
+	}
+	if ancestors := TagAncestors("prod/db/replica"); !slices.Equal(ancestors, []string{"prod", "prod/db"}) {
+		t.Fatalf("unexpected ancestors %q", ancestors)
+	}
+}
+
+func TestRestoreOrganization(t *testing.T) {
+	current := &VaultItemOverview{Tags: []string{"prod"}, Favorite: true, FolderID: "folder-a"}
+	overview := &VaultItemOverview{Title: "Renamed"}
+	overview.RestoreOrganization(current)
+	if !slices.Equal(overview.Tags, current.Tags) || !overview.Favorite || overview.FolderID != "folder-a" {
+		t.Fatalf("expected unset tags, favorite and folder to be kept, got %+v", overview)
+	}
+	overview = &VaultItemOverview{Tags: []string{"dev"}, FolderID: "folder-b"}
+	overview.RestoreOrganization(current)
+	if !slices.Equal(overview.Tags, []string{"dev"}) || overview.FolderID != "folder-b" {
+		t.Fatalf("expected explicitly set tags and folder to be kept, got %+v", overview)
+	}
+}
@@ -2,6 +2,7 @@ package structs
 
 import (
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"github.com/BradHacker/openvault/cryptolib"
@@ -12,10 +13,17 @@ type Vault struct {
 	AccountID         string         `json:"account_id"`
 	EncryptedMetadata *cryptolib.JWE `json:"encrypted_metadata"`
 	EncryptedVaultKey *cryptolib.JWE `json:"encrypted_vault_key"`
+	// When the vault was moved to the trash (empty if not trashed)
+	TrashedAt string `json:"trashed_at,omitempty"`
+}
+
+// IsTrashed returns whether the vault is in the trash
+func (v *Vault) IsTrashed() bool {
+	return v.TrashedAt != ""
 }
 
 // DecryptVaultKey decrypts the vault key using the vault's encrypted vault key and the keyset's private key
-func (v *Vault) decryptVaultKey(privKey *cryptolib.JWK) (*cryptolib.JWK, error) {
+func (v *Vault) DecryptVaultKey(privKey *cryptolib.JWK) (*cryptolib.JWK, error) {
 	// Decrypt the vault key using the vault's encrypted vault key and the keyset's private key
 	vaultKey, err := v.EncryptedVaultKey.Unwrap(privKey)
 	if err != nil {
@@ -27,7 +35,7 @@ func (v *Vault) decryptVaultKey(privKey *cryptolib.JWK) (*cryptolib.JWK, error)
 // DecryptMetadata decrypts the vault metadata using the provided private key
 func (v *Vault) DecryptMetadata(privKey *cryptolib.JWK) (*VaultMetadata, error) {
 	// Decrypt the vault key
-	vaultKey, err := v.decryptVaultKey(privKey)
+	vaultKey, err := v.DecryptVaultKey(privKey)
 	if err != nil {
 		return nil, err
 	}
@@ -48,7 +56,7 @@ func (v *Vault) DecryptMetadata(privKey *cryptolib.JWK) (*VaultMetadata, error)
 // DecryptItemOverviews decrypts the vault item overviews using the provided private key
 func (v *Vault) DecryptItemOverviews(privKey *cryptolib.JWK, encryptedOverviews ...*EncryptedVaultItemOverview) ([]*VaultItemOverview, error) {
 	// Decrypt the vault key
-	vaultKey, err := v.decryptVaultKey(privKey)
+	vaultKey, err := v.DecryptVaultKey(privKey)
 	if err != nil {
 		return nil, err
 	}
@@ -73,7 +81,7 @@ func (v *Vault) DecryptItemOverviews(privKey *cryptolib.JWK, encryptedOverviews
 // DecryptItemDetails decrypts the vault item details using the provided private key
 func (v *Vault) DecryptItemDetails(privKey *cryptolib.JWK, encryptedDetails *EncryptedVaultItemDetails) (*VaultItemDetails, error) {
 	// Decrypt the vault key
-	vaultKey, err := v.decryptVaultKey(privKey)
+	vaultKey, err := v.DecryptVaultKey(privKey)
 	if err != nil {
 		return nil, err
 	}
@@ -98,6 +106,8 @@ type VaultMetadata struct {
 	Description string `json:"description"`
 	CreatedAt   string `json:"created_at"`
 	UpdatedAt   string `json:"updated_at"`
+	// Folders items in the vault can be filed into
+	Folders []*Folder `json:"folders,omitempty"`
 	// Members     []string `json:"members"`
 }
 
@@ -115,11 +125,46 @@ type EncryptedVaultItemOverview struct {
 	CreatedAt         string         `json:"created_at"`
 	UpdatedAt         string         `json:"updated_at"`
 	EncryptedOverview *cryptolib.JWE `json:"encrypted_overview"`
+	// When the item was moved to the trash (empty if not trashed)
+	TrashedAt string `json:"trashed_at,omitempty"`
+}
+
+// IsTrashed returns whether the item is in the trash
+func (vio *EncryptedVaultItemOverview) IsTrashed() bool {
+	return vio.TrashedAt != ""
 }
 
 type VaultItemOverview struct {
-	Title string `json:"title"`
-	URL   string `json:"url"`
+	Category ItemCategory `json:"category"`
+	Title    string       `json:"title"`
+	URL      string       `json:"url"`
+	// Public half of the key for SSH key items
+	SSHKey *SSHKeyOverview `json:"ssh_key,omitempty"`
+	// Hierarchical tags, e.g. "prod/db"
+	Tags     []string `json:"tags,omitempty"`
+	Favorite bool     `json:"favorite,omitempty"`
+	// ID of the folder in the item's vault the item is filed into (empty if unfiled)
+	FolderID string `json:"folder_id,omitempty"`
+}
+
+// ItemFlag marks an item which needs attention. Flags are worked out while the app runs and are never saved.
+type ItemFlag string
+
+var (
+	// The item's password was found in the Pwned Passwords list
+	ItemFlagBreached ItemFlag = "breached"
+)
+
+// UnmarshalJSON decodes the overview, treating items written before categories existed as logins
+func (vo *VaultItemOverview) UnmarshalJSON(data []byte) error {
+	type overview VaultItemOverview
+	if err := json.Unmarshal(data, (*overview)(vo)); err != nil {
+		return err
+	}
+	if vo.Category == "" {
+		vo.Category = CategoryLogin
+	}
+	return nil
 }
 
 func (vio *EncryptedVaultItemOverview) Update(vaultKey *cryptolib.JWK, data *VaultItemOverview) (err error) {
@@ -142,10 +187,84 @@ type EncryptedVaultItemDetails struct {
 	EncryptedDetails *cryptolib.JWE `json:"encrypted_details"`
 }
 
+// VaultItemDetailsVersion is the current version of the item details payload.
+//
+// Version 0 payloads predate categories and only contain login fields.
+const VaultItemDetailsVersion = 1
+
 type VaultItemDetails struct {
+	Version  int          `json:"version"`
+	Category ItemCategory `json:"category"`
+	// Login fields
 	Username string `json:"username"`
 	Password string `json:"password"`
-	Notes    string `json:"notes"`
+	// Notes are available for every category and hold the body of secure notes
+	Notes    string                `json:"notes"`
+	Card     *CreditCardDetails    `json:"card,omitempty"`
+	Identity *IdentityDetails      `json:"identity,omitempty"`
+	API      *APICredentialDetails `json:"api,omitempty"`
+	SSH      *SSHKeyDetails        `json:"ssh,omitempty"`
+	// Ordered sections of custom fields
+	Sections []*Section `json:"sections,omitempty"`
+}
+
+// UnmarshalJSON decodes the details, upgrading older payloads to the current version
+func (vd *VaultItemDetails) UnmarshalJSON(data []byte) error {
+	type details VaultItemDetails
+	if err := json.Unmarshal(data, (*details)(vd)); err != nil {
+		return err
+	}
+	if vd.Version == 0 {
+		vd.Version = VaultItemDetailsVersion
+		vd.Category = CategoryLogin
+	}
+	return nil
+}
+
+// Validate checks that the category is supported, that only the fields of that category are set and
+// that the typed and custom fields are well formed.
+func (vd *VaultItemDetails) Validate() error {
+	if !vd.Category.IsValid() {
+		return fmt.Errorf("%w: %q", ErrInvalidCategory, vd.Category)
+	}
+	if vd.Card != nil && vd.Category != CategoryCreditCard {
+		return fmt.Errorf("%w: card fields are only allowed on credit cards", ErrInvalidDetails)
+	}
+	if vd.Identity != nil && vd.Category != CategoryIdentity {
+		return fmt.Errorf("%w: identity fields are only allowed on identities", ErrInvalidDetails)
+	}
+	if vd.API != nil && vd.Category != CategoryAPICredential {
+		return fmt.Errorf("%w: API fields are only allowed on API credentials", ErrInvalidDetails)
+	}
+	if vd.SSH != nil && vd.Category != CategorySSHKey {
+		return fmt.Errorf("%w: SSH key fields are only allowed on SSH keys", ErrInvalidDetails)
+	}
+	if err := validateSections(vd.Sections); err != nil {
+		return err
+	}
+	switch vd.Category {
+	case CategoryCreditCard:
+		if vd.Card == nil {
+			return fmt.Errorf("%w: credit card details are missing", ErrInvalidDetails)
+		}
+		return vd.Card.Validate()
+	case CategoryIdentity:
+		if vd.Identity == nil {
+			return fmt.Errorf("%w: identity details are missing", ErrInvalidDetails)
+		}
+		return vd.Identity.Validate()
+	case CategoryAPICredential:
+		if vd.API == nil {
+			return fmt.Errorf("%w: API credential details are missing", ErrInvalidDetails)
+		}
+		return vd.API.Validate()
+	case CategorySSHKey:
+		if vd.SSH == nil {
+			return fmt.Errorf("%w: SSH key details are missing", ErrInvalidDetails)
+		}
+		return vd.SSH.Validate()
+	}
+	return nil
 }
 
 func (vio *EncryptedVaultItemDetails) Update(vaultKey *cryptolib.JWK, data *VaultItemDetails) (err error) {
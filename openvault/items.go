@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+)
+
+// CreateItem encrypts a new item with the vault key and saves it to the given vault.
+func (a *CoreService) CreateItem(vaultId string, overview structs.VaultItemOverview, details structs.VaultItemDetails) (*DecryptedVaultItemOverview, error) {
+	a.state.mu.Lock()
+	defer a.state.mu.Unlock()
+	return a.createItem(vaultId, overview, details)
+}
+
+func (a *CoreService) createItem(vaultId string, overview structs.VaultItemOverview, details structs.VaultItemDetails) (*DecryptedVaultItemOverview, error) {
+	if a.isLocked() {
+		return nil, fmt.Errorf("application not unlocked")
+	}
+	vaultKey, err := a.state.VaultKey(vaultId)
+	if err != nil {
+		return nil, err
+	}
+	defer vaultKey.Close()
+	if err := a.checkFolder(vaultId, overview.FolderID); err != nil {
+		return nil, err
+	}
+	encOverview, _, err := a.state.CreateItem(vaultId, vaultKey, &overview, &details)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.state.SaveItems(); err != nil {
+		return nil, err
+	}
+	return &DecryptedVaultItemOverview{
+		EncryptedVaultItemOverview: encOverview,
+		VaultItemOverview:          &overview,
+	}, nil
+}
+
+// UpdateItem replaces the overview and details of an item. The previous version is kept in the item's history.
+// Tags, the favorite flag and the folder are kept unless the new overview sets them.
+func (a *CoreService) UpdateItem(itemId string, overview structs.VaultItemOverview, details structs.VaultItemDetails) (*DecryptedVaultItemOverview, error) {
+	a.state.mu.Lock()
+	defer a.state.mu.Unlock()
+	return a.updateItem(itemId, overview, details)
+}
+
+func (a *CoreService) updateItem(itemId string, overview structs.VaultItemOverview, details structs.VaultItemDetails) (*DecryptedVaultItemOverview, error) {
+	if a.isLocked() {
+		return nil, fmt.Errorf("application not unlocked")
+	}
+	encOverview, ok := a.state.ItemOverviews[itemId]
+	if !ok {
+		return nil, fmt.Errorf("no item overview found for item %s", itemId)
+	}
+	encDetails, ok := a.state.ItemDetails[itemId]
+	if !ok {
+		return nil, fmt.Errorf("no item details found for item %s", itemId)
+	}
+	vault, ok := a.state.Vaults[encOverview.VaultID]
+	if !ok {
+		return nil, fmt.Errorf("vault %s not found", encOverview.VaultID)
+	}
+	vaultKey, err := a.state.VaultKey(vault.VaultID)
+	if err != nil {
+		return nil, err
+	}
+	defer vaultKey.Close()
+	// Keep the values of concealed fields which were never revealed to the caller
+	current, err := encDetails.Read(vaultKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt item details for item %s: %w", itemId, err)
+	}
+	details.RestoreRedacted(current)
+	currentOverview, err := encOverview.Read(vaultKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt item overview for item %s: %w", itemId, err)
+	}
+	overview.RestoreOrganization(currentOverview)
+	if err := a.checkFolder(vault.VaultID, overview.FolderID); err != nil {
+		return nil, err
+	}
+	if err := structs.NormalizeItem(&overview, &details); err != nil {
+		return nil, err
+	}
+	// Encrypt into copies so a failure leaves both the item and its history untouched
+	updatedOverview := *encOverview
+	if err := updatedOverview.Update(vaultKey, &overview); err != nil {
+		return nil, fmt.Errorf("failed to encrypt item overview: %w", err)
+	}
+	updatedDetails := *encDetails
+	if err := updatedDetails.Update(vaultKey, &details); err != nil {
+		return nil, fmt.Errorf("failed to encrypt item details: %w", err)
+	}
+	if err := a.state.ArchiveItem(itemId, vault.AccountID); err != nil {
+		return nil, err
+	}
+	*encOverview = updatedOverview
+	*encDetails = updatedDetails
+	if err := a.state.SaveItemHistory(); err != nil {
+		return nil, err
+	}
+	if err := a.state.SaveItems(); err != nil {
+		return nil, err
+	}
+	return &DecryptedVaultItemOverview{
+		EncryptedVaultItemOverview: encOverview,
+		VaultItemOverview:          &overview,
+	}, nil
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"embed"
 	"log"
+	"os"
 
 	"github.com/wailsapp/wails/v3/pkg/application"
 )
@@ -11,6 +12,11 @@ import (
 var assets embed.FS
 
 func main() {
+	// Run a command line mode (e.g. a credential helper) instead of the desktop app if one was requested
+	if code, ok := runCLI(os.Args); ok {
+		os.Exit(code)
+	}
+
 	// Create an instance of the app structure
 	app := application.New(application.Options{
 		Name:        "openvault",
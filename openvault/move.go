@@ -0,0 +1,190 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/BradHacker/openvault/openvault/internal/fs"
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+
+	"github.com/BradHacker/openvault/cryptolib"
+	"github.com/google/uuid"
+)
+
+type reencryptedItem struct {
+	overview *structs.EncryptedVaultItemOverview
+	details  *structs.EncryptedVaultItemDetails
+	history  []*structs.EncryptedItemRevision
+	// Attachments re-wrapped for the destination, mapped by the source attachment IDs
+	attachments map[string]*structs.EncryptedAttachment
+}
+
+// reencryptItem decrypts an item and its history under the source vault key and encrypts them under the
+// destination vault key as newItemId. Attachment file keys are re-wrapped; if newAttachmentIds is set each
+// attachment is given a new ID. The state is not modified, so a failure part way leaves the item untouched.
+func (a *CoreService) reencryptItem(itemId string, destVaultId string, newItemId string, newAttachmentIds bool) (*reencryptedItem, error) {
+	encOverview, ok := a.state.ItemOverviews[itemId]
+	if !ok {
+		return nil, fmt.Errorf("no item overview found for item %s", itemId)
+	}
+	encDetails, ok := a.state.ItemDetails[itemId]
+	if !ok {
+		return nil, fmt.Errorf("no item details found for item %s", itemId)
+	}
+	if destVault, ok := a.state.Vaults[destVaultId]; !ok {
+		return nil, fmt.Errorf("vault %s not found", destVaultId)
+	} else if destVault.IsTrashed() {
+		return nil, fmt.Errorf("vault %s is in the trash", destVaultId)
+	}
+	srcKey, err := a.state.VaultKey(encOverview.VaultID)
+	if err != nil {
+		return nil, err
+	}
+	defer srcKey.Close()
+	dstKey, err := a.state.VaultKey(destVaultId)
+	if err != nil {
+		return nil, err
+	}
+	defer dstKey.Close()
+
+	item := &reencryptedItem{attachments: make(map[string]*structs.EncryptedAttachment)}
+	item.overview, err = encOverview.Reencrypt(srcKey, dstKey, newItemId, destVaultId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to re-encrypt item overview for item %s: %w", itemId, err)
+	}
+	// Folders belong to a vault, so the item and its earlier versions are unfiled in the destination
+	changesVault := destVaultId != encOverview.VaultID
+	if changesVault {
+		if item.overview.EncryptedOverview, err = unfileOverview(dstKey, item.overview.EncryptedOverview); err != nil {
+			return nil, fmt.Errorf("failed to unfile item %s: %w", itemId, err)
+		}
+	}
+	item.details, err = encDetails.Reencrypt(srcKey, dstKey, newItemId, destVaultId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to re-encrypt item details for item %s: %w", itemId, err)
+	}
+	for _, rev := range a.state.ItemHistory[itemId] {
+		movedRev, err := rev.Reencrypt(srcKey, dstKey, newItemId, destVaultId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to re-encrypt revision %s of item %s: %w", rev.RevisionID, itemId, err)
+		}
+		if changesVault {
+			if movedRev.EncryptedOverview, err = unfileOverview(dstKey, movedRev.EncryptedOverview); err != nil {
+				return nil, fmt.Errorf("failed to unfile revision %s of item %s: %w", rev.RevisionID, itemId, err)
+			}
+		}
+		item.history = append(item.history, movedRev)
+	}
+	for _, attachment := range a.state.ItemAttachments(itemId) {
+		attachmentId := attachment.AttachmentID
+		if newAttachmentIds {
+			attachmentId = uuid.New().String()
+		}
+		movedAttachment, err := attachment.Rewrap(srcKey, dstKey, attachmentId, newItemId, destVaultId)
+		if err != nil {
+			return nil, fmt.Errorf("failed to re-wrap attachment %s of item %s: %w", attachment.AttachmentID, itemId, err)
+		}
+		item.attachments[attachment.AttachmentID] = movedAttachment
+	}
+	return item, nil
+}
+
+// unfileOverview clears the folder of an encrypted overview, returning it unchanged if it is not filed
+func unfileOverview(vaultKey *cryptolib.JWK, encOverview *cryptolib.JWE) (*cryptolib.JWE, error) {
+	var overview structs.VaultItemOverview
+	if err := vaultKey.DecryptJSON(encOverview, &overview); err != nil {
+		return nil, err
+	}
+	if overview.FolderID == "" {
+		return encOverview, nil
+	}
+	overview.FolderID = ""
+	return vaultKey.EncryptJSON(&overview)
+}
+
+// decryptOverview returns the decrypted overview of a stored item
+func (a *CoreService) decryptOverview(encOverview *structs.EncryptedVaultItemOverview) (*DecryptedVaultItemOverview, error) {
+	vaultKey, err := a.state.VaultKey(encOverview.VaultID)
+	if err != nil {
+		return nil, err
+	}
+	defer vaultKey.Close()
+	overview, err := encOverview.Read(vaultKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt item overview for item %s: %w", encOverview.ItemID, err)
+	}
+	return &DecryptedVaultItemOverview{
+		EncryptedVaultItemOverview: encOverview,
+		VaultItemOverview:          overview,
+	}, nil
+}
+
+// MoveItem moves an item, including its history, to another vault. The destination vault may belong to
+// any unlocked account. The item keeps its ID and timestamps.
+func (a *CoreService) MoveItem(itemId string, destVaultId string) (*DecryptedVaultItemOverview, error) {
+	a.state.mu.Lock()
+	defer a.state.mu.Unlock()
+	if a.isLocked() {
+		return nil, fmt.Errorf("application not unlocked")
+	}
+	if encOverview, ok := a.state.ItemOverviews[itemId]; ok && encOverview.VaultID == destVaultId {
+		return a.decryptOverview(encOverview)
+	}
+	item, err := a.reencryptItem(itemId, destVaultId, itemId, false)
+	if err != nil {
+		return nil, err
+	}
+	a.state.ItemOverviews[itemId] = item.overview
+	a.state.ItemDetails[itemId] = item.details
+	if len(item.history) > 0 {
+		a.state.ItemHistory[itemId] = item.history
+	}
+	for _, attachment := range item.attachments {
+		a.state.Attachments[attachment.AttachmentID] = attachment
+	}
+	if err := a.state.SaveAll(); err != nil {
+		return nil, err
+	}
+	return a.decryptOverview(item.overview)
+}
+
+// CopyItem copies an item, including its history, to another vault under a new item ID. The destination
+// vault may belong to any unlocked account. The copy keeps the original timestamps.
+func (a *CoreService) CopyItem(itemId string, destVaultId string) (*DecryptedVaultItemOverview, error) {
+	a.state.mu.Lock()
+	defer a.state.mu.Unlock()
+	if a.isLocked() {
+		return nil, fmt.Errorf("application not unlocked")
+	}
+	newItemId := uuid.New().String()
+	item, err := a.reencryptItem(itemId, destVaultId, newItemId, true)
+	if err != nil {
+		return nil, err
+	}
+	// Copy the attachment blobs before touching the state, cleaning up on failure
+	var copied []string
+	for srcAttachmentId, attachment := range item.attachments {
+		if err := fs.CopyAttachmentBlob(srcAttachmentId, attachment.AttachmentID); err != nil {
+			for _, attachmentId := range copied {
+				fs.RemoveAttachmentBlob(attachmentId)
+			}
+			return nil, fmt.Errorf("failed to copy attachment %s: %w", srcAttachmentId, err)
+		}
+		copied = append(copied, attachment.AttachmentID)
+	}
+	item.overview.TrashedAt = ""
+	for _, rev := range item.history {
+		rev.RevisionID = uuid.New().String()
+	}
+	a.state.ItemOverviews[newItemId] = item.overview
+	a.state.ItemDetails[newItemId] = item.details
+	if len(item.history) > 0 {
+		a.state.ItemHistory[newItemId] = item.history
+	}
+	for _, attachment := range item.attachments {
+		a.state.Attachments[attachment.AttachmentID] = attachment
+	}
+	if err := a.state.SaveAll(); err != nil {
+		return nil, err
+	}
+	return a.decryptOverview(item.overview)
+}
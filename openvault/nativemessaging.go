@@ -0,0 +1,63 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+
+	"github.com/BradHacker/openvault/openvault/internal/constants"
+	"github.com/BradHacker/openvault/openvault/internal/nativemsg"
+)
+
+// nativeMessagingExtension returns the extension which started the host from the arguments the browser passes:
+// Chrome passes the extension's origin and Firefox passes the manifest path followed by the extension ID
+func nativeMessagingExtension(args []string) (string, error) {
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "chrome-extension://") {
+			return strings.TrimSuffix(arg, "/"), nil
+		}
+	}
+	if len(args) >= 2 && strings.HasSuffix(args[0], ".json") {
+		return args[1], nil
+	}
+	return "", fmt.Errorf("usage: openvault-native-messaging-host <chrome-extension://id/ | manifest.json extension-id>")
+}
+
+// runNativeMessagingHost is started by the browser for the OpenVault extension (see the native messaging host
+// manifest for Chrome and Firefox). It relays the extension's messages to the running desktop app, which holds
+// the unlocked vaults and asks the user to approve requests.
+func runNativeMessagingHost(core *CoreService, args []string, getenv func(string) string, stdin io.Reader, stdout io.Writer) error {
+	extension, err := nativeMessagingExtension(args)
+	if err != nil {
+		return err
+	}
+	conn, err := net.Dial("unix", constants.BROWSER_SOCKET)
+	if err != nil {
+		// Answer the pending request so the extension can tell the user to start the app
+		var req browserRequest
+		if readErr := nativemsg.ReadMessage(stdin, &req); readErr == nil {
+			nativemsg.WriteMessage(stdout, &browserResponse{
+				ID:     req.ID,
+				Error:  "openvault is not running or browser integration is disabled",
+				Locked: true,
+			})
+		}
+		return fmt.Errorf("failed to connect to openvault: %w", err)
+	}
+	defer conn.Close()
+	if err := nativemsg.WriteMessage(conn, &browserHello{Extension: extension}); err != nil {
+		return fmt.Errorf("failed to connect to openvault: %w", err)
+	}
+	// Both sides use the same framing, so messages are copied as is. The browser closes stdin when the extension
+	// disconnects, which ends the connection to the app.
+	go func() {
+		io.Copy(conn, stdin)
+		conn.Close()
+	}()
+	if _, err := io.Copy(stdout, conn); err != nil && !errors.Is(err, net.ErrClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/BradHacker/openvault/openvault/internal/search"
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+
+	"github.com/BradHacker/openvault/cryptolib"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultSearchLimit = 50
+	maxSearchLimit     = 500
+)
+
+// itemSearchIndex is the search index of the unlocked items along with what each item was indexed from
+type itemSearchIndex struct {
+	index *search.Index
+	// The encrypted overview and details each item was indexed from. Updating an item encrypts it again, which
+	// replaces both, so an item needs indexing again whenever they differ from the item's current ones.
+	indexed map[string][2]*cryptolib.JWE
+}
+
+// RefreshSearchIndex indexes the items added or changed since the last refresh and drops removed items. Items in
+// locked accounts are left out until their account is unlocked. It does nothing if there is no search index.
+func (s *State) RefreshSearchIndex() {
+	if s.Search == nil {
+		return
+	}
+	vaultKeys := make(map[string]*cryptolib.JWK)
+	defer func() {
+		for _, vaultKey := range vaultKeys {
+			if vaultKey != nil {
+				vaultKey.Close()
+			}
+		}
+	}()
+	for itemId, encOverview := range s.ItemOverviews {
+		encDetails, ok := s.ItemDetails[itemId]
+		if !ok {
+			continue
+		}
+		source := [2]*cryptolib.JWE{encOverview.EncryptedOverview, encDetails.EncryptedDetails}
+		if indexed, ok := s.Search.indexed[itemId]; ok && indexed == source {
+			continue
+		}
+		vaultKey, ok := vaultKeys[encOverview.VaultID]
+		if !ok {
+			// A nil key marks a vault whose account is locked
+			vaultKey, _ = s.VaultKey(encOverview.VaultID)
+			vaultKeys[encOverview.VaultID] = vaultKey
+		}
+		if vaultKey == nil {
+			continue
+		}
+		overview, err := encOverview.Read(vaultKey)
+		if err != nil {
+			logrus.Errorf("failed to decrypt item overview for item %s: %v", itemId, err)
+			continue
+		}
+		details, err := encDetails.Read(vaultKey)
+		if err != nil {
+			logrus.Errorf("failed to decrypt item details for item %s: %v", itemId, err)
+			continue
+		}
+		s.Search.index.Add(itemId, &search.Document{
+			Title:    overview.Title,
+			URL:      overview.URL,
+			Username: details.Username,
+			Notes:    details.Notes,
+			Tags:     overview.Tags,
+			Category: string(overview.Category),
+		})
+		s.Search.indexed[itemId] = source
+	}
+	for itemId := range s.Search.indexed {
+		if _, ok := s.ItemOverviews[itemId]; !ok {
+			s.Search.index.Remove(itemId)
+			delete(s.Search.indexed, itemId)
+		}
+	}
+}
+
+// buildSearchIndex replaces the search index with one of every unlocked item
+func (a *CoreService) buildSearchIndex() {
+	a.state.Search = &itemSearchIndex{
+		index:   search.NewIndex(),
+		indexed: make(map[string][2]*cryptolib.JWE),
+	}
+	a.state.RefreshSearchIndex()
+	logrus.Printf("indexed %d items for search", a.state.Search.index.Len())
+}
+
+type SearchFilters struct {
+	// Only return items in these vaults (all vaults if empty)
+	VaultIDs []string `json:"vault_ids"`
+	// Only return items of these categories (all categories if empty)
+	Categories     []structs.ItemCategory `json:"categories"`
+	IncludeTrashed bool                   `json:"include_trashed"`
+	// Number of results to skip
+	Offset int `json:"offset"`
+	// Maximum number of results to return (defaults to 50)
+	Limit int `json:"limit"`
+}
+
+type SearchResult struct {
+	*DecryptedVaultItemOverview
+	Score float64 `json:"score"`
+}
+
+type SearchResults struct {
+	// Number of matching items across every page
+	Total int             `json:"total"`
+	Items []*SearchResult `json:"items"`
+}
+
+// SearchItems searches the titles, URLs, usernames, tags and notes of the unlocked items. Every word of the query
+// must match a word of the item exactly, as a prefix or, for longer words, with a typo. Results are ranked by how
+// well and where they match; an empty query returns every item sorted by title.
+func (a *CoreService) SearchItems(query string, filters SearchFilters) (*SearchResults, error) {
+	a.state.mu.Lock()
+	defer a.state.mu.Unlock()
+	if a.isLocked() {
+		return nil, fmt.Errorf("application not unlocked")
+	}
+	if filters.Offset < 0 || filters.Limit < 0 {
+		return nil, fmt.Errorf("offset and limit cannot be negative")
+	}
+	limit := filters.Limit
+	if limit == 0 {
+		limit = defaultSearchLimit
+	}
+	limit = min(limit, maxSearchLimit)
+	if a.state.Search == nil {
+		a.buildSearchIndex()
+	} else {
+		a.state.RefreshSearchIndex()
+	}
+
+	hits := a.state.Search.index.Search(query, func(itemId string, category string) bool {
+		encOverview, ok := a.state.ItemOverviews[itemId]
+		if !ok || !filters.IncludeTrashed && a.state.IsItemTrashed(encOverview) {
+			return false
+		}
+		if len(filters.VaultIDs) > 0 && !slices.Contains(filters.VaultIDs, encOverview.VaultID) {
+			return false
+		}
+		return len(filters.Categories) == 0 || slices.Contains(filters.Categories, structs.ItemCategory(category))
+	})
+	results := &SearchResults{Total: len(hits), Items: make([]*SearchResult, 0)}
+	if filters.Offset >= len(hits) {
+		return results, nil
+	}
+	// Only the overviews of the requested page are decrypted
+	for _, hit := range hits[filters.Offset:min(filters.Offset+limit, len(hits))] {
+		overview, err := a.getItemOverview(hit.ID)
+		if err != nil {
+			return nil, err
+		}
+		results.Items = append(results.Items, &SearchResult{DecryptedVaultItemOverview: overview, Score: hit.Score})
+	}
+	return results, nil
+}
@@ -0,0 +1,323 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/BradHacker/openvault/openvault/internal/secretservice"
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/sirupsen/logrus"
+)
+
+// Title of the section holding the lookup attributes of items stored through the Secret Service
+const secretServiceSection = "Secret Service Attributes"
+
+var errSecretServiceDenied = errors.New("the request was denied")
+
+// secretServiceItemCache holds the Secret Service items of a vault. Building it decrypts the details of every item
+// in the vault, so it is kept until the items change rather than rebuilt for every lookup. Requests holding the
+// state lock for reading may fill it at the same time, so it has a lock of its own.
+type secretServiceItemCache struct {
+	mu      sync.Mutex
+	vaultId string
+	items   []*secretservice.Item
+	byId    map[string]*secretservice.Item
+}
+
+// get returns the items of the vault and the same items mapped by their IDs, building them first if needed
+func (c *secretServiceItemCache) get(vaultId string, build func() ([]*secretservice.Item, error)) ([]*secretservice.Item, map[string]*secretservice.Item, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.byId == nil || c.vaultId != vaultId {
+		items, err := build()
+		if err != nil {
+			return nil, nil, err
+		}
+		c.vaultId = vaultId
+		c.items = items
+		c.byId = make(map[string]*secretservice.Item, len(items))
+		for _, item := range items {
+			c.byId[item.ID] = item
+		}
+	}
+	return c.items, c.byId, nil
+}
+
+// clear drops the cached items. The caller holds the state lock for writing.
+func (c *secretServiceItemCache) clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.vaultId = ""
+	c.items = nil
+	c.byId = nil
+}
+
+// secretServiceBackend exposes the items of the secret service vault to the Secret Service API. Secrets are kept in
+// the password of login items and their attributes in a section of text fields.
+type secretServiceBackend struct {
+	core *CoreService
+	// Closed when the Secret Service stops, which denies requests waiting for approval
+	done chan struct{}
+}
+
+func (b *secretServiceBackend) vaultId() string {
+	return b.core.state.Settings.SecretServiceVaultID
+}
+
+func (b *secretServiceBackend) IsLocked() bool {
+	b.core.state.mu.RLock()
+	defer b.core.state.mu.RUnlock()
+	return b.core.isSecretServiceLocked()
+}
+
+// Lock locks the app once the user approves it, as the whole app locks rather than only the Secret Service vault
+func (b *secretServiceBackend) Lock() error {
+	if !askApproval("Secret Service", "Allow an application to lock OpenVault? Every vault will be locked.", b.done) {
+		return errSecretServiceDenied
+	}
+	b.core.state.mu.Lock()
+	defer b.core.state.mu.Unlock()
+	// The Secret Service may have been stopped while the prompt was open
+	select {
+	case <-b.done:
+		return errSecretServiceDenied
+	default:
+	}
+	return b.core.lock()
+}
+
+func (b *secretServiceBackend) Label() string {
+	b.core.state.mu.RLock()
+	defer b.core.state.mu.RUnlock()
+	if meta, err := b.core.getVaultMetadata(b.vaultId()); err == nil {
+		return meta.Name
+	}
+	return "OpenVault"
+}
+
+func (b *secretServiceBackend) Items() ([]*secretservice.Item, error) {
+	b.core.state.mu.RLock()
+	defer b.core.state.mu.RUnlock()
+	items, _, err := b.core.state.SecretServiceItems.get(b.vaultId(), b.items)
+	return items, err
+}
+
+func (b *secretServiceBackend) Item(id string) (*secretservice.Item, error) {
+	b.core.state.mu.RLock()
+	defer b.core.state.mu.RUnlock()
+	_, byId, err := b.core.state.SecretServiceItems.get(b.vaultId(), b.items)
+	if err != nil {
+		return nil, err
+	}
+	return byId[id], nil
+}
+
+// items decrypts the items of the secret service vault with their lookup attributes
+func (b *secretServiceBackend) items() ([]*secretservice.Item, error) {
+	overviews, err := b.core.listVaultItemOverviews(b.vaultId(), false)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]*secretservice.Item, 0, len(overviews))
+	for _, overview := range overviews {
+		details, err := b.core.getVaultItemDetails(overview.ItemID, ItemDetailsOptions{})
+		if err != nil {
+			return nil, err
+		}
+		created, _ := time.Parse(time.RFC3339, overview.CreatedAt)
+		modified, _ := time.Parse(time.RFC3339, overview.UpdatedAt)
+		items = append(items, &secretservice.Item{
+			ID:         overview.ItemID,
+			Label:      overview.Title,
+			Attributes: secretServiceAttributes(details.VaultItemDetails),
+			Created:    created,
+			Modified:   modified,
+		})
+	}
+	return items, nil
+}
+
+func (b *secretServiceBackend) Secret(id string) ([]byte, error) {
+	b.core.state.mu.RLock()
+	defer b.core.state.mu.RUnlock()
+	details, err := b.details(id)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(details.Password), nil
+}
+
+func (b *secretServiceBackend) CreateItem(label string, attributes map[string]string, secret []byte) (string, error) {
+	b.core.state.mu.Lock()
+	defer b.core.state.mu.Unlock()
+	details := structs.VaultItemDetails{
+		Category: structs.CategoryLogin,
+		Password: string(secret),
+	}
+	setSecretServiceAttributes(&details, attributes)
+	overview, err := b.core.createItem(b.vaultId(), structs.VaultItemOverview{Title: label}, details)
+	if err != nil {
+		return "", err
+	}
+	return overview.ItemID, nil
+}
+
+func (b *secretServiceBackend) UpdateItem(id string, label *string, attributes map[string]string, secret []byte) error {
+	b.core.state.mu.Lock()
+	defer b.core.state.mu.Unlock()
+	details, err := b.details(id)
+	if err != nil {
+		return err
+	}
+	overview, err := b.core.getItemOverview(id)
+	if err != nil {
+		return err
+	}
+	if label != nil {
+		overview.Title = *label
+	}
+	if attributes != nil {
+		setSecretServiceAttributes(details, attributes)
+	}
+	if secret != nil {
+		details.Password = string(secret)
+	}
+	_, err = b.core.updateItem(id, *overview.VaultItemOverview, *details)
+	return err
+}
+
+// DeleteItem moves the item to the trash
+func (b *secretServiceBackend) DeleteItem(id string) error {
+	b.core.state.mu.Lock()
+	defer b.core.state.mu.Unlock()
+	if _, err := b.details(id); err != nil {
+		return err
+	}
+	return b.core.deleteItem(id)
+}
+
+// details returns the revealed details of an item, checking that it is in the secret service vault
+func (b *secretServiceBackend) details(id string) (*structs.VaultItemDetails, error) {
+	encOverview, ok := b.core.state.ItemOverviews[id]
+	if !ok || encOverview.VaultID != b.vaultId() || b.core.state.IsItemTrashed(encOverview) {
+		return nil, fmt.Errorf("no item overview found for item %s", id)
+	}
+	details, err := b.core.getVaultItemDetails(id, ItemDetailsOptions{RevealConcealed: true})
+	if err != nil {
+		return nil, err
+	}
+	return details.VaultItemDetails, nil
+}
+
+// secretServiceAttributes returns the lookup attributes stored on an item
+func secretServiceAttributes(details *structs.VaultItemDetails) map[string]string {
+	attributes := make(map[string]string)
+	for _, section := range details.Sections {
+		if section.Title != secretServiceSection {
+			continue
+		}
+		for _, field := range section.Fields {
+			attributes[field.Label] = field.Value
+		}
+	}
+	return attributes
+}
+
+// setSecretServiceAttributes replaces the lookup attributes stored on an item
+func setSecretServiceAttributes(details *structs.VaultItemDetails, attributes map[string]string) {
+	section := &structs.Section{Title: secretServiceSection}
+	for key, value := range attributes {
+		section.Fields = append(section.Fields, &structs.Field{
+			Label: key,
+			Type:  structs.FieldTypeText,
+			Value: value,
+		})
+	}
+	sections := make([]*structs.Section, 0, len(details.Sections)+1)
+	for _, s := range details.Sections {
+		if s.Title != secretServiceSection {
+			sections = append(sections, s)
+		}
+	}
+	if len(section.Fields) > 0 {
+		sections = append(sections, section)
+	}
+	details.Sections = sections
+}
+
+// startSecretService claims the org.freedesktop.secrets name on the session bus if the Secret Service is enabled
+// and not already running. Like the SSH agent it is never started by command line modes.
+func (a *CoreService) startSecretService() error {
+	if a.headless || a.secretService != nil || !a.state.Settings.SecretServiceEnabled || runtime.GOOS != "linux" {
+		return nil
+	}
+	if _, ok := a.state.Vaults[a.state.Settings.SecretServiceVaultID]; !ok {
+		return fmt.Errorf("secret service vault %s not found", a.state.Settings.SecretServiceVaultID)
+	}
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return fmt.Errorf("failed to connect to session bus: %w", err)
+	}
+	done := make(chan struct{})
+	server, err := secretservice.Serve(conn, &secretServiceBackend{core: a, done: done})
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to start secret service: %w", err)
+	}
+	a.secretService = server
+	a.secretServiceConn = conn
+	a.secretServiceDone = done
+	logrus.Printf("secret service running on the session bus")
+	return nil
+}
+
+// stopSecretService releases the Secret Service bus name if it is running
+func (a *CoreService) stopSecretService() {
+	if a.secretService == nil {
+		return
+	}
+	if err := a.secretService.Close(); err != nil {
+		logrus.Errorf("failed to release secret service name: %v", err)
+	}
+	close(a.secretServiceDone)
+	a.secretServiceConn.Close()
+	a.secretService = nil
+	a.secretServiceConn = nil
+	a.secretServiceDone = nil
+	logrus.Printf("secret service stopped")
+}
+
+// isSecretServiceLocked returns whether the account owning the secret service vault is locked
+func (a *CoreService) isSecretServiceLocked() bool {
+	vault, ok := a.state.Vaults[a.state.Settings.SecretServiceVaultID]
+	if !ok {
+		return true
+	}
+	_, unlocked := a.state.AUK[vault.AccountID]
+	return !unlocked
+}
+
+// notifySecretServiceLock tells Secret Service clients that the vault was locked or unlocked
+func (a *CoreService) notifySecretServiceLock() {
+	if a.secretService != nil {
+		a.secretService.NotifyLockChanged(a.isSecretServiceLocked())
+	}
+}
+
+type SecretServiceStatus struct {
+	Running bool `json:"running"`
+}
+
+// GetSecretServiceStatus returns whether the Secret Service is running
+func (a *CoreService) GetSecretServiceStatus() *SecretServiceStatus {
+	a.state.mu.RLock()
+	defer a.state.mu.RUnlock()
+	return &SecretServiceStatus{
+		Running: a.secretService != nil,
+	}
+}
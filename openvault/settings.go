@@ -0,0 +1,86 @@
+package main
+
+import (
+	"fmt"
+	"runtime"
+
+	"github.com/BradHacker/openvault/openvault/internal/apiserver"
+	"github.com/BradHacker/openvault/openvault/internal/breach"
+	"github.com/BradHacker/openvault/openvault/internal/fs"
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+)
+
+// GetSettings returns the current application settings
+func (a *CoreService) GetSettings() *structs.Settings {
+	a.state.mu.RLock()
+	defer a.state.mu.RUnlock()
+	return a.state.Settings
+}
+
+// UpdateSettings validates and saves the application settings
+func (a *CoreService) UpdateSettings(settings structs.Settings) error {
+	a.state.mu.Lock()
+	defer a.state.mu.Unlock()
+	if settings.HistoryRetention < 0 {
+		return fmt.Errorf("history retention cannot be negative")
+	}
+	if settings.TrashRetentionDays < 0 {
+		return fmt.Errorf("trash retention cannot be negative")
+	}
+	if settings.PasswordMaxAgeDays < 0 {
+		return fmt.Errorf("password age cannot be negative")
+	}
+	if settings.PwnedPasswordsPath != "" {
+		source, err := breach.Open(settings.PwnedPasswordsPath)
+		if err != nil {
+			return err
+		}
+		source.Close()
+	}
+	if settings.APIServerAddress != "" {
+		if err := apiserver.CheckLoopbackAddress(settings.APIServerAddress); err != nil {
+			return err
+		}
+	} else if settings.APIServerEnabled && runtime.GOOS == "windows" {
+		return fmt.Errorf("the api server needs a loopback address on windows")
+	}
+	if settings.SecretServiceEnabled {
+		if vault, ok := a.state.Vaults[settings.SecretServiceVaultID]; !ok || vault.IsTrashed() {
+			return fmt.Errorf("a vault must be selected for the secret service")
+		}
+	}
+	if err := fs.SaveSettings(&settings); err != nil {
+		return fmt.Errorf("failed to save settings: %w", err)
+	}
+	previous := a.state.Settings
+	a.state.Settings = &settings
+	// The Secret Service runs while the app is open, locked or not
+	if !settings.SecretServiceEnabled || settings.SecretServiceVaultID != previous.SecretServiceVaultID {
+		a.stopSecretService()
+	}
+	if err := a.startSecretService(); err != nil {
+		return err
+	}
+	if !settings.BrowserIntegrationEnabled {
+		a.stopBrowserHost()
+	} else if err := a.startBrowserHost(); err != nil {
+		return err
+	}
+	if !settings.CredentialHelpersEnabled {
+		a.stopCredentialHelperHost()
+	} else if err := a.startCredentialHelperHost(); err != nil {
+		return err
+	}
+	if !settings.APIServerEnabled || settings.APIServerAddress != previous.APIServerAddress {
+		a.stopAPIServer()
+	}
+	if err := a.startAPIServer(); err != nil {
+		return err
+	}
+	if !settings.SSHAgentEnabled {
+		a.stopSSHAgent()
+	} else if !a.isLocked() {
+		return a.startSSHAgent()
+	}
+	return nil
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/BradHacker/openvault/openvault/internal/fs"
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+
+	"github.com/BradHacker/openvault/cryptolib"
+)
+
+// GetPublicKey returns the public encryption key of the given account as a JWK. This is the key
+// other users need in order to send share packages to this account.
+func (a *CoreService) GetPublicKey(accountId string) (string, error) {
+	a.state.mu.RLock()
+	defer a.state.mu.RUnlock()
+	keySet, ok := a.state.KeySets[accountId]
+	if !ok {
+		return "", fmt.Errorf("no keyset found for account %s", accountId)
+	}
+	pubKeyBytes, err := json.Marshal(keySet.PubKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal public key: %w", err)
+	}
+	return string(pubKeyBytes), nil
+}
+
+type ShareExportOptions struct {
+	// The recipient's public encryption key as a JWK (see GetPublicKey)
+	RecipientPublicKey string `json:"recipient_public_key"`
+	// Optional RFC 3339 time after which the package can no longer be imported
+	ExpiresAt string `json:"expires_at"`
+	// Whether the package may only be imported once
+	OneTime bool `json:"one_time"`
+	// The file to write the share package to
+	Path string `json:"path"`
+}
+
+// ExportItemShare encrypts a single item to the recipient's public key, signs it with the owning
+// account's signing key and writes the resulting share package to opts.Path.
+func (a *CoreService) ExportItemShare(itemId string, opts ShareExportOptions) error {
+	a.state.mu.RLock()
+	defer a.state.mu.RUnlock()
+	if a.isLocked() {
+		return fmt.Errorf("application not unlocked")
+	}
+	var recipientKey cryptolib.JWK
+	if err := json.Unmarshal([]byte(opts.RecipientPublicKey), &recipientKey); err != nil {
+		return fmt.Errorf("failed to parse recipient public key: %w", err)
+	}
+	shareOpts := structs.ShareOptions{OneTime: opts.OneTime}
+	if opts.ExpiresAt != "" {
+		expiresAt, err := time.Parse(time.RFC3339, opts.ExpiresAt)
+		if err != nil {
+			return fmt.Errorf("invalid expiry %q: %w", opts.ExpiresAt, err)
+		}
+		shareOpts.ExpiresAt = expiresAt
+	}
+	encOverview, ok := a.state.ItemOverviews[itemId]
+	if !ok {
+		return fmt.Errorf("no item overview found for item %s", itemId)
+	}
+	encDetails, ok := a.state.ItemDetails[itemId]
+	if !ok {
+		return fmt.Errorf("no item details found for item %s", itemId)
+	}
+	keySet, auk, vault, err := a.state.LookupVaultCrypto(encOverview.VaultID)
+	if err != nil {
+		return err
+	}
+	vaultKey, err := a.state.VaultKey(vault.VaultID)
+	if err != nil {
+		return err
+	}
+	defer vaultKey.Close()
+	overview, err := encOverview.Read(vaultKey)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt item overview for item %s: %w", itemId, err)
+	}
+	details, err := encDetails.Read(vaultKey)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt item details for item %s: %w", itemId, err)
+	}
+	signKey, err := keySet.SigningKey(auk)
+	if err != nil {
+		return fmt.Errorf("failed to decrypt signing key: %w", err)
+	}
+	defer signKey.Close()
+	sp, err := structs.SealSharePackage(a.state.Accounts[vault.AccountID], signKey, keySet.PubSignKey, &recipientKey, overview, details, shareOpts)
+	if err != nil {
+		return fmt.Errorf("failed to seal share package: %w", err)
+	}
+	if err := fs.SaveSharePackage(opts.Path, sp); err != nil {
+		return fmt.Errorf("failed to save share package: %w", err)
+	}
+	return nil
+}
+
+// ShareSender identifies who signed a share package
+type ShareSender struct {
+	// The email address the sender claimed in the package
+	SenderEmail string `json:"sender_email"`
+	// SHA-256 thumbprint of the sender's signing key, to be compared out of band
+	SenderFingerprint string `json:"sender_fingerprint"`
+}
+
+// PreviewItemShare verifies the share package at path and returns its sender without importing it, so the
+// fingerprint can be confirmed with the sender before calling ImportItemShare
+func (a *CoreService) PreviewItemShare(path string) (*ShareSender, error) {
+	sp, err := fs.LoadSharePackage(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load share package: %w", err)
+	}
+	if err := sp.Verify(time.Now()); err != nil {
+		return nil, err
+	}
+	fingerprint, err := sp.SenderFingerprint()
+	if err != nil {
+		return nil, err
+	}
+	return &ShareSender{SenderEmail: sp.SenderEmail, SenderFingerprint: fingerprint}, nil
+}
+
+type ShareImportResult struct {
+	Item *DecryptedVaultItemOverview `json:"item"`
+	// The email address the sender claimed in the package
+	SenderEmail string `json:"sender_email"`
+	// SHA-256 thumbprint of the sender's signing key, to be compared out of band
+	SenderFingerprint string `json:"sender_fingerprint"`
+}
+
+// ImportItemShare verifies and decrypts the share package at path and adds its item to the given vault.
+// The package must be signed by the key with expectedSenderFingerprint, as returned by PreviewItemShare and
+// confirmed with the sender, since anyone can claim any email address. Expired packages and one-time packages
+// which have already been imported are rejected.
+func (a *CoreService) ImportItemShare(vaultId string, path string, expectedSenderFingerprint string) (*ShareImportResult, error) {
+	a.state.mu.Lock()
+	defer a.state.mu.Unlock()
+	if a.isLocked() {
+		return nil, fmt.Errorf("application not unlocked")
+	}
+	sp, err := fs.LoadSharePackage(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load share package: %w", err)
+	}
+	fingerprint, err := sp.SenderFingerprint()
+	if err != nil {
+		return nil, err
+	}
+	if expectedSenderFingerprint == "" || !strings.EqualFold(fingerprint, expectedSenderFingerprint) {
+		return nil, structs.ErrShareWrongSender
+	}
+	if _, ok := a.state.ImportedShares[sp.PackageID]; ok && sp.OneTime {
+		return nil, structs.ErrShareAlreadyUsed
+	}
+	// Find the unlocked account the package was encrypted for
+	var privKey *cryptolib.JWK
+	for accountId, keySet := range a.state.KeySets {
+		auk, ok := a.state.AUK[accountId]
+		if !ok || keySet.PubKey.KeyID != sp.RecipientKeyID {
+			continue
+		}
+		privKey, err = keySet.PrivateKey(auk)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt private key: %w", err)
+		}
+		defer privKey.Close()
+		break
+	}
+	if privKey == nil {
+		return nil, structs.ErrShareWrongKey
+	}
+	overview, details, err := sp.Open(privKey, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	vaultKey, err := a.state.VaultKey(vaultId)
+	if err != nil {
+		return nil, err
+	}
+	defer vaultKey.Close()
+	encOverview, _, err := a.state.CreateItem(vaultId, vaultKey, overview, details)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.state.SaveItems(); err != nil {
+		return nil, err
+	}
+	if sp.OneTime {
+		a.state.ImportedShares[sp.PackageID] = time.Now().Format(time.RFC3339)
+		if err := fs.SaveImportedShares(a.state.ImportedShares); err != nil {
+			return nil, fmt.Errorf("failed to save imported shares: %w", err)
+		}
+	}
+	return &ShareImportResult{
+		Item: &DecryptedVaultItemOverview{
+			EncryptedVaultItemOverview: encOverview,
+			VaultItemOverview:          overview,
+		},
+		SenderEmail:       sp.SenderEmail,
+		SenderFingerprint: fingerprint,
+	}, nil
+}
@@ -0,0 +1,14 @@
+//go:build !unix
+
+package main
+
+import (
+	"errors"
+	"net"
+)
+
+func listenLocalSocket(socketPath string) (net.Listener, error) {
+	return nil, errors.New("local sockets are only supported on Unix systems")
+}
+
+func removeLocalSocket(socketPath string) {}
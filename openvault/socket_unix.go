@@ -0,0 +1,32 @@
+//go:build unix
+
+package main
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+)
+
+// listenLocalSocket listens on a Unix socket only accessible to the current user, replacing any stale socket
+// left behind by a previous run
+func listenLocalSocket(socketPath string) (net.Listener, error) {
+	if err := os.MkdirAll(filepath.Dir(socketPath), 0700); err != nil {
+		return nil, err
+	}
+	removeLocalSocket(socketPath)
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(socketPath, 0600); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return listener, nil
+}
+
+// removeLocalSocket deletes the socket file. Errors are ignored since a missing socket is the desired state.
+func removeLocalSocket(socketPath string) {
+	os.Remove(socketPath)
+}
@@ -0,0 +1,264 @@
+package main
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/BradHacker/openvault/openvault/internal/constants"
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+var (
+	errAgentReadOnly = errors.New("keys are managed in openvault and cannot be changed through the agent")
+	errAgentDenied   = errors.New("signature request was denied")
+	errAgentNoKey    = errors.New("no ssh key found for the requested identity")
+)
+
+// sshAgent serves the SSH keys stored in unlocked vaults over the ssh-agent protocol. Private keys are only
+// ever decrypted in memory for the duration of a single signature.
+type sshAgent struct {
+	core     *CoreService
+	listener net.Listener
+
+	mu    sync.Mutex
+	conns map[net.Conn]struct{}
+	// Closed when the agent stops, which denies requests waiting for approval
+	done chan struct{}
+}
+
+// sshAgentIdentity is an SSH key item which the agent can sign with
+type sshAgentIdentity struct {
+	itemId    string
+	title     string
+	publicKey ssh.PublicKey
+}
+
+// identities returns the SSH keys in the vaults of every unlocked account, skipping trashed items
+func (sa *sshAgent) identities() []*sshAgentIdentity {
+	identities := make([]*sshAgentIdentity, 0)
+	for _, overview := range sa.core.unlockedItemOverviews() {
+		if overview.Category != structs.CategorySSHKey || overview.SSHKey == nil {
+			continue
+		}
+		publicKey, _, _, _, err := ssh.ParseAuthorizedKey([]byte(overview.SSHKey.PublicKey))
+		if err != nil {
+			logrus.Errorf("ssh agent failed to parse public key of item %s: %v", overview.ItemID, err)
+			continue
+		}
+		identities = append(identities, &sshAgentIdentity{
+			itemId:    overview.ItemID,
+			title:     overview.Title,
+			publicKey: publicKey,
+		})
+	}
+	return identities
+}
+
+func (sa *sshAgent) List() ([]*agent.Key, error) {
+	sa.core.state.mu.RLock()
+	defer sa.core.state.mu.RUnlock()
+	if sa.core.isLocked() {
+		return []*agent.Key{}, nil
+	}
+	keys := make([]*agent.Key, 0)
+	for _, identity := range sa.identities() {
+		keys = append(keys, &agent.Key{
+			Format:  identity.publicKey.Type(),
+			Blob:    identity.publicKey.Marshal(),
+			Comment: identity.title,
+		})
+	}
+	return keys, nil
+}
+
+func (sa *sshAgent) Sign(key ssh.PublicKey, data []byte) (*ssh.Signature, error) {
+	return sa.SignWithFlags(key, data, 0)
+}
+
+// lookup returns the identity for a public key and whether signing with it needs the user's approval
+func (sa *sshAgent) lookup(key ssh.PublicKey) (*sshAgentIdentity, bool, error) {
+	sa.core.state.mu.RLock()
+	defer sa.core.state.mu.RUnlock()
+	if sa.core.isLocked() {
+		return nil, false, errAgentNoKey
+	}
+	for _, identity := range sa.identities() {
+		if bytes.Equal(identity.publicKey.Marshal(), key.Marshal()) {
+			return identity, sa.core.state.Settings.SSHAgentConfirm, nil
+		}
+	}
+	return nil, false, errAgentNoKey
+}
+
+// SignWithFlags signs with the key of an SSH key item. The state lock is released while the user is asked to
+// approve the request, so the rest of the app keeps working; everything is checked again once it is retaken.
+func (sa *sshAgent) SignWithFlags(key ssh.PublicKey, data []byte, flags agent.SignatureFlags) (*ssh.Signature, error) {
+	identity, confirm, err := sa.lookup(key)
+	if err != nil {
+		return nil, err
+	}
+	if confirm && !sa.approve(identity) {
+		return nil, errAgentDenied
+	}
+	sa.core.state.mu.RLock()
+	defer sa.core.state.mu.RUnlock()
+	// The agent may have been stopped or the app locked while the prompt was open
+	if sa.core.sshAgent != sa || sa.core.isLocked() {
+		return nil, errAgentNoKey
+	}
+	details, err := sa.core.getVaultItemDetails(identity.itemId, ItemDetailsOptions{RevealConcealed: true})
+	if err != nil {
+		return nil, err
+	}
+	if details.SSH == nil {
+		return nil, errAgentNoKey
+	}
+	signer, err := details.SSH.Signer()
+	if err != nil {
+		return nil, err
+	}
+	// The item may also have been given a different key
+	if !bytes.Equal(signer.PublicKey().Marshal(), key.Marshal()) {
+		return nil, errAgentNoKey
+	}
+	logrus.Printf("ssh agent signing with %s (%s)", identity.title, ssh.FingerprintSHA256(identity.publicKey))
+	if algSigner, ok := signer.(ssh.AlgorithmSigner); ok && key.Type() == ssh.KeyAlgoRSA {
+		switch {
+		case flags&agent.SignatureFlagRsaSha512 != 0:
+			return algSigner.SignWithAlgorithm(rand.Reader, data, ssh.KeyAlgoRSASHA512)
+		case flags&agent.SignatureFlagRsaSha256 != 0:
+			return algSigner.SignWithAlgorithm(rand.Reader, data, ssh.KeyAlgoRSASHA256)
+		}
+	}
+	return signer.Sign(rand.Reader, data)
+}
+
+// approve asks the user whether the agent may sign with the given identity
+func (sa *sshAgent) approve(identity *sshAgentIdentity) bool {
+	return askApproval("SSH Key Request",
+		fmt.Sprintf("Allow an SSH client to use the key %q (%s)?", identity.title, ssh.FingerprintSHA256(identity.publicKey)),
+		sa.done)
+}
+
+func (sa *sshAgent) Add(key agent.AddedKey) error {
+	return errAgentReadOnly
+}
+
+func (sa *sshAgent) Remove(key ssh.PublicKey) error {
+	return errAgentReadOnly
+}
+
+func (sa *sshAgent) RemoveAll() error {
+	return errAgentReadOnly
+}
+
+func (sa *sshAgent) Lock(passphrase []byte) error {
+	return errAgentReadOnly
+}
+
+func (sa *sshAgent) Unlock(passphrase []byte) error {
+	return errAgentReadOnly
+}
+
+func (sa *sshAgent) Signers() ([]ssh.Signer, error) {
+	return nil, errAgentReadOnly
+}
+
+func (sa *sshAgent) Extension(extensionType string, contents []byte) ([]byte, error) {
+	return nil, agent.ErrExtensionUnsupported
+}
+
+// serve accepts connections until the listener is closed
+func (sa *sshAgent) serve() {
+	for {
+		conn, err := sa.listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				logrus.Errorf("ssh agent stopped accepting connections: %v", err)
+			}
+			return
+		}
+		sa.mu.Lock()
+		sa.conns[conn] = struct{}{}
+		sa.mu.Unlock()
+		go func() {
+			defer func() {
+				sa.mu.Lock()
+				delete(sa.conns, conn)
+				sa.mu.Unlock()
+				conn.Close()
+			}()
+			if err := agent.ServeAgent(sa, conn); err != nil && !errors.Is(err, net.ErrClosed) {
+				logrus.Debugf("ssh agent connection closed: %v", err)
+			}
+		}()
+	}
+}
+
+// stop closes the listener and every open connection, so no further requests are served
+func (sa *sshAgent) stop() {
+	close(sa.done)
+	sa.listener.Close()
+	sa.mu.Lock()
+	defer sa.mu.Unlock()
+	for conn := range sa.conns {
+		conn.Close()
+	}
+	removeLocalSocket(constants.SSH_AGENT_SOCKET)
+}
+
+// startSSHAgent starts serving the SSH agent socket if it is enabled and not already running. The agent is
+// never started by command line modes, which would otherwise take over the desktop app's socket.
+func (a *CoreService) startSSHAgent() error {
+	if a.headless || a.sshAgent != nil || !a.state.Settings.SSHAgentEnabled {
+		return nil
+	}
+	listener, err := listenLocalSocket(constants.SSH_AGENT_SOCKET)
+	if err != nil {
+		return fmt.Errorf("failed to start ssh agent: %w", err)
+	}
+	a.sshAgent = &sshAgent{
+		core:     a,
+		listener: listener,
+		conns:    make(map[net.Conn]struct{}),
+		done:     make(chan struct{}),
+	}
+	go a.sshAgent.serve()
+	logrus.Printf("ssh agent listening on %s", constants.SSH_AGENT_SOCKET)
+	return nil
+}
+
+// stopSSHAgent stops the SSH agent if it is running. Callers hold the state lock for writing, so it waits for
+// signatures in progress, which hold it for reading; requests still waiting for approval are denied.
+func (a *CoreService) stopSSHAgent() {
+	if a.sshAgent == nil {
+		return
+	}
+	a.sshAgent.stop()
+	a.sshAgent = nil
+	logrus.Printf("ssh agent stopped")
+}
+
+type SSHAgentStatus struct {
+	Running bool `json:"running"`
+	// Value to use for SSH_AUTH_SOCK
+	SocketPath string `json:"socket_path"`
+}
+
+// GetSSHAgentStatus returns whether the SSH agent is running and the socket it listens on
+func (a *CoreService) GetSSHAgentStatus() *SSHAgentStatus {
+	a.state.mu.RLock()
+	defer a.state.mu.RUnlock()
+	return &SSHAgentStatus{
+		Running:    a.sshAgent != nil,
+		SocketPath: constants.SSH_AGENT_SOCKET,
+	}
+}
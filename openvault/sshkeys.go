@@ -0,0 +1,107 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+
+	"golang.org/x/crypto/ssh"
+)
+
+type SSHKeyGenerateOptions struct {
+	Title   string             `json:"title"`
+	KeyType structs.SSHKeyType `json:"key_type"`
+	// RSA modulus size or ECDSA curve size (0 for the default)
+	Bits    int    `json:"bits"`
+	Comment string `json:"comment"`
+}
+
+// GenerateSSHKey generates a new SSH key pair and stores it as a new item in the given vault. The private
+// key never leaves the vault.
+func (a *CoreService) GenerateSSHKey(vaultId string, opts SSHKeyGenerateOptions) (*DecryptedVaultItemOverview, error) {
+	a.state.mu.Lock()
+	defer a.state.mu.Unlock()
+	if a.isLocked() {
+		return nil, fmt.Errorf("application not unlocked")
+	}
+	key, err := structs.GenerateSSHKey(opts.KeyType, opts.Bits, opts.Comment)
+	if err != nil {
+		return nil, err
+	}
+	title := opts.Title
+	if title == "" {
+		title = key.Fingerprint
+	}
+	return a.createItem(vaultId, structs.VaultItemOverview{Title: title}, structs.VaultItemDetails{
+		Category: structs.CategorySSHKey,
+		SSH:      key,
+	})
+}
+
+type SSHKeyImportOptions struct {
+	Title string `json:"title"`
+	// Path to a private key file. Ignored if PrivateKey is set.
+	Path string `json:"path"`
+	// Private key in OpenSSH or PEM format
+	PrivateKey string `json:"private_key"`
+	// Passphrase of an encrypted private key
+	Passphrase string `json:"passphrase"`
+	// Comment for the key. When importing from a file, defaults to the comment in the matching .pub file.
+	Comment string `json:"comment"`
+}
+
+// ImportSSHKey imports an existing SSH private key as a new item in the given vault
+func (a *CoreService) ImportSSHKey(vaultId string, opts SSHKeyImportOptions) (*DecryptedVaultItemOverview, error) {
+	a.state.mu.Lock()
+	defer a.state.mu.Unlock()
+	if a.isLocked() {
+		return nil, fmt.Errorf("application not unlocked")
+	}
+	data := []byte(opts.PrivateKey)
+	title := opts.Title
+	comment := opts.Comment
+	if len(data) == 0 {
+		if opts.Path == "" {
+			return nil, fmt.Errorf("no ssh private key provided")
+		}
+		var err error
+		data, err = os.ReadFile(opts.Path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ssh private key: %w", err)
+		}
+		if title == "" {
+			title = filepath.Base(opts.Path)
+		}
+		if comment == "" {
+			comment = publicKeyComment(opts.Path + ".pub")
+		}
+	}
+	key, err := structs.ImportSSHKey(data, []byte(opts.Passphrase), comment)
+	if err != nil {
+		return nil, err
+	}
+	if title == "" {
+		title = key.Fingerprint
+	}
+	return a.createItem(vaultId, structs.VaultItemOverview{Title: title}, structs.VaultItemDetails{
+		Category: structs.CategorySSHKey,
+		SSH:      key,
+	})
+}
+
+// publicKeyComment returns the comment of an authorized_keys formatted public key file, or an empty string
+// if it cannot be read
+func publicKeyComment(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	_, comment, _, _, err := ssh.ParseAuthorizedKey(data)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(comment)
+}
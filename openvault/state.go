@@ -2,14 +2,21 @@ package main
 
 import (
 	"fmt"
+	"sync"
+	"time"
 
 	"github.com/BradHacker/openvault/openvault/internal/fs"
 	"github.com/BradHacker/openvault/openvault/internal/structs"
 
 	"github.com/BradHacker/openvault/cryptolib"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
 )
 
 type State struct {
+	// Guards every field. CoreService methods and the servers sharing the state (SSH agent, browser integration,
+	// Secret Service and local API) take it for reading or writing before they touch anything else.
+	mu sync.RWMutex
 	// Whether the application has been initialized with at least one account
 	IsInitialized bool
 	// Accounts mapped by their IDs
@@ -24,6 +31,25 @@ type State struct {
 	ItemDetails fs.ItemDetailsStore
 	// The account unlock keys for each account
 	AUK map[string]*cryptolib.JWK
+	// IDs of one-time share packages which have already been imported
+	ImportedShares fs.ImportedSharesStore
+	// Previous versions of items mapped by their item IDs
+	ItemHistory fs.ItemHistoryStore
+	// The user-configurable application settings
+	Settings *structs.Settings
+	// Attachment records mapped by their attachment IDs
+	Attachments fs.AttachmentStore
+	// Results of the last breached password check mapped by item IDs (kept in memory only)
+	BreachedItems map[string]*breachResult
+	// Search index of the unlocked items (nil while locked)
+	Search *itemSearchIndex
+	// Tokens for the local API mapped by their token IDs
+	APITokens fs.APITokenStore
+	// Paired browser extensions mapped by their extension IDs
+	BrowserPairings fs.BrowserPairingStore
+	// Items of the Secret Service vault with their lookup attributes, built on first use and cleared whenever
+	// items or vaults are saved or the app locks
+	SecretServiceItems secretServiceItemCache
 }
 
 func (s *State) LookupVaultCrypto(vaultId string) (keySet *cryptolib.KeySet, auk *cryptolib.JWK, vault *structs.Vault, err error) {
@@ -42,3 +68,226 @@ func (s *State) LookupVaultCrypto(vaultId string) (keySet *cryptolib.KeySet, auk
 	}
 	return keySet, auk, vault, nil
 }
+
+// VaultKey decrypts the vault key for the given vault ID. The caller is responsible for closing the returned key.
+func (s *State) VaultKey(vaultId string) (*cryptolib.JWK, error) {
+	keySet, auk, vault, err := s.LookupVaultCrypto(vaultId)
+	if err != nil {
+		return nil, err
+	}
+	privKey, err := keySet.PrivateKey(auk)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt private key: %w", err)
+	}
+	defer privKey.Close()
+	vaultKey, err := vault.DecryptVaultKey(privKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt vault key for vault %s: %w", vaultId, err)
+	}
+	return vaultKey, nil
+}
+
+// CreateItem validates and encrypts a new item with the vault key and adds it to the in-memory stores. SaveItems must be
+// called to persist the new item.
+func (s *State) CreateItem(vaultId string, vaultKey *cryptolib.JWK, overview *structs.VaultItemOverview, details *structs.VaultItemDetails) (*structs.EncryptedVaultItemOverview, *structs.EncryptedVaultItemDetails, error) {
+	if err := structs.NormalizeItem(overview, details); err != nil {
+		return nil, nil, err
+	}
+	itemId := uuid.New().String()
+	now := time.Now().Format(time.RFC3339)
+	encOverview := &structs.EncryptedVaultItemOverview{
+		ItemID:    itemId,
+		VaultID:   vaultId,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := encOverview.Update(vaultKey, overview); err != nil {
+		return nil, nil, fmt.Errorf("failed to encrypt item overview: %w", err)
+	}
+	encDetails := &structs.EncryptedVaultItemDetails{
+		ItemID:    itemId,
+		VaultID:   vaultId,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := encDetails.Update(vaultKey, details); err != nil {
+		return nil, nil, fmt.Errorf("failed to encrypt item details: %w", err)
+	}
+	s.ItemOverviews[itemId] = encOverview
+	s.ItemDetails[itemId] = encDetails
+	return encOverview, encDetails, nil
+}
+
+// SaveItems persists the item overviews and details to the filesystem and brings the search index up to date
+// with the changes
+func (s *State) SaveItems() error {
+	if err := fs.SaveItemOverviews(s.ItemOverviews); err != nil {
+		return fmt.Errorf("failed to save item overviews: %w", err)
+	}
+	if err := fs.SaveItemDetails(s.ItemDetails); err != nil {
+		return fmt.Errorf("failed to save item details: %w", err)
+	}
+	s.RefreshSearchIndex()
+	s.SecretServiceItems.clear()
+	return nil
+}
+
+// ArchiveItem appends the current version of an item to its history, dropping the oldest revisions
+// beyond the configured retention limit. SaveItemHistory must be called to persist the change.
+func (s *State) ArchiveItem(itemId string, accountId string) error {
+	encOverview, ok := s.ItemOverviews[itemId]
+	if !ok {
+		return fmt.Errorf("no item overview found for item %s", itemId)
+	}
+	encDetails, ok := s.ItemDetails[itemId]
+	if !ok {
+		return fmt.Errorf("no item details found for item %s", itemId)
+	}
+	history := append(s.ItemHistory[itemId], structs.NewItemRevision(encOverview, encDetails, accountId))
+	if limit := s.Settings.HistoryRetention; limit > 0 && len(history) > limit {
+		history = history[len(history)-limit:]
+	}
+	s.ItemHistory[itemId] = history
+	return nil
+}
+
+// ImportRevision adds an earlier version of an item, written at createdAt, to the end of its history. Revisions must be
+// imported oldest first and are subject to the same retention limit as ArchiveItem. SaveItemHistory must be called
+// to persist the change.
+func (s *State) ImportRevision(itemId string, accountId string, vaultKey *cryptolib.JWK, overview *structs.VaultItemOverview, details *structs.VaultItemDetails, createdAt time.Time) error {
+	if err := structs.NormalizeItem(overview, details); err != nil {
+		return err
+	}
+	encOverview, ok := s.ItemOverviews[itemId]
+	if !ok {
+		return fmt.Errorf("no item overview found for item %s", itemId)
+	}
+	revOverview := &structs.EncryptedVaultItemOverview{ItemID: itemId, VaultID: encOverview.VaultID}
+	if err := revOverview.Update(vaultKey, overview); err != nil {
+		return fmt.Errorf("failed to encrypt item overview: %w", err)
+	}
+	revDetails := &structs.EncryptedVaultItemDetails{ItemID: itemId, VaultID: encOverview.VaultID}
+	if err := revDetails.Update(vaultKey, details); err != nil {
+		return fmt.Errorf("failed to encrypt item details: %w", err)
+	}
+	revision := structs.NewItemRevision(revOverview, revDetails, accountId)
+	if !createdAt.IsZero() {
+		revision.CreatedAt = createdAt.Format(time.RFC3339)
+	}
+	history := append(s.ItemHistory[itemId], revision)
+	if limit := s.Settings.HistoryRetention; limit > 0 && len(history) > limit {
+		history = history[len(history)-limit:]
+	}
+	s.ItemHistory[itemId] = history
+	return nil
+}
+
+// SaveItemHistory persists the item history to the filesystem
+func (s *State) SaveItemHistory() error {
+	if err := fs.SaveItemHistory(s.ItemHistory); err != nil {
+		return fmt.Errorf("failed to save item history: %w", err)
+	}
+	return nil
+}
+
+// SaveVaults persists the vaults to the filesystem
+func (s *State) SaveVaults() error {
+	if err := fs.SaveVaults(s.Vaults); err != nil {
+		return fmt.Errorf("failed to save vaults: %w", err)
+	}
+	s.SecretServiceItems.clear()
+	return nil
+}
+
+// IsItemTrashed returns whether an item is in the trash, either directly or because its vault is
+func (s *State) IsItemTrashed(encOverview *structs.EncryptedVaultItemOverview) bool {
+	if encOverview.IsTrashed() {
+		return true
+	}
+	vault, ok := s.Vaults[encOverview.VaultID]
+	return ok && vault.IsTrashed()
+}
+
+// ItemAttachments returns the attachment records belonging to an item
+func (s *State) ItemAttachments(itemId string) []*structs.EncryptedAttachment {
+	attachments := make([]*structs.EncryptedAttachment, 0)
+	for _, attachment := range s.Attachments {
+		if attachment.ItemID == itemId {
+			attachments = append(attachments, attachment)
+		}
+	}
+	return attachments
+}
+
+// SaveAttachments persists the attachment records to the filesystem
+func (s *State) SaveAttachments() error {
+	if err := fs.SaveAttachments(s.Attachments); err != nil {
+		return fmt.Errorf("failed to save attachments: %w", err)
+	}
+	return nil
+}
+
+// PurgeItem permanently removes an item, its history and its attachments from the in-memory stores.
+// Attachment blobs are deleted from the filesystem immediately.
+func (s *State) PurgeItem(itemId string) {
+	delete(s.ItemOverviews, itemId)
+	delete(s.ItemDetails, itemId)
+	delete(s.ItemHistory, itemId)
+	for _, attachment := range s.ItemAttachments(itemId) {
+		delete(s.Attachments, attachment.AttachmentID)
+		if err := fs.RemoveAttachmentBlob(attachment.AttachmentID); err != nil {
+			logrus.Errorf("failed to remove attachment blob %s: %v", attachment.AttachmentID, err)
+		}
+	}
+}
+
+// PurgeVault permanently removes a vault and all of its items from the in-memory stores
+func (s *State) PurgeVault(vaultId string) {
+	for itemId, encOverview := range s.ItemOverviews {
+		if encOverview.VaultID == vaultId {
+			s.PurgeItem(itemId)
+		}
+	}
+	delete(s.Vaults, vaultId)
+}
+
+// PurgeExpiredTrash permanently removes trashed items and vaults which have been in the trash longer than
+// the configured retention period. It returns whether anything was purged.
+func (s *State) PurgeExpiredTrash(now time.Time) bool {
+	if s.Settings.TrashRetentionDays <= 0 {
+		return false
+	}
+	cutoff := now.AddDate(0, 0, -s.Settings.TrashRetentionDays)
+	expired := func(trashedAt string) bool {
+		t, err := time.Parse(time.RFC3339, trashedAt)
+		return err == nil && t.Before(cutoff)
+	}
+	purged := false
+	for vaultId, vault := range s.Vaults {
+		if vault.IsTrashed() && expired(vault.TrashedAt) {
+			s.PurgeVault(vaultId)
+			purged = true
+		}
+	}
+	for itemId, encOverview := range s.ItemOverviews {
+		if encOverview.IsTrashed() && expired(encOverview.TrashedAt) {
+			s.PurgeItem(itemId)
+			purged = true
+		}
+	}
+	return purged
+}
+
+// SaveAll persists the vaults, items, item history and attachment records to the filesystem
+func (s *State) SaveAll() error {
+	if err := s.SaveVaults(); err != nil {
+		return err
+	}
+	if err := s.SaveItems(); err != nil {
+		return err
+	}
+	if err := s.SaveItemHistory(); err != nil {
+		return err
+	}
+	return s.SaveAttachments()
+}
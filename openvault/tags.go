@@ -0,0 +1,266 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+
+	"github.com/BradHacker/openvault/cryptolib"
+	"github.com/sirupsen/logrus"
+)
+
+// ItemFilter narrows down the items returned by ListAllItemOverviews. Empty fields match every item.
+type ItemFilter struct {
+	// Only return items with this tag or a tag nested under it
+	Tag string `json:"tag"`
+	// Only return favorite items
+	Favorites bool `json:"favorites"`
+	// Only return items filed into this folder
+	FolderID string `json:"folder_id"`
+}
+
+// Matches returns whether an item overview passes the filter. The filter tag must be normalized.
+func (f ItemFilter) Matches(overview *structs.VaultItemOverview) bool {
+	if f.Favorites && !overview.Favorite {
+		return false
+	}
+	if f.FolderID != "" && overview.FolderID != f.FolderID {
+		return false
+	}
+	return f.Tag == "" || slices.ContainsFunc(overview.Tags, func(tag string) bool {
+		return structs.TagWithin(tag, f.Tag)
+	})
+}
+
+type TagCount struct {
+	Tag string `json:"tag"`
+	// Number of non-trashed items with the tag or a tag nested under it
+	Count int `json:"count"`
+}
+
+// editItemOverview decrypts the overview of an item, applies edit and saves the encrypted result. Organizing an
+// item does not change its contents, so no revision is added to its history.
+func (a *CoreService) editItemOverview(itemId string, edit func(overview *structs.VaultItemOverview) error) (*DecryptedVaultItemOverview, error) {
+	if a.isLocked() {
+		return nil, fmt.Errorf("application not unlocked")
+	}
+	encOverview, ok := a.state.ItemOverviews[itemId]
+	if !ok {
+		return nil, fmt.Errorf("no item overview found for item %s", itemId)
+	}
+	vaultKey, err := a.state.VaultKey(encOverview.VaultID)
+	if err != nil {
+		return nil, err
+	}
+	defer vaultKey.Close()
+	overview, err := encOverview.Read(vaultKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt item overview for item %s: %w", itemId, err)
+	}
+	if err := edit(overview); err != nil {
+		return nil, err
+	}
+	if err := encOverview.Update(vaultKey, overview); err != nil {
+		return nil, fmt.Errorf("failed to encrypt item overview: %w", err)
+	}
+	if err := a.state.SaveItems(); err != nil {
+		return nil, err
+	}
+	return &DecryptedVaultItemOverview{
+		EncryptedVaultItemOverview: encOverview,
+		VaultItemOverview:          overview,
+		Flags:                      a.itemFlags(itemId),
+	}, nil
+}
+
+// editAllItemOverviews applies edit to the overview of every item, including trashed items, in the vaults of
+// unlocked accounts, or only in the given vault if vaultId is set. The overviews edit reports as changed are
+// encrypted again and saved. If edit fails no item is changed. It returns the number of changed items.
+func (a *CoreService) editAllItemOverviews(vaultId string, edit func(overview *structs.VaultItemOverview) (bool, error)) (int, error) {
+	vaultKeys := make(map[string]*cryptolib.JWK)
+	defer func() {
+		for _, vaultKey := range vaultKeys {
+			vaultKey.Close()
+		}
+	}()
+	type changedItem struct {
+		encOverview *structs.EncryptedVaultItemOverview
+		overview    *structs.VaultItemOverview
+		vaultKey    *cryptolib.JWK
+	}
+	var changed []*changedItem
+	for itemId, encOverview := range a.state.ItemOverviews {
+		if vaultId != "" && encOverview.VaultID != vaultId {
+			continue
+		}
+		vaultKey, ok := vaultKeys[encOverview.VaultID]
+		if !ok {
+			var err error
+			if vaultKey, err = a.state.VaultKey(encOverview.VaultID); err != nil {
+				continue
+			}
+			vaultKeys[encOverview.VaultID] = vaultKey
+		}
+		overview, err := encOverview.Read(vaultKey)
+		if err != nil {
+			return 0, fmt.Errorf("failed to decrypt item overview for item %s: %w", itemId, err)
+		}
+		edited, err := edit(overview)
+		if err != nil {
+			return 0, err
+		}
+		if edited {
+			changed = append(changed, &changedItem{encOverview: encOverview, overview: overview, vaultKey: vaultKey})
+		}
+	}
+	// Only encrypt once every overview has been edited, so a failure leaves every item untouched
+	for _, item := range changed {
+		if err := item.encOverview.Update(item.vaultKey, item.overview); err != nil {
+			return 0, fmt.Errorf("failed to encrypt item overview: %w", err)
+		}
+	}
+	if len(changed) > 0 {
+		if err := a.state.SaveItems(); err != nil {
+			return 0, err
+		}
+	}
+	return len(changed), nil
+}
+
+// SetItemTags replaces the tags of an item. Tags are stored in the encrypted item overview.
+func (a *CoreService) SetItemTags(itemId string, tags []string) (*DecryptedVaultItemOverview, error) {
+	a.state.mu.Lock()
+	defer a.state.mu.Unlock()
+	normalized, err := structs.NormalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+	return a.editItemOverview(itemId, func(overview *structs.VaultItemOverview) error {
+		overview.Tags = normalized
+		return nil
+	})
+}
+
+// SetItemFavorite marks or unmarks an item as a favorite
+func (a *CoreService) SetItemFavorite(itemId string, favorite bool) (*DecryptedVaultItemOverview, error) {
+	a.state.mu.Lock()
+	defer a.state.mu.Unlock()
+	return a.editItemOverview(itemId, func(overview *structs.VaultItemOverview) error {
+		overview.Favorite = favorite
+		return nil
+	})
+}
+
+// ListTags returns every tag used by the non-trashed items in the vaults of unlocked accounts, along with the
+// tags they are nested under, sorted by name
+func (a *CoreService) ListTags() ([]*TagCount, error) {
+	a.state.mu.RLock()
+	defer a.state.mu.RUnlock()
+	if a.isLocked() {
+		return nil, fmt.Errorf("application not unlocked")
+	}
+	// Tags differing only in case are counted together under the first spelling seen
+	counts := make(map[string]*TagCount)
+	for _, overview := range a.unlockedItemOverviews() {
+		seen := make(map[string]bool)
+		for _, tag := range overview.Tags {
+			for _, t := range append(structs.TagAncestors(tag), tag) {
+				key := strings.ToLower(t)
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				if counts[key] == nil {
+					counts[key] = &TagCount{Tag: t}
+				}
+				counts[key].Count++
+			}
+		}
+	}
+	tags := make([]*TagCount, 0, len(counts))
+	for _, count := range counts {
+		tags = append(tags, count)
+	}
+	slices.SortFunc(tags, func(x, y *TagCount) int {
+		return strings.Compare(strings.ToLower(x.Tag), strings.ToLower(y.Tag))
+	})
+	return tags, nil
+}
+
+// RenameTag renames a tag on every item in the vaults of unlocked accounts. Tags nested under it are moved along,
+// so renaming "prod" to "production" turns "prod/db" into "production/db". It returns the number of changed items.
+func (a *CoreService) RenameTag(tag string, newTag string) (int, error) {
+	a.state.mu.Lock()
+	defer a.state.mu.Unlock()
+	if a.isLocked() {
+		return 0, fmt.Errorf("application not unlocked")
+	}
+	tag, err := structs.NormalizeTag(tag)
+	if err != nil {
+		return 0, err
+	}
+	newTag, err = structs.NormalizeTag(newTag)
+	if err != nil {
+		return 0, err
+	}
+	if tag == "" || newTag == "" {
+		return 0, fmt.Errorf("%w: tag cannot be empty", structs.ErrInvalidTag)
+	}
+	changed, err := a.editAllItemOverviews("", func(overview *structs.VaultItemOverview) (bool, error) {
+		renamed := make([]string, 0, len(overview.Tags))
+		found := false
+		for _, t := range overview.Tags {
+			if structs.TagWithin(t, tag) {
+				t = newTag + t[len(tag):]
+				found = true
+			}
+			renamed = append(renamed, t)
+		}
+		if !found {
+			return false, nil
+		}
+		// Renaming may merge tags or push nested tags over the length limit
+		tags, err := structs.NormalizeTags(renamed)
+		if err != nil {
+			return false, err
+		}
+		overview.Tags = tags
+		return true, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	logrus.Printf("renamed tag on %d items", changed)
+	return changed, nil
+}
+
+// DeleteTag removes a tag, and the tags nested under it, from every item in the vaults of unlocked accounts. It
+// returns the number of changed items.
+func (a *CoreService) DeleteTag(tag string) (int, error) {
+	a.state.mu.Lock()
+	defer a.state.mu.Unlock()
+	if a.isLocked() {
+		return 0, fmt.Errorf("application not unlocked")
+	}
+	tag, err := structs.NormalizeTag(tag)
+	if err != nil {
+		return 0, err
+	}
+	if tag == "" {
+		return 0, fmt.Errorf("%w: tag cannot be empty", structs.ErrInvalidTag)
+	}
+	changed, err := a.editAllItemOverviews("", func(overview *structs.VaultItemOverview) (bool, error) {
+		n := len(overview.Tags)
+		overview.Tags = slices.DeleteFunc(overview.Tags, func(t string) bool {
+			return structs.TagWithin(t, tag)
+		})
+		return len(overview.Tags) != n, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	logrus.Printf("deleted tag from %d items", changed)
+	return changed, nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+
+	"github.com/BradHacker/openvault/cryptolib"
+)
+
+type TOTPCode struct {
+	Code             string `json:"code"`
+	SecondsRemaining int    `json:"seconds_remaining"`
+	Period           int    `json:"period"`
+}
+
+// GetTOTPCode returns the current one-time password for a one-time password field of an item
+func (a *CoreService) GetTOTPCode(itemId string, fieldId string) (*TOTPCode, error) {
+	a.state.mu.RLock()
+	defer a.state.mu.RUnlock()
+	if a.isLocked() {
+		return nil, fmt.Errorf("application not unlocked")
+	}
+	details, err := a.getVaultItemDetails(itemId, ItemDetailsOptions{FieldID: fieldId})
+	if err != nil {
+		return nil, err
+	}
+	field := details.Field(fieldId)
+	if field.Type != structs.FieldTypeOTP {
+		return nil, fmt.Errorf("field %s of item %s is not a one-time password", fieldId, itemId)
+	}
+	params, err := cryptolib.ParseTOTP(field.Value)
+	if err != nil {
+		return nil, err
+	}
+	code, remaining, err := params.Code(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return &TOTPCode{
+		Code:             code,
+		SecondsRemaining: remaining,
+		Period:           params.Period,
+	}, nil
+}
+
+type OTPAccount struct {
+	Issuer      string `json:"issuer"`
+	AccountName string `json:"account_name"`
+	// otpauth:// URI to store in a one-time password field
+	URI string `json:"uri"`
+}
+
+// ParseOTPMigration decodes an authenticator export QR payload (otpauth-migration://) or a single
+// otpauth:// URI into the accounts it contains.
+func (a *CoreService) ParseOTPMigration(payload string) ([]*OTPAccount, error) {
+	var params []*cryptolib.TOTPParams
+	var err error
+	if p, parseErr := cryptolib.ParseOTPAuthURI(payload); parseErr == nil {
+		params = []*cryptolib.TOTPParams{p}
+	} else if params, err = cryptolib.ParseOTPMigration(payload); err != nil {
+		return nil, err
+	}
+	var accounts []*OTPAccount
+	for _, p := range params {
+		accounts = append(accounts, &OTPAccount{
+			Issuer:      p.Issuer,
+			AccountName: p.AccountName,
+			URI:         p.URI(),
+		})
+	}
+	return accounts, nil
+}
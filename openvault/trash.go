@@ -0,0 +1,183 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/BradHacker/openvault/openvault/internal/structs"
+)
+
+// DeleteItem moves an item to the trash. It can be restored with RestoreItem until it is purged.
+func (a *CoreService) DeleteItem(itemId string) error {
+	a.state.mu.Lock()
+	defer a.state.mu.Unlock()
+	return a.deleteItem(itemId)
+}
+
+func (a *CoreService) deleteItem(itemId string) error {
+	if a.isLocked() {
+		return fmt.Errorf("application not unlocked")
+	}
+	encOverview, ok := a.state.ItemOverviews[itemId]
+	if !ok {
+		return fmt.Errorf("no item overview found for item %s", itemId)
+	}
+	if encOverview.IsTrashed() {
+		return nil
+	}
+	encOverview.TrashedAt = time.Now().Format(time.RFC3339)
+	return a.state.SaveItems()
+}
+
+// RestoreItem moves an item out of the trash
+func (a *CoreService) RestoreItem(itemId string) error {
+	a.state.mu.Lock()
+	defer a.state.mu.Unlock()
+	if a.isLocked() {
+		return fmt.Errorf("application not unlocked")
+	}
+	encOverview, ok := a.state.ItemOverviews[itemId]
+	if !ok {
+		return fmt.Errorf("no item overview found for item %s", itemId)
+	}
+	if vault, ok := a.state.Vaults[encOverview.VaultID]; ok && vault.IsTrashed() {
+		return fmt.Errorf("vault %s is in the trash and must be restored first", vault.VaultID)
+	}
+	encOverview.TrashedAt = ""
+	return a.state.SaveItems()
+}
+
+// PurgeItem permanently deletes a trashed item along with its history
+func (a *CoreService) PurgeItem(itemId string) error {
+	a.state.mu.Lock()
+	defer a.state.mu.Unlock()
+	if a.isLocked() {
+		return fmt.Errorf("application not unlocked")
+	}
+	encOverview, ok := a.state.ItemOverviews[itemId]
+	if !ok {
+		return fmt.Errorf("no item overview found for item %s", itemId)
+	}
+	if !a.state.IsItemTrashed(encOverview) {
+		return fmt.Errorf("item %s is not in the trash", itemId)
+	}
+	a.state.PurgeItem(itemId)
+	return a.state.SaveAll()
+}
+
+// DeleteVault moves a vault, and with it all of its items, to the trash
+func (a *CoreService) DeleteVault(vaultId string) error {
+	a.state.mu.Lock()
+	defer a.state.mu.Unlock()
+	if a.isLocked() {
+		return fmt.Errorf("application not unlocked")
+	}
+	vault, ok := a.state.Vaults[vaultId]
+	if !ok {
+		return fmt.Errorf("vault %s not found", vaultId)
+	}
+	if vault.IsTrashed() {
+		return nil
+	}
+	vault.TrashedAt = time.Now().Format(time.RFC3339)
+	return a.state.SaveVaults()
+}
+
+// RestoreVault moves a vault out of the trash. Items which were trashed individually stay in the trash.
+func (a *CoreService) RestoreVault(vaultId string) error {
+	a.state.mu.Lock()
+	defer a.state.mu.Unlock()
+	if a.isLocked() {
+		return fmt.Errorf("application not unlocked")
+	}
+	vault, ok := a.state.Vaults[vaultId]
+	if !ok {
+		return fmt.Errorf("vault %s not found", vaultId)
+	}
+	vault.TrashedAt = ""
+	return a.state.SaveVaults()
+}
+
+// PurgeVault permanently deletes a trashed vault and all of its items
+func (a *CoreService) PurgeVault(vaultId string) error {
+	a.state.mu.Lock()
+	defer a.state.mu.Unlock()
+	if a.isLocked() {
+		return fmt.Errorf("application not unlocked")
+	}
+	vault, ok := a.state.Vaults[vaultId]
+	if !ok {
+		return fmt.Errorf("vault %s not found", vaultId)
+	}
+	if !vault.IsTrashed() {
+		return fmt.Errorf("vault %s is not in the trash", vaultId)
+	}
+	a.state.PurgeVault(vaultId)
+	return a.state.SaveAll()
+}
+
+type TrashedVault struct {
+	*structs.VaultMetadata
+	TrashedAt string `json:"trashed_at"`
+}
+
+type TrashContents struct {
+	Vaults []*TrashedVault               `json:"vaults"`
+	Items  []*DecryptedVaultItemOverview `json:"items"`
+}
+
+// ListTrash returns the trashed vaults and items. Items inside a trashed vault are included in Items. Vaults and
+// items past the retention period are purged first, so it takes the write lock.
+func (a *CoreService) ListTrash() (*TrashContents, error) {
+	a.state.mu.Lock()
+	defer a.state.mu.Unlock()
+	if a.isLocked() {
+		return nil, fmt.Errorf("application not unlocked")
+	}
+	if a.state.PurgeExpiredTrash(time.Now()) {
+		if err := a.state.SaveAll(); err != nil {
+			return nil, err
+		}
+	}
+	trash := &TrashContents{}
+	for _, vault := range a.state.Vaults {
+		if !vault.IsTrashed() {
+			continue
+		}
+		meta, err := a.getVaultMetadata(vault.VaultID)
+		if err != nil {
+			return nil, err
+		}
+		trash.Vaults = append(trash.Vaults, &TrashedVault{VaultMetadata: meta, TrashedAt: vault.TrashedAt})
+	}
+	overviews, err := a.listAllItemOverviews(true, ItemFilter{})
+	if err != nil {
+		return nil, err
+	}
+	for _, ov := range overviews {
+		if a.state.IsItemTrashed(ov.EncryptedVaultItemOverview) {
+			trash.Items = append(trash.Items, ov)
+		}
+	}
+	return trash, nil
+}
+
+// EmptyTrash permanently deletes every trashed vault and item
+func (a *CoreService) EmptyTrash() error {
+	a.state.mu.Lock()
+	defer a.state.mu.Unlock()
+	if a.isLocked() {
+		return fmt.Errorf("application not unlocked")
+	}
+	for vaultId, vault := range a.state.Vaults {
+		if vault.IsTrashed() {
+			a.state.PurgeVault(vaultId)
+		}
+	}
+	for itemId, encOverview := range a.state.ItemOverviews {
+		if encOverview.IsTrashed() {
+			a.state.PurgeItem(itemId)
+		}
+	}
+	return a.state.SaveAll()
+}